@@ -1,14 +1,51 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/user"
+	"path/filepath"
+	"strings"
 
+	"github.com/asanoviskhak/alipp/src/compiler"
+	"github.com/asanoviskhak/alipp/src/evaluator"
+	"github.com/asanoviskhak/alipp/src/format"
+	"github.com/asanoviskhak/alipp/src/highlight"
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/lsp"
+	"github.com/asanoviskhak/alipp/src/manifest"
+	"github.com/asanoviskhak/alipp/src/object"
 	"github.com/asanoviskhak/alipp/src/repl"
+	"github.com/asanoviskhak/alipp/src/stdlib"
+	"github.com/asanoviskhak/alipp/src/token"
+	"github.com/asanoviskhak/alipp/src/translit"
+	"github.com/asanoviskhak/alipp/src/types"
 )
 
 func main() {
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
+	os.Exit(replCommand(os.Args[1:]))
+}
+
+// replCommand starts the REPL, which is what alipp does without a
+// subcommand.
+func replCommand(args []string) int {
+	flags := flag.NewFlagSet("alipp", flag.ContinueOnError)
+	dictionary := dictionaryFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Белгисиз буйрук: %s\n", flags.Arg(0))
+		return 2
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
@@ -17,5 +54,621 @@ func main() {
 	fmt.Printf("Салам %s! Бул alipp программалоо тили!\n", user.Username)
 	fmt.Printf("Өзүңүз каалагандай тилди изилдеп көрүңүз\n\n")
 	fmt.Printf("Бул жерден чыгуу үчүн 'чыгуу' деп терип 'Enter' басыңыз же 'Ctrl' жана 'C' баскычтарын басыңыз\n\n")
-	repl.Start(os.Stdin, os.Stdout)
+	repl.Start(os.Stdin, os.Stdout, *dictionary)
+	return 0
+}
+
+// runCommand runs a subcommand and returns the process exit code.
+func runCommand(name string, args []string) int {
+	switch name {
+	case "lsp":
+		return lspCommand(args)
+	case "highlight":
+		return highlightCommand(args)
+	case "fmt":
+		return fmtCommand(args)
+	case "translit":
+		return translitCommand(args)
+	case "run":
+		return runProgramCommand(args)
+	case "build":
+		return buildCommand(args)
+	case "check":
+		return checkCommand(args)
+	case "init":
+		return initCommand(args)
+	case "deps":
+		return depsCommand(args)
+	default:
+		fmt.Fprintf(os.Stderr, "Белгисиз буйрук: %s\n", name)
+		return 2
+	}
+}
+
+// lspCommand serves the Language Server Protocol on stdin and stdout.
+func lspCommand(args []string) int {
+	flags := flag.NewFlagSet("lsp", flag.ContinueOnError)
+	dictionary := dictionaryFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	server := lsp.NewServer(os.Stdin, os.Stdout)
+	server.Dictionary = *dictionary
+	if err := server.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "lsp: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// highlightCommand prints source files, or stdin when none are given,
+// with syntax highlighting.
+func highlightCommand(args []string) int {
+	flags := flag.NewFlagSet("highlight", flag.ContinueOnError)
+	asHTML := flags.Bool("html", false, "ANSI түстөрдүн ордуна HTML <span> белгилерин чыгаруу")
+	dictionary := dictionaryFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	render := highlight.ANSI
+	if *asHTML {
+		render = highlight.HTML
+	}
+
+	sources, err := readSources(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "highlight: %s\n", err)
+		return 1
+	}
+
+	for _, source := range sources {
+		fmt.Print(render(source, *dictionary))
+	}
+
+	return 0
+}
+
+// translitCommand converts the keywords and identifiers of source files,
+// or stdin when none are given, to the other script. With -w the files
+// are rewritten in place instead of printed.
+func translitCommand(args []string) int {
+	flags := flag.NewFlagSet("translit", flag.ContinueOnError)
+	to := flags.String("to", "latin", "максаттуу жазуу: latin же cyrillic")
+	write := flags.Bool("w", false, "натыйжаны файлдын өзүнө жазуу")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var target translit.Script
+	switch *to {
+	case "latin":
+		target = translit.Latin
+	case "cyrillic":
+		target = translit.Cyrillic
+	default:
+		fmt.Fprintf(os.Stderr, "translit: белгисиз жазуу: %s\n", *to)
+		return 2
+	}
+
+	paths := flags.Args()
+	if *write && len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "translit: -w үчүн файл керек")
+		return 2
+	}
+
+	sources, err := readSources(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "translit: %s\n", err)
+		return 1
+	}
+
+	for i, source := range sources {
+		name := "<stdin>"
+		if len(paths) > 0 {
+			name = paths[i]
+		}
+
+		result, warnings := translit.Source(source, target)
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "%s:%d:%d: %s\n", name, warning.Line, warning.Column, warning.Message)
+		}
+
+		if !*write {
+			fmt.Print(result)
+			continue
+		}
+		if err := os.WriteFile(name, []byte(result), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "translit: %s\n", err)
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// fmtCommand respells the keywords of source files, or stdin when none
+// are given, in another language's dictionary.
+func fmtCommand(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	from := flags.String("from", "ky", "\"тил\" белгиси жок файлдардын тили")
+	to := flags.String("to", "", "максаттуу тил; берилбесе файлдын өз тили")
+	write := flags.Bool("w", false, "натыйжаны файлдын өзүнө жазуу")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	source, ok := token.LookupDictionary(*from)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "fmt: белгисиз тил: %s\n", *from)
+		return 2
+	}
+	var target *token.Dictionary
+	if *to != "" {
+		if target, ok = token.LookupDictionary(*to); !ok {
+			fmt.Fprintf(os.Stderr, "fmt: белгисиз тил: %s\n", *to)
+			return 2
+		}
+	}
+
+	paths := flags.Args()
+	if *write && len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "fmt: -w үчүн файл керек")
+		return 2
+	}
+
+	sources, err := readSources(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fmt: %s\n", err)
+		return 1
+	}
+
+	status := 0
+	for i, text := range sources {
+		name := "<stdin>"
+		if len(paths) > 0 {
+			name = paths[i]
+		}
+
+		result, errors := format.Translate(text, source, target)
+		for _, formatError := range errors {
+			fmt.Fprintf(os.Stderr, "%s:%d:%d: %s\n", name, formatError.Line, formatError.Column, formatError.Message)
+		}
+		if len(errors) > 0 {
+			status = 1
+			continue
+		}
+
+		if !*write {
+			fmt.Print(result)
+			continue
+		}
+		if err := os.WriteFile(name, []byte(result), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "fmt: %s\n", err)
+			return 1
+		}
+	}
+
+	return status
+}
+
+// initCommand writes a manifest for a new package in a directory, the
+// current one unless one is given, named after the directory.
+func initCommand(args []string) int {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	name := flags.String("name", "", "пакеттин аты; берилбесе папканын аты")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "init: бир гана папка керек")
+		return 2
+	}
+
+	directory := "."
+	if flags.NArg() == 1 {
+		directory = flags.Arg(0)
+		if err := os.MkdirAll(directory, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "init: %s\n", err)
+			return 1
+		}
+	}
+	if *name == "" {
+		absolute, err := filepath.Abs(directory)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "init: %s\n", err)
+			return 1
+		}
+		*name = filepath.Base(absolute)
+	}
+
+	created, err := manifest.Init(directory, *name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "init: %s\n", err)
+		return 1
+	}
+
+	fmt.Printf("%s пакети түзүлдү: %s\n", created.Name, filepath.Join(directory, manifest.FileName))
+	return 0
+}
+
+// depsCommand resolves the dependencies of the package the current
+// directory is in, copies those from the registry into its vendor
+// directory and writes its lockfile.
+func depsCommand(args []string) int {
+	flags := flag.NewFlagSet("deps", flag.ContinueOnError)
+	registry := flags.String("registry", "", "пакеттер реестри турган папка; берилбесе "+manifest.RegistryVariable+" же ~/.alipp/registry")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "deps: аргумент күтүлгөн жок")
+		return 2
+	}
+	if *registry == "" {
+		*registry = manifest.DefaultRegistry()
+	}
+
+	found, err := manifest.Find(".")
+	if err == nil && found == nil {
+		err = fmt.Errorf("%s табылган жок; `alipp init` менен түзүңүз", manifest.FileName)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "deps: %s\n", err)
+		return 1
+	}
+
+	packages, err := manifest.Resolve(found, *registry)
+	if err == nil {
+		err = manifest.WriteLock(found, packages)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "deps: %s\n", err)
+		return 1
+	}
+
+	for _, resolved := range packages {
+		fmt.Printf("%s %s (%s)\n", resolved.Name, resolved.Version, resolved.Source)
+	}
+	return 0
+}
+
+// runProgramCommand evaluates a source file, or stdin when none is
+// given, after the modules it imports.
+func runProgramCommand(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	dictionary := dictionaryFlag(flags)
+	capabilities := capabilityFlags(flags)
+	searchPath := searchPathFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	loaderInstance := loader.New(append(*searchPath, loader.DefaultSearchPath()...))
+	loaderInstance.Dictionary = *dictionary
+	loaderInstance.Reserved = stdlib.ReservedNames()
+	modules, status := loadProgram("run", loaderInstance, flags.Args())
+	if modules == nil {
+		return status
+	}
+
+	// The modules share one reader, so that none reads ahead of another.
+	in := bufio.NewReader(os.Stdin)
+	runner := evaluator.NewModules(loaderInstance, func() *object.Environment {
+		return evaluator.NewEnvironment(in, os.Stdout, *capabilities)
+	})
+	if result, ok := runner.Run(modules).(*object.Error); ok {
+		fmt.Fprint(os.Stderr, evaluator.ReportModules(result, modules))
+		return 1
+	}
+
+	return 0
+}
+
+// checkCommand reports the type errors of a source file, or stdin when
+// none is given, and of the modules it imports, without running them.
+func checkCommand(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	dictionary := dictionaryFlag(flags)
+	searchPath := searchPathFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	loaderInstance := loader.New(append(*searchPath, loader.DefaultSearchPath()...))
+	loaderInstance.Dictionary = *dictionary
+	loaderInstance.Reserved = stdlib.ReservedNames()
+	modules, status := loadProgram("check", loaderInstance, flags.Args())
+	if modules == nil {
+		return status
+	}
+
+	// Warnings do not fail the check.
+	for _, module := range modules {
+		for _, warning := range module.Warnings {
+			fmt.Fprintf(os.Stderr, "%s:%d:%d: эскертүү: %s\n", warning.File, warning.Line, warning.Column, warning.Message)
+		}
+	}
+
+	errors := types.CheckModules(modules)
+	for _, checkError := range errors {
+		fmt.Fprintln(os.Stderr, checkError)
+	}
+	if len(errors) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// buildCommand compiles a source file, or stdin when none is given, to
+// JavaScript. A program that imports other modules becomes a directory
+// of ES modules, one for each.
+func buildCommand(args []string) int {
+	flags := flag.NewFlagSet("build", flag.ContinueOnError)
+	dictionary := dictionaryFlag(flags)
+	output := flags.String("o", "", "JavaScript жазыла турган файл же, модулдары бар программа үчүн, папка; берилбесе stdout")
+	sourceMap := flags.Bool("source-map", false, "ар бир скрипттин жанына .map булак картасын жазуу")
+	inlineSourceMap := flags.Bool("inline-source-map", false, "булак картасын скрипттин өзүнө жазуу")
+	declarations := flags.Bool("declarations", false, "ар бир скрипттин жанына экспорттордун TypeScript .d.ts жарыяларын жазуу")
+	capabilities := capabilityFlags(flags)
+	searchPath := searchPathFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	loaderInstance := loader.New(append(*searchPath, loader.DefaultSearchPath()...))
+	loaderInstance.Dictionary = *dictionary
+	loaderInstance.Reserved = stdlib.ReservedNames()
+	modules, status := loadProgram("build", loaderInstance, flags.Args())
+	if modules == nil {
+		return status
+	}
+
+	options := compiler.Options{Capabilities: *capabilities, SourceMap: *sourceMap, InlineSourceMap: *inlineSourceMap, Declarations: *declarations}
+	if len(modules) > 1 {
+		return buildModules(modules, *output, options)
+	}
+
+	name := ""
+	if *output != "" {
+		name = filepath.Base(*output)
+		options.OutputDirectory = filepath.Dir(*output)
+	} else if *sourceMap && !*inlineSourceMap {
+		fmt.Fprintln(os.Stderr, "build: -source-map үчүн -o керек; stdout үчүн -inline-source-map колдонуңуз")
+		return 2
+	} else if *declarations {
+		fmt.Fprintln(os.Stderr, "build: -declarations үчүн -o керек")
+		return 2
+	}
+
+	script, errors := compiler.CompileModule(modules[0], name, options)
+	for _, compileError := range errors {
+		fmt.Fprintf(os.Stderr, "%s:%d:%d: %s\n", modules[0].Name, compileError.Line, compileError.Column, compileError.Message)
+	}
+	if len(errors) > 0 {
+		return 1
+	}
+
+	if *output == "" {
+		fmt.Print(script.JavaScript)
+		return 0
+	}
+	if err := writeScript(*output, script); err != nil {
+		fmt.Fprintf(os.Stderr, "build: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// buildModules writes the ES modules compiled from modules to the
+// directory output.
+func buildModules(modules []*loader.Module, output string, options compiler.Options) int {
+	if output == "" {
+		fmt.Fprintln(os.Stderr, "build: импорттору бар программа үчүн -o папкасы керек")
+		return 2
+	}
+
+	options.OutputDirectory = output
+	scripts, errors := compiler.CompileModules(modules, options)
+	for _, compileError := range errors {
+		fmt.Fprintf(os.Stderr, "%s:%d:%d: %s\n", compileError.File, compileError.Line, compileError.Column, compileError.Message)
+	}
+	if len(errors) > 0 {
+		return 1
+	}
+
+	for _, script := range scripts {
+		path := filepath.Join(output, filepath.FromSlash(script.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "build: %s\n", err)
+			return 1
+		}
+		if err := writeScript(path, script); err != nil {
+			fmt.Fprintf(os.Stderr, "build: %s\n", err)
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// writeScript writes a script to path, and its source map and
+// declarations, if it has them, next to it.
+func writeScript(path string, script compiler.Script) error {
+	if err := os.WriteFile(path, []byte(script.JavaScript), 0644); err != nil {
+		return err
+	}
+	if script.SourceMap != "" {
+		if err := os.WriteFile(path+".map", []byte(script.SourceMap+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	if script.Declarations == "" {
+		return nil
+	}
+
+	return os.WriteFile(compiler.DeclarationPath(path), []byte(script.Declarations), 0644)
+}
+
+// capabilityFlags defines the flags that let a program touch the disk.
+func capabilityFlags(flags *flag.FlagSet) *stdlib.Capabilities {
+	capabilities := &stdlib.Capabilities{}
+	flags.Var((*pathList)(&capabilities.Read), "allow-read", "программа окуй ала турган папкалар, үтүр менен")
+	flags.Var((*pathList)(&capabilities.Write), "allow-write", "программа жаза ала турган папкалар, үтүр менен")
+
+	return capabilities
+}
+
+// searchPathFlag defines the flag that lists the directories searched
+// for imports. Those in the environment are searched after them.
+func searchPathFlag(flags *flag.FlagSet) *[]string {
+	searchPath := &[]string{}
+	flags.Var((*pathList)(searchPath), "path", "модулдар изделе турган папкалар, үтүр менен; "+loader.PathVariable+" ичиндегилер алардан кийин")
+
+	return searchPath
+}
+
+// pathList is a flag that may be repeated and takes comma-separated
+// paths.
+type pathList []string
+
+func (list *pathList) String() string {
+	return strings.Join(*list, ",")
+}
+
+func (list *pathList) Set(value string) error {
+	*list = append(*list, strings.Split(value, ",")...)
+	return nil
+}
+
+// dictionaryFlag adds the -lang flag, the dictionary of the files that
+// have no "тил" comment.
+func dictionaryFlag(flags *flag.FlagSet) **token.Dictionary {
+	name := &dictionaryName{dictionary: token.Kyrgyz}
+	flags.Var(name, "lang", "\"тил\" белгиси жок файлдардын тили: "+strings.Join(token.Dictionaries(), ", "))
+	return &name.dictionary
+}
+
+// dictionaryName is a flag.Value holding a built-in dictionary, set by
+// its name.
+type dictionaryName struct {
+	dictionary *token.Dictionary
+}
+
+func (name *dictionaryName) String() string {
+	if name.dictionary == nil {
+		return ""
+	}
+	return name.dictionary.Name
+}
+
+func (name *dictionaryName) Set(value string) error {
+	dictionary, ok := token.LookupDictionary(value)
+	if !ok {
+		return fmt.Errorf("белгисиз тил: %s", value)
+	}
+	if err := dictionary.Validate(stdlib.ReservedNames()); err != nil {
+		return err
+	}
+
+	name.dictionary = dictionary
+	return nil
+}
+
+// loadProgram loads the one file named in paths, or stdin, and the
+// modules it imports, in the order they run. A directory stands for the
+// entry point of the package in it. It reports the errors that keep
+// them from running and returns nil modules and the exit code on
+// failure.
+func loadProgram(command string, loaderInstance *loader.Loader, paths []string) ([]*loader.Module, int) {
+	if len(paths) > 1 {
+		fmt.Fprintf(os.Stderr, "%s: бир гана файл керек\n", command)
+		return nil, 2
+	}
+
+	directory := "."
+	if len(paths) == 1 {
+		directory = filepath.Dir(paths[0])
+		if info, err := os.Stat(paths[0]); err == nil && info.IsDir() {
+			directory = paths[0]
+		}
+	}
+	found, err := manifest.Find(directory)
+	if err == nil && found != nil {
+		err = usePackages(loaderInstance, found)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", command, err)
+		return nil, 1
+	}
+	if len(paths) == 1 && directory == paths[0] {
+		if found == nil {
+			fmt.Fprintf(os.Stderr, "%s: %s ичинде %s жок\n", command, paths[0], manifest.FileName)
+			return nil, 1
+		}
+		paths = []string{filepath.Join(found.Directory, found.Entry)}
+	}
+
+	var modules []*loader.Module
+	var errors []loader.Error
+	if len(paths) == 0 {
+		sources, err := readSources(paths)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", command, err)
+			return nil, 1
+		}
+		modules, errors = loaderInstance.LoadSource("<stdin>", ".", sources[0])
+	} else {
+		modules, errors = loaderInstance.Load(paths[0])
+	}
+
+	for _, loadError := range errors {
+		fmt.Fprintln(os.Stderr, loadError)
+	}
+	if len(errors) > 0 {
+		return nil, 1
+	}
+
+	return modules, 0
+}
+
+// usePackages lets the loader import the packages found's lockfile
+// records, which must be up to date.
+func usePackages(loaderInstance *loader.Loader, found *manifest.Manifest) error {
+	packages, err := manifest.Locked(found)
+	if err != nil {
+		return err
+	}
+
+	loaderInstance.Packages = map[string]loader.Package{}
+	for _, locked := range packages {
+		loaderInstance.Packages[locked.Name] = loader.Package{Directory: locked.Directory, Entry: locked.Entry}
+	}
+	return nil
+}
+
+// readSources reads every named file, or stdin when no names are given.
+func readSources(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(input)}, nil
+	}
+
+	sources := []string{}
+	for _, path := range paths {
+		input, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, string(input))
+	}
+
+	return sources, nil
 }
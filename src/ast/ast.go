@@ -2,6 +2,8 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
+	"strings"
 
 	"github.com/asanoviskhak/alipp/src/token"
 )
@@ -47,7 +49,11 @@ func (program *Program) String() string {
 type LetStatement struct {
 	Token token.Token
 	Name  *Identifier
-	Value Expression
+	// Pattern is the list or hash pattern the value is taken apart by,
+	// when there is one instead of Name.
+	Pattern Pattern
+	Type    TypeExpression // nil when the name is not annotated
+	Value   Expression
 }
 
 func (statement *LetStatement) statementNode() {}
@@ -59,7 +65,14 @@ func (statement *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(statement.TokenLiteral() + " ")
-	out.WriteString(statement.Name.String())
+	if statement.Pattern != nil {
+		out.WriteString(statement.Pattern.String())
+	} else {
+		out.WriteString(statement.Name.String())
+	}
+	if statement.Type != nil {
+		out.WriteString(": " + statement.Type.String())
+	}
 	out.WriteString(" = ")
 
 	if statement.Value != nil {
@@ -130,6 +143,8 @@ func (es *ExpressionStatement) String() string {
 type IntegerLiteral struct {
 	Token token.Token
 	Value int64
+	// Big holds a literal too large for an int64, which leaves Value 0.
+	Big *big.Int
 }
 
 func (integerLiteral *IntegerLiteral) expressionNode() {}
@@ -140,6 +155,34 @@ func (integerLiteral *IntegerLiteral) String() string {
 	return integerLiteral.Token.Literal
 }
 
+// Float literal
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (floatLiteral *FloatLiteral) expressionNode() {}
+func (floatLiteral *FloatLiteral) TokenLiteral() string {
+	return floatLiteral.Token.Literal
+}
+func (floatLiteral *FloatLiteral) String() string {
+	return floatLiteral.Token.Literal
+}
+
+// String literal
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+func (stringLiteral *StringLiteral) expressionNode() {}
+func (stringLiteral *StringLiteral) TokenLiteral() string {
+	return stringLiteral.Token.Literal
+}
+func (stringLiteral *StringLiteral) String() string {
+	return stringLiteral.Token.Literal
+}
+
 // Prefix expression
 type PrefixExpression struct {
 	Token    token.Token
@@ -185,3 +228,600 @@ func (infixExpression *InfixExpression) String() string {
 
 	return out.String()
 }
+
+// Boolean
+type Boolean struct {
+	Token token.Token
+	Value bool
+}
+
+func (boolean *Boolean) expressionNode() {}
+func (boolean *Boolean) TokenLiteral() string {
+	return boolean.Token.Literal
+}
+func (boolean *Boolean) String() string {
+	return boolean.Token.Literal
+}
+
+// Block statement
+type BlockStatement struct {
+	Token      token.Token // The '{' token
+	Statements []Statement
+	End        token.Token // The '}' token
+}
+
+func (blockStatement *BlockStatement) statementNode() {}
+func (blockStatement *BlockStatement) TokenLiteral() string {
+	return blockStatement.Token.Literal
+}
+func (blockStatement *BlockStatement) String() string {
+	var out bytes.Buffer
+
+	for _, s := range blockStatement.Statements {
+		out.WriteString(s.String())
+	}
+
+	return out.String()
+}
+
+// If expression
+type IfExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement
+}
+
+func (ifExpression *IfExpression) expressionNode() {}
+func (ifExpression *IfExpression) TokenLiteral() string {
+	return ifExpression.Token.Literal
+}
+func (ifExpression *IfExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ifExpression.TokenLiteral())
+	out.WriteString(ifExpression.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ifExpression.Consequence.String())
+
+	if ifExpression.Alternative != nil {
+		out.WriteString("же ")
+		out.WriteString(ifExpression.Alternative.String())
+	}
+
+	return out.String()
+}
+
+// Function literal
+type FunctionLiteral struct {
+	Token token.Token
+	// Parameters are names, or list and hash patterns that take the
+	// arguments apart.
+	Parameters []Pattern
+	// ParameterTypes holds the annotation of each parameter, nil where
+	// there is none. It is nil when no parameter is annotated.
+	ParameterTypes []TypeExpression
+	ReturnType     TypeExpression // nil when the result is not annotated
+	Body           *BlockStatement
+}
+
+func (functionLiteral *FunctionLiteral) expressionNode() {}
+func (functionLiteral *FunctionLiteral) TokenLiteral() string {
+	return functionLiteral.Token.Literal
+}
+func (functionLiteral *FunctionLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for i, p := range functionLiteral.Parameters {
+		if i < len(functionLiteral.ParameterTypes) && functionLiteral.ParameterTypes[i] != nil {
+			if defaultPattern, ok := p.(*DefaultPattern); ok {
+				params = append(params, defaultPattern.Pattern.String()+": "+functionLiteral.ParameterTypes[i].String()+" = "+defaultPattern.Default.String())
+				continue
+			}
+			params = append(params, p.String()+": "+functionLiteral.ParameterTypes[i].String())
+			continue
+		}
+		params = append(params, p.String())
+	}
+
+	out.WriteString(functionLiteral.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") ")
+	if functionLiteral.ReturnType != nil {
+		out.WriteString(": " + functionLiteral.ReturnType.String() + " ")
+	}
+	out.WriteString(functionLiteral.Body.String())
+
+	return out.String()
+}
+
+// Call expression
+type CallExpression struct {
+	Token     token.Token // The '(' token
+	Function  Expression  // Identifier or FunctionLiteral
+	Arguments []Expression
+	End       token.Token // The ')' token
+}
+
+func (callExpression *CallExpression) expressionNode() {}
+func (callExpression *CallExpression) TokenLiteral() string {
+	return callExpression.Token.Literal
+}
+func (callExpression *CallExpression) String() string {
+	var out bytes.Buffer
+
+	args := []string{}
+	for _, a := range callExpression.Arguments {
+		args = append(args, a.String())
+	}
+
+	out.WriteString(callExpression.Function.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(args, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+type ArrayLiteral struct {
+	Token    token.Token // The '[' token
+	Elements []Expression
+	End      token.Token // The ']' token
+}
+
+func (arrayLiteral *ArrayLiteral) expressionNode() {}
+func (arrayLiteral *ArrayLiteral) TokenLiteral() string {
+	return arrayLiteral.Token.Literal
+}
+func (arrayLiteral *ArrayLiteral) String() string {
+	elements := []string{}
+	for _, element := range arrayLiteral.Elements {
+		elements = append(elements, element.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// HashLiteral keeps its pairs in source order, Keys[i] mapping to
+// Values[i].
+type HashLiteral struct {
+	Token  token.Token // The '{' token
+	Keys   []Expression
+	Values []Expression
+	End    token.Token // The '}' token
+}
+
+func (hashLiteral *HashLiteral) expressionNode() {}
+func (hashLiteral *HashLiteral) TokenLiteral() string {
+	return hashLiteral.Token.Literal
+}
+func (hashLiteral *HashLiteral) String() string {
+	pairs := []string{}
+	for i, key := range hashLiteral.Keys {
+		pairs = append(pairs, key.String()+": "+hashLiteral.Values[i].String())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+type IndexExpression struct {
+	Token token.Token // The '[' token
+	Left  Expression
+	Index Expression
+	End   token.Token // The ']' token
+}
+
+func (indexExpression *IndexExpression) expressionNode() {}
+func (indexExpression *IndexExpression) TokenLiteral() string {
+	return indexExpression.Token.Literal
+}
+func (indexExpression *IndexExpression) String() string {
+	return "(" + indexExpression.Left.String() + "[" + indexExpression.Index.String() + "])"
+}
+
+// Member expression reads a named member of a value, such as a function
+// of a builtin module in сап.узундук.
+type MemberExpression struct {
+	Token    token.Token // The '.' token
+	Object   Expression
+	Property *Identifier
+}
+
+func (memberExpression *MemberExpression) expressionNode() {}
+func (memberExpression *MemberExpression) TokenLiteral() string {
+	return memberExpression.Token.Literal
+}
+func (memberExpression *MemberExpression) String() string {
+	return memberExpression.Object.String() + "." + memberExpression.Property.String()
+}
+
+// Try statement runs Block, and Catch when Block raises an error, with
+// the error bound to Parameter. Finally runs after both, however they
+// end. Catch or Finally may be nil, but not both.
+type TryStatement struct {
+	Token     token.Token // The аракет token
+	Block     *BlockStatement
+	Parameter *Identifier // nil when the кармоо clause binds nothing
+	Catch     *BlockStatement
+	Finally   *BlockStatement
+}
+
+func (tryStatement *TryStatement) statementNode() {}
+func (tryStatement *TryStatement) TokenLiteral() string {
+	return tryStatement.Token.Literal
+}
+func (tryStatement *TryStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(tryStatement.TokenLiteral() + " {" + tryStatement.Block.String() + "}")
+	if tryStatement.Catch != nil {
+		out.WriteString(" кармоо ")
+		if tryStatement.Parameter != nil {
+			out.WriteString("(" + tryStatement.Parameter.String() + ") ")
+		}
+		out.WriteString("{" + tryStatement.Catch.String() + "}")
+	}
+	if tryStatement.Finally != nil {
+		out.WriteString(" акыры {" + tryStatement.Finally.String() + "}")
+	}
+
+	return out.String()
+}
+
+// Throw statement raises its value as an error.
+type ThrowStatement struct {
+	Token token.Token // The ыргыт token
+	Value Expression
+}
+
+func (throwStatement *ThrowStatement) statementNode() {}
+func (throwStatement *ThrowStatement) TokenLiteral() string {
+	return throwStatement.Token.Literal
+}
+func (throwStatement *ThrowStatement) String() string {
+	return throwStatement.TokenLiteral() + " " + throwStatement.Value.String() + ";"
+}
+
+// Import statement binds Names to the values the module in the file at
+// Path exports.
+type ImportStatement struct {
+	Token token.Token // The импорт token
+	Names []*Identifier
+	Path  *StringLiteral
+}
+
+func (importStatement *ImportStatement) statementNode() {}
+func (importStatement *ImportStatement) TokenLiteral() string {
+	return importStatement.Token.Literal
+}
+func (importStatement *ImportStatement) String() string {
+	names := []string{}
+	for _, name := range importStatement.Names {
+		names = append(names, name.String())
+	}
+
+	return importStatement.TokenLiteral() + " { " + strings.Join(names, ", ") + " } \"" + importStatement.Path.Value + "\";"
+}
+
+// Export statement makes the name Statement binds visible to the modules
+// that import its file.
+type ExportStatement struct {
+	Token     token.Token // The экспорт token
+	Statement *LetStatement
+}
+
+func (exportStatement *ExportStatement) statementNode() {}
+func (exportStatement *ExportStatement) TokenLiteral() string {
+	return exportStatement.Token.Literal
+}
+func (exportStatement *ExportStatement) String() string {
+	return exportStatement.TokenLiteral() + " " + exportStatement.Statement.String()
+}
+
+// Extern name is a name an extern statement binds, and the JavaScript
+// it stands for when that is not the name itself.
+type ExternName struct {
+	Name       *Identifier
+	JavaScript *StringLiteral
+}
+
+func (externName *ExternName) String() string {
+	if externName.JavaScript == nil {
+		return externName.Name.String()
+	}
+
+	return externName.Name.String() + " = \"" + externName.JavaScript.Value + "\""
+}
+
+// Extern statement binds Names to JavaScript the program is compiled
+// with: globals, such as Math or console.log, or the exports of the
+// JavaScript module Module when it is set.
+type ExternStatement struct {
+	Token  token.Token // The тышкы token
+	Names  []*ExternName
+	Module *StringLiteral
+}
+
+func (externStatement *ExternStatement) statementNode() {}
+func (externStatement *ExternStatement) TokenLiteral() string {
+	return externStatement.Token.Literal
+}
+func (externStatement *ExternStatement) String() string {
+	if externStatement.Module == nil && len(externStatement.Names) == 1 {
+		return externStatement.TokenLiteral() + " " + externStatement.Names[0].String() + ";"
+	}
+
+	names := []string{}
+	for _, name := range externStatement.Names {
+		names = append(names, name.String())
+	}
+
+	return externStatement.TokenLiteral() + " { " + strings.Join(names, ", ") + " } \"" + externStatement.Module.Value + "\";"
+}
+
+// Match expression is салыштыр (Subject) { Arms }, whose value is that
+// of the first arm whose pattern matches the subject.
+type MatchExpression struct {
+	Token   token.Token // The салыштыр token
+	Subject Expression
+	Arms    []*MatchArm
+	End     token.Token // The '}' token
+}
+
+func (matchExpression *MatchExpression) expressionNode() {}
+func (matchExpression *MatchExpression) TokenLiteral() string {
+	return matchExpression.Token.Literal
+}
+func (matchExpression *MatchExpression) String() string {
+	arms := []string{}
+	for _, arm := range matchExpression.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	return matchExpression.TokenLiteral() + " (" + matchExpression.Subject.String() + ") { " + strings.Join(arms, ", ") + " }"
+}
+
+// MatchArm is Pattern эгер Guard => Body. The arm is taken when the
+// pattern matches and the guard, which sees the names the pattern binds,
+// holds. Guard is nil when there is none.
+type MatchArm struct {
+	Pattern Pattern
+	IfToken token.Token // The эгер token, when there is a guard
+	Guard   Expression
+	Arrow   token.Token // The => token
+	Body    Expression
+}
+
+func (matchArm *MatchArm) String() string {
+	out := matchArm.Pattern.String()
+	if matchArm.Guard != nil {
+		out += " " + matchArm.IfToken.Literal + " " + matchArm.Guard.String()
+	}
+
+	return out + " => " + matchArm.Body.String()
+}
+
+// Pattern is what an arm of салыштыр matches a value against, or what
+// сакта and a parameter take a value apart by.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// Literal pattern matches a value equal to Value, which is a number,
+// string or boolean literal, or a negated number.
+type LiteralPattern struct {
+	Value Expression
+}
+
+func (literalPattern *LiteralPattern) patternNode() {}
+func (literalPattern *LiteralPattern) TokenLiteral() string {
+	return literalPattern.Value.TokenLiteral()
+}
+func (literalPattern *LiteralPattern) String() string { return literalPattern.Value.String() }
+
+// Binding pattern matches any value and binds Name to it.
+type BindingPattern struct {
+	Name *Identifier
+}
+
+func (bindingPattern *BindingPattern) patternNode() {}
+func (bindingPattern *BindingPattern) TokenLiteral() string {
+	return bindingPattern.Name.TokenLiteral()
+}
+func (bindingPattern *BindingPattern) String() string { return bindingPattern.Name.String() }
+
+// Wildcard pattern is _, which matches any value and binds nothing.
+type WildcardPattern struct {
+	Token token.Token
+}
+
+func (wildcardPattern *WildcardPattern) patternNode() {}
+func (wildcardPattern *WildcardPattern) TokenLiteral() string {
+	return wildcardPattern.Token.Literal
+}
+func (wildcardPattern *WildcardPattern) String() string { return wildcardPattern.Token.Literal }
+
+// Array pattern matches a list with as many elements as it has, each
+// matching its pattern, or at least as many when it has a Rest, which
+// binds a list of the elements after them.
+type ArrayPattern struct {
+	Token    token.Token // The '[' token
+	Elements []Pattern
+	Ellipsis token.Token // The '...' token, when there is a Rest
+	Rest     *Identifier
+	End      token.Token // The ']' token
+}
+
+func (arrayPattern *ArrayPattern) patternNode() {}
+func (arrayPattern *ArrayPattern) TokenLiteral() string {
+	return arrayPattern.Token.Literal
+}
+func (arrayPattern *ArrayPattern) String() string {
+	elements := []string{}
+	for _, element := range arrayPattern.Elements {
+		elements = append(elements, element.String())
+	}
+	if arrayPattern.Rest != nil {
+		elements = append(elements, "..."+arrayPattern.Rest.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Hash pattern matches a hash that has each of Keys, which are literals,
+// with a value that matches the pattern in Values. Other keys are
+// ignored. A name alone, as in {аты}, is the key "аты" bound to the
+// name; its key is a string literal with the name's token.
+type HashPattern struct {
+	Token  token.Token // The '{' token
+	Keys   []Expression
+	Values []Pattern
+	End    token.Token // The '}' token
+}
+
+func (hashPattern *HashPattern) patternNode() {}
+func (hashPattern *HashPattern) TokenLiteral() string {
+	return hashPattern.Token.Literal
+}
+func (hashPattern *HashPattern) String() string {
+	pairs := []string{}
+	for i, key := range hashPattern.Keys {
+		if hashPattern.IsShorthand(i) {
+			pairs = append(pairs, hashPattern.Values[i].String())
+			continue
+		}
+		pairs = append(pairs, key.String()+": "+hashPattern.Values[i].String())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// IsShorthand reports whether the i-th key was written as a name alone.
+func (hashPattern *HashPattern) IsShorthand(i int) bool {
+	key, ok := hashPattern.Keys[i].(*StringLiteral)
+	return ok && key.Token.Type == token.IDENT
+}
+
+// Default pattern is Pattern = Default in сакта or a parameter. Default
+// is the value taken apart when a list has no element or a hash no key
+// for the pattern.
+type DefaultPattern struct {
+	Pattern Pattern
+	Assign  token.Token // The '=' token
+	Default Expression
+}
+
+func (defaultPattern *DefaultPattern) patternNode() {}
+func (defaultPattern *DefaultPattern) TokenLiteral() string {
+	return defaultPattern.Pattern.TokenLiteral()
+}
+func (defaultPattern *DefaultPattern) String() string {
+	return defaultPattern.Pattern.String() + " = " + defaultPattern.Default.String()
+}
+
+// Bindings returns the names pattern binds, in the order they appear.
+func Bindings(pattern Pattern) []*Identifier {
+	switch pattern := pattern.(type) {
+	case *BindingPattern:
+		return []*Identifier{pattern.Name}
+	case *DefaultPattern:
+		return Bindings(pattern.Pattern)
+	case *ArrayPattern:
+		names := []*Identifier{}
+		for _, element := range pattern.Elements {
+			names = append(names, Bindings(element)...)
+		}
+		if pattern.Rest != nil {
+			names = append(names, pattern.Rest)
+		}
+		return names
+	case *HashPattern:
+		names := []*Identifier{}
+		for _, value := range pattern.Values {
+			names = append(names, Bindings(value)...)
+		}
+		return names
+	}
+
+	return nil
+}
+
+// TypeExpression is a type annotation.
+type TypeExpression interface {
+	Node
+	typeNode()
+}
+
+// Named type is a type written as a name, such as Сан, with the types
+// in brackets of the types that take them, such as Тизме[Сан].
+type NamedType struct {
+	Token     token.Token // The name
+	Name      string
+	Arguments []TypeExpression
+	End       token.Token // The ] token, when there are arguments
+}
+
+func (namedType *NamedType) typeNode() {}
+func (namedType *NamedType) TokenLiteral() string {
+	return namedType.Token.Literal
+}
+func (namedType *NamedType) String() string {
+	if namedType.Arguments == nil {
+		return namedType.Name
+	}
+
+	arguments := []string{}
+	for _, argument := range namedType.Arguments {
+		arguments = append(arguments, argument.String())
+	}
+
+	return namedType.Name + "[" + strings.Join(arguments, ", ") + "]"
+}
+
+// Function type is the type of a function, such as функ(Сан): Сан.
+type FunctionType struct {
+	Token      token.Token // The функ token
+	Parameters []TypeExpression
+	Result     TypeExpression
+}
+
+func (functionType *FunctionType) typeNode() {}
+func (functionType *FunctionType) TokenLiteral() string {
+	return functionType.Token.Literal
+}
+func (functionType *FunctionType) String() string {
+	parameters := []string{}
+	for _, parameter := range functionType.Parameters {
+		parameters = append(parameters, parameter.String())
+	}
+
+	return functionType.TokenLiteral() + "(" + strings.Join(parameters, ", ") + "): " + functionType.Result.String()
+}
+
+// Bad expression is a placeholder the parser leaves where an expression
+// could not be parsed, so the rest of the tree stays usable.
+type BadExpression struct {
+	Token token.Token // The token the error was detected at
+}
+
+func (badExpression *BadExpression) expressionNode() {}
+func (badExpression *BadExpression) TokenLiteral() string {
+	return badExpression.Token.Literal
+}
+func (badExpression *BadExpression) String() string { return "<?>" }
+
+// Bad statement covers the tokens skipped while recovering from a
+// syntax error.
+type BadStatement struct {
+	Token token.Token // The first skipped token
+	End   token.Token // The last skipped token
+}
+
+func (badStatement *BadStatement) statementNode() {}
+func (badStatement *BadStatement) TokenLiteral() string {
+	return badStatement.Token.Literal
+}
+func (badStatement *BadStatement) String() string { return "<?>;" }
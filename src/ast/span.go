@@ -0,0 +1,223 @@
+package ast
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+// Position is a place in the source. Line and Column are 1-based, and
+// Column counts runes.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Span is the part of the source a node was parsed from, from Start up
+// to, but not including, End. The zero Span is nowhere.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// IsZero reports whether span is nowhere.
+func (span Span) IsZero() bool {
+	return span.Start.Line == 0
+}
+
+// SpanOf returns the span of node, from its first token to its last.
+func SpanOf(node Node) Span {
+	first, last := firstToken(node), lastToken(node)
+	if first.Line == 0 {
+		return Span{}
+	}
+	if last.Line == 0 {
+		last = first
+	}
+
+	return Span{Start: Position{Line: first.Line, Column: first.Column}, End: tokenEnd(last)}
+}
+
+func firstToken(node Node) token.Token {
+	switch node := node.(type) {
+	case *Program:
+		if len(node.Statements) > 0 {
+			return firstToken(node.Statements[0])
+		}
+		return token.Token{}
+	case *ExpressionStatement:
+		if node.Expression != nil {
+			return firstToken(node.Expression)
+		}
+	case *InfixExpression:
+		if node.Left != nil {
+			return firstToken(node.Left)
+		}
+	case *CallExpression:
+		if node.Function != nil {
+			return firstToken(node.Function)
+		}
+	case *IndexExpression:
+		if node.Left != nil {
+			return firstToken(node.Left)
+		}
+	case *MemberExpression:
+		if node.Object != nil {
+			return firstToken(node.Object)
+		}
+	case *LiteralPattern:
+		return firstToken(node.Value)
+	case *BindingPattern:
+		return node.Name.Token
+	case *DefaultPattern:
+		return firstToken(node.Pattern)
+	}
+
+	var first token.Token
+	Tokens(node, func(tok *token.Token) {
+		if first.Line == 0 {
+			first = *tok
+		}
+	})
+	return first
+}
+
+func lastToken(node Node) token.Token {
+	var last Node
+	switch node := node.(type) {
+	case *Program:
+		if len(node.Statements) > 0 {
+			return lastToken(node.Statements[len(node.Statements)-1])
+		}
+		return token.Token{}
+	case *LetStatement:
+		if node.Value != nil {
+			last = node.Value
+		}
+	case *ReturnStatement:
+		if node.ReturnValue != nil {
+			last = node.ReturnValue
+		}
+	case *ExpressionStatement:
+		if node.Expression != nil {
+			last = node.Expression
+		}
+	case *BlockStatement:
+		if node.End.Line != 0 {
+			return node.End
+		}
+		if len(node.Statements) > 0 {
+			last = node.Statements[len(node.Statements)-1]
+		}
+	case *TryStatement:
+		switch {
+		case node.Finally != nil:
+			last = node.Finally
+		case node.Catch != nil:
+			last = node.Catch
+		case node.Block != nil:
+			last = node.Block
+		}
+	case *ThrowStatement:
+		if node.Value != nil {
+			last = node.Value
+		}
+	case *ImportStatement:
+		if node.Path != nil {
+			last = node.Path
+		}
+	case *ExportStatement:
+		if node.Statement != nil {
+			last = node.Statement
+		}
+	case *ExternStatement:
+		if node.Module != nil {
+			last = node.Module
+		} else if len(node.Names) > 0 {
+			name := node.Names[len(node.Names)-1]
+			last = name.Name
+			if name.JavaScript != nil {
+				last = name.JavaScript
+			}
+		}
+	case *PrefixExpression:
+		if node.Right != nil {
+			last = node.Right
+		}
+	case *InfixExpression:
+		if node.Right != nil {
+			last = node.Right
+		}
+	case *IfExpression:
+		switch {
+		case node.Alternative != nil:
+			last = node.Alternative
+		case node.Consequence != nil:
+			last = node.Consequence
+		}
+	case *FunctionLiteral:
+		if node.Body != nil {
+			last = node.Body
+		}
+	case *CallExpression:
+		return node.End
+	case *ArrayLiteral:
+		return node.End
+	case *HashLiteral:
+		return node.End
+	case *IndexExpression:
+		return node.End
+	case *MemberExpression:
+		if node.Property != nil {
+			return node.Property.Token
+		}
+	case *MatchExpression:
+		if node.End.Line != 0 {
+			return node.End
+		}
+		if len(node.Arms) > 0 && node.Arms[len(node.Arms)-1].Body != nil {
+			last = node.Arms[len(node.Arms)-1].Body
+		}
+	case *LiteralPattern:
+		last = node.Value
+	case *DefaultPattern:
+		last = node.Default
+	case *ArrayPattern:
+		return node.End
+	case *HashPattern:
+		return node.End
+	case *NamedType:
+		if node.End.Line != 0 {
+			return node.End
+		}
+		return node.Token
+	case *FunctionType:
+		if node.Result != nil {
+			last = node.Result
+		}
+	case *BadStatement:
+		return node.End
+	}
+
+	if last != nil {
+		return lastToken(last)
+	}
+	return firstToken(node)
+}
+
+// tokenEnd returns the position just past tok. String literals lose
+// their quotes when lexed and may span lines.
+func tokenEnd(tok token.Token) Position {
+	if tok.Type != token.STRING {
+		return Position{Line: tok.Line, Column: tok.Column + utf8.RuneCountInString(tok.Literal)}
+	}
+
+	if newline := strings.LastIndexByte(tok.Literal, '\n'); newline >= 0 {
+		return Position{
+			Line:   tok.Line + strings.Count(tok.Literal, "\n"),
+			Column: utf8.RuneCountInString(tok.Literal[newline+1:]) + 2,
+		}
+	}
+	return Position{Line: tok.Line, Column: tok.Column + utf8.RuneCountInString(tok.Literal) + 2}
+}
@@ -0,0 +1,278 @@
+package ast
+
+import "github.com/asanoviskhak/alipp/src/token"
+
+// Inspect traverses the tree rooted at node in depth-first order. It calls
+// visit for every node; when visit returns false the children of that node
+// are skipped. Nil children are never visited.
+func Inspect(node Node, visit func(Node) bool) {
+	if node == nil || !visit(node) {
+		return
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		for _, statement := range node.Statements {
+			Inspect(statement, visit)
+		}
+	case *LetStatement:
+		if node.Name != nil {
+			Inspect(node.Name, visit)
+		}
+		if node.Pattern != nil {
+			Inspect(node.Pattern, visit)
+		}
+		if node.Type != nil {
+			Inspect(node.Type, visit)
+		}
+		if node.Value != nil {
+			Inspect(node.Value, visit)
+		}
+	case *ReturnStatement:
+		if node.ReturnValue != nil {
+			Inspect(node.ReturnValue, visit)
+		}
+	case *ExpressionStatement:
+		if node.Expression != nil {
+			Inspect(node.Expression, visit)
+		}
+	case *TryStatement:
+		if node.Block != nil {
+			Inspect(node.Block, visit)
+		}
+		if node.Parameter != nil {
+			Inspect(node.Parameter, visit)
+		}
+		if node.Catch != nil {
+			Inspect(node.Catch, visit)
+		}
+		if node.Finally != nil {
+			Inspect(node.Finally, visit)
+		}
+	case *ThrowStatement:
+		if node.Value != nil {
+			Inspect(node.Value, visit)
+		}
+	case *ImportStatement:
+		for _, name := range node.Names {
+			Inspect(name, visit)
+		}
+		if node.Path != nil {
+			Inspect(node.Path, visit)
+		}
+	case *ExportStatement:
+		if node.Statement != nil {
+			Inspect(node.Statement, visit)
+		}
+	case *ExternStatement:
+		for _, name := range node.Names {
+			Inspect(name.Name, visit)
+			if name.JavaScript != nil {
+				Inspect(name.JavaScript, visit)
+			}
+		}
+		if node.Module != nil {
+			Inspect(node.Module, visit)
+		}
+	case *BlockStatement:
+		for _, statement := range node.Statements {
+			Inspect(statement, visit)
+		}
+	case *PrefixExpression:
+		if node.Right != nil {
+			Inspect(node.Right, visit)
+		}
+	case *InfixExpression:
+		if node.Left != nil {
+			Inspect(node.Left, visit)
+		}
+		if node.Right != nil {
+			Inspect(node.Right, visit)
+		}
+	case *IfExpression:
+		if node.Condition != nil {
+			Inspect(node.Condition, visit)
+		}
+		if node.Consequence != nil {
+			Inspect(node.Consequence, visit)
+		}
+		if node.Alternative != nil {
+			Inspect(node.Alternative, visit)
+		}
+	case *FunctionLiteral:
+		for i, parameter := range node.Parameters {
+			Inspect(parameter, visit)
+			if i < len(node.ParameterTypes) && node.ParameterTypes[i] != nil {
+				Inspect(node.ParameterTypes[i], visit)
+			}
+		}
+		if node.ReturnType != nil {
+			Inspect(node.ReturnType, visit)
+		}
+		if node.Body != nil {
+			Inspect(node.Body, visit)
+		}
+	case *CallExpression:
+		if node.Function != nil {
+			Inspect(node.Function, visit)
+		}
+		for _, argument := range node.Arguments {
+			Inspect(argument, visit)
+		}
+	case *ArrayLiteral:
+		for _, element := range node.Elements {
+			Inspect(element, visit)
+		}
+	case *HashLiteral:
+		for i, key := range node.Keys {
+			Inspect(key, visit)
+			Inspect(node.Values[i], visit)
+		}
+	case *IndexExpression:
+		if node.Left != nil {
+			Inspect(node.Left, visit)
+		}
+		if node.Index != nil {
+			Inspect(node.Index, visit)
+		}
+	case *MemberExpression:
+		if node.Object != nil {
+			Inspect(node.Object, visit)
+		}
+		if node.Property != nil {
+			Inspect(node.Property, visit)
+		}
+	case *MatchExpression:
+		if node.Subject != nil {
+			Inspect(node.Subject, visit)
+		}
+		for _, arm := range node.Arms {
+			Inspect(arm.Pattern, visit)
+			if arm.Guard != nil {
+				Inspect(arm.Guard, visit)
+			}
+			if arm.Body != nil {
+				Inspect(arm.Body, visit)
+			}
+		}
+	case *LiteralPattern:
+		Inspect(node.Value, visit)
+	case *BindingPattern:
+		Inspect(node.Name, visit)
+	case *ArrayPattern:
+		for _, element := range node.Elements {
+			Inspect(element, visit)
+		}
+		if node.Rest != nil {
+			Inspect(node.Rest, visit)
+		}
+	case *DefaultPattern:
+		Inspect(node.Pattern, visit)
+		Inspect(node.Default, visit)
+	case *HashPattern:
+		for i, key := range node.Keys {
+			Inspect(key, visit)
+			Inspect(node.Values[i], visit)
+		}
+	case *NamedType:
+		for _, argument := range node.Arguments {
+			Inspect(argument, visit)
+		}
+	case *FunctionType:
+		for _, parameter := range node.Parameters {
+			Inspect(parameter, visit)
+		}
+		if node.Result != nil {
+			Inspect(node.Result, visit)
+		}
+	}
+}
+
+// Tokens calls visit with a pointer to every token held by node itself,
+// not by its children. Tools use it to update positions in place.
+func Tokens(node Node, visit func(*token.Token)) {
+	switch node := node.(type) {
+	case *LetStatement:
+		visit(&node.Token)
+	case *ReturnStatement:
+		visit(&node.Token)
+	case *ExpressionStatement:
+		visit(&node.Token)
+	case *TryStatement:
+		visit(&node.Token)
+	case *ThrowStatement:
+		visit(&node.Token)
+	case *ImportStatement:
+		visit(&node.Token)
+	case *ExportStatement:
+		visit(&node.Token)
+	case *ExternStatement:
+		visit(&node.Token)
+	case *BlockStatement:
+		visit(&node.Token)
+		visit(&node.End)
+	case *Identifier:
+		visit(&node.Token)
+	case *IntegerLiteral:
+		visit(&node.Token)
+	case *FloatLiteral:
+		visit(&node.Token)
+	case *StringLiteral:
+		visit(&node.Token)
+	case *Boolean:
+		visit(&node.Token)
+	case *PrefixExpression:
+		visit(&node.Token)
+	case *InfixExpression:
+		visit(&node.Token)
+	case *IfExpression:
+		visit(&node.Token)
+	case *FunctionLiteral:
+		visit(&node.Token)
+	case *CallExpression:
+		visit(&node.Token)
+		visit(&node.End)
+	case *ArrayLiteral:
+		visit(&node.Token)
+		visit(&node.End)
+	case *HashLiteral:
+		visit(&node.Token)
+		visit(&node.End)
+	case *IndexExpression:
+		visit(&node.Token)
+		visit(&node.End)
+	case *MemberExpression:
+		visit(&node.Token)
+	case *MatchExpression:
+		visit(&node.Token)
+		// The arms are not nodes, so their tokens are the expression's.
+		for _, arm := range node.Arms {
+			visit(&arm.IfToken)
+			visit(&arm.Arrow)
+		}
+		visit(&node.End)
+	case *WildcardPattern:
+		visit(&node.Token)
+	case *ArrayPattern:
+		visit(&node.Token)
+		if node.Rest != nil {
+			visit(&node.Ellipsis)
+		}
+		visit(&node.End)
+	case *DefaultPattern:
+		visit(&node.Assign)
+	case *HashPattern:
+		visit(&node.Token)
+		visit(&node.End)
+	case *NamedType:
+		visit(&node.Token)
+		visit(&node.End)
+	case *FunctionType:
+		visit(&node.Token)
+	case *BadExpression:
+		visit(&node.Token)
+	case *BadStatement:
+		visit(&node.Token)
+		visit(&node.End)
+	}
+}
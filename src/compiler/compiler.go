@@ -0,0 +1,1117 @@
+// Package compiler translates alipp programs to JavaScript that behaves
+// like the evaluator: only бош and ката are false in conditions, integer
+// division truncates, and the builtin modules come along as a prelude.
+package compiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/stdlib"
+	"github.com/asanoviskhak/alipp/src/token"
+	"github.com/asanoviskhak/alipp/src/types"
+)
+
+// Error points at a construct that has no JavaScript translation, in
+// the module File when there are several.
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// helpers are the runtime functions the emitted code may call. Their
+// names start with $, which alipp identifiers cannot, so they never
+// collide with the program's names.
+//
+// Integers are numbers while they are safe integers and BigInts beyond
+// that, so arithmetic goes through helpers that switch between the two
+// the way the evaluator switches between int64 and big.Int. A float
+// without a fraction would look like an integer as a number, so it is a
+// Number object instead; see $float.
+var helpers = map[string]string{
+	"$truthy":    "const $truthy = (value) => value !== false && value !== null && value !== undefined;",
+	"$normalize": "const $normalize = (value) => value >= -9007199254740991n && value <= 9007199254740991n ? Number(value) : value;",
+	"$isInteger": "const $isInteger = (value) => typeof value === \"bigint\" || Number.isSafeInteger(value);",
+	// $float makes the result of float arithmetic a Number object when it
+	// would pass for an integer. Number objects compute and print like
+	// numbers, and are the same class in every module of a program.
+	"$float":    "const $float = (value) => Number.isSafeInteger(value) ? new Number(value) : value;",
+	"$isNumber": "const $isNumber = (value) => typeof value === \"number\" || typeof value === \"bigint\" || value instanceof Number;",
+	// $eq compares an integer with a float as floats, as the evaluator
+	// does, and two integers exactly.
+	"$eq": `const $eq = (a, b) => {
+  if (!$isNumber(a) || !$isNumber(b)) return a === b;
+  if ($isInteger(a) && $isInteger(b)) return a === b;
+  return Number(a) === Number(b);
+};`,
+	"$arithmetic": `const $arithmetic = (a, b, numbers, bigints) => {
+  if (!$isInteger(a) || !$isInteger(b)) return $float(numbers(Number(a), Number(b)));
+  if (typeof a === "number" && typeof b === "number") {
+    const result = numbers(a, b);
+    if (Number.isSafeInteger(result)) return result + 0;
+  }
+  return $normalize(bigints(BigInt(a), BigInt(b)));
+};`,
+	"$add": "const $add = (a, b) => typeof a === \"string\" ? a + b : $arithmetic(a, b, (x, y) => x + y, (x, y) => x + y);",
+	"$sub": "const $sub = (a, b) => $arithmetic(a, b, (x, y) => x - y, (x, y) => x - y);",
+	"$mul": "const $mul = (a, b) => $arithmetic(a, b, (x, y) => x * y, (x, y) => x * y);",
+	"$div": `const $div = (a, b) => {
+  if (b == 0) throw new Error("нөлгө бөлүүгө болбойт");
+  if (!$isInteger(a) || !$isInteger(b)) return $float(Number(a) / Number(b));
+  if (typeof a === "number" && typeof b === "number" && Math.abs(a) <= 2 ** 31) return Math.trunc(a / b) + 0;
+  return $normalize(BigInt(a) / BigInt(b));
+};`,
+	"$typeName": `const $typeName = (value) => {
+  if (value === null || value === undefined) return "БОШ";
+  if (typeof value === "boolean") return "ЛОГИКАЛЫК";
+  if (typeof value === "string") return "САП";
+  if ($isInteger(value)) return "БҮТҮН_САН";
+  if (typeof value === "number" || value instanceof Number) return "БӨЛЧӨК_САН";
+  if (Array.isArray(value)) return "ТИЗМЕ";
+  if (value instanceof Map) return "СӨЗДҮК";
+  return typeof value === "function" ? "ФУНКЦИЯ" : "МОДУЛЬ";
+};`,
+	"$key": `const $key = (key) => {
+  if (typeof key === "string" || typeof key === "boolean" || $isInteger(key)) return key;
+  throw new Error(` + "`${$typeName(key)} сөздүктүн ачкычы боло албайт`" + `);
+};`,
+	"$elements": `const $elements = (value, required, count, rest) => {
+  if (!Array.isArray(value)) throw new Error(` + "`тизме күтүлгөн, ${$typeName(value)} берилди`" + `);
+  const given = value.length;
+  if (rest && given < required) throw new Error(` + "`тизмеде жок дегенде ${required} элемент күтүлгөн, ${given} берилди`" + `);
+  if (!rest && required === count && given !== required) throw new Error(` + "`тизмеде ${required} элемент күтүлгөн, ${given} берилди`" + `);
+  if (!rest && (given < required || given > count)) throw new Error(` + "`тизмеде ${required}-${count} элемент күтүлгөн, ${given} берилди`" + `);
+  return value;
+};`,
+	"$fields": `const $fields = (value, required, keys) => {
+  if (!(value instanceof Map)) throw new Error(` + "`сөздүк күтүлгөн, ${$typeName(value)} берилди`" + `);
+  for (const key of required) if (!value.has(key)) throw new Error(` + "`сөздүктө ${$inspect(key)} ачкычы жок`" + `);
+  return keys.map((key) => value.get(key));
+};`,
+	"$index": "const $index = (value, key) => (value instanceof Map ? value.get($key(key)) : value[key]) ?? null;",
+	"$inspect": `const $inspect = (value) => {
+  if (value === null || value === undefined) return "бош";
+  if (value === true) return "туура";
+  if (value === false) return "ката";
+  if (Array.isArray(value)) return "[" + value.map($inspect).join(", ") + "]";
+  if (value instanceof Map) return "{" + Array.from(value, ([key, element]) => $inspect(key) + ": " + $inspect(element)).join(", ") + "}";
+  if (typeof value === "function") return "функция";
+  return String(value);
+};`,
+	"$thrown":  "const $thrown = (value) => Object.assign(new Error(typeof value === \"string\" ? value : $inspect(value)), { value });",
+	"$caught":  "const $caught = (error) => !(error instanceof Error) ? error : \"value\" in error ? error.value : error.message;",
+	"$noMatch": "const $noMatch = (value) => { throw new Error(\"салыштыр: эч бир үлгү дал келген жок: \" + $inspect(value)); };",
+	"$neg":     "const $neg = (a) => typeof a === \"bigint\" ? $normalize(-a) : Number.isSafeInteger(a) ? -a + 0 : $float(-a);",
+}
+
+// requires lists the helpers each helper calls.
+var requires = map[string][]string{
+	"$arithmetic": {"$isInteger", "$normalize", "$float"},
+	"$add":        {"$arithmetic"},
+	"$sub":        {"$arithmetic"},
+	"$mul":        {"$arithmetic"},
+	"$div":        {"$isInteger", "$normalize", "$float"},
+	"$neg":        {"$normalize", "$float"},
+	"$eq":         {"$isNumber", "$isInteger"},
+	"$typeName":   {"$isInteger"},
+	"$key":        {"$isInteger", "$typeName"},
+	"$index":      {"$key"},
+	"$thrown":     {"$inspect"},
+	"$noMatch":    {"$inspect"},
+	"$elements":   {"$typeName"},
+	"$fields":     {"$inspect", "$typeName"},
+}
+
+// maxSafeInteger is the largest integer a JavaScript number holds
+// exactly.
+const maxSafeInteger = 1<<53 - 1
+
+// reserved are the JavaScript words a program may not declare. alipp
+// names spelled like one get a $ appended.
+var reserved = map[string]bool{}
+
+func init() {
+	words := "arguments await break case catch class const continue debugger default delete do else enum eval export extends false finally for function if implements import in instanceof interface let new null package private protected public require return static super switch this throw true try typeof undefined var void while with yield NaN Infinity"
+	for _, word := range strings.Fields(words) {
+		reserved[word] = true
+	}
+}
+
+// mode says what happens to the value of the last statement of a block.
+type mode int
+
+const (
+	discard mode = iota
+	// tail returns the value from the enclosing function.
+	tail
+	// assign stores the value in a target variable.
+	assign
+)
+
+// Options configure the JavaScript a program compiles to.
+type Options struct {
+	// Capabilities are the directories the файл module of the compiled
+	// program may read and write.
+	Capabilities stdlib.Capabilities
+	// ImportPath returns what the compiled program imports for an import
+	// path of the program. By default the extension becomes .mjs.
+	ImportPath func(path string) string
+	// SourceMap makes a source map for each script, which the script
+	// links to as its own name with .map added. InlineSourceMap puts the
+	// map in the script itself instead.
+	SourceMap       bool
+	InlineSourceMap bool
+	// OutputDirectory is where the scripts are written. Source maps name
+	// the source files relative to the scripts in it.
+	OutputDirectory string
+	// Declarations makes TypeScript declarations of what each script
+	// exports, to be written next to it as DeclarationPath names.
+	Declarations bool
+}
+
+type compiler struct {
+	options Options
+	out     *bytes.Buffer
+	indent  int
+	errors  []Error
+
+	helpers  map[string]bool
+	builtins map[string]bool
+	modules  map[string]bool
+	// esModule is set for a program that imports or exports, which
+	// becomes an ES module. exports lists the names it exports.
+	esModule bool
+	exports  []string
+	// externs holds the JavaScript each extern name of the program stands
+	// for, written as it is.
+	externs map[string]string
+	// scopes holds the names declared in each enclosing function, which
+	// hide the builtin modules of the same name.
+	scopes []map[string]bool
+	// functions counts the functions around the code being compiled, and
+	// is 0 at the top level, where JavaScript has no return.
+	functions int
+	// temporaries counts the variables made up for the parts of values
+	// that destructuring takes apart further, named $1, $2 and so on.
+	temporaries int
+
+	// marks are the places in the source that the markers in the output
+	// stand for. statementMark is the marker of the statement whose first
+	// line is next.
+	marks         []mark
+	statementMark string
+}
+
+// Compile returns the JavaScript for program, which must be free of
+// syntax errors. A program that imports or exports becomes an ES module.
+func Compile(program *ast.Program, options Options) (string, []Error) {
+	script, errors := CompileModule(&loader.Module{Program: program}, "", options)
+
+	return script.JavaScript, errors
+}
+
+// Script is the JavaScript compiled from one module, to be written to
+// the file Name, and the source map to be written next to it with .map
+// added to its name. Declarations are its TypeScript declarations.
+type Script struct {
+	Name         string
+	JavaScript   string
+	SourceMap    string
+	Declarations string
+}
+
+// CompileModule compiles a module that imports nothing, as Compile
+// does, into a script to be written to the file name. Its source is
+// what source maps map the script back to.
+func CompileModule(module *loader.Module, name string, options Options) (Script, []Error) {
+	compiler := newCompiler()
+	compiler.options = options
+	for _, statement := range module.Program.Statements {
+		switch statement := statement.(type) {
+		case *ast.ImportStatement, *ast.ExportStatement:
+			compiler.esModule = true
+		case *ast.ExternStatement:
+			compiler.esModule = compiler.esModule || statement.Module != nil
+		}
+	}
+	compiler.program(module.Program)
+
+	script := Script{Name: name}
+	script.JavaScript, script.SourceMap = compiler.sourceMap(compiler.prelude()+compiler.out.String(), module, name)
+	if options.Declarations {
+		script.Declarations = compiler.typeScript(module, types.Exports([]*loader.Module{module})[module])
+	}
+
+	return script, compiler.errors
+}
+
+// CompileModules compiles modules, as the loader returns them, to ES
+// modules that import each other. Each script is named by the path of
+// its module relative to the directory that holds them all, with the
+// extension .mjs.
+func CompileModules(modules []*loader.Module, options Options) ([]Script, []Error) {
+	root := filepath.Dir(modules[0].Path)
+	for _, module := range modules[1:] {
+		for !strings.HasPrefix(module.Path, root+string(filepath.Separator)) && filepath.Dir(root) != root {
+			root = filepath.Dir(root)
+		}
+	}
+
+	outputs := map[*loader.Module]string{}
+	for _, module := range modules {
+		relative, _ := filepath.Rel(root, module.Path)
+		outputs[module] = filepath.ToSlash(strings.TrimSuffix(relative, loader.Extension) + ".mjs")
+	}
+
+	var schemes map[*loader.Module]map[string]*types.Scheme
+	if options.Declarations {
+		schemes = types.Exports(modules)
+	}
+
+	scripts := []Script{}
+	var errors []Error
+	for _, module := range modules {
+		compiler := newCompiler()
+		compiler.options = options
+		compiler.options.ImportPath = func(path string) string {
+			return importPath(outputs[module], outputs[module.Imports[path]])
+		}
+		compiler.esModule = true
+		compiler.program(module.Program)
+
+		for _, compileError := range compiler.errors {
+			compileError.File = module.Name
+			errors = append(errors, compileError)
+		}
+		script := Script{Name: outputs[module]}
+		script.JavaScript, script.SourceMap = compiler.sourceMap(compiler.prelude()+compiler.out.String(), module, outputs[module])
+		if options.Declarations {
+			script.Declarations = compiler.typeScript(module, schemes[module])
+		}
+		scripts = append(scripts, script)
+	}
+
+	return scripts, errors
+}
+
+// importPath returns the specifier the file from imports the file to
+// with, both relative to the same directory.
+func importPath(from string, to string) string {
+	relative := path.Join(strings.Repeat("../", strings.Count(from, "/")), to)
+	if !strings.HasPrefix(relative, "../") {
+		relative = "./" + relative
+	}
+
+	return relative
+}
+
+func newCompiler() *compiler {
+	return &compiler{
+		out:      &bytes.Buffer{},
+		helpers:  map[string]bool{},
+		builtins: map[string]bool{},
+		modules:  map[string]bool{},
+		externs:  map[string]string{},
+	}
+}
+
+func (compiler *compiler) program(program *ast.Program) {
+	compiler.scopes = append(compiler.scopes, declarations(program.Statements, nil))
+	for _, statement := range program.Statements {
+		compiler.statement(statement, discard, "")
+	}
+
+	if len(compiler.exports) > 0 {
+		names := make([]string, len(compiler.exports))
+		for i, name := range compiler.exports {
+			names[i] = compiler.name(name)
+		}
+		compiler.line("export { %s };", strings.Join(names, ", "))
+	}
+}
+
+// prelude defines the helpers, builtin functions and builtin modules
+// the program uses.
+func (compiler *compiler) prelude() string {
+	var out bytes.Buffer
+	if compiler.esModule && (len(compiler.builtins) > 0 || len(compiler.modules) > 0) {
+		// The builtins read files through require, which ES modules lack.
+		out.WriteString("const require = typeof process === \"undefined\" ? undefined : (await import(\"node:module\")).createRequire(import.meta.url);\n")
+	}
+	for _, name := range sortedKeys(compiler.helpers) {
+		out.WriteString(helpers[name] + "\n")
+	}
+	if len(compiler.builtins) > 0 {
+		bindings := []string{}
+		for _, name := range sortedKeys(compiler.builtins) {
+			bindings = append(bindings, name+": $"+name)
+		}
+		fmt.Fprintf(&out, "const { %s } = %s;\n", strings.Join(bindings, ", "), strings.TrimSpace(stdlib.BuiltinsJavaScript()))
+	}
+	for _, name := range sortedKeys(compiler.modules) {
+		fmt.Fprintf(&out, "const $%s = %s;\n", name, stdlib.JavaScript(name, compiler.options.Capabilities))
+	}
+	if out.Len() > 0 {
+		out.WriteString("\n")
+	}
+
+	return out.String()
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// declarations collects the names a function body binds with сакта,
+// including in nested blocks but not in nested functions, together with
+// the names its parameters bind. JavaScript hoists them the same way
+// var does.
+func declarations(statements []ast.Statement, parameters []ast.Pattern) map[string]bool {
+	names := map[string]bool{}
+	for _, parameter := range parameters {
+		for _, name := range ast.Bindings(parameter) {
+			names[name.Value] = true
+		}
+	}
+
+	for _, statement := range statements {
+		ast.Inspect(statement, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.LetStatement:
+				if node.Pattern != nil {
+					for _, name := range ast.Bindings(node.Pattern) {
+						names[name.Value] = true
+					}
+				} else {
+					names[node.Name.Value] = true
+				}
+			case *ast.ImportStatement:
+				for _, name := range node.Names {
+					names[name.Value] = true
+				}
+			case *ast.ExternStatement:
+				for _, extern := range node.Names {
+					names[extern.Name.Value] = true
+				}
+			case *ast.TryStatement:
+				if node.Parameter != nil {
+					names[node.Parameter.Value] = true
+				}
+			case *ast.FunctionLiteral:
+				return false
+			}
+			return true
+		})
+	}
+
+	return names
+}
+
+// use adds a helper and the ones it calls to the prelude, and returns
+// its name.
+func (compiler *compiler) use(helper string) string {
+	compiler.helpers[helper] = true
+	for _, required := range requires[helper] {
+		compiler.use(required)
+	}
+
+	return helper
+}
+
+func (compiler *compiler) addError(tok token.Token, message string) {
+	compiler.errors = append(compiler.errors, Error{Line: tok.Line, Column: tok.Column, Message: message})
+}
+
+func (compiler *compiler) line(format string, a ...interface{}) {
+	compiler.out.WriteString(strings.Repeat("  ", compiler.indent) + compiler.statementMark)
+	compiler.statementMark = ""
+	fmt.Fprintf(compiler.out, format, a...)
+	compiler.out.WriteString("\n")
+}
+
+// statement emits one statement. When it is the last one of a block in
+// tail or assign mode, its value is returned or stored in target.
+func (compiler *compiler) statement(statement ast.Statement, mode mode, target string) {
+	compiler.statementMark = compiler.mark(statement, "")
+
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		if statement.Pattern != nil {
+			compiler.destructure(statement.Pattern, compiler.expression(statement.Value))
+			compiler.finish(mode, target, "null")
+			return
+		}
+		name := compiler.name(statement.Name.Value)
+		marked := compiler.mark(statement.Name, statement.Name.Value) + name
+		if ifExpression, ok := statement.Value.(*ast.IfExpression); ok && !compiler.isSimple(ifExpression) {
+			compiler.line("var %s;", marked)
+			compiler.ifStatement(ifExpression, assign, name)
+		} else {
+			compiler.line("var %s = %s;", marked, compiler.expression(statement.Value))
+		}
+		compiler.finish(mode, target, "null")
+	case *ast.ReturnStatement:
+		if compiler.functions == 0 {
+			compiler.addError(statement.Token, "кайтар функциянын сыртында колдоого алынбайт")
+			return
+		}
+		compiler.line("return %s;", compiler.expression(statement.ReturnValue))
+	case *ast.ExpressionStatement:
+		if ifExpression, ok := statement.Expression.(*ast.IfExpression); ok {
+			compiler.ifStatement(ifExpression, mode, target)
+			return
+		}
+		if mode == discard {
+			compiler.line("%s;", compiler.expression(statement.Expression))
+			return
+		}
+		compiler.finish(mode, target, compiler.expression(statement.Expression))
+	case *ast.BlockStatement:
+		compiler.block(statement, mode, target)
+	case *ast.TryStatement:
+		compiler.tryStatement(statement)
+		compiler.finish(mode, target, "null")
+	case *ast.ThrowStatement:
+		compiler.line("throw %s(%s);", compiler.use("$thrown"), compiler.expression(statement.Value))
+	case *ast.ImportStatement:
+		names := make([]string, len(statement.Names))
+		for i, name := range statement.Names {
+			names[i] = compiler.name(name.Value)
+		}
+		compiler.line("import { %s } from %s;", strings.Join(names, ", "), quote(compiler.importPath(statement.Path.Value)))
+	case *ast.ExternStatement:
+		compiler.externStatement(statement)
+	case *ast.ExportStatement:
+		compiler.statement(statement.Statement, mode, target)
+		names := []*ast.Identifier{statement.Statement.Name}
+		if statement.Statement.Pattern != nil {
+			names = ast.Bindings(statement.Statement.Pattern)
+		}
+		for _, name := range names {
+			compiler.export(name.Value)
+		}
+	}
+}
+
+func (compiler *compiler) export(name string) {
+	for _, exported := range compiler.exports {
+		if exported == name {
+			return
+		}
+	}
+	compiler.exports = append(compiler.exports, name)
+}
+
+// destructure declares the names of pattern, the list or hash pattern of
+// a сакта or of a parameter, with a JavaScript destructuring of value.
+// $elements and $fields check that value has the shape of the pattern
+// first, and $fields looks the keys up in the Map. A part that is taken apart
+// further goes to a temporary, destructured in turn after it.
+func (compiler *compiler) destructure(pattern ast.Pattern, value string) {
+	type part struct {
+		pattern   ast.Pattern
+		temporary string
+	}
+	var parts []part
+	// target returns what an element of the pattern is assigned to.
+	var target func(element ast.Pattern) string
+	target = func(element ast.Pattern) string {
+		switch element := element.(type) {
+		case *ast.BindingPattern:
+			return compiler.mark(element.Name, element.Name.Value) + compiler.name(element.Name.Value)
+		case *ast.DefaultPattern:
+			inner := target(element.Pattern)
+			if inner == "" {
+				// A default is evaluated even when it binds nothing.
+				inner = compiler.temporary()
+			}
+			return inner + " = " + compiler.expression(element.Default)
+		case *ast.ArrayPattern, *ast.HashPattern:
+			temporary := compiler.temporary()
+			parts = append(parts, part{element, temporary})
+			return temporary
+		}
+		return ""
+	}
+
+	switch pattern := pattern.(type) {
+	case *ast.ArrayPattern:
+		required := 0
+		targets := make([]string, len(pattern.Elements))
+		for i, element := range pattern.Elements {
+			if _, ok := element.(*ast.DefaultPattern); !ok {
+				required = i + 1
+			}
+			targets[i] = target(element)
+		}
+		if pattern.Rest != nil {
+			targets = append(targets, "..."+compiler.mark(pattern.Rest, pattern.Rest.Value)+compiler.name(pattern.Rest.Value))
+		}
+		for len(targets) > 0 && targets[len(targets)-1] == "" {
+			targets = targets[:len(targets)-1]
+		}
+		compiler.line("var [%s] = %s(%s, %d, %d, %t);", strings.Join(targets, ", "), compiler.use("$elements"), value, required, len(pattern.Elements), pattern.Rest != nil)
+	case *ast.HashPattern:
+		required := []string{}
+		keys := make([]string, len(pattern.Keys))
+		targets := make([]string, len(pattern.Keys))
+		for i, key := range pattern.Keys {
+			keys[i] = compiler.hashKey(key)
+			if _, ok := pattern.Values[i].(*ast.DefaultPattern); !ok {
+				required = append(required, keys[i])
+			}
+			targets[i] = target(pattern.Values[i])
+		}
+		for len(targets) > 0 && targets[len(targets)-1] == "" {
+			targets = targets[:len(targets)-1]
+		}
+		compiler.line("var [%s] = %s(%s, [%s], [%s]);", strings.Join(targets, ", "), compiler.use("$fields"), value, strings.Join(required, ", "), strings.Join(keys, ", "))
+	}
+
+	for _, part := range parts {
+		compiler.destructure(part.pattern, part.temporary)
+	}
+}
+
+func (compiler *compiler) temporary() string {
+	compiler.temporaries++
+	return "$" + strconv.Itoa(compiler.temporaries)
+}
+
+// externStatement imports the exports of a JavaScript module, and only
+// records what globals stand for. Neither name is changed the way names
+// that are JavaScript words are, since JavaScript defines them.
+func (compiler *compiler) externStatement(statement *ast.ExternStatement) {
+	if statement.Module == nil {
+		for _, extern := range statement.Names {
+			compiler.externs[extern.Name.Value] = extern.Name.Value
+			if extern.JavaScript != nil {
+				compiler.externs[extern.Name.Value] = extern.JavaScript.Value
+			}
+		}
+		compiler.statementMark = ""
+		return
+	}
+
+	names := make([]string, len(statement.Names))
+	for i, extern := range statement.Names {
+		compiler.externs[extern.Name.Value] = extern.Name.Value
+		names[i] = extern.Name.Value
+		if extern.JavaScript != nil && extern.JavaScript.Value != extern.Name.Value {
+			names[i] = extern.JavaScript.Value + " as " + extern.Name.Value
+		}
+	}
+	compiler.line("import { %s } from %s;", strings.Join(names, ", "), quote(statement.Module.Value))
+}
+
+// importPath returns what the program imports for path.
+func (compiler *compiler) importPath(path string) string {
+	if compiler.options.ImportPath != nil {
+		return compiler.options.ImportPath(path)
+	}
+
+	return strings.TrimSuffix(path, loader.Extension) + ".mjs"
+}
+
+// tryStatement emits a native try statement. The caught error becomes
+// the value alipp binds: the thrown value, or the message of a runtime
+// error. It is declared with var, as сакта is.
+func (compiler *compiler) tryStatement(statement *ast.TryStatement) {
+	compiler.line("try {")
+	compiler.indent++
+	compiler.block(statement.Block, discard, "")
+	compiler.indent--
+
+	if statement.Catch != nil {
+		if statement.Parameter != nil {
+			compiler.line("} catch ($error) {")
+			compiler.indent++
+			compiler.line("var %s = %s($error);", compiler.name(statement.Parameter.Value), compiler.use("$caught"))
+		} else {
+			compiler.line("} catch {")
+			compiler.indent++
+		}
+		compiler.block(statement.Catch, discard, "")
+		compiler.indent--
+	}
+
+	if statement.Finally != nil {
+		compiler.line("} finally {")
+		compiler.indent++
+		compiler.block(statement.Finally, discard, "")
+		compiler.indent--
+	}
+	compiler.line("}")
+}
+
+// finish returns or stores value.
+func (compiler *compiler) finish(mode mode, target string, value string) {
+	switch mode {
+	case tail:
+		compiler.line("return %s;", value)
+	case assign:
+		compiler.line("%s = %s;", target, value)
+	}
+}
+
+func (compiler *compiler) block(block *ast.BlockStatement, mode mode, target string) {
+	if block == nil || len(block.Statements) == 0 {
+		compiler.finish(mode, target, "null")
+		return
+	}
+
+	last := len(block.Statements) - 1
+	for _, statement := range block.Statements[:last] {
+		compiler.statement(statement, discard, "")
+	}
+	compiler.statement(block.Statements[last], mode, target)
+}
+
+func (compiler *compiler) ifStatement(ifExpression *ast.IfExpression, mode mode, target string) {
+	compiler.line("if (%s) {", compiler.condition(ifExpression.Condition))
+	compiler.indent++
+	compiler.block(ifExpression.Consequence, mode, target)
+	compiler.indent--
+
+	if ifExpression.Alternative != nil || mode != discard {
+		compiler.line("} else {")
+		compiler.indent++
+		compiler.block(ifExpression.Alternative, mode, target)
+		compiler.indent--
+	}
+	compiler.line("}")
+}
+
+// isSimple reports whether both branches are a single expression, so the
+// эгер expression can become a conditional operator.
+func (compiler *compiler) isSimple(ifExpression *ast.IfExpression) bool {
+	for _, block := range []*ast.BlockStatement{ifExpression.Consequence, ifExpression.Alternative} {
+		if block == nil || len(block.Statements) == 0 {
+			continue
+		}
+		if len(block.Statements) != 1 {
+			return false
+		}
+		statement, ok := block.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			return false
+		}
+		if inner, ok := statement.Expression.(*ast.IfExpression); ok && !compiler.isSimple(inner) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// expression emits an expression, marked with where it starts in the
+// source.
+func (compiler *compiler) expression(expression ast.Expression) string {
+	name := ""
+	if identifier, ok := expression.(*ast.Identifier); ok {
+		name = identifier.Value
+	}
+
+	return compiler.mark(expression, name) + compiler.translate(expression)
+}
+
+func (compiler *compiler) translate(expression ast.Expression) string {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		if expression.Big != nil {
+			return expression.Big.String() + "n"
+		}
+		if expression.Value > maxSafeInteger {
+			return strconv.FormatInt(expression.Value, 10) + "n"
+		}
+		return strconv.FormatInt(expression.Value, 10)
+	case *ast.FloatLiteral:
+		return floatLiteral(expression.Value)
+	case *ast.StringLiteral:
+		return quote(expression.Value)
+	case *ast.Boolean:
+		return strconv.FormatBool(expression.Value)
+	case *ast.Identifier:
+		return compiler.identifier(expression)
+	case *ast.PrefixExpression:
+		if expression.Operator == "!" {
+			return "!" + compiler.condition(expression.Right)
+		}
+		switch right := expression.Right.(type) {
+		case *ast.IntegerLiteral:
+			// Negating a literal needs no helper, unless it would give -0.
+			if right.Value != 0 || right.Big != nil {
+				return "(-" + compiler.expression(right) + ")"
+			}
+		case *ast.FloatLiteral:
+			return floatLiteral(-right.Value)
+		}
+		return compiler.use("$neg") + "(" + compiler.expression(expression.Right) + ")"
+	case *ast.InfixExpression:
+		left, right := compiler.expression(expression.Left), compiler.expression(expression.Right)
+		switch expression.Operator {
+		case "+":
+			return compiler.use("$add") + "(" + left + ", " + right + ")"
+		case "-":
+			return compiler.use("$sub") + "(" + left + ", " + right + ")"
+		case "*":
+			return compiler.use("$mul") + "(" + left + ", " + right + ")"
+		case "/":
+			return compiler.use("$div") + "(" + left + ", " + right + ")"
+		case "==":
+			return compiler.equal(left, right, expression, "===", "")
+		case "!=":
+			return compiler.equal(left, right, expression, "!==", "!")
+		default:
+			return "(" + left + " " + expression.Operator + " " + right + ")"
+		}
+	case *ast.IfExpression:
+		return compiler.ifExpression(expression)
+	case *ast.MatchExpression:
+		return compiler.matchExpression(expression)
+	case *ast.FunctionLiteral:
+		return compiler.function(expression)
+	case *ast.CallExpression:
+		return compiler.expression(expression.Function) + "(" + compiler.expressions(expression.Arguments) + ")"
+	case *ast.ArrayLiteral:
+		return "[" + compiler.expressions(expression.Elements) + "]"
+	case *ast.HashLiteral:
+		pairs := make([]string, len(expression.Keys))
+		for i, key := range expression.Keys {
+			pairs[i] = "[" + compiler.hashKey(key) + ", " + compiler.expression(expression.Values[i]) + "]"
+		}
+		return "new Map([" + strings.Join(pairs, ", ") + "])"
+	case *ast.IndexExpression:
+		// Hashes are Maps, and reading a missing key or past the end of an
+		// array gives бош, not undefined.
+		return compiler.use("$index") + "(" + compiler.expression(expression.Left) + ", " + compiler.expression(expression.Index) + ")"
+	case *ast.MemberExpression:
+		return compiler.expression(expression.Object) + "." + compiler.mark(expression.Property, expression.Property.Value) + expression.Property.Value
+	default:
+		// Only a program with syntax errors has other expressions.
+		return "undefined"
+	}
+}
+
+func (compiler *compiler) expressions(expressions []ast.Expression) string {
+	compiled := make([]string, len(expressions))
+	for i, expression := range expressions {
+		compiled[i] = compiler.expression(expression)
+	}
+
+	return strings.Join(compiled, ", ")
+}
+
+// hashKey emits a key of a hash literal. Keys that are not literals go
+// through $key, which refuses the values the evaluator cannot hash.
+func (compiler *compiler) hashKey(key ast.Expression) string {
+	switch key.(type) {
+	case *ast.StringLiteral, *ast.IntegerLiteral:
+		return compiler.expression(key)
+	}
+	if isBoolean(key) {
+		return compiler.expression(key)
+	}
+
+	return compiler.use("$key") + "(" + compiler.expression(key) + ")"
+}
+
+// condition emits an expression tested for truth. Comparisons are
+// already booleans; anything else goes through $truthy, because 0 and ""
+// are true in alipp.
+func (compiler *compiler) condition(expression ast.Expression) string {
+	compiled := compiler.expression(expression)
+	if isBoolean(expression) {
+		return compiled
+	}
+
+	return compiler.use("$truthy") + "(" + compiled + ")"
+}
+
+func isBoolean(expression ast.Expression) bool {
+	switch expression := expression.(type) {
+	case *ast.Boolean:
+		return true
+	case *ast.PrefixExpression:
+		return expression.Operator == "!"
+	case *ast.InfixExpression:
+		switch expression.Operator {
+		case "<", ">", "==", "!=":
+			return true
+		}
+	}
+
+	return false
+}
+
+// floatLiteral emits a float, as a Number object when it has no fraction.
+func floatLiteral(value float64) string {
+	literal := strconv.FormatFloat(value, 'g', -1, 64)
+	if value == math.Trunc(value) && math.Abs(value) <= maxSafeInteger {
+		return "new Number(" + literal + ")"
+	}
+
+	return literal
+}
+
+// equal emits the == or != of expression. Strings and booleans are
+// compared with strict, === or !==; anything else may be a number and
+// goes through $eq, negated by not.
+func (compiler *compiler) equal(left string, right string, expression *ast.InfixExpression, strict string, not string) string {
+	if isPlainValue(expression.Left) || isPlainValue(expression.Right) {
+		return "(" + left + " " + strict + " " + right + ")"
+	}
+
+	return not + compiler.use("$eq") + "(" + left + ", " + right + ")"
+}
+
+// isPlainValue reports whether expression is sure not to be a number.
+func isPlainValue(expression ast.Expression) bool {
+	_, isString := expression.(*ast.StringLiteral)
+	return isString || isBoolean(expression)
+}
+
+// ifExpression emits an эгер expression used as a value: a conditional
+// operator when it can be, or else a function that is called at once.
+func (compiler *compiler) ifExpression(ifExpression *ast.IfExpression) string {
+	if compiler.isSimple(ifExpression) {
+		consequence := compiler.branch(ifExpression.Consequence)
+		alternative := compiler.branch(ifExpression.Alternative)
+		return "(" + compiler.condition(ifExpression.Condition) + " ? " + consequence + " : " + alternative + ")"
+	}
+
+	ast.Inspect(ifExpression, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.ReturnStatement:
+			compiler.addError(node.Token, "маани катары колдонулган эгер ичинде кайтар колдоого алынбайт")
+		case *ast.FunctionLiteral:
+			return false
+		}
+		return true
+	})
+
+	return compiler.wrap(func() {
+		compiler.ifStatement(ifExpression, tail, "")
+	})
+}
+
+// matchExpression emits a салыштыр expression as a function that is
+// called at once and tries the arms in turn. The names an arm binds are
+// constants of its block.
+func (compiler *compiler) matchExpression(expression *ast.MatchExpression) string {
+	return compiler.wrap(func() {
+		compiler.line("const $subject = %s;", compiler.expression(expression.Subject))
+		for _, arm := range expression.Arms {
+			var tests, bindings []string
+			compiler.pattern(arm.Pattern, "$subject", &tests, &bindings)
+
+			names := map[string]bool{}
+			for _, name := range ast.Bindings(arm.Pattern) {
+				names[name.Value] = true
+			}
+			compiler.scopes = append(compiler.scopes, names)
+
+			if len(tests) == 0 {
+				tests = []string{"true"}
+			}
+			compiler.line("if (%s) {", strings.Join(tests, " && "))
+			compiler.indent++
+			for _, binding := range bindings {
+				compiler.line("%s", binding)
+			}
+			if arm.Guard != nil {
+				compiler.line("if (%s) return %s;", compiler.condition(arm.Guard), compiler.expression(arm.Body))
+			} else {
+				compiler.line("return %s;", compiler.expression(arm.Body))
+			}
+			compiler.indent--
+			compiler.line("}")
+
+			compiler.scopes = compiler.scopes[:len(compiler.scopes)-1]
+		}
+		compiler.line("return %s($subject);", compiler.use("$noMatch"))
+	})
+}
+
+// pattern adds the tests that the value value must pass to match
+// pattern to tests, and the declarations of the names it binds to
+// bindings.
+func (compiler *compiler) pattern(pattern ast.Pattern, value string, tests *[]string, bindings *[]string) {
+	switch pattern := pattern.(type) {
+	case *ast.LiteralPattern:
+		literal := compiler.expression(pattern.Value)
+		if isPlainValue(pattern.Value) {
+			*tests = append(*tests, value+" === "+literal)
+		} else {
+			*tests = append(*tests, compiler.use("$eq")+"("+value+", "+literal+")")
+		}
+	case *ast.BindingPattern:
+		name := compiler.mark(pattern.Name, pattern.Name.Value) + compiler.name(pattern.Name.Value)
+		*bindings = append(*bindings, "const "+name+" = "+value+";")
+	case *ast.ArrayPattern:
+		length := value + ".length === " + strconv.Itoa(len(pattern.Elements))
+		if pattern.Rest != nil {
+			length = value + ".length >= " + strconv.Itoa(len(pattern.Elements))
+		}
+		*tests = append(*tests, "Array.isArray("+value+")", length)
+		for i, element := range pattern.Elements {
+			compiler.pattern(element, value+"["+strconv.Itoa(i)+"]", tests, bindings)
+		}
+		if pattern.Rest != nil {
+			name := compiler.mark(pattern.Rest, pattern.Rest.Value) + compiler.name(pattern.Rest.Value)
+			*bindings = append(*bindings, "const "+name+" = "+value+".slice("+strconv.Itoa(len(pattern.Elements))+");")
+		}
+	case *ast.HashPattern:
+		*tests = append(*tests, value+" instanceof Map")
+		for i, keyNode := range pattern.Keys {
+			key := compiler.hashKey(keyNode)
+			*tests = append(*tests, value+".has("+key+")")
+			compiler.pattern(pattern.Values[i], value+".get("+key+")", tests, bindings)
+		}
+	}
+}
+
+func (compiler *compiler) branch(block *ast.BlockStatement) string {
+	if block == nil || len(block.Statements) == 0 {
+		return "null"
+	}
+
+	return compiler.expression(block.Statements[0].(*ast.ExpressionStatement).Expression)
+}
+
+func (compiler *compiler) function(function *ast.FunctionLiteral) string {
+	var statements []ast.Statement
+	if function.Body != nil {
+		statements = function.Body.Statements
+	}
+
+	compiler.scopes = append(compiler.scopes, declarations(statements, function.Parameters))
+	compiler.functions++
+	defer func() {
+		compiler.scopes = compiler.scopes[:len(compiler.scopes)-1]
+		compiler.functions--
+	}()
+
+	// A parameter that is a pattern is a temporary, destructured first
+	// thing in the body. A default is the JavaScript default of the
+	// parameter, which applies when the argument is left out.
+	parameters := make([]string, len(function.Parameters))
+	defaults := make([]string, len(function.Parameters))
+	destructured := map[int]ast.Pattern{}
+	for i, parameter := range function.Parameters {
+		if defaultPattern, ok := parameter.(*ast.DefaultPattern); ok {
+			parameter = defaultPattern.Pattern
+			defaults[i] = " = " + compiler.expression(defaultPattern.Default)
+		}
+		if binding, ok := parameter.(*ast.BindingPattern); ok {
+			parameters[i] = compiler.name(binding.Name.Value)
+		} else {
+			parameters[i] = compiler.temporary()
+			destructured[i] = parameter
+		}
+	}
+
+	body := compiler.nested(func() {
+		for i := range function.Parameters {
+			if pattern, ok := destructured[i]; ok {
+				compiler.destructure(pattern, parameters[i])
+			}
+		}
+		compiler.block(function.Body, tail, "")
+	})
+
+	list := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		list[i] = parameter + defaults[i]
+	}
+
+	return "function (" + strings.Join(list, ", ") + ") {\n" + body + strings.Repeat("  ", compiler.indent) + "}"
+}
+
+// wrap emits statements as the body of an arrow function and calls it.
+func (compiler *compiler) wrap(emit func()) string {
+	return "(() => {\n" + compiler.nested(emit) + strings.Repeat("  ", compiler.indent) + "})()"
+}
+
+// nested returns what emit writes one level deeper than the current
+// line, leaving the output written so far untouched.
+func (compiler *compiler) nested(emit func()) string {
+	saved, statementMark := compiler.out, compiler.statementMark
+	compiler.out = &bytes.Buffer{}
+	compiler.indent++
+
+	emit()
+
+	compiler.indent--
+	body := compiler.out.String()
+	compiler.out, compiler.statementMark = saved, statementMark
+
+	return body
+}
+
+// identifier refers to a variable, to the JavaScript an extern name
+// stands for, or to a builtin function or module when no enclosing
+// function declares the name.
+func (compiler *compiler) identifier(identifier *ast.Identifier) string {
+	for i := len(compiler.scopes) - 1; i >= 0; i-- {
+		if compiler.scopes[i][identifier.Value] {
+			if extern, ok := compiler.externs[identifier.Value]; ok && i == 0 {
+				return extern
+			}
+			return compiler.name(identifier.Value)
+		}
+	}
+
+	if stdlib.IsBuiltin(identifier.Value) {
+		compiler.builtins[identifier.Value] = true
+		return "$" + identifier.Value
+	}
+
+	if _, ok := stdlib.Lookup(identifier.Value); ok {
+		compiler.modules[identifier.Value] = true
+		return "$" + identifier.Value
+	}
+
+	return compiler.name(identifier.Value)
+}
+
+func (compiler *compiler) name(name string) string {
+	return javaScriptName(name)
+}
+
+// javaScriptName returns name as the compiled program declares it.
+func javaScriptName(name string) string {
+	if reserved[name] {
+		return name + "$"
+	}
+
+	return name
+}
+
+// quote writes a JavaScript string literal, which JSON strings are. The
+// runes that mark places for source maps are escaped.
+func quote(value string) string {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+
+	return strings.NewReplacer(string(markerStart), `\ue000`, string(markerEnd), `\ue001`).Replace(strings.TrimSuffix(out.String(), "\n"))
+}
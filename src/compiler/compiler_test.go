@@ -0,0 +1,636 @@
+package compiler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/evaluator"
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/object"
+	"github.com/asanoviskhak/alipp/src/parser"
+	"github.com/asanoviskhak/alipp/src/stdlib"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	parserInstance := parser.NewParser(lexer.New(input))
+	program := parserInstance.ParseProgram()
+	if errors := parserInstance.Errors(); len(errors) > 0 {
+		t.Fatalf("parser errors for %q: %v", input, errors)
+	}
+
+	return program
+}
+
+func compile(t *testing.T, input string) string {
+	output, errors := Compile(parse(t, input), Options{})
+	if len(errors) > 0 {
+		t.Fatalf("compile errors for %q: %v", input, errors)
+	}
+
+	return output
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		prelude  []string
+	}{
+		{"сакта x = 1 + 2 * 3;", "var x = $add(1, $mul(2, 3));\n", []string{"$add", "$arithmetic", "$float", "$isInteger", "$mul", "$normalize"}},
+		{"сакта x = 7 / -2; -x;", "var x = $div(7, (-2));\n$neg(x);\n", []string{"$div", "$float", "$isInteger", "$neg", "$normalize"}},
+		{"сакта x = 9007199254740991 < 9007199254740992;", "var x = (9007199254740991 < 9007199254740992n);\n", nil},
+		{"сакта x = 123456789012345678901234567890 == 1.5;", "var x = $eq(123456789012345678901234567890n, 1.5);\n", []string{"$eq", "$isInteger", "$isNumber"}},
+		{"сакта x = [4.0, -2.0, 2.5, 1000000000000000000000.0];", "var x = [new Number(4), new Number(-2), 2.5, 1e+21];\n", nil},
+		{`сакта x = y == "a"; y != ката;`, "var x = (y === \"a\");\n(y !== false);\n", nil},
+		{`сакта сөз = "<Дүйнө>";`, "var сөз = \"<Дүйнө>\";\n", nil},
+		{"сакта new = 1; new == 2;", "var new$ = 1;\n$eq(new$, 2);\n", []string{"$eq", "$isInteger", "$isNumber"}},
+		{"сакта y = эгер (x) { 1 } же { 2 };", "var y = ($truthy(x) ? 1 : 2);\n", []string{"$truthy"}},
+		{
+			"сакта кош = функ(a, b) { a < b };",
+			"var кош = function (a, b) {\n  return (a < b);\n};\n", nil,
+		},
+		{
+			"сакта f = функ(n) { эгер (n < 2) { кайтар 1; } сакта m = n; эгер (m > 1) { m } же { 0 } };",
+			"var f = function (n) {\n  if ((n < 2)) {\n    return 1;\n  }\n  var m = n;\n  if ((m > 1)) {\n    return m;\n  } else {\n    return 0;\n  }\n};\n", nil,
+		},
+		{
+			"сакта z = эгер (1 < 2) { сакта a = 1; a } же { 2 };",
+			"var z;\nif ((1 < 2)) {\n  var a = 1;\n  z = a;\n} else {\n  z = 2;\n}\n", nil,
+		},
+		{"сап.узундук(\"аб\");", "$сап.узундук(\"аб\");\n", nil},
+		{"сакта сап = 1; сап;", "var сап = 1;\nсап;\n", nil},
+		{"көрсөтүү(окуу());", "$көрсөтүү($окуу());\n", nil},
+		{`сакта с = {"а": 1, туура: [2]}; с["а"];`, "var с = new Map([[\"а\", 1], [true, [2]]]);\n$index(с, \"а\");\n", []string{"$index", "$isInteger", "$key", "$typeName"}},
+		{"сакта с = {x: 1};", "var с = new Map([[$key(x), 1]]);\n", []string{"$isInteger", "$key", "$typeName"}},
+		{"сакта жаз = функ(x) { x }; жаз(1);", "var жаз = function (x) {\n  return x;\n};\nжаз(1);\n", nil},
+		{"сакта f: функ(Сан): Сан = функ(x: Сан): Сан { x };", "var f = function (x) {\n  return x;\n};\n", nil},
+		{
+			`импорт { кош, delete } "./математика.alipp"; экспорт сакта үч = кош(1, 2); экспорт сакта new = 1; экспорт сакта үч = 3;`,
+			"import { кош, delete$ } from \"./математика.mjs\";\nvar үч = кош(1, 2);\nvar new$ = 1;\nvar үч = 3;\nexport { үч, new$ };\n", nil,
+		},
+		{
+			"аракет { ыргыт 1; } кармоо (e) { e } акыры { 2 }",
+			"try {\n  throw $thrown(1);\n} catch ($error) {\n  var e = $caught($error);\n  e;\n} finally {\n  2;\n}\n",
+			[]string{"$caught", "$inspect", "$thrown"},
+		},
+		{
+			`тышкы Math; тышкы журнал = "console.log"; тышкы eval; журнал(Math.max(1, 2), eval);`,
+			"console.log(Math.max(1, 2), eval);\n", nil,
+		},
+		{
+			`тышкы { readFileSync, оку = "readFileSync", json = "JSON" } "node:fs"; оку(json); сакта f = функ(оку) { оку };`,
+			"import { readFileSync, readFileSync as оку, JSON as json } from \"node:fs\";\nоку(json);\nvar f = function (оку) {\n  return оку;\n};\n", nil,
+		},
+		{"сакта тизме = [1]; тизме.push(2);", "var тизме = [1];\nтизме.push(2);\n", nil},
+		{
+			`сакта [a, _, ...калган] = x; сакта { аты, жашы = 0, "new": [new, _] } = y;`,
+			"var [a, , ...калган] = $elements(x, 2, 2, true);\n" +
+				"var [аты, жашы = 0, $1] = $fields(y, [\"аты\", \"new\"], [\"аты\", \"жашы\", \"new\"]);\nvar [new$] = $elements($1, 2, 2, false);\n",
+			[]string{"$elements", "$fields", "$inspect", "$isInteger", "$typeName"},
+		},
+		{
+			"сакта f = функ(x, [a, b = 1]) { a };",
+			"var f = function (x, $1) {\n  var [a, b = 1] = $elements($1, 1, 2, false);\n  return a;\n};\n",
+			[]string{"$elements", "$isInteger", "$typeName"},
+		},
+		{
+			"сакта f = функ(x, y = x, [a] = [1]) { a };",
+			"var f = function (x, y = x, $1 = [1]) {\n  var [a] = $elements($1, 1, 1, false);\n  return a;\n};\n",
+			[]string{"$elements", "$isInteger", "$typeName"},
+		},
+		{
+			`сакта x = салыштыр (y) { 0 => "нөл", [сап, _] эгер сап => сап, {"а": а} => а };`,
+			"var x = (() => {\n  const $subject = y;\n  if ($eq($subject, 0)) {\n    return \"нөл\";\n  }\n" +
+				"  if (Array.isArray($subject) && $subject.length === 2) {\n    const сап = $subject[0];\n    if ($truthy(сап)) return сап;\n  }\n" +
+				"  if ($subject instanceof Map && $subject.has(\"а\")) {\n    const а = $subject.get(\"а\");\n    return а;\n  }\n" +
+				"  return $noMatch($subject);\n})();\n",
+			[]string{"$eq", "$inspect", "$isInteger", "$isNumber", "$noMatch", "$truthy"},
+		},
+	}
+
+	for _, test := range tests {
+		compiler := newCompiler()
+		compiler.program(parse(t, test.input))
+
+		if output := compiler.out.String(); output != test.expected {
+			t.Errorf("%s: wrong output.\nwant=%q\ngot= %q", test.input, test.expected, output)
+		}
+		if helpers := sortedKeys(compiler.helpers); strings.Join(helpers, " ") != strings.Join(test.prelude, " ") {
+			t.Errorf("%s: wrong helpers. want=%v, got=%v", test.input, test.prelude, helpers)
+		}
+	}
+}
+
+func TestPrelude(t *testing.T) {
+	output := compile(t, "сап.узундук(\"аб\") + 1;")
+
+	expected := helpers["$add"] + "\n" + helpers["$arithmetic"] + "\n" + helpers["$float"] + "\n" + helpers["$isInteger"] + "\n" + helpers["$normalize"] + "\n" +
+		"const $сап = " + stdlib.JavaScript("сап", stdlib.Capabilities{}) + ";\n\n" +
+		"$add($сап.узундук(\"аб\"), 1);\n"
+	if output != expected {
+		t.Errorf("wrong output.\nwant=%q\ngot= %q", expected, output)
+	}
+}
+
+func TestBuiltinsPrelude(t *testing.T) {
+	output := compile(t, "окуу(); көрсөтүү(1);")
+
+	expected := "const { көрсөтүү: $көрсөтүү, окуу: $окуу } = " + strings.TrimSpace(stdlib.BuiltinsJavaScript()) + ";\n\n" +
+		"$окуу();\n$көрсөтүү(1);\n"
+	if output != expected {
+		t.Errorf("wrong output.\nwant=%q\ngot= %q", expected, output)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		column  int
+	}{
+		{"кайтар 1;", "кайтар функциянын сыртында колдоого алынбайт", 1},
+		{"эгер (туура) { кайтар 1; }", "кайтар функциянын сыртында колдоого алынбайт", 16},
+		{"аракет { кайтар 1; } кармоо { 2 }", "кайтар функциянын сыртында колдоого алынбайт", 10},
+		{"эгер (туура) { сакта f = функ() { 1 }; } же { кайтар 2; }", "кайтар функциянын сыртында колдоого алынбайт", 47},
+		{"сакта f = функ(x) { f(эгер (x) { кайтар 1; 2 } же { 3 }) };", "маани катары колдонулган эгер ичинде кайтар колдоого алынбайт", 34},
+	}
+
+	for _, test := range tests {
+		_, errors := Compile(parse(t, test.input), Options{})
+		if len(errors) != 1 || errors[0].Message != test.message || errors[0].Column != test.column {
+			t.Errorf("%s: want %q at column %d, got=%v", test.input, test.message, test.column, errors)
+		}
+	}
+}
+
+// TestCompiledBehaviour runs each program with the evaluator and, as
+// JavaScript, with node, and compares the results.
+func TestCompiledBehaviour(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+
+	programs := []string{
+		"(5 + 10 * 2 + 15 / 3) * 2 + -10",
+		"-7 / 2",
+		"эгер (0) { 1 } же { 2 }",
+		`эгер ("") { 1 } же { 2 }`,
+		"эгер (ката) { 1 }",
+		"!0",
+		"[1, 2][5]",
+		"сакта факт = функ(n) { эгер (n < 2) { 1 } же { n * факт(n - 1) } }; факт(10)",
+		"сакта кошуучу = функ(x) { функ(y) { x + y } }; кошуучу(2)(3)",
+		"сакта f = функ(x) { эгер (x > 0) { эгер (x > 5) { кайтар \"чоң\"; } \"кичине\" } же { \"терс\" } }; [f(10), f(1), f(-1)]",
+		"сакта g = функ() { сакта a = 1; }; g()",
+		"[эгер (туура) { сакта a = 2; a * 3 }, эгер (ката) { 1 }]",
+		`сап.узундук("өңүт")`,
+		`сап["узундук"]("өңүт")`,
+		`сап.бөл("а,б,в", ",")`,
+		`сап.бөл("өң", "")`,
+		`сап.бириктир(["а", "б"], "-")`,
+		"сап.кырк(\"  салам\t\")",
+		`сап.алмаштыр("ала-була", "а", "о")`,
+		`сап.алмаштыр("өң", "", "-")`,
+		`сап.камтыйбы("Бишкек", "шке")`,
+		`сап.чоң_тамга("өңүр ёлка kıyın iş")`,
+		`сап.кичине_тамга("ӨҢҮР ЁЛКА KIYIN İŞ")`,
+		`[сап.алфавит_боюнча("ң", "о"), сап.алфавит_боюнча("Ала", "ала"), сап.алфавит_боюнча("ала", "ала")]`,
+		`сап.иреттө(["үй", "Ой", "ой", "ноо", "ңаа", "өрүк", "уул", "ёж", "жер", "ар", "zoo", "Ёж", "і"])`,
+		"1 / 0",
+		"9223372036854775807 + 1",
+		"9223372036854775807 + 1 - 1",
+		"-9223372036854775807 - 1",
+		"-9223372036854775807 - 1 - 1",
+		"(-9223372036854775807 - 1) / -1",
+		"(-9223372036854775807 - 1) * -1",
+		"-(-9223372036854775807 - 1)",
+		"3037000499 * 3037000499",
+		"3037000500 * 3037000500",
+		"9007199254740991 + 2",
+		"-9007199254740991 - 2",
+		"123456789012345678901234567890 / 1000000000000",
+		"123456789012345678901234567890 - 123456789012345678901234567889",
+		"-123456789012345678901234567890 / 7",
+		"99999999999999999999 > 9223372036854775807",
+		"[0 * -1, -0, 0 / -5]",
+		"сакта факт = функ(n) { эгер (n < 2) { 1 } же { n * факт(n - 1) } }; факт(25)",
+		"[7.5 / 2, 1 + 0.5, 0.1 + 0.2, 2 < 2.5, 2 == 2.0, -1.5, 1.0 / 3]",
+		"сакта x = 4.0; x / 8",
+		"математика.тамыр(16) / 8",
+		"99999999999999999999 == 99999999999999999999.0",
+		"сакта сегизге = функ(x) { x / 8 }; [сегизге(4.0), сегизге(4), сегизге(2.5 + 1.5), сегизге(-(4.0)), сегизге(2.0 * 2)]",
+		"сакта x = 4.0; [x == 4, x != 4, 4 == x, x == 4.0, 2.0 + 2 == 4, 9007199254740993 == 9007199254740992.0, -0.0 == 0]",
+		"[1.0, -2.0, 3.0 - 1, 2.5 * 2, 5 / 2.5, математика.абсолют(-4.0), 1.0 + 99999999999999999999, -(2.0)]",
+		"сакта x = 4.0; салыштыр (x / 2) { 2 => \"эки\", _ => \"жок\" }",
+		`[json.оку("4.0") / 8, json.оку("[1e2, 2]"), json.жаз([4.0, 2.5, 1]), математика.даража(4.0, 2), математика.даража(2, -1)]`,
+		`[математика.төмөн_тегеректе(4.0), математика.эң_чоң(1, 4.0), {1: 2}[1.0], {"а": 1}[4.0]]`,
+		"[математика.абсолют(-5), математика.абсолют(-2.5), математика.абсолют(-9223372036854775807 - 1)]",
+		"[математика.эң_кичине(3, 1.5, 2), математика.эң_чоң(3, 99999999999999999999, 2), математика.эң_чоң(1)]",
+		"[математика.даража(2, 100), математика.даража(2, 10), математика.даража(2, -1), математика.даража(-3, 3), математика.даража(1, 99999999999999999999)]",
+		"математика.даража(10, 1000000)",
+		"[математика.тамыр(16), математика.тамыр(2)]",
+		"математика.тамыр(-1)",
+		"[математика.төмөн_тегеректе(2.7), математика.жогору_тегеректе(-2.5), математика.төмөн_тегеректе(-2.5), математика.төмөн_тегеректе(5)]",
+		"[математика.төмөн_тегеректе(100000000000000000000.0), математика.жогору_тегеректе(9007199254740993.0)]",
+		"математика.үрөн(42); [математика.кокус(100), математика.кокус(1000000), математика.кокус(), математика.кокус(1)]",
+		"математика.үрөн(-1); математика.кокус(9007199254740992)",
+		"математика.кокус(0)",
+		`{"аты": "Айбек", 1: [1, 2], туура: {}}`,
+		`сакта с = {"а": 1, "б": {"в": 2}}; [с["а"], с["б"]["в"], с["жок"], {1: "бир"}[1], {туура: 1}[1 < 2]]`,
+		`{1: "бир"}[1.5]`,
+		`{"а": 1, "б": 2, "а": 3}`,
+		`{[1]: 2}`,
+		`сакта ачкыч = функ(x) { x }; {ачкыч: 1}`,
+		`[json.жаз({"б": [1, 2.5, эгер (ката) { 1 }], "а": {}}), json.жаз([], {"чегинүү": 2})]`,
+		`json.жаз({"б": [1, {"в": "г"}], "а": 99999999999999999999}, {"чегинүү": 2, "иреттөө": туура})`,
+		`json.жаз({1: 2})`,
+		`json.жаз(json)`,
+		`json.жаз(1, {"чегинүү": -1})`,
+		`json.жаз(1, [])`,
+		`салыштыр (-1) { 0 => "нөл", -1 => "минус бир", _ => "көп" }`,
+		`сакта баш = функ(x) { салыштыр (x) { [h, _] => h, [h] => h, _ => "жок" } }; [баш([1, 2]), баш([[3]]), баш([]), баш("а")]`,
+		`салыштыр ({"аты": "Айбек", 1: [2, 3]}) { {"аты": n, 2: _} => 0, {1: [a, b], "аты": n} => [n, a + b] }`,
+		`салыштыр (5) { n эгер n < 0 => "терс", n эгер n > 0 => салыштыр (n) { 5 => "беш", _ => n } }`,
+		`салыштыр (2.0) { 2 => "эки" }`,
+		`салыштыр ([1, {"а": туура}]) { [1, {"а": ката}] => 1, [x, y] => x }`,
+		`салыштыр (3) { 1 => "бир" }`,
+		`салыштыр ([1, 2, 3]) { [h, ...t] => [h, t] }`,
+		`салыштыр ([]) { [h, ...t] => h, [...t] => t }`,
+		`сакта [a, b, ...калган] = [1, 2, 3, 4]; [a, b, калган]`,
+		`сакта [_, [b, _], ...калган] = [1, [2, 3]]; [b, калган]`,
+		`сакта { аты, жашы = 0 } = {"аты": "Айбек"}; [аты, жашы]`,
+		`сакта {"аты": n, 1: [x], туура: т, -2: м} = {1: [2], "аты": "Ош", туура: 3, -2: 4}; [n, x, т, м]`,
+		`сакта [a = 1, b = a + 1, _ = 3] = []; [a, b]`,
+		`сакта [{ аты = "белгисиз" } = {}] = []; аты`,
+		`сакта f = функ([a, b], { аты }) { a + b + аты }; f([1, 2], {"аты": 3})`,
+		`сакта [a, b] = [1, 2, 3];`,
+		`сакта [a, b = 2] = [];`,
+		`сакта [a, b, ...c] = [1];`,
+		`сакта [a] = {"а": 1};`,
+		`сакта { аты } = [1];`,
+		`сакта { аты } = {"жашы": 1};`,
+		`сакта { 1: a } = {"1": 1};`,
+		`функ([a]) { a }(5)`,
+		`сакта { toString = 1, constructor = 2, hasOwnProperty = 3 } = {}; [toString, constructor, hasOwnProperty]`,
+		`сакта { __proto__ = 3 } = {}; __proto__`,
+		`сакта { __proto__, valueOf } = {"__proto__": [4], "valueOf": 5}; [__proto__, valueOf]`,
+		`сакта f = функ({ toString }) { toString }; f({"toString": 6})`,
+		`сакта { toString } = {};`,
+		`сакта {1: a, "1": b, туура: c, "туура": d, -1: e} = {1: "int", "1": "str", туура: "bool", "туура": "сап", -1: "терс"}; [a, b, c, d, e]`,
+		`сакта {1: a, "1": b = "жок"} = {1: "int"}; [a, b]`,
+		`сакта f = функ(a, b = a + 1, [c] = [3]) { [a, b, c] }; [f(1), f(1, 5), f(1, 2, [4])]`,
+		`сакта f = функ(a, { аты = "белгисиз" } = {}) { [a, аты] }; [f(1), f(2, {"аты": "Ош"})]`,
+	}
+
+	// One node process runs every program, each in a function of its own.
+	// Errors become strings like the evaluator's, BigInts digits and
+	// floats objects, so that 4.0 is not taken for 4.
+	functions := make([]string, len(programs))
+	for i, program := range programs {
+		functions[i] = "функ() { " + program + " }"
+	}
+	script := compile(t, "сакта программалар = ["+strings.Join(functions, ", ")+"];") + `
+const results = программалар.map((program) => {
+  try {
+    return program();
+  } catch (error) {
+    return "КАТА: " + error.message;
+  }
+});
+console.log(JSON.stringify(results, (key, value) => {
+  if (typeof value === "bigint") return value.toString();
+  if (value instanceof Number || (typeof value === "number" && !Number.isSafeInteger(value))) return { "бөлчөк": String(Number(value)) };
+  if (value instanceof Map) return { "сөздүк": Array.from(value) };
+  return value;
+}));
+`
+
+	output, err := exec.Command(node, "-e", script).CombinedOutput()
+	if err != nil {
+		t.Fatalf("node failed: %s\n%s", err, output)
+	}
+	results := []json.RawMessage{}
+	if err := json.Unmarshal(output, &results); err != nil || len(results) != len(programs) {
+		t.Fatalf("unexpected node output: %s", output)
+	}
+
+	for i, program := range programs {
+		expected, err := json.Marshal(toJSON(evaluator.Eval(parse(t, program), object.NewEnvironment())))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(results[i]) != string(expected) {
+			t.Errorf("%s: want=%s, got=%s", program, expected, results[i])
+		}
+	}
+}
+
+// TestCompiledOutput compares what programs print and read when they
+// run with the evaluator and with node.
+func TestCompiledOutput(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+
+	tests := []struct {
+		program string
+		input   string
+	}{
+		{`көрсөтүү("Салам,", "Дүйнө!"); көрсөтүү(); көрсөтүү(1, 2.5, 99999999999999999999, туура, ката, [1, [""]], эгер (ката) { 1 })`, ""},
+		{`жаз("{} + {} = {}", 1, 2, 1 + 2); жаз("{{}} {{ }}"); жаз("{}")`, ""},
+		{`жаз(1)`, ""},
+		{`сакта аты = окуу("Атыңыз: "); жаз("Салам, {}!", аты); көрсөтүү(окуу(), окуу(), окуу())`, "Айбек\r\n\nакыркы"},
+		{`көрсөтүү(окуу())`, ""},
+		{`сакта маалымат = json.оку(окуу()); көрсөтүү(маалымат); көрсөтүү(маалымат["чоң"] + 1, маалымат["тизме"][1]); жаз(json.жаз(маалымат, {"чегинүү": "	"}))`,
+			`{"аты": "Айбек \"Ала-Тоо\"\n", "чоң": 123456789012345678901234567890, "тизме": [1.5, -0, 1e3, null, true], "бош": {}}`},
+		{`json.оку(окуу())`, "{\"а\": [1, 2,]}"},
+		{`json.оку(окуу())`, "\"\\u12\""},
+		{`json.оку(окуу())`, "[1"},
+		{`сакта { toString = 1 } = {}; сакта { __proto__ = 3 } = {}; көрсөтүү(toString, __proto__)`, ""},
+		{`json.оку(окуу())`, "[1,\n -1e400]"},
+		{`сакта x = json.оку(окуу()); көрсөтүү(x, json.жаз(x))`, "[2.0, 1e-400, 9007199254740993.0, 1e21, 2]"},
+		{`json.оку(1)`, ""},
+		{`сакта r = ката_билдирүү(функ() { json.оку("[") }); көрсөтүү(r["билдирүү"], ката_билдирүү(функ() { 1 }), ката_билдирүү(көрсөтүү))`, ""},
+		{`ката_билдирүү("функ")`, ""},
+		{`ката_билдирүү(функ(x) { x })`, ""},
+		{`аракет { көрсөтүү(1); 1 / 0; көрсөтүү(2) } кармоо (e) { көрсөтүү("кармалды:", e) } акыры { көрсөтүү("акыры") }`, ""},
+		{`аракет { аракет { json.оку("[") } кармоо (e) { ыргыт "кайра: " + e; } } кармоо (e) { көрсөтүү(e) }`, ""},
+		{`аракет { ыргыт {"код": 7, "тизме": [1, "x"]}; } кармоо (e) { көрсөтүү(e["код"], e["тизме"]) } аракет { 1 } кармоо { көрсөтүү("жок") }`, ""},
+		{`сакта f = функ() { аракет { кайтар 1; } акыры { көрсөтүү("акыры") } 2 }; сакта g = функ() { аракет { ыргыт 1; } акыры { кайтар 2; } }; көрсөтүү(f(), g())`, ""},
+		{`ыргыт [1, "эки", {"а": туура}]`, ""},
+		{`аракет { 1 } акыры { ыргыт 5; }`, ""},
+	}
+
+	for _, test := range tests {
+		var expected bytes.Buffer
+		result := evaluator.Eval(parse(t, test.program), evaluator.NewEnvironment(strings.NewReader(test.input), &expected, stdlib.Capabilities{}))
+		if err, ok := result.(*object.Error); ok {
+			fmt.Fprintln(&expected, err.Message)
+		}
+
+		script := "try {\n" + compile(t, test.program) + "} catch (error) {\n  console.log(error.message);\n}\n"
+		command := exec.Command(node, "-e", script)
+		command.Stdin = strings.NewReader(test.input)
+		output, err := command.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: node failed: %s\n%s", test.program, err, output)
+		}
+
+		if string(output) != expected.String() {
+			t.Errorf("%s: want=%q, got=%q", test.program, expected.String(), output)
+		}
+	}
+}
+
+// TestCompiledFiles runs programs that use the файл module with the
+// evaluator and with node, each in a directory of its own, and compares
+// what they print and the files they leave.
+func TestCompiledFiles(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+
+	programs := []string{
+		`көрсөтүү(файл.оку("{}/data/сандар.csv"), файл.каталог("{}/data"), файл.барбы("{}/data/жок"))`,
+		`файл.жаз("{}/out/отчет.txt", "биринчи"); файл.кошуп_жаз("{}/out/отчет.txt", "экинчи"); көрсөтүү(файл.оку("{}/out/отчет.txt"))`,
+		`файл.оку("{}/купуя.txt")`,
+		`файл.оку("{}/data/../купуя.txt")`,
+		`файл.оку("{}/data/шилтеме")`,
+		`файл.жаз("{}/data/x", "")`,
+		`файл.оку("{}/data/жок")`,
+		`файл.оку("{}/data")`,
+		`файл.каталог("{}/data/сандар.csv")`,
+	}
+
+	prepare := func() string {
+		root := t.TempDir()
+		for _, directory := range []string{"data", "out"} {
+			if err := os.Mkdir(filepath.Join(root, directory), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for name, content := range map[string]string{"data/сандар.csv": "1,2", "data/б.txt": "", "купуя.txt": "сыр"} {
+			if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Symlink(filepath.Join(root, "купуя.txt"), filepath.Join(root, "data", "шилтеме")); err != nil {
+			t.Fatal(err)
+		}
+		return root
+	}
+
+	for _, program := range programs {
+		evaluated, compiled := prepare(), prepare()
+
+		var expected bytes.Buffer
+		capabilities := stdlib.Capabilities{Read: []string{evaluated + "/data", evaluated + "/out"}, Write: []string{evaluated + "/out"}}
+		result := evaluator.Eval(parse(t, strings.ReplaceAll(program, "{}", evaluated)), evaluator.NewEnvironment(strings.NewReader(""), &expected, capabilities))
+		if err, ok := result.(*object.Error); ok {
+			fmt.Fprintln(&expected, err.Message)
+		}
+
+		capabilities = stdlib.Capabilities{Read: []string{compiled + "/data", compiled + "/out"}, Write: []string{compiled + "/out"}}
+		script, errors := Compile(parse(t, strings.ReplaceAll(program, "{}", compiled)), Options{Capabilities: capabilities})
+		if len(errors) > 0 {
+			t.Fatalf("%s: compile errors: %v", program, errors)
+		}
+		output, err := exec.Command(node, "-e", "try {\n"+script+"} catch (error) {\n  console.log(error.message);\n}\n").CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: node failed: %s\n%s", program, err, output)
+		}
+
+		if want, got := strings.ReplaceAll(expected.String(), evaluated, "{}"), strings.ReplaceAll(string(output), compiled, "{}"); want != got {
+			t.Errorf("%s: want=%q, got=%q", program, want, got)
+		}
+		if want, got := readFiles(t, evaluated+"/out"), readFiles(t, compiled+"/out"); want != got {
+			t.Errorf("%s: wrong files. want=%q, got=%q", program, want, got)
+		}
+	}
+}
+
+// TestCompiledModules runs a program made of modules with the evaluator
+// and, compiled to ES modules, with node, and compares what they print.
+func TestCompiledModules(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+
+	files := map[string]string{
+		"app/main.alipp": `импорт { кош, биринчи } "../lib/math"; импорт { квадрат, new } "helpers";
+көрсөтүү("main", биринчи, окуу());
+көрсөтүү(кош(1, 2), квадрат(99999999), new(2));
+аракет { квадрат(-1) } кармоо (e) { көрсөтүү("кармалды:", e) }`,
+		"lib/math.alipp": `импорт { квадрат } "helpers";
+экспорт сакта биринчи = окуу();
+экспорт сакта кош = функ(a, b) { квадрат(a) + b };`,
+		"search/helpers.alipp": `көрсөтүү("helpers");
+экспорт сакта квадрат = функ(x) { эгер (x < 0) { ыргыт {"x": x}; } x * x };
+экспорт сакта new = функ(x) { json.жаз([x]) };`,
+	}
+	directory := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(directory, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	input := "бир\nэки\n"
+
+	loaderInstance := loader.New([]string{filepath.Join(directory, "search")})
+	modules, loadErrors := loaderInstance.Load(filepath.Join(directory, "app", "main.alipp"))
+	if len(loadErrors) > 0 {
+		t.Fatalf("load errors: %v", loadErrors)
+	}
+
+	var expected bytes.Buffer
+	in := bufio.NewReader(strings.NewReader(input))
+	runner := evaluator.NewModules(loaderInstance, func() *object.Environment {
+		return evaluator.NewEnvironment(in, &expected, stdlib.Capabilities{})
+	})
+	if err, ok := runner.Run(modules).(*object.Error); ok {
+		t.Fatalf("evaluation failed: %s", err.Message)
+	}
+
+	scripts, errors := CompileModules(modules, Options{})
+	if len(errors) > 0 {
+		t.Fatalf("compile errors: %v", errors)
+	}
+	output := t.TempDir()
+	names := []string{}
+	for _, script := range scripts {
+		names = append(names, script.Name)
+		path := filepath.Join(output, filepath.FromSlash(script.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(script.JavaScript), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if strings.Join(names, " ") != "search/helpers.mjs lib/math.mjs app/main.mjs" {
+		t.Errorf("wrong scripts. got=%v", names)
+	}
+
+	command := exec.Command(node, filepath.Join(output, "app", "main.mjs"))
+	command.Stdin = strings.NewReader(input)
+	actual, err := command.CombinedOutput()
+	if err != nil {
+		t.Fatalf("node failed: %s\n%s", err, actual)
+	}
+	if string(actual) != expected.String() {
+		t.Errorf("want=%q, got=%q", expected.String(), actual)
+	}
+}
+
+// TestExterns runs programs that use JavaScript through тышкы with node.
+// The evaluator has no JavaScript to run them with.
+func TestExterns(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+
+	tests := []struct {
+		program  string
+		expected string
+	}{
+		{
+			`тышкы Math; тышкы журнал = "console.log";
+сакта тизме = [3, 1, 2];
+тизме.push(Math.max(4, 5));
+журнал(тизме.length, тизме.join("-"));`,
+			"4 3-1-2-5\n",
+		},
+		{
+			`тышкы { basename, бириктир = "join" } "node:path"; тышкы JSON;
+сакта жол = бириктир("a", "b", "c.alipp");
+көрсөтүү(basename(жол), JSON.stringify([1, "a"]));`,
+			"c.alipp [1,\"a\"]\n",
+		},
+	}
+
+	for _, test := range tests {
+		script := compile(t, test.program)
+		path := filepath.Join(t.TempDir(), "program.mjs")
+		if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := exec.Command(node, path).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: node failed: %s\n%s", test.program, err, output)
+		}
+		if string(output) != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.program, test.expected, output)
+		}
+	}
+}
+
+func readFiles(t *testing.T, directory string) string {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var files strings.Builder
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(directory, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&files, "%s: %s\n", entry.Name(), content)
+	}
+
+	return files.String()
+}
+
+func toJSON(value object.Object) interface{} {
+	switch value := value.(type) {
+	case *object.Integer:
+		if value.Big != nil || value.Value > maxSafeInteger || value.Value < -maxSafeInteger {
+			return value.Inspect()
+		}
+		return value.Value
+	case *object.Float:
+		return map[string]interface{}{"бөлчөк": value.Inspect()}
+	case *object.String:
+		return value.Value
+	case *object.Boolean:
+		return value.Value
+	case *object.Array:
+		elements := []interface{}{}
+		for _, element := range value.Elements {
+			elements = append(elements, toJSON(element))
+		}
+		return elements
+	case *object.Hash:
+		pairs := [][]interface{}{}
+		for _, key := range value.Keys {
+			pair := value.Pairs[key]
+			pairs = append(pairs, []interface{}{toJSON(pair.Key), toJSON(pair.Value)})
+		}
+		return map[string]interface{}{"сөздүк": pairs}
+	case *object.Error:
+		return "КАТА: " + value.Message
+	default:
+		return nil
+	}
+}
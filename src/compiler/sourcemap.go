@@ -0,0 +1,237 @@
+package compiler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/loader"
+)
+
+// The compiler builds the JavaScript out of strings, so the places that
+// map back to the source travel inside them: markerStart, the index of a
+// mark, markerEnd. sourceMap takes them out again once the script is
+// whole. Both runes are for private use, and quote escapes them, so the
+// program cannot write them itself.
+const (
+	markerStart = '\uE000'
+	markerEnd   = '\uE001'
+)
+
+// mark is a place in the source that the JavaScript after its marker
+// came from, with the alipp name when that JavaScript is an identifier.
+type mark struct {
+	position ast.Position
+	name     string
+}
+
+// mark returns a marker for where node starts in the source, or nothing
+// unless a source map is being made.
+func (compiler *compiler) mark(node ast.Node, name string) string {
+	if !compiler.options.SourceMap && !compiler.options.InlineSourceMap {
+		return ""
+	}
+
+	position := ast.SpanOf(node).Start
+	if position.Line == 0 {
+		return ""
+	}
+	compiler.marks = append(compiler.marks, mark{position: position, name: name})
+
+	return string(markerStart) + strconv.Itoa(len(compiler.marks)-1) + string(markerEnd)
+}
+
+// segment maps a column of the JavaScript to a place in the source.
+// Lines and columns are 0-based and count UTF-16 code units, as source
+// maps do.
+type segment struct {
+	column       int
+	sourceLine   int
+	sourceColumn int
+	// name indexes the names of the map, and is -1 for none.
+	name int
+}
+
+// sourceMapJSON is the layout of a version 3 source map.
+type sourceMapJSON struct {
+	Version        int      `json:"version"`
+	File           string   `json:"file,omitempty"`
+	Sources        []string `json:"sources"`
+	SourcesContent []string `json:"sourcesContent,omitempty"`
+	Names          []string `json:"names"`
+	Mappings       string   `json:"mappings"`
+}
+
+// sourceMap takes the markers out of script, which was compiled from
+// module to be written to name, and returns it with the source map the
+// markers made. The map is empty when none is being made.
+func (compiler *compiler) sourceMap(script string, module *loader.Module, name string) (string, string) {
+	if !compiler.options.SourceMap && !compiler.options.InlineSourceMap {
+		return script, ""
+	}
+
+	sourceLines := strings.Split(module.Source, "\n")
+	names := []string{}
+	nameIndexes := map[string]int{}
+	lines := [][]segment{{}}
+	var out strings.Builder
+	column := 0
+
+	for i := 0; i < len(script); {
+		r, size := utf8.DecodeRuneInString(script[i:])
+
+		switch r {
+		case markerStart:
+			end := strings.IndexRune(script[i:], markerEnd)
+			index, _ := strconv.Atoi(script[i+size : i+end])
+			i += end + utf8.RuneLen(markerEnd)
+
+			found := compiler.marks[index]
+			current := segment{
+				column:       column,
+				sourceLine:   found.position.Line - 1,
+				sourceColumn: utf16Column(sourceLines, found.position),
+				name:         -1,
+			}
+			if found.name != "" {
+				if _, ok := nameIndexes[found.name]; !ok {
+					nameIndexes[found.name] = len(names)
+					names = append(names, found.name)
+				}
+				current.name = nameIndexes[found.name]
+			}
+
+			// Of the marks at one column, the innermost one is kept.
+			segments := &lines[len(lines)-1]
+			if last := len(*segments) - 1; last >= 0 && (*segments)[last].column == column {
+				(*segments)[last] = current
+			} else {
+				*segments = append(*segments, current)
+			}
+			continue
+		case '\n':
+			lines = append(lines, []segment{})
+			column = 0
+		default:
+			column += len(utf16.Encode([]rune{r}))
+		}
+
+		out.WriteString(script[i : i+size])
+		i += size
+	}
+
+	source := module.Name
+	if compiler.options.OutputDirectory != "" && filepath.IsAbs(module.Path) {
+		directory, err := filepath.Abs(filepath.Join(compiler.options.OutputDirectory, filepath.Dir(name)))
+		if relative, relativeErr := filepath.Rel(directory, module.Path); err == nil && relativeErr == nil {
+			source = filepath.ToSlash(relative)
+		}
+	}
+
+	file := ""
+	if name != "" {
+		file = filepath.Base(name)
+	}
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(sourceMapJSON{
+		Version:        3,
+		File:           file,
+		Sources:        []string{source},
+		SourcesContent: []string{module.Source},
+		Names:          names,
+		Mappings:       encodeMappings(lines),
+	})
+	sourceMap := strings.TrimSuffix(encoded.String(), "\n")
+
+	if compiler.options.InlineSourceMap {
+		out.WriteString("//# sourceMappingURL=data:application/json;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(sourceMap)) + "\n")
+		return out.String(), ""
+	}
+	if file != "" {
+		out.WriteString("//# sourceMappingURL=" + file + ".map\n")
+	}
+
+	return out.String(), sourceMap
+}
+
+// utf16Column converts the rune column of position to a 0-based column
+// in UTF-16 code units.
+func utf16Column(lines []string, position ast.Position) int {
+	if position.Line > len(lines) {
+		return position.Column - 1
+	}
+
+	column := 0
+	runes := []rune(lines[position.Line-1])
+	for i := 0; i < position.Column-1 && i < len(runes); i++ {
+		column += len(utf16.Encode([]rune{runes[i]}))
+	}
+
+	return column
+}
+
+// encodeMappings writes the mappings field: lines separated by ;,
+// segments by , and every field a Base64 VLQ relative to the same field
+// of the segment before it. Columns start again on every line; the
+// other fields carry on across lines.
+func encodeMappings(lines [][]segment) string {
+	var out strings.Builder
+	sourceLine, sourceColumn, name := 0, 0, 0
+
+	for i, segments := range lines {
+		if i > 0 {
+			out.WriteByte(';')
+		}
+
+		column := 0
+		for j, current := range segments {
+			if j > 0 {
+				out.WriteByte(',')
+			}
+			writeVLQ(&out, current.column-column)
+			writeVLQ(&out, 0)
+			writeVLQ(&out, current.sourceLine-sourceLine)
+			writeVLQ(&out, current.sourceColumn-sourceColumn)
+			if current.name >= 0 {
+				writeVLQ(&out, current.name-name)
+				name = current.name
+			}
+			column, sourceLine, sourceColumn = current.column, current.sourceLine, current.sourceColumn
+		}
+	}
+
+	return out.String()
+}
+
+const base64Digits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// writeVLQ writes value in Base64 VLQ: the sign in the lowest bit, then
+// five bits to a digit, lowest first, with the sixth bit set on every
+// digit but the last.
+func writeVLQ(out *strings.Builder, value int) {
+	bits := value << 1
+	if value < 0 {
+		bits = -value<<1 | 1
+	}
+
+	for {
+		digit := bits & 31
+		bits >>= 5
+		if bits > 0 {
+			digit |= 32
+		}
+		out.WriteByte(base64Digits[digit])
+		if bits == 0 {
+			return
+		}
+	}
+}
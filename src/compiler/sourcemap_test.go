@@ -0,0 +1,249 @@
+package compiler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+// decodedSegment is a segment of the mappings field with its fields made
+// absolute again.
+type decodedSegment struct {
+	line, column             int
+	sourceLine, sourceColumn int
+	name                     int
+}
+
+// decodeMappings reads the mappings field of a source map back.
+func decodeMappings(t *testing.T, mappings string) []decodedSegment {
+	t.Helper()
+
+	segments := []decodedSegment{}
+	sourceLine, sourceColumn, name := 0, 0, 0
+	for line, text := range strings.Split(mappings, ";") {
+		column := 0
+		if text == "" {
+			continue
+		}
+		for _, field := range strings.Split(text, ",") {
+			values := decodeVLQ(t, field)
+			if len(values) != 4 && len(values) != 5 {
+				t.Fatalf("segment %q has %d fields", field, len(values))
+			}
+			if values[1] != 0 {
+				t.Errorf("segment %q names a source other than the first", field)
+			}
+
+			column += values[0]
+			sourceLine += values[2]
+			sourceColumn += values[3]
+			decoded := decodedSegment{line: line, column: column, sourceLine: sourceLine, sourceColumn: sourceColumn, name: -1}
+			if len(values) == 5 {
+				name += values[4]
+				decoded.name = name
+			}
+			segments = append(segments, decoded)
+		}
+	}
+
+	return segments
+}
+
+func decodeVLQ(t *testing.T, text string) []int {
+	t.Helper()
+
+	values := []int{}
+	value, shift := 0, 0
+	for _, r := range text {
+		digit := strings.IndexRune(base64Digits, r)
+		if digit < 0 {
+			t.Fatalf("%q is not a Base64 digit", r)
+		}
+
+		value |= (digit & 31) << shift
+		shift += 5
+		if digit&32 != 0 {
+			continue
+		}
+		if value&1 == 1 {
+			values = append(values, -(value >> 1))
+		} else {
+			values = append(values, value>>1)
+		}
+		value, shift = 0, 0
+	}
+	if shift != 0 {
+		t.Fatalf("%q ends inside a value", text)
+	}
+
+	return values
+}
+
+func TestVLQ(t *testing.T) {
+	tests := map[int]string{0: "A", 1: "C", -1: "D", 15: "e", 16: "gB", -16: "hB", 1000: "w+B", -123456: "hkxH"}
+
+	for value, expected := range tests {
+		var out strings.Builder
+		writeVLQ(&out, value)
+		if out.String() != expected {
+			t.Errorf("%d: want=%q, got=%q", value, expected, out.String())
+		}
+		if decoded := decodeVLQ(t, out.String()); len(decoded) != 1 || decoded[0] != value {
+			t.Errorf("%d did not read back. got=%v", value, decoded)
+		}
+	}
+}
+
+// utf16Prefix is the length, in UTF-16 code units, of the first runes
+// of text.
+func utf16Prefix(text string, runes int) int {
+	return len(utf16.Encode([]rune(text)[:min(runes, len([]rune(text)))]))
+}
+
+// fromUTF16 returns what is left of text after skipping units UTF-16
+// code units.
+func fromUTF16(text string, units int) string {
+	runes := []rune(text)
+	for i := range runes {
+		if utf16Prefix(text, i) == units {
+			return string(runes[i:])
+		}
+	}
+
+	return ""
+}
+
+func TestSourceMaps(t *testing.T) {
+	programs := []string{
+		"сакта кош = функ(a, b) { a + b };\nкөрсөтүү(кош(1, 2));",
+		"сакта x = 5;\nэгер (x > 3) {\n  көрсөтүү(\"чоң\");\n} же {\n  көрсөтүү(\"кичине\");\n}",
+		"сакта с = {\"a\": [1, 2], \"b\": функ(x) { x * 2 }};\nс[\"b\"](с[\"a\"][1]);",
+		"сакта тизме = сап.бөл(\"😀 a b\", \" \"); сакта з = 1;\nсап.узундук(\"😀\") + з;",
+		"аракет {\n  ыргыт \"ката\";\n} кармоо (e) {\n  көрсөтүү(e);\n} акыры {\n  1\n}",
+		"сакта f = функ(n) {\n  сакта y = эгер (n < 2) { сакта z = 1; z } же { f(n - 1) * n };\n  y\n};\nf(5);",
+		"сакта new = \"\ue000 0 \ue001\";\nnew;",
+	}
+
+	for _, program := range programs {
+		module := &loader.Module{Name: "прог.alipp", Program: parse(t, program), Source: program}
+		script, errors := CompileModule(module, "прог.js", Options{SourceMap: true})
+		if len(errors) > 0 {
+			t.Fatalf("%q: compile errors: %v", program, errors)
+		}
+
+		var sourceMap sourceMapJSON
+		if err := json.Unmarshal([]byte(script.SourceMap), &sourceMap); err != nil {
+			t.Fatalf("%q: the source map is not JSON: %s", program, err)
+		}
+		if sourceMap.Version != 3 || sourceMap.File != "прог.js" || strings.Join(sourceMap.Sources, " ") != "прог.alipp" ||
+			len(sourceMap.SourcesContent) != 1 || sourceMap.SourcesContent[0] != program {
+			t.Errorf("%q: wrong source map. got=%+v", program, sourceMap)
+		}
+		if !strings.HasSuffix(script.JavaScript, "\n//# sourceMappingURL=прог.js.map\n") {
+			t.Errorf("%q: the script does not link its source map", program)
+		}
+		if strings.ContainsAny(script.JavaScript, "\ue000\ue001") {
+			t.Errorf("%q: markers were left in the script", program)
+		}
+
+		// Every segment points at the start of a token, named segments at
+		// the name in both the script and the source.
+		starts := map[[2]int]token.Token{}
+		sourceLines := strings.Split(program, "\n")
+		lexerInstance := lexer.New(program)
+		for tok := lexerInstance.NextToken(); tok.Type != token.EOF; tok = lexerInstance.NextToken() {
+			starts[[2]int{tok.Line - 1, utf16Prefix(sourceLines[tok.Line-1], tok.Column-1)}] = tok
+		}
+
+		scriptLines := strings.Split(script.JavaScript, "\n")
+		mapped := map[int]bool{}
+		for _, found := range decodeMappings(t, sourceMap.Mappings) {
+			tok, ok := starts[[2]int{found.sourceLine, found.sourceColumn}]
+			if !ok {
+				t.Errorf("%q: segment %+v does not point at a token", program, found)
+				continue
+			}
+			mapped[tok.Line] = true
+			if found.name < 0 {
+				continue
+			}
+
+			name := sourceMap.Names[found.name]
+			generated := fromUTF16(scriptLines[found.line], found.column)
+			if tok.Literal != name || !strings.HasPrefix(strings.TrimPrefix(generated, "$"), name) {
+				t.Errorf("%q: segment named %s points at %q in the source and %q in the script", program, name, tok.Literal, generated)
+			}
+		}
+		for line := range sourceLines {
+			if !strings.HasPrefix(strings.TrimSpace(sourceLines[line]), "}") && !mapped[line+1] {
+				t.Errorf("%q: line %d is not mapped", program, line+1)
+			}
+		}
+
+		inline, _ := CompileModule(module, "прог.js", Options{InlineSourceMap: true})
+		prefix := "//# sourceMappingURL=data:application/json;charset=utf-8;base64,"
+		index := strings.LastIndex(inline.JavaScript, prefix)
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(inline.JavaScript[index+len(prefix):]))
+		if index < 0 || err != nil || string(decoded) != script.SourceMap || inline.SourceMap != "" {
+			t.Errorf("%q: the inline source map differs. got=%q, %v", program, decoded, err)
+		}
+	}
+}
+
+// TestSourceMappedErrors runs a compiled program that fails with node,
+// which reads the source map to say where in the alipp file it failed.
+func TestSourceMappedErrors(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+
+	directory := t.TempDir()
+	source := "сакта бөл = функ(a, b) {\n  a / b\n};\n\nбөл(4, 0);\n"
+	path := filepath.Join(directory, "src", "бөлүү.alipp")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modules, loadErrors := loader.New(nil).Load(path)
+	if len(loadErrors) > 0 {
+		t.Fatalf("load errors: %v", loadErrors)
+	}
+	output := filepath.Join(directory, "out")
+	script, errors := CompileModule(modules[0], "бөлүү.js", Options{SourceMap: true, OutputDirectory: output})
+	if len(errors) > 0 {
+		t.Fatalf("compile errors: %v", errors)
+	}
+	if !strings.Contains(script.SourceMap, `"sources":["../src/бөлүү.alipp"]`) {
+		t.Errorf("the source is not named relative to the script. got=%s", script.SourceMap)
+	}
+
+	if err := os.MkdirAll(output, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(output, "бөлүү.js"), []byte(script.JavaScript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(output, "бөлүү.js.map"), []byte(script.SourceMap), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stack, _ := exec.Command(node, "--enable-source-maps", filepath.Join(output, "бөлүү.js")).CombinedOutput()
+	for _, frame := range []string{"at бөл (" + path + ":2:3)", "(" + path + ":5:1)"} {
+		if !strings.Contains(string(stack), frame) {
+			t.Errorf("the stack has no %q.\n%s", frame, stack)
+		}
+	}
+}
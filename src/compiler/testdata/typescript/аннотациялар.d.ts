@@ -0,0 +1,7 @@
+export declare function кош(a: number | bigint, b: number | bigint): number | bigint;
+export declare function орточо(сандар: number[], n: number | bigint): number;
+export declare function тапшыр(аты: string, f: (arg1: string) => boolean): boolean;
+export declare const эсеп: Map<string, number | bigint>;
+export declare const баалар: Map<number | bigint, string[]>;
+export declare const жок: null;
+export declare const бирдеме: unknown;
@@ -0,0 +1,3 @@
+export declare const квадраттар: (number | bigint)[];
+export declare const баштапкы: number | bigint;
+export declare function квадрат_кайра<T extends number | bigint>(x: T): T;
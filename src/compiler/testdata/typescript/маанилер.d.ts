@@ -0,0 +1,15 @@
+export declare const облустарСаны: number | bigint;
+export declare const пи: number;
+export declare const чоң: number | bigint;
+export declare const борбор: string;
+export declare const туурабы: boolean;
+export declare const шаарлар: string[];
+export declare const аралаш: unknown[];
+export declare const бош_тизме: unknown[];
+export declare const калк: Map<string, number | bigint>;
+export declare const new$: number | bigint;
+export declare const биринчи: string;
+export declare const балким: unknown;
+export declare const аты: string;
+export declare const биринчиШаар: string;
+export declare const калганШаарлар: string[];
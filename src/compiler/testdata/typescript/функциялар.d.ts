@@ -0,0 +1,15 @@
+export declare function кош<T extends number | bigint | string>(a: T, b: T): T;
+export declare function эки_эсе<T extends number | bigint>(x: T): T;
+export declare function жарым<T extends number | bigint>(x: T): number;
+export declare function салам(аты: string): string;
+export declare function чоңбу<T extends number | bigint>(x: T): boolean;
+export declare function факт(n: number | bigint): number | bigint;
+export declare function белги<T extends number | bigint>(x: T): unknown;
+export declare function жасоочу<T extends number | bigint>(кадам: T): (arg1: T) => T;
+export declare function ката_ыргыт<T, T2>(билдирүү: T): T2;
+export declare function эч_нерсе(): null;
+export declare function new$<T>(class$: T): T;
+export declare function дагы_факт(n: number | bigint): number | bigint;
+export declare const жыйынтык: number | bigint;
+export declare function аралык(arg1: number[], arg2: Map<string, number | bigint>): number;
+export declare function жылдыр<T extends number | bigint>(x: T, кадам?: number | bigint, arg3?: (number | bigint)[]): T;
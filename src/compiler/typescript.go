@@ -0,0 +1,327 @@
+package compiler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/types"
+)
+
+// typing is the TypeScript type of an alipp value, as far as the
+// compiler can tell it without running the program.
+type typing interface {
+	String() string
+}
+
+// primitive is a type TypeScript names with a word: number, bigint,
+// string, boolean, null, unknown and never.
+type primitive string
+
+func (primitive primitive) String() string { return string(primitive) }
+
+var (
+	unknownTyping typing = primitive("unknown")
+	neverTyping   typing = primitive("never")
+	nullTyping    typing = primitive("null")
+	// integerTyping is what arithmetic on integers gives, since it goes
+	// on with BigInts past 2^53.
+	integerTyping = union(primitive("number"), primitive("bigint"))
+)
+
+// arrayTyping is a list, which compiles to an array.
+type arrayTyping struct {
+	element typing
+}
+
+func (array arrayTyping) String() string {
+	switch array.element.(type) {
+	case unionTyping, functionTyping:
+		return "(" + array.element.String() + ")[]"
+	}
+
+	return array.element.String() + "[]"
+}
+
+// mapTyping is a hash, which compiles to a Map.
+type mapTyping struct {
+	key, value typing
+}
+
+func (hash mapTyping) String() string {
+	return "Map<" + hash.key.String() + ", " + hash.value.String() + ">"
+}
+
+// functionTyping is a function, with its parameters named as the
+// JavaScript names them.
+type functionTyping struct {
+	parameters []string
+	// parameterTypings are nil for parameters that are unknown.
+	parameterTypings []typing
+	// optional is how many of the last parameters may be left out.
+	optional int
+	result   typing
+}
+
+func (function functionTyping) String() string {
+	return "(" + function.parameterList() + ") => " + function.result.String()
+}
+
+func (function functionTyping) parameterList() string {
+	parameters := make([]string, len(function.parameters))
+	for i, parameter := range function.parameters {
+		if i >= len(function.parameters)-function.optional {
+			parameter += "?"
+		}
+		parameters[i] = parameter + ": unknown"
+		if i < len(function.parameterTypings) && function.parameterTypings[i] != nil {
+			parameters[i] = parameter + ": " + function.parameterTypings[i].String()
+		}
+	}
+
+	return strings.Join(parameters, ", ")
+}
+
+// unionTyping is a value of one of several types.
+type unionTyping []typing
+
+func (members unionTyping) String() string {
+	written := make([]string, len(members))
+	for i, member := range members {
+		written[i] = member.String()
+		if _, ok := member.(functionTyping); ok {
+			written[i] = "(" + written[i] + ")"
+		}
+	}
+
+	return strings.Join(written, " | ")
+}
+
+// union returns the type of a value of any of typings. unknown takes
+// over the others, and never leaves them as they are.
+func union(typings ...typing) typing {
+	members := unionTyping{}
+	seen := map[string]bool{}
+	var add func(typing typing) bool
+	add = func(typing typing) bool {
+		switch typing := typing.(type) {
+		case unionTyping:
+			for _, member := range typing {
+				if !add(member) {
+					return false
+				}
+			}
+			return true
+		case primitive:
+			if typing == unknownTyping {
+				return false
+			}
+			if typing == neverTyping {
+				return true
+			}
+		}
+		if !seen[typing.String()] {
+			seen[typing.String()] = true
+			members = append(members, typing)
+		}
+		return true
+	}
+
+	for _, typing := range typings {
+		if !add(typing) {
+			return unknownTyping
+		}
+	}
+	switch len(members) {
+	case 0:
+		return neverTyping
+	case 1:
+		return members[0]
+	}
+
+	return members
+}
+
+// typeScriptType returns the TypeScript type of the values of a type the
+// checker worked out. Variables are the type parameters generics names,
+// and unknown when it is nil.
+func typeScriptType(value types.Type, generics *generics) typing {
+	switch value := types.Prune(value).(type) {
+	case *types.Variable:
+		if generics == nil {
+			return unknownTyping
+		}
+		return generics.name(value)
+	case *types.List:
+		return arrayTyping{element: typeScriptType(value.Element, generics)}
+	case *types.Hash:
+		return mapTyping{key: typeScriptType(value.Key, generics), value: typeScriptType(value.Value, generics)}
+	case *types.Function:
+		function := functionTyping{optional: value.Optional}
+		for i, parameter := range value.Parameters {
+			function.parameters = append(function.parameters, "arg"+strconv.Itoa(i+1))
+			function.parameterTypings = append(function.parameterTypings, typeScriptType(parameter, generics))
+		}
+		function.result = typeScriptType(value.Result, generics)
+		return function
+	case types.Basic:
+		switch value {
+		case types.Integer:
+			return integerTyping
+		case types.Float:
+			// A float without a fraction is a Number object, which
+			// computes like a number.
+			return primitive("number")
+		case types.String:
+			return primitive("string")
+		case types.Boolean:
+			return primitive("boolean")
+		case types.Null:
+			return nullTyping
+		}
+	}
+
+	return unknownTyping
+}
+
+// generics names the type parameters of a generic function, one for
+// each type variable in its type, in the order they are met.
+type generics struct {
+	names     map[*types.Variable]string
+	variables []*types.Variable
+}
+
+func (generics *generics) name(variable *types.Variable) typing {
+	if name, ok := generics.names[variable]; ok {
+		return primitive(name)
+	}
+
+	name := "T"
+	if len(generics.variables) > 0 {
+		name += strconv.Itoa(len(generics.variables) + 1)
+	}
+	generics.names[variable] = name
+	generics.variables = append(generics.variables, variable)
+	return primitive(name)
+}
+
+// String writes the type parameters, each limited to the types the
+// operators on it take, or nothing when there are none.
+func (generics *generics) String() string {
+	if len(generics.variables) == 0 {
+		return ""
+	}
+
+	parameters := make([]string, len(generics.variables))
+	for i, variable := range generics.variables {
+		parameters[i] = generics.names[variable]
+		if limits := variable.Limits(); limits != nil {
+			members := make([]typing, len(limits))
+			for j, limit := range limits {
+				members[j] = typeScriptType(limit, nil)
+			}
+			parameters[i] += " extends " + union(members...).String()
+		}
+	}
+
+	return "<" + strings.Join(parameters, ", ") + ">"
+}
+
+// parameterNames returns what the JavaScript calls the parameters of the
+// function literal name is bound to in module, following names bound to
+// other names and imports, or nil when it is bound to something else.
+func parameterNames(module *loader.Module, name string, seen map[string]bool) []string {
+	if seen[module.Path+"\x00"+name] {
+		return nil
+	}
+	seen[module.Path+"\x00"+name] = true
+
+	for _, statement := range module.Program.Statements {
+		let, _ := statement.(*ast.LetStatement)
+		switch statement := statement.(type) {
+		case *ast.ImportStatement:
+			for _, imported := range statement.Names {
+				if imported.Value == name && module.Imports[statement.Path.Value] != nil {
+					return parameterNames(module.Imports[statement.Path.Value], name, seen)
+				}
+			}
+		case *ast.ExportStatement:
+			let = statement.Statement
+		}
+		if names := boundParameters(module, let, name, seen); names != nil {
+			return names
+		}
+	}
+
+	return nil
+}
+
+func boundParameters(module *loader.Module, statement *ast.LetStatement, name string, seen map[string]bool) []string {
+	if statement == nil || statement.Pattern != nil || statement.Name == nil || statement.Name.Value != name {
+		return nil
+	}
+
+	switch value := statement.Value.(type) {
+	case *ast.FunctionLiteral:
+		names := make([]string, len(value.Parameters))
+		for i, parameter := range value.Parameters {
+			names[i] = "arg" + strconv.Itoa(i+1)
+			if defaultPattern, ok := parameter.(*ast.DefaultPattern); ok {
+				parameter = defaultPattern.Pattern
+			}
+			if binding, ok := parameter.(*ast.BindingPattern); ok {
+				names[i] = javaScriptName(binding.Name.Value)
+			}
+		}
+		return names
+	case *ast.Identifier:
+		return parameterNames(module, value.Value, seen)
+	}
+
+	return nil
+}
+
+// typeScript returns the TypeScript declarations of what module
+// exports, for a .d.ts file next to its script, from the types in
+// schemes. A function is generic in the type variables of its type.
+func (compiler *compiler) typeScript(module *loader.Module, schemes map[string]*types.Scheme) string {
+	if len(compiler.exports) == 0 {
+		return "export {};\n"
+	}
+
+	var out strings.Builder
+	for _, name := range compiler.exports {
+		scheme, ok := schemes[name]
+		if !ok {
+			out.WriteString("export declare const " + javaScriptName(name) + ": unknown;\n")
+			continue
+		}
+
+		generics := &generics{names: map[*types.Variable]string{}}
+		function, isFunction := typeScriptType(scheme.Type, generics).(functionTyping)
+		if !isFunction {
+			out.WriteString("export declare const " + javaScriptName(name) + ": " + typeScriptType(scheme.Type, nil).String() + ";\n")
+			continue
+		}
+
+		if names := parameterNames(module, name, map[string]bool{}); len(names) == len(function.parameters) {
+			function.parameters = names
+		}
+		out.WriteString("export declare function " + javaScriptName(name) + generics.String() + "(" + function.parameterList() + "): " + function.result.String() + ";\n")
+	}
+
+	return out.String()
+}
+
+// DeclarationPath returns the name of the TypeScript declarations for
+// the script name: .d.ts for .js, .d.mts for .mjs and .d.cts for .cjs.
+func DeclarationPath(name string) string {
+	for _, extension := range []string{".js", ".mjs", ".cjs"} {
+		if strings.HasSuffix(name, extension) {
+			return strings.TrimSuffix(name, extension) + ".d." + extension[1:len(extension)-2] + "ts"
+		}
+	}
+
+	return name + ".d.ts"
+}
@@ -0,0 +1,75 @@
+package compiler
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/loader"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden .d.ts files in testdata")
+
+// TestDeclarations compiles each program in testdata/typescript and
+// compares its declarations with the .d.ts file of the same name. Run
+// with -update to write those files instead.
+func TestDeclarations(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "typescript", "*"+loader.Extension))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no programs in testdata/typescript: %v", err)
+	}
+
+	for _, path := range paths {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		modules, loadErrors := loader.New(nil).Load(absolute)
+		if len(loadErrors) > 0 {
+			t.Errorf("%s: load errors: %v", path, loadErrors)
+			continue
+		}
+
+		scripts, errors := CompileModules(modules, Options{Declarations: true})
+		if len(errors) > 0 {
+			t.Errorf("%s: compile errors: %v", path, errors)
+			continue
+		}
+
+		// The loader puts a module after the ones it imports.
+		declarations := scripts[len(scripts)-1].Declarations
+		golden := strings.TrimSuffix(path, loader.Extension) + ".d.ts"
+		if *update {
+			if err := os.WriteFile(golden, []byte(declarations), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Errorf("%s: %s", path, err)
+			continue
+		}
+		if declarations != string(expected) {
+			t.Errorf("%s: wrong declarations.\nwant:\n%s\ngot:\n%s", path, expected, declarations)
+		}
+	}
+}
+
+func TestDeclarationPath(t *testing.T) {
+	tests := map[string]string{
+		"программа.js":  "программа.d.ts",
+		"модуль.mjs":    "модуль.d.mts",
+		"a/b.cjs":       "a/b.d.cts",
+		"программа":     "программа.d.ts",
+		"программа.jsx": "программа.jsx.d.ts",
+	}
+
+	for name, expected := range tests {
+		if declarationPath := DeclarationPath(name); declarationPath != expected {
+			t.Errorf("%s: want=%s, got=%s", name, expected, declarationPath)
+		}
+	}
+}
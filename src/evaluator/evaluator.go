@@ -0,0 +1,664 @@
+// Package evaluator runs alipp programs by walking their syntax tree.
+package evaluator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/object"
+	"github.com/asanoviskhak/alipp/src/stdlib"
+)
+
+var (
+	NULL  = object.NULL
+	TRUE  = object.TRUE
+	FALSE = object.FALSE
+)
+
+func init() {
+	stdlib.Apply = applyFunction
+}
+
+// NewEnvironment returns a top-level environment whose input and output
+// builtins read in and write out, and whose файл module may touch the
+// directories in capabilities.
+func NewEnvironment(in io.Reader, out io.Writer, capabilities stdlib.Capabilities) *object.Environment {
+	env := object.NewEnvironment()
+	for name, builtin := range stdlib.NewConsole(in, out).Builtins() {
+		env.Set(name, builtin)
+	}
+	for name, module := range capabilities.Modules() {
+		env.Set(name, module)
+	}
+
+	return env
+}
+
+// Eval evaluates node in env. Runtime errors are returned as an
+// *object.Error and stop the evaluation. An error gets the span of the
+// innermost node it came out of.
+func Eval(node ast.Node, env *object.Environment) object.Object {
+	result := eval(node, env)
+	if err, ok := result.(*object.Error); ok && err.Span.IsZero() {
+		err.Span = ast.SpanOf(node)
+		err.File = env.File()
+	}
+
+	return result
+}
+
+func eval(node ast.Node, env *object.Environment) object.Object {
+	switch node := node.(type) {
+	// Statements
+	case *ast.Program:
+		return evalProgram(node, env)
+	case *ast.BlockStatement:
+		return evalBlockStatement(node, env)
+	case *ast.ExpressionStatement:
+		return Eval(node.Expression, env)
+	case *ast.ReturnStatement:
+		value := Eval(node.ReturnValue, env)
+		if isError(value) {
+			return value
+		}
+		return &object.ReturnValue{Value: value}
+	case *ast.LetStatement:
+		value := Eval(node.Value, env)
+		if isError(value) {
+			return value
+		}
+		if node.Pattern != nil {
+			return destructure(node.Pattern, value, env)
+		}
+		if function, ok := value.(*object.Function); ok && function.Name == "" {
+			function.Name = node.Name.Value
+		}
+		env.Set(node.Name.Value, value)
+
+	case *ast.TryStatement:
+		return evalTryStatement(node, env)
+	case *ast.ThrowStatement:
+		value := Eval(node.Value, env)
+		if isError(value) {
+			return value
+		}
+		return &object.Error{Message: value.Inspect(), Value: value}
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+	case *ast.ExportStatement:
+		return Eval(node.Statement, env)
+	case *ast.ExternStatement:
+		// The names stand for JavaScript, which only compiled programs have.
+		return newError("%s JavaScript'ке компиляцияланган программада гана иштейт: alipp build колдонуңуз", node.TokenLiteral())
+
+	// Expressions
+	case *ast.IntegerLiteral:
+		return &object.Integer{Value: node.Value, Big: node.Big}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
+	case *ast.StringLiteral:
+		return &object.String{Value: node.Value}
+	case *ast.Boolean:
+		return object.NativeBoolean(node.Value)
+	case *ast.PrefixExpression:
+		right := Eval(node.Right, env)
+		if isError(right) {
+			return right
+		}
+		return evalPrefixExpression(node.Operator, right)
+	case *ast.InfixExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		right := Eval(node.Right, env)
+		if isError(right) {
+			return right
+		}
+		return evalInfixExpression(node.Operator, left, right)
+	case *ast.IfExpression:
+		return evalIfExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+	case *ast.Identifier:
+		return evalIdentifier(node, env)
+	case *ast.FunctionLiteral:
+		return &object.Function{Parameters: node.Parameters, Body: node.Body, Env: env}
+	case *ast.CallExpression:
+		function := Eval(node.Function, env)
+		if isError(function) {
+			return function
+		}
+		args := evalExpressions(node.Arguments, env)
+		if len(args) == 1 && isError(args[0]) {
+			return args[0]
+		}
+		return callFunction(node, env, function, args)
+	case *ast.ArrayLiteral:
+		elements := evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return &object.Array{Elements: elements}
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
+	case *ast.IndexExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index
+		}
+		return evalIndexExpression(left, index)
+	case *ast.MemberExpression:
+		value := Eval(node.Object, env)
+		if isError(value) {
+			return value
+		}
+		return evalMemberExpression(value, node.Property.Value)
+	case *ast.BadExpression, *ast.BadStatement:
+		return newError("синтаксистик катасы бар программаны аткарууга болбойт")
+	}
+
+	return nil
+}
+
+func evalProgram(program *ast.Program, env *object.Environment) object.Object {
+	var result object.Object
+
+	for _, statement := range program.Statements {
+		result = Eval(statement, env)
+
+		switch result := result.(type) {
+		case *object.ReturnValue:
+			return result.Value
+		case *object.Error:
+			return result
+		}
+	}
+
+	return result
+}
+
+// evalBlockStatement stops at a return value without unwrapping it, so
+// that it also stops the enclosing blocks up to the function call.
+func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
+	var result object.Object
+
+	for _, statement := range block.Statements {
+		result = Eval(statement, env)
+
+		if result != nil {
+			resultType := result.Type()
+			if resultType == object.RETURN_VALUE_OBJ || resultType == object.ERROR_OBJ {
+				return result
+			}
+		}
+	}
+
+	return result
+}
+
+// evalTryStatement runs the кармоо block when the аракет block raises an
+// error, and then the акыры block, which runs however the others end. An
+// error or кайтар in акыры takes the place of theirs.
+func evalTryStatement(statement *ast.TryStatement, env *object.Environment) object.Object {
+	result := Eval(statement.Block, env)
+
+	if err, ok := result.(*object.Error); ok && statement.Catch != nil {
+		if statement.Parameter != nil {
+			env.Set(statement.Parameter.Value, caughtValue(err))
+		}
+		result = Eval(statement.Catch, env)
+	}
+
+	if statement.Finally != nil {
+		if finally := Eval(statement.Finally, env); isError(finally) || isReturnValue(finally) {
+			return finally
+		}
+	}
+
+	if isError(result) || isReturnValue(result) {
+		return result
+	}
+	return nil
+}
+
+// evalImportStatement binds the names the statement lists to what the
+// module it names exports.
+func evalImportStatement(statement *ast.ImportStatement, env *object.Environment) object.Object {
+	importer := env.Importer()
+	if importer == nil {
+		return newError("импорт файлдан жүктөлгөн программада гана иштейт")
+	}
+
+	imported := importer(statement.Path.Value)
+	if isError(imported) {
+		return imported
+	}
+
+	module := imported.(*object.Module)
+	for _, name := range statement.Names {
+		value, ok := module.Members[name.Value]
+		if !ok {
+			return newError("%s модулу %s экспорттобойт", module.Name, name.Value)
+		}
+		env.Set(name.Value, value)
+	}
+
+	return nil
+}
+
+// caughtValue is what a кармоо clause binds: the value that was thrown,
+// or the message of an error of the runtime.
+func caughtValue(err *object.Error) object.Object {
+	if err.Value != nil {
+		return err.Value
+	}
+	return &object.String{Value: err.Message}
+}
+
+func evalPrefixExpression(operator string, right object.Object) object.Object {
+	switch operator {
+	case "!":
+		return object.NativeBoolean(!isTruthy(right))
+	case "-":
+		return evalMinusPrefixExpression(right)
+	default:
+		return newError("белгисиз оператор: %s%s", operator, right.Type())
+	}
+}
+
+func evalInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	switch {
+	case isNumber(left) && isNumber(right):
+		return evalNumberInfixExpression(operator, left, right)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInfixExpression(operator, left.(*object.String), right.(*object.String))
+	case left.Type() != right.Type():
+		return newError("түрлөр дал келбейт: %s %s %s", left.Type(), operator, right.Type())
+	case operator == "==":
+		return object.NativeBoolean(left == right)
+	case operator == "!=":
+		return object.NativeBoolean(left != right)
+	default:
+		return newError("белгисиз оператор: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalStringInfixExpression(operator string, left *object.String, right *object.String) object.Object {
+	switch operator {
+	case "+":
+		return &object.String{Value: left.Value + right.Value}
+	case "==":
+		return object.NativeBoolean(left.Value == right.Value)
+	case "!=":
+		return object.NativeBoolean(left.Value != right.Value)
+	default:
+		return newError("белгисиз оператор: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalIfExpression(ifExpression *ast.IfExpression, env *object.Environment) object.Object {
+	condition := Eval(ifExpression.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+
+	var result object.Object
+	if isTruthy(condition) {
+		result = Eval(ifExpression.Consequence, env)
+	} else if ifExpression.Alternative != nil {
+		result = Eval(ifExpression.Alternative, env)
+	}
+
+	// An empty block, or one ending in a сакта statement, has no value.
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
+// evalMatchExpression evaluates the first arm whose pattern matches the
+// subject and whose guard holds. The names a pattern binds are visible
+// only in its arm.
+func evalMatchExpression(expression *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(expression.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range expression.Arms {
+		armEnv := object.NewEnclosedEnvironment(env)
+		if !matchPattern(arm.Pattern, subject, armEnv) {
+			continue
+		}
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+		return Eval(arm.Body, armEnv)
+	}
+
+	return newError("салыштыр: эч бир үлгү дал келген жок: %s", subject.Inspect())
+}
+
+// matchPattern reports whether value matches pattern, and binds the
+// names in the pattern in env when it does. A literal matches the
+// values it is == to.
+func matchPattern(pattern ast.Pattern, value object.Object, env *object.Environment) bool {
+	switch pattern := pattern.(type) {
+	case *ast.LiteralPattern:
+		return evalInfixExpression("==", value, Eval(pattern.Value, env)) == TRUE
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value, value)
+		return true
+	case *ast.WildcardPattern:
+		return true
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if !ok || len(array.Elements) < len(pattern.Elements) || (pattern.Rest == nil && len(array.Elements) > len(pattern.Elements)) {
+			return false
+		}
+		for i, element := range pattern.Elements {
+			if !matchPattern(element, array.Elements[i], env) {
+				return false
+			}
+		}
+		if pattern.Rest != nil {
+			env.Set(pattern.Rest.Value, rest(array, len(pattern.Elements)))
+		}
+		return true
+	case *ast.HashPattern:
+		hash, ok := value.(*object.Hash)
+		if !ok {
+			return false
+		}
+		for i, keyNode := range pattern.Keys {
+			element, found := hash.Get(Eval(keyNode, env).(object.Hashable))
+			if !found || !matchPattern(pattern.Values[i], element, env) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// destructure binds the names in pattern, the pattern of a сакта or of
+// a parameter, to the parts of value, and returns an error pointing at
+// the pattern when value does not have its shape. A default value is
+// evaluated only when the element or key it stands for is missing.
+func destructure(pattern ast.Pattern, value object.Object, env *object.Environment) object.Object {
+	switch pattern := pattern.(type) {
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value, value)
+	case *ast.DefaultPattern:
+		return destructure(pattern.Pattern, value, env)
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if !ok {
+			return patternError(pattern, env, "тизме күтүлгөн, %s берилди", value.Type())
+		}
+		required := requiredPatterns(pattern.Elements)
+		given := len(array.Elements)
+		switch {
+		case pattern.Rest != nil && given < required:
+			return patternError(pattern, env, "тизмеде жок дегенде %d элемент күтүлгөн, %d берилди", required, given)
+		case pattern.Rest == nil && required == len(pattern.Elements) && given != required:
+			return patternError(pattern, env, "тизмеде %d элемент күтүлгөн, %d берилди", required, given)
+		case pattern.Rest == nil && (given < required || given > len(pattern.Elements)):
+			return patternError(pattern, env, "тизмеде %d-%d элемент күтүлгөн, %d берилди", required, len(pattern.Elements), given)
+		}
+		for i, element := range pattern.Elements {
+			var result object.Object
+			if i < given {
+				result = destructure(element, array.Elements[i], env)
+			} else {
+				result = destructureDefault(element.(*ast.DefaultPattern), env)
+			}
+			if result != nil {
+				return result
+			}
+		}
+		if pattern.Rest != nil {
+			env.Set(pattern.Rest.Value, rest(array, len(pattern.Elements)))
+		}
+	case *ast.HashPattern:
+		hash, ok := value.(*object.Hash)
+		if !ok {
+			return patternError(pattern, env, "сөздүк күтүлгөн, %s берилди", value.Type())
+		}
+		for i, keyNode := range pattern.Keys {
+			key := Eval(keyNode, env)
+			element, found := hash.Get(key.(object.Hashable))
+			var result object.Object
+			if found {
+				result = destructure(pattern.Values[i], element, env)
+			} else if defaultPattern, ok := pattern.Values[i].(*ast.DefaultPattern); ok {
+				result = destructureDefault(defaultPattern, env)
+			} else {
+				result = patternError(pattern, env, "сөздүктө %s ачкычы жок", key.Inspect())
+			}
+			if result != nil {
+				return result
+			}
+		}
+	}
+
+	return nil
+}
+
+// requiredPatterns returns how many of patterns must be given a value:
+// all of them up to the last one without a default.
+func requiredPatterns(patterns []ast.Pattern) int {
+	required := 0
+	for i, pattern := range patterns {
+		if _, ok := pattern.(*ast.DefaultPattern); !ok {
+			required = i + 1
+		}
+	}
+
+	return required
+}
+
+func destructureDefault(pattern *ast.DefaultPattern, env *object.Environment) object.Object {
+	value := Eval(pattern.Default, env)
+	if isError(value) {
+		return value
+	}
+
+	return destructure(pattern.Pattern, value, env)
+}
+
+func patternError(pattern ast.Pattern, env *object.Environment, format string, a ...interface{}) *object.Error {
+	err := newError(format, a...)
+	err.Span, err.File = ast.SpanOf(pattern), env.File()
+
+	return err
+}
+
+// rest returns a list of the elements of array from the one at start.
+func rest(array *object.Array, start int) *object.Array {
+	return &object.Array{Elements: append([]object.Object{}, array.Elements[start:]...)}
+}
+
+// evalIdentifier looks the name up in the environment first, so that a
+// program may reuse the name of a builtin function or module.
+func evalIdentifier(identifier *ast.Identifier, env *object.Environment) object.Object {
+	if value, ok := env.Get(identifier.Value); ok {
+		return value
+	}
+
+	if module, ok := stdlib.Lookup(identifier.Value); ok {
+		return module
+	}
+
+	return newError("белгисиз идентификатор: %s", identifier.Value)
+}
+
+func evalExpressions(expressions []ast.Expression, env *object.Environment) []object.Object {
+	result := []object.Object{}
+
+	for _, expression := range expressions {
+		evaluated := Eval(expression, env)
+		if isError(evaluated) {
+			return []object.Object{evaluated}
+		}
+		result = append(result, evaluated)
+	}
+
+	return result
+}
+
+// callFunction applies function at call, made in env. An error raised
+// inside the function records the call on its way out.
+func callFunction(call *ast.CallExpression, env *object.Environment, function object.Object, args []object.Object) object.Object {
+	result := applyFunction(function, args)
+
+	err, ok := result.(*object.Error)
+	called, isFunction := function.(*object.Function)
+	if ok && isFunction && !err.Span.IsZero() {
+		err.Stack = append(err.Stack, object.Frame{Function: called.Name, File: env.File(), Position: ast.SpanOf(call).Start})
+	}
+
+	return result
+}
+
+func applyFunction(function object.Object, args []object.Object) object.Object {
+	switch function := function.(type) {
+	case *object.Function:
+		required := requiredPatterns(function.Parameters)
+		switch {
+		case required == len(function.Parameters) && len(args) != required:
+			return newError("%d аргумент керек, %d берилди", required, len(args))
+		case len(args) < required || len(args) > len(function.Parameters):
+			return newError("%d-%d аргумент керек, %d берилди", required, len(function.Parameters), len(args))
+		}
+
+		env := object.NewEnclosedEnvironment(function.Env)
+		for i, parameter := range function.Parameters {
+			var err object.Object
+			if i < len(args) {
+				err = destructure(parameter, args[i], env)
+			} else {
+				err = destructureDefault(parameter.(*ast.DefaultPattern), env)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		evaluated := Eval(function.Body, env)
+		if returnValue, ok := evaluated.(*object.ReturnValue); ok {
+			return returnValue.Value
+		}
+		if evaluated == nil {
+			return NULL
+		}
+		return evaluated
+	case *object.Builtin:
+		if result := function.Function(args...); result != nil {
+			return result
+		}
+		return NULL
+	default:
+		return newError("функция эмес: %s", function.Type())
+	}
+}
+
+func evalHashLiteral(hashLiteral *ast.HashLiteral, env *object.Environment) object.Object {
+	hash := object.NewHash()
+
+	for i, keyNode := range hashLiteral.Keys {
+		key := Eval(keyNode, env)
+		if isError(key) {
+			return key
+		}
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return newError("%s сөздүктүн ачкычы боло албайт", key.Type())
+		}
+
+		value := Eval(hashLiteral.Values[i], env)
+		if isError(value) {
+			return value
+		}
+		hash.Set(hashable, value)
+	}
+
+	return hash
+}
+
+func evalIndexExpression(left object.Object, index object.Object) object.Object {
+	switch {
+	case left.Type() == object.HASH_OBJ:
+		hashable, ok := index.(object.Hashable)
+		if !ok {
+			return newError("%s сөздүктүн ачкычы боло албайт", index.Type())
+		}
+		if value, ok := left.(*object.Hash).Get(hashable); ok {
+			return value
+		}
+		return NULL
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+		elements := left.(*object.Array).Elements
+		i := index.(*object.Integer).Value
+		if index.(*object.Integer).Big != nil || i < 0 || i >= int64(len(elements)) {
+			return NULL
+		}
+		return elements[i]
+	case left.Type() == object.MODULE_OBJ && index.Type() == object.STRING_OBJ:
+		return evalModuleMember(left.(*object.Module), index.(*object.String).Value)
+	default:
+		return newError("индекс оператору колдоого алынбайт: %s[%s]", left.Type(), index.Type())
+	}
+}
+
+func evalMemberExpression(value object.Object, name string) object.Object {
+	module, ok := value.(*object.Module)
+	if !ok {
+		return newError("%s түрүнүн мүчөлөрү жок: .%s", value.Type(), name)
+	}
+
+	return evalModuleMember(module, name)
+}
+
+func evalModuleMember(module *object.Module, name string) object.Object {
+	member, ok := module.Members[name]
+	if !ok {
+		return newError("%s модулунда %s жок", module.Name, name)
+	}
+
+	return member
+}
+
+func isTruthy(value object.Object) bool {
+	switch value {
+	case NULL, FALSE:
+		return false
+	default:
+		return true
+	}
+}
+
+func newError(format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...)}
+}
+
+func isError(value object.Object) bool {
+	return value != nil && value.Type() == object.ERROR_OBJ
+}
+
+func isReturnValue(value object.Object) bool {
+	return value != nil && value.Type() == object.RETURN_VALUE_OBJ
+}
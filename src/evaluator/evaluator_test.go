@@ -0,0 +1,485 @@
+package evaluator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/object"
+	"github.com/asanoviskhak/alipp/src/parser"
+	"github.com/asanoviskhak/alipp/src/stdlib"
+)
+
+func testEval(t *testing.T, input string) object.Object {
+	parserInstance := parser.NewParser(lexer.New(input))
+	program := parserInstance.ParseProgram()
+	if errors := parserInstance.Errors(); len(errors) > 0 {
+		t.Fatalf("parser errors for %q: %v", input, errors)
+	}
+
+	return Eval(program, NewEnvironment(strings.NewReader(""), io.Discard, stdlib.Capabilities{}))
+}
+
+// testObject checks a value against an int, bool, string, []string for a
+// list of strings, or nil for бош.
+func testObject(t *testing.T, input string, actual object.Object, expected interface{}) {
+	t.Helper()
+
+	switch expected := expected.(type) {
+	case int:
+		integer, ok := actual.(*object.Integer)
+		if !ok || integer.Value != int64(expected) {
+			t.Errorf("%s: want=%d, got=%#v", input, expected, actual)
+		}
+	case bool:
+		if actual != object.NativeBoolean(expected) {
+			t.Errorf("%s: want=%t, got=%#v", input, expected, actual)
+		}
+	case string:
+		str, ok := actual.(*object.String)
+		if !ok || str.Value != expected {
+			t.Errorf("%s: want=%q, got=%#v", input, expected, actual)
+		}
+	case []string:
+		array, ok := actual.(*object.Array)
+		if !ok || len(array.Elements) != len(expected) {
+			t.Errorf("%s: want=%q, got=%#v", input, expected, actual)
+			return
+		}
+		for i, element := range array.Elements {
+			testObject(t, input, element, expected[i])
+		}
+	case nil:
+		if actual != NULL {
+			t.Errorf("%s: want=бош, got=%#v", input, actual)
+		}
+	}
+}
+
+func TestEvalExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"5", 5},
+		{"-10 + 2 * 3", -4},
+		{"(5 + 10 * 2 + 15 / 3) * 2 + -10", 50},
+		{"7 / 2", 3},
+		{"1 < 2", true},
+		{"(1 > 2) == ката", true},
+		{"!5", false},
+		{"!!туура", true},
+		{`"Салам" + ", " + "Дүйнө"`, "Салам, Дүйнө"},
+		{`"а" == "а"`, true},
+		{"эгер (1 < 2) { 10 } же { 20 }", 10},
+		{"эгер (ката) { 10 }", nil},
+		{"эгер (0) { 10 }", 10},
+		{"сакта a = 5; сакта b = a * 2; b", 10},
+		{"эгер (туура) { эгер (туура) { кайтар 10; } кайтар 1; }", 10},
+		{"сакта кош = функция(a, b) { a + b }; кош(1, кош(2, 3))", 6},
+		{"сакта кошуучу = функ(x) { функ(y) { x + y } }; кошуучу(2)(3)", 5},
+		{"сакта кош = функ(a: Сан, b: Сан): Сан { a + b }; сакта x: Сан = кош(1, 2); x", 3},
+		{"сакта факт = функ(n) { эгер (n < 2) { 1 } же { n * факт(n - 1) } }; факт(10)", 3628800},
+		{"[1, 2 * 2, 3][1]", 4},
+		{"сакта тизме = [1, 2]; тизме[2]", nil},
+		{"[1][-1]", nil},
+		{"[1][99999999999999999999]", nil},
+		{`{"а": 1, "б": 2}["б"]`, 2},
+		{`{"а": 1}["жок"]`, nil},
+		{`{1: "бир", туура: "ооба"}[1]`, "бир"},
+		{`{1: "бир", туура: "ооба"}[1 == 1]`, "ооба"},
+		{`{99999999999999999999: 1}[99999999999999999998 + 1]`, 1},
+		{`сакта ачкыч = "а"; {ачкыч: 5}["а"]`, 5},
+		{`["а", "б"]`, []string{"а", "б"}},
+		{"сакта сап = 1; сап", 1},
+	}
+
+	for _, test := range tests {
+		testObject(t, test.input, testEval(t, test.input), test.expected)
+	}
+}
+
+func TestErrorHandling(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"5 + туура;", "түрлөр дал келбейт: БҮТҮН_САН + ЛОГИКАЛЫК"},
+		{"5 + туура; 5;", "түрлөр дал келбейт: БҮТҮН_САН + ЛОГИКАЛЫК"},
+		{"-туура", "белгисиз оператор: -ЛОГИКАЛЫК"},
+		{"туура + ката;", "белгисиз оператор: ЛОГИКАЛЫК + ЛОГИКАЛЫК"},
+		{`"а" - "б"`, "белгисиз оператор: САП - САП"},
+		{"эгер (10 > 1) { эгер (10 > 1) { кайтар туура + ката; } кайтар 1; }", "белгисиз оператор: ЛОГИКАЛЫК + ЛОГИКАЛЫК"},
+		{"белгисиз", "белгисиз идентификатор: белгисиз"},
+		{"1 / 0", "нөлгө бөлүүгө болбойт"},
+		{"5(1)", "функция эмес: БҮТҮН_САН"},
+		{"функ(a) { a }(1, 2)", "1 аргумент керек, 2 берилди"},
+		{"1[0]", "индекс оператору колдоого алынбайт: БҮТҮН_САН[БҮТҮН_САН]"},
+		{"сап.жок", "сап модулунда жок жок"},
+		{"5.узундук", "БҮТҮН_САН түрүнүн мүчөлөрү жок: .узундук"},
+		{"сап[0]", "индекс оператору колдоого алынбайт: МОДУЛЬ[БҮТҮН_САН]"},
+		{"тышкы Math; Math.max(1, 2)", "тышкы JavaScript'ке компиляцияланган программада гана иштейт: alipp build колдонуңуз"},
+		{"сап.узундук(1)", "сап.узундук: 1-аргумент САП болушу керек, БҮТҮН_САН берилди"},
+		{`сап.бөл("а")`, "сап.бөл: 2 аргумент керек, 1 берилди"},
+		{`сап.бириктир(["а", 1], "")`, "сап.бириктир: тизменин 2-элементи БҮТҮН_САН, САП болушу керек"},
+		{"1.5 / 0", "нөлгө бөлүүгө болбойт"},
+		{"{[1]: 2}", "ТИЗМЕ сөздүктүн ачкычы боло албайт"},
+		{`{"а": 1}[1.5]`, "БӨЛЧӨК_САН сөздүктүн ачкычы боло албайт"},
+		{`{"а": белгисиз}`, "белгисиз идентификатор: белгисиз"},
+		{"99999999999999999999 / 0", "нөлгө бөлүүгө болбойт"},
+		{"математика.тамыр(-1)", "математика.тамыр: терс сандын тамыры жок"},
+		{"математика.даража(10, 1000000)", "математика.даража: натыйжа өтө чоң"},
+		{"математика.абсолют(\"а\")", "математика.абсолют: 1-аргумент сан болушу керек, САП берилди"},
+		{"математика.эң_чоң()", "математика.эң_чоң: жок дегенде бир аргумент керек"},
+		{"математика.кокус(0)", "математика.кокус: чек 1ден 2^53кө чейин болушу керек, 0 берилди"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+
+		err, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("%s: no error object returned. got=%#v", test.input, evaluated)
+			continue
+		}
+		if err.Message != test.message {
+			t.Errorf("%s: wrong error message. want=%q, got=%q", test.input, test.message, err.Message)
+		}
+	}
+}
+
+func TestStringsModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`сап.узундук("өңүт")`, 4},
+		{`сап.узундук("")`, 0},
+		{`сап.бөл("а,б,в", ",")`, []string{"а", "б", "в"}},
+		{`сап.бөл("өң", "")`, []string{"ө", "ң"}},
+		{`сап.бириктир(["а", "б"], "-")`, "а-б"},
+		{"сап.кырк(\"  салам\t\")", "салам"},
+		{`сап.алмаштыр("ала-була", "а", "о")`, "оло-було"},
+		{`сап.камтыйбы("Бишкек", "шке")`, true},
+		{`сап.камтыйбы("Бишкек", "Ош")`, false},
+		{`сап.чоң_тамга("өңүр ёлка")`, "ӨҢҮР ЁЛКА"},
+		{`сап.кичине_тамга("ӨҢҮР")`, "өңүр"},
+		{`сап.чоң_тамга("kıyın iş")`, "KIYIN İŞ"},
+		{`сап.кичине_тамга("KIYIN İŞ")`, "kıyın iş"},
+		{`сап.алфавит_боюнча("ң", "о")`, -1},
+		{`сап.алфавит_боюнча("я", "ө")`, 1},
+		{`сап.алфавит_боюнча("Ала", "ала")`, 1},
+		{`сап.иреттө(["үй", "ой", "ноо", "ңаа", "өрүк", "уул", "ёж", "жер", "ар"])`, []string{"ар", "ёж", "жер", "ноо", "ңаа", "ой", "өрүк", "уул", "үй"}},
+		{`сакта s = сап; s.узундук("аб")`, 2},
+		{`сап["узундук"]("аб")`, 2},
+	}
+
+	for _, test := range tests {
+		testObject(t, test.input, testEval(t, test.input), test.expected)
+	}
+}
+
+// TestNumbers checks values by how they print, since big integers and
+// floats have no native Go form to compare with.
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		big      bool
+	}{
+		{"9223372036854775807", "9223372036854775807", false},
+		{"9223372036854775807 + 1", "9223372036854775808", true},
+		{"9223372036854775807 + 1 - 1", "9223372036854775807", false},
+		{"-9223372036854775807 - 1", "-9223372036854775808", false},
+		{"-9223372036854775807 - 1 - 1", "-9223372036854775809", true},
+		{"(-9223372036854775807 - 1) / -1", "9223372036854775808", true},
+		{"(-9223372036854775807 - 1) * -1", "9223372036854775808", true},
+		{"-(-9223372036854775807 - 1)", "9223372036854775808", true},
+		{"3037000499 * 3037000499", "9223372030926249001", false},
+		{"3037000500 * 3037000500", "9223372037000250000", true},
+		{"-123456789012345678901234567890 / 7", "-17636684144620811271604938270", true},
+		{"99999999999999999999 > 9223372036854775807", "туура", false},
+		{"7.5 / 2", "3.75", false},
+		{"1 + 0.5", "1.5", false},
+		{"0.1 + 0.2", "0.30000000000000004", false},
+		{"2 == 2.0", "туура", false},
+		{"-1.5", "-1.5", false},
+		{"100000000000000000000.0 * 10", "1e+21", false},
+		{"1.0 / 3000000", "3.3333333333333335e-7", false},
+		{"математика.даража(2, 100)", "1267650600228229401496703205376", true},
+		{"математика.даража(2, -1)", "0.5", false},
+		{"математика.абсолют(-9223372036854775807 - 1)", "9223372036854775808", true},
+		{"математика.эң_чоң(3, 99999999999999999999, 2)", "99999999999999999999", true},
+		{"математика.эң_кичине(3, 1.5, 2)", "1.5", false},
+		{"математика.тамыр(16)", "4", false},
+		{"математика.төмөн_тегеректе(-2.5)", "-3", false},
+		{"математика.жогору_тегеректе(2.1)", "3", false},
+		{"математика.төмөн_тегеректе(100000000000000000000.0)", "100000000000000000000", true},
+		{"математика.үрөн(42); математика.кокус(100)", "60", false},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		if evaluated.Inspect() != test.expected {
+			t.Errorf("%s: want=%s, got=%s", test.input, test.expected, evaluated.Inspect())
+		}
+		if integer, ok := evaluated.(*object.Integer); ok && (integer.Big != nil) != test.big {
+			t.Errorf("%s: want big=%t, got=%#v", test.input, test.big, integer)
+		}
+	}
+}
+
+func TestConsoleBuiltins(t *testing.T) {
+	input := `сакта аты = окуу(); жаз("Салам, {}!", аты); көрсөтүү(окуу())`
+
+	parserInstance := parser.NewParser(lexer.New(input))
+	program := parserInstance.ParseProgram()
+	var out bytes.Buffer
+	evaluated := Eval(program, NewEnvironment(strings.NewReader("Айбек\n"), &out, stdlib.Capabilities{}))
+
+	if evaluated != NULL {
+		t.Errorf("wrong result. got=%#v", evaluated)
+	}
+	if expected := "Салам, Айбек!\nбош\n"; out.String() != expected {
+		t.Errorf("wrong output. want=%q, got=%q", expected, out.String())
+	}
+
+	testObject(t, "сакта көрсөтүү = 1; көрсөтүү", testEval(t, "сакта көрсөтүү = 1; көрсөтүү"), 1)
+
+	// The builtins come only with the streams NewEnvironment is given.
+	unbound := Eval(parser.NewParser(lexer.New("көрсөтүү(1)")).ParseProgram(), object.NewEnvironment())
+	if err, ok := unbound.(*object.Error); !ok || err.Message != "белгисиз идентификатор: көрсөтүү" {
+		t.Errorf("builtin found outside NewEnvironment. got=%#v", unbound)
+	}
+}
+
+func TestHashOrder(t *testing.T) {
+	evaluated := testEval(t, `{"б": 1, "а": 2, "б": 3}`)
+
+	hash, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("object is not Hash. got=%#v", evaluated)
+	}
+	if expected := "{б: 3, а: 2}"; hash.Inspect() != expected {
+		t.Errorf("wrong hash. want=%s, got=%s", expected, hash.Inspect())
+	}
+}
+
+func TestErrorPlaces(t *testing.T) {
+	tests := []struct {
+		input string
+		span  string
+		stack []string
+	}{
+		{"5 + туура;", "1:1-1:10", nil},
+		{"сакта x = 1;\nкөрсөтүү(x, белгисиз)", "2:13-2:21", nil},
+		{"[1](2)", "1:1-1:7", nil},
+		{"сакта f = функ(a) { a };\nf(1, 2)", "2:1-2:8", nil},
+		{`сап.узундук(1)`, "1:1-1:15", nil},
+		{"сакта f = функ(n) {\n  эгер (n < 1) { -туура } же { f(n - 1) }\n};\nf(2)", "2:18-2:24", []string{"f 2:32", "f 2:32", "f 4:1"}},
+		{"сакта g = функ() { функ() { 1 / 0 } };\ng()()", "1:29-1:34", []string{" 2:1"}},
+		{"сакта [a, b] = [1];", "1:7-1:13", nil},
+		{"сакта f = функ(x, {у}) { у };\nf(1, {})", "1:19-1:22", []string{"f 2:1"}},
+	}
+
+	for _, test := range tests {
+		err, ok := testEval(t, test.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: no error object returned", test.input)
+			continue
+		}
+
+		span := fmt.Sprintf("%d:%d-%d:%d", err.Span.Start.Line, err.Span.Start.Column, err.Span.End.Line, err.Span.End.Column)
+		if span != test.span {
+			t.Errorf("%s: wrong span. want=%s, got=%s", test.input, test.span, span)
+		}
+
+		stack := []string{}
+		for _, frame := range err.Stack {
+			stack = append(stack, fmt.Sprintf("%s %d:%d", frame.Function, frame.Position.Line, frame.Position.Column))
+		}
+		if strings.Join(stack, ", ") != strings.Join(test.stack, ", ") {
+			t.Errorf("%s: wrong stack. want=%v, got=%v", test.input, test.stack, stack)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	source := "сакта бөл = функ(a, b) {\n\ta / b\n};\nбөл(1, 0);\n"
+	err := testEval(t, source).(*object.Error)
+
+	expected := "бөлүү.alipp:2:2: нөлгө бөлүүгө болбойт\n" +
+		" 2 | \ta / b\n" +
+		"   | \t^^^^^\n" +
+		"  бөл чакырылган жер: бөлүү.alipp:4:1\n"
+	if report := Report(err, "бөлүү.alipp", source); report != expected {
+		t.Errorf("wrong report.\nwant=%q\ngot= %q", expected, report)
+	}
+
+	if report := Report(&object.Error{Message: "ката"}, "x.alipp", ""); report != "x.alipp: ката\n" {
+		t.Errorf("wrong report for an error without a place: %q", report)
+	}
+}
+
+func TestDescribeError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"ката_билдирүү(функ() { 1 })", "бош"},
+		{"ката_билдирүү(функ() { 1 + \"а\" })", "{билдирүү: түрлөр дал келбейт: БҮТҮН_САН + САП, сап: 1, тилке: 24, чакыруулар: []}"},
+		{"сакта f = функ() { [1](2) };\nката_билдирүү(функ() { f() })", "{билдирүү: функция эмес: ТИЗМЕ, сап: 1, тилке: 20, чакыруулар: [{функция: f, сап: 2, тилке: 24}]}"},
+		{"ката_билдирүү(окуу)", "бош"},
+		{"ката_билдирүү(1)", "КАТА: ката_билдирүү: 1-аргумент ФУНКЦИЯ болушу керек, БҮТҮН_САН берилди"},
+		{"ката_билдирүү(функ(x) { x })", "КАТА: ката_билдирүү: функция аргументсиз болушу керек, 1 параметри бар"},
+	}
+
+	for _, test := range tests {
+		parserInstance := parser.NewParser(lexer.New(test.input))
+		program := parserInstance.ParseProgram()
+		evaluated := Eval(program, NewEnvironment(strings.NewReader(""), io.Discard, stdlib.Capabilities{}))
+
+		if evaluated.Inspect() != test.expected {
+			t.Errorf("%s: want=%s, got=%s", test.input, test.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestTryStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		output   string
+		expected string
+	}{
+		{`аракет { көрсөтүү(1); ыргыт "жок"; көрсөтүү(2); } кармоо (e) { көрсөтүү(e); }`, "1\nжок\n", "бош"},
+		{`аракет { 1 / 0 } кармоо (e) { көрсөтүү(e) } акыры { көрсөтүү("акыры") }`, "нөлгө бөлүүгө болбойт\nакыры\n", "бош"},
+		{`аракет { ыргыт {"код": 7}; } кармоо (e) { e["код"] }`, "", "бош"},
+		{`аракет { ыргыт {"код": 7}; } кармоо (e) { сакта код = e["код"]; } код`, "", "7"},
+		{`аракет { 1 } кармоо (e) { 2 } e`, "", "КАТА: белгисиз идентификатор: e"},
+		// Nested handlers: the inner one rethrows to the outer one.
+		{`аракет { аракет { ыргыт 1; } кармоо (e) { ыргыт e + 1; } акыры { көрсөтүү("ички") } } кармоо (e) { көрсөтүү("тышкы", e) }`, "ички\nтышкы 2\n", "бош"},
+		{`аракет { аракет { ыргыт 1; } акыры { көрсөтүү("акыры") } } кармоо (e) { көрсөтүү(e) }`, "акыры\n1\n", "бош"},
+		{`аракет { json.оку("[") } кармоо { көрсөтүү("кармалды") }`, "кармалды\n", "бош"},
+		// акыры runs on the way out of a function through кайтар.
+		{`сакта f = функ() { аракет { кайтар 1; } акыры { көрсөтүү("акыры") } 2 }; f()`, "акыры\n", "1"},
+		{`сакта f = функ() { аракет { кайтар 1; } акыры { кайтар 2; } }; f()`, "", "2"},
+		{`сакта f = функ() { аракет { ыргыт 1; } акыры { кайтар 2; } }; f()`, "", "2"},
+		{`сакта f = функ() { аракет { кайтар 1; } кармоо (e) { кайтар 2; } }; f()`, "", "1"},
+		{`сакта f = функ() { аракет { ыргыт "x"; } кармоо (e) { кайтар e + "!"; } }; f()`, "", "x!"},
+		{`аракет { 1 } акыры { ыргыт "акырыда"; }`, "", "КАТА: акырыда"},
+		{`ыргыт [1, "эки"]`, "", "КАТА: [1, эки]"},
+		{`ыргыт белгисиз`, "", "КАТА: белгисиз идентификатор: белгисиз"},
+	}
+
+	for _, test := range tests {
+		parserInstance := parser.NewParser(lexer.New(test.input))
+		program := parserInstance.ParseProgram()
+		if errors := parserInstance.Errors(); len(errors) > 0 {
+			t.Fatalf("parser errors for %q: %v", test.input, errors)
+		}
+
+		var out bytes.Buffer
+		evaluated := Eval(program, NewEnvironment(strings.NewReader(""), &out, stdlib.Capabilities{}))
+		if evaluated == nil {
+			evaluated = NULL
+		}
+
+		if out.String() != test.output {
+			t.Errorf("%s: wrong output. want=%q, got=%q", test.input, test.output, out.String())
+		}
+		if evaluated.Inspect() != test.expected {
+			t.Errorf("%s: want=%s, got=%s", test.input, test.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMatchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`салыштыр (2) { 1 => "бир", 2 => "эки", _ => "көп" }`, "эки"},
+		{`салыштыр (7) { 1 => "бир", _ => "көп" }`, "көп"},
+		{`салыштыр (-1) { -1 => "минус бир", n => n }`, "минус бир"},
+		{`салыштыр (2.0) { 2 => "эки" }`, "эки"},
+		{`салыштыр ("а") { 1 => "сан", "а" => "сап" }`, "сап"},
+		{`салыштыр ([1, [2, 3]]) { [a] => a, [a, [b, c]] => a + b + c }`, "6"},
+		{`салыштыр ([1, 2]) { [a, b, c] => 3, [] => 0, _ => "башка" }`, "башка"},
+		{`салыштыр ({"аты": "Айбек", "жашы": 20}) { {"аты": n, "жашы": 18} => 18, {"аты": n} => n }`, "Айбек"},
+		{`салыштыр ({"а": 1}) { {"б": x} => x, {} => "бош эмес" }`, "бош эмес"},
+		{`салыштыр (5) { n эгер n < 0 => "терс", n эгер n > 0 => "оң", _ => "нөл" }`, "оң"},
+		{`салыштыр (1 < 2) { туура => "ооба", ката => "жок" }`, "ооба"},
+		{`салыштыр ([1, 2, 3]) { [h, ...t] => [h, t] }`, "[1, [2, 3]]"},
+		{`салыштыр ([]) { [h, ...t] => h, [...t] => t }`, "[]"},
+		{`салыштыр ({"аты": "Ош"}) { { аты } => аты }`, "Ош"},
+		{`сакта n = 1; салыштыр (2) { n => n }; n`, "1"},
+		{`сакта баш = функ(x) { салыштыр (x) { [h, _] => h, _ => "жок" } }; [баш([1, 2]), баш(1)]`, `[1, жок]`},
+		{`салыштыр (3) { 1 => "бир", 2 => "эки" }`, "КАТА: салыштыр: эч бир үлгү дал келген жок: 3"},
+		{`салыштыр (1) { n эгер белгисиз => n }`, "КАТА: белгисиз идентификатор: белгисиз"},
+	}
+
+	for _, test := range tests {
+		if evaluated := testEval(t, test.input); evaluated.Inspect() != test.expected {
+			t.Errorf("%s: want=%s, got=%s", test.input, test.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`сакта [a, b, ...калган] = [1, 2, 3, 4]; [a, b, калган]`, "[1, 2, [3, 4]]"},
+		{`сакта [a, ...калган] = [1]; калган`, "[]"},
+		{`сакта [_, [b, c]] = [1, [2, 3]]; b + c`, "5"},
+		{`сакта { аты, жашы = 0 } = {"аты": "Айбек"}; [аты, жашы]`, "[Айбек, 0]"},
+		{`сакта { жашы = 0 } = {"жашы": эгер (ката) { 1 }}; жашы`, "бош"},
+		{`сакта {"аты": n, 1: [x]} = {1: [2], "аты": "Ош"}; [n, x]`, "[Ош, 2]"},
+		{`сакта [a = 1, b = a + 1] = []; [a, b]`, "[1, 2]"},
+		{`сакта [a = белгисиз] = [1]; a`, "1"},
+		{`сакта f = функ([a, b], { аты }) { a + b + аты }; f([1, 2], {"аты": 3})`, "6"},
+		{`сакта баш = функ([h, ...t]) { h }; баш([5, 6])`, "5"},
+		{`сакта [a, b] = [1, 2, 3];`, "КАТА: тизмеде 2 элемент күтүлгөн, 3 берилди"},
+		{`сакта [a, b = 2] = [];`, "КАТА: тизмеде 1-2 элемент күтүлгөн, 0 берилди"},
+		{`сакта [a, b, ...c] = [1];`, "КАТА: тизмеде жок дегенде 2 элемент күтүлгөн, 1 берилди"},
+		{`сакта [a] = {"а": 1};`, "КАТА: тизме күтүлгөн, СӨЗДҮК берилди"},
+		{`сакта { аты } = [1];`, "КАТА: сөздүк күтүлгөн, ТИЗМЕ берилди"},
+		{`сакта { аты } = {"жашы": 1};`, "КАТА: сөздүктө аты ачкычы жок"},
+		{`сакта [a = белгисиз] = [];`, "КАТА: белгисиз идентификатор: белгисиз"},
+		{`функ([a]) { a }(5)`, "КАТА: тизме күтүлгөн, БҮТҮН_САН берилди"},
+		{`сакта f = функ(a, b = a + 1, [c] = [3]) { [a, b, c] }; [f(1), f(1, 5), f(1, 2, [4])]`, "[[1, 2, 3], [1, 5, 3], [1, 2, 4]]"},
+		{`функ(a = 1, b) { [a, b] }(2, 3)`, "[2, 3]"},
+		{`функ(a, b = 1) { a }()`, "КАТА: 1-2 аргумент керек, 0 берилди"},
+		{`функ(a = 1) { a }(1, 2)`, "КАТА: 0-1 аргумент керек, 2 берилди"},
+		{`функ(a = белгисиз) { a }()`, "КАТА: белгисиз идентификатор: белгисиз"},
+	}
+
+	for _, test := range tests {
+		if evaluated := testEval(t, test.input); evaluated.Inspect() != test.expected {
+			t.Errorf("%s: want=%s, got=%s", test.input, test.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestThrownErrors(t *testing.T) {
+	source := "сакта текшер = функ(x) {\n  эгер (x < 0) { ыргыт \"терс сан\"; }\n  x\n};\nтекшер(-1);\n"
+	err, ok := testEval(t, source).(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned")
+	}
+
+	if err.Value == nil || err.Value.Inspect() != "терс сан" {
+		t.Errorf("wrong thrown value. got=%#v", err.Value)
+	}
+	expected := "текшер.alipp:2:18: терс сан\n" +
+		" 2 |   эгер (x < 0) { ыргыт \"терс сан\"; }\n" +
+		"   |                  ^^^^^^^^^^^^^^^^\n" +
+		"  текшер чакырылган жер: текшер.alipp:5:1\n"
+	if report := Report(err, "текшер.alipp", source); report != expected {
+		t.Errorf("wrong report.\nwant=%q\ngot= %q", expected, report)
+	}
+}
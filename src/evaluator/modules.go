@@ -0,0 +1,76 @@
+package evaluator
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+// Modules runs programs made of modules. Every module is evaluated once,
+// in an environment of its own, however many modules import it.
+type Modules struct {
+	loader         *loader.Loader
+	newEnvironment func() *object.Environment
+	exports        map[*loader.Module]*object.Module
+}
+
+// NewModules returns modules that import through loaderInstance and
+// evaluate in the environments newEnvironment returns.
+func NewModules(loaderInstance *loader.Loader, newEnvironment func() *object.Environment) *Modules {
+	return &Modules{loader: loaderInstance, newEnvironment: newEnvironment, exports: map[*loader.Module]*object.Module{}}
+}
+
+// Run evaluates modules in the order the loader returned them, skipping
+// the ones evaluated before, and returns the result of the last one.
+func (modules *Modules) Run(order []*loader.Module) object.Object {
+	var result object.Object
+
+	for _, module := range order {
+		if _, ok := modules.exports[module]; ok {
+			continue
+		}
+
+		env := modules.newEnvironment()
+		env.SetModule(module.Name, modules.Importer(filepath.Dir(module.Path)))
+		if result = Eval(module.Program, env); isError(result) {
+			return result
+		}
+
+		members := map[string]object.Object{}
+		for _, name := range module.Exports {
+			if value, ok := env.Get(name); ok {
+				members[name] = value
+			}
+		}
+		modules.exports[module] = &object.Module{Name: module.Name, Members: members}
+	}
+
+	return result
+}
+
+// Importer returns the importer of a module in directory. It loads and
+// evaluates the module an import names, unless that was done before.
+func (modules *Modules) Importer(directory string) object.Importer {
+	return func(path string) object.Object {
+		resolved, err := modules.loader.Resolve(directory, path)
+		if err != nil {
+			return newError("%s", err)
+		}
+
+		order, errors := modules.loader.Load(resolved)
+		if len(errors) > 0 {
+			messages := make([]string, len(errors))
+			for i, loadError := range errors {
+				messages[i] = loadError.Error()
+			}
+			return newError("%s", strings.Join(messages, "\n"))
+		}
+
+		if result := modules.Run(order); isError(result) {
+			return result
+		}
+		return modules.exports[order[len(order)-1]]
+	}
+}
@@ -0,0 +1,90 @@
+package evaluator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/object"
+	"github.com/asanoviskhak/alipp/src/stdlib"
+)
+
+// runModules writes files under a new directory, runs the one called
+// main.alipp and returns what it printed, its result and its modules.
+func runModules(t *testing.T, files map[string]string) (string, object.Object, []*loader.Module) {
+	t.Helper()
+
+	directory := t.TempDir()
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaderInstance := loader.New(nil)
+	modules, errors := loaderInstance.Load(filepath.Join(directory, "main.alipp"))
+	if len(errors) > 0 {
+		t.Fatalf("load errors: %v", errors)
+	}
+
+	var out bytes.Buffer
+	runner := NewModules(loaderInstance, func() *object.Environment {
+		return NewEnvironment(strings.NewReader(""), &out, stdlib.Capabilities{})
+	})
+	result := runner.Run(modules)
+
+	return out.String(), result, modules
+}
+
+func TestModules(t *testing.T) {
+	output, result, _ := runModules(t, map[string]string{
+		"main.alipp": `импорт { кош, эсеп } "./math"; импорт { квадрат } "./square";
+көрсөтүү("негизги");
+[кош(1, 2), квадрат(3), эсеп(), эсеп()]`,
+		"math.alipp": `импорт { квадрат } "./square";
+көрсөтүү("math");
+сакта саноо = 0;
+экспорт сакта кош = функ(a, b) { a + b };
+экспорт сакта эсеп = функ() { квадрат(2) + саноо };
+сакта ичинде = 1;`,
+		"square.alipp": `көрсөтүү("square");
+экспорт сакта квадрат = функ(x) { x * x };
+экспорт сакта квадрат = функ(x) { x * x * 1 };`,
+	})
+
+	if output != "square\nmath\nнегизги\n" {
+		t.Errorf("modules did not run once each, in order. got=%q", output)
+	}
+	if result.Inspect() != "[3, 9, 4, 4]" {
+		t.Errorf("wrong result. got=%s", result.Inspect())
+	}
+}
+
+func TestModuleErrors(t *testing.T) {
+	_, result, modules := runModules(t, map[string]string{
+		"main.alipp": "импорт { бөл } \"./div\";\n\nбөл(1, 0);\n",
+		"div.alipp":  "экспорт сакта бөл = функ(a, b) {\n  a / b\n};\n",
+	})
+
+	err, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%#v", result)
+	}
+
+	div, main := modules[0].Name, modules[1].Name
+	expected := div + ":2:3: нөлгө бөлүүгө болбойт\n" +
+		" 2 |   a / b\n" +
+		"   |   ^^^^^\n" +
+		"  бөл чакырылган жер: " + main + ":3:1\n"
+	if report := ReportModules(err, modules); report != expected {
+		t.Errorf("wrong report.\nwant=%q\ngot= %q", expected, report)
+	}
+
+	imported := testEval(t, `импорт { x } "./x";`)
+	if imported.Inspect() != "КАТА: импорт файлдан жүктөлгөн программада гана иштейт" {
+		t.Errorf("wrong error for an import outside a module. got=%s", imported.Inspect())
+	}
+}
@@ -0,0 +1,149 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+func isNumber(value object.Object) bool {
+	return value.Type() == object.INTEGER_OBJ || value.Type() == object.FLOAT_OBJ
+}
+
+func toFloat(value object.Object) float64 {
+	if integer, ok := value.(*object.Integer); ok {
+		return integer.Float64()
+	}
+	return value.(*object.Float).Value
+}
+
+// evalNumberInfixExpression applies an operator to two numbers. An
+// integer operand meets a float one as a float.
+func evalNumberInfixExpression(operator string, left object.Object, right object.Object) object.Object {
+	leftInteger, leftIsInteger := left.(*object.Integer)
+	rightInteger, rightIsInteger := right.(*object.Integer)
+	if leftIsInteger && rightIsInteger {
+		return evalIntegerInfixExpression(operator, leftInteger, rightInteger)
+	}
+
+	return evalFloatInfixExpression(operator, toFloat(left), toFloat(right))
+}
+
+// evalIntegerInfixExpression computes with int64 as long as the result
+// fits, and with big.Int once it does not.
+func evalIntegerInfixExpression(operator string, left *object.Integer, right *object.Integer) object.Object {
+	if left.Big == nil && right.Big == nil {
+		if result, ok := evalSmallIntegerInfixExpression(operator, left.Value, right.Value); ok {
+			return result
+		}
+	}
+
+	return evalBigIntegerInfixExpression(operator, left.BigValue(), right.BigValue())
+}
+
+// evalSmallIntegerInfixExpression reports false when the result
+// overflows an int64.
+func evalSmallIntegerInfixExpression(operator string, left int64, right int64) (object.Object, bool) {
+	switch operator {
+	case "+":
+		sum := left + right
+		return &object.Integer{Value: sum}, (sum > left) == (right > 0)
+	case "-":
+		difference := left - right
+		return &object.Integer{Value: difference}, (difference < left) == (right > 0)
+	case "*":
+		if left == 0 || right == 0 {
+			return &object.Integer{Value: 0}, true
+		}
+		product := left * right
+		overflows := product/right != left || left == -1 && right == math.MinInt64 || right == -1 && left == math.MinInt64
+		return &object.Integer{Value: product}, !overflows
+	case "/":
+		if right == 0 {
+			return newError("нөлгө бөлүүгө болбойт"), true
+		}
+		if left == math.MinInt64 && right == -1 {
+			return nil, false
+		}
+		return &object.Integer{Value: left / right}, true
+	case "<":
+		return object.NativeBoolean(left < right), true
+	case ">":
+		return object.NativeBoolean(left > right), true
+	case "==":
+		return object.NativeBoolean(left == right), true
+	case "!=":
+		return object.NativeBoolean(left != right), true
+	default:
+		return newError("белгисиз оператор: %s %s %s", object.INTEGER_OBJ, operator, object.INTEGER_OBJ), true
+	}
+}
+
+func evalBigIntegerInfixExpression(operator string, left *big.Int, right *big.Int) object.Object {
+	switch operator {
+	case "+":
+		return object.NewInteger(left.Add(left, right))
+	case "-":
+		return object.NewInteger(left.Sub(left, right))
+	case "*":
+		return object.NewInteger(left.Mul(left, right))
+	case "/":
+		if right.Sign() == 0 {
+			return newError("нөлгө бөлүүгө болбойт")
+		}
+		// Quo truncates towards zero like int64 division does.
+		return object.NewInteger(left.Quo(left, right))
+	case "<":
+		return object.NativeBoolean(left.Cmp(right) < 0)
+	case ">":
+		return object.NativeBoolean(left.Cmp(right) > 0)
+	case "==":
+		return object.NativeBoolean(left.Cmp(right) == 0)
+	case "!=":
+		return object.NativeBoolean(left.Cmp(right) != 0)
+	default:
+		return newError("белгисиз оператор: %s %s %s", object.INTEGER_OBJ, operator, object.INTEGER_OBJ)
+	}
+}
+
+func evalFloatInfixExpression(operator string, left float64, right float64) object.Object {
+	switch operator {
+	case "+":
+		return &object.Float{Value: left + right}
+	case "-":
+		return &object.Float{Value: left - right}
+	case "*":
+		return &object.Float{Value: left * right}
+	case "/":
+		if right == 0 {
+			return newError("нөлгө бөлүүгө болбойт")
+		}
+		return &object.Float{Value: left / right}
+	case "<":
+		return object.NativeBoolean(left < right)
+	case ">":
+		return object.NativeBoolean(left > right)
+	case "==":
+		return object.NativeBoolean(left == right)
+	case "!=":
+		return object.NativeBoolean(left != right)
+	default:
+		return newError("белгисиз оператор: %s %s %s", object.FLOAT_OBJ, operator, object.FLOAT_OBJ)
+	}
+}
+
+func evalMinusPrefixExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		if right.Big == nil && right.Value != math.MinInt64 {
+			return &object.Integer{Value: -right.Value}
+		}
+		value := right.BigValue()
+		return object.NewInteger(value.Neg(value))
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
+		return newError("белгисиз оператор: -%s", right.Type())
+	}
+}
@@ -0,0 +1,95 @@
+package evaluator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+// maxFrames is how many calls a report lists before it leaves the rest
+// out, as deep recursion would otherwise fill the screen.
+const maxFrames = 20
+
+// Report describes err for the person running the program read from the
+// file name: where it happened and its message, the source line with the
+// place underlined, and the calls the error left.
+func Report(err *object.Error, name string, source string) string {
+	return ReportModules(err, []*loader.Module{{Name: name, Source: source}})
+}
+
+// ReportModules is Report for a program made of modules, in the order
+// the loader returned them. Errors and calls name the module they are
+// in, or the last one when they do not say.
+func ReportModules(err *object.Error, modules []*loader.Module) string {
+	sources := map[string]string{}
+	for _, module := range modules {
+		sources[module.Name] = module.Source
+	}
+	file := func(name string) string {
+		if name == "" {
+			return modules[len(modules)-1].Name
+		}
+		return name
+	}
+
+	name := file(err.File)
+	if err.Span.IsZero() {
+		return fmt.Sprintf("%s: %s\n", name, err.Message)
+	}
+
+	var out strings.Builder
+	start, end := err.Span.Start, err.Span.End
+	fmt.Fprintf(&out, "%s:%d:%d: %s\n", name, start.Line, start.Column, err.Message)
+
+	// The source of a module imported from outside the program, such as
+	// from the REPL, is not at hand.
+	lines := strings.Split(sources[name], "\n")
+	if _, ok := sources[name]; ok && start.Line <= len(lines) {
+		text := strings.TrimRight(lines[start.Line-1], "\r")
+		number := strconv.Itoa(start.Line)
+		fmt.Fprintf(&out, " %s | %s\n", number, text)
+
+		length := utf8.RuneCountInString(text) - start.Column + 1
+		if end.Line == start.Line {
+			length = min(end.Column-start.Column, length)
+		}
+		fmt.Fprintf(&out, " %s | %s%s\n", strings.Repeat(" ", len(number)), indentation(text, start.Column), strings.Repeat("^", max(length, 1)))
+	}
+
+	for i, frame := range err.Stack {
+		if i == maxFrames {
+			fmt.Fprintf(&out, "  ... дагы %d чакыруу\n", len(err.Stack)-maxFrames)
+			break
+		}
+
+		function := frame.Function
+		if function == "" {
+			function = "аты жок функция"
+		}
+		fmt.Fprintf(&out, "  %s чакырылган жер: %s:%d:%d\n", function, file(frame.File), frame.Position.Line, frame.Position.Column)
+	}
+
+	return out.String()
+}
+
+// indentation returns blanks as wide as the text before column, keeping
+// its tabs so that the underline lines up.
+func indentation(text string, column int) string {
+	var out strings.Builder
+	for i, r := range []rune(text) {
+		if i >= column-1 {
+			break
+		}
+		if r == '\t' {
+			out.WriteRune('\t')
+		} else {
+			out.WriteRune(' ')
+		}
+	}
+
+	return out.String()
+}
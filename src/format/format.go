@@ -0,0 +1,75 @@
+// Package format rewrites alipp source files without changing what they
+// mean, such as respelling their keywords in another language.
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+// Error points at something that keeps a file from being rewritten.
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Translate rewrites every keyword of source in the preferred spelling of
+// the target dictionary and points the "тил" comment at target, adding
+// one when target is not the default. Files without such a comment are
+// read with from. A nil target keeps the file's own dictionary, which
+// only normalises keyword aliases such as функ.
+//
+// Identifiers that are keywords of target cannot be translated and are
+// reported; source is returned unchanged then.
+func Translate(source string, from *token.Dictionary, target *token.Dictionary) (string, []Error) {
+	lexerInstance := lexer.New(source)
+	lexerInstance.UseDictionary(from)
+
+	var out strings.Builder
+	errors := []Error{}
+	position := 0
+
+	tok := lexerInstance.NextToken()
+
+	pragma, hasPragma := lexerInstance.Pragma()
+	if hasPragma && pragma.Type == token.ILLEGAL {
+		return source, []Error{{pragma.Line, pragma.Column, fmt.Sprintf("%q деген тил жок", pragma.Literal)}}
+	}
+	if target == nil {
+		target = lexerInstance.Dictionary()
+	}
+	if hasPragma {
+		out.WriteString(source[:pragma.Offset])
+		out.WriteString(target.Name)
+		position = pragma.Offset + len(pragma.Literal)
+	} else if target != token.Kyrgyz {
+		fmt.Fprintf(&out, "// тил: %s\n", target.Name)
+	}
+
+	for ; tok.Type != token.EOF; tok = lexerInstance.NextToken() {
+		if tok.Type == token.IDENT {
+			if keywordType := target.Lookup(tok.Literal); keywordType != token.IDENT {
+				errors = append(errors, Error{tok.Line, tok.Column, fmt.Sprintf("%q идентификатору %s тилинде ачкыч сөз", tok.Literal, target.Name)})
+			}
+			continue
+		}
+		if !token.IsKeyword(tok.Type) {
+			continue
+		}
+
+		out.WriteString(source[position:tok.Offset])
+		out.WriteString(target.Spelling(tok.Type))
+		position = tok.Offset + len(tok.Literal)
+	}
+	out.WriteString(source[position:])
+
+	if len(errors) > 0 {
+		return source, errors
+	}
+
+	return out.String(), errors
+}
@@ -0,0 +1,106 @@
+package format
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+func dictionary(t *testing.T, name string) *token.Dictionary {
+	t.Helper()
+
+	result, ok := token.LookupDictionary(name)
+	if !ok {
+		t.Fatalf("no dictionary %q", name)
+	}
+
+	return result
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		input    string
+		target   string
+		expected string
+	}{
+		{
+			"сакта f = функ(x) { эгер (x) { кайтар туура; } же { кайтар ката; } };",
+			"kk",
+			"// тил: kk\nсақта f = функция(x) { егер (x) { қайтар ақиқат; } әйтпесе { қайтар жалған; } };",
+		},
+		{
+			"// тил: kk\n// сақта деген түсүнүк\nсақта x = \"сақта\";",
+			"ky",
+			"// тил: ky\n// сақта деген түсүнүк\nсакта x = \"сақта\";",
+		},
+		{
+			"  //тил:tt\nсакла x = дөрес;",
+			"uz",
+			"  //тил:uz\nsaqla x = rost;",
+		},
+		{
+			"функ(x) { x };",
+			"",
+			"функция(x) { x };",
+		},
+	}
+
+	for _, tt := range tests {
+		var target *token.Dictionary
+		if tt.target != "" {
+			target = dictionary(t, tt.target)
+		}
+
+		actual, errors := Translate(tt.input, token.Kyrgyz, target)
+		if len(errors) != 0 {
+			t.Errorf("Translate(%q) failed: %v", tt.input, errors)
+			continue
+		}
+		if actual != tt.expected {
+			t.Errorf("Translate(%q, %s) wrong.\nwant=%q\ngot= %q", tt.input, tt.target, tt.expected, actual)
+		}
+	}
+}
+
+func TestTranslateDefaultDictionary(t *testing.T) {
+	actual, errors := Translate("saqla x = rost;", dictionary(t, "uz"), token.Kyrgyz)
+	if len(errors) != 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+	if expected := "сакта x = туура;"; actual != expected {
+		t.Errorf("wrong result. want=%q, got=%q", expected, actual)
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		target   string
+		expected []Error
+	}{
+		{
+			"сакта егер = 1;\nегер;",
+			"kk",
+			[]Error{
+				{1, 7, `"егер" идентификатору kk тилинде ачкыч сөз`},
+				{2, 1, `"егер" идентификатору kk тилинде ачкыч сөз`},
+			},
+		},
+		{
+			"// тил: xx\nсакта x = 1;",
+			"kk",
+			[]Error{{1, 9, `"xx" деген тил жок`}},
+		},
+	}
+
+	for _, tt := range tests {
+		actual, errors := Translate(tt.input, token.Kyrgyz, dictionary(t, tt.target))
+		if actual != tt.input {
+			t.Errorf("source was rewritten despite errors: %q", actual)
+		}
+		if fmt.Sprint(errors) != fmt.Sprint(tt.expected) {
+			t.Errorf("errors wrong.\nwant=%v\ngot= %v", tt.expected, errors)
+		}
+	}
+}
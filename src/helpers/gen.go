@@ -0,0 +1,346 @@
+//go:build ignore
+
+// gen.go writes tables.go, and the normalization tables of ../norm, from
+// the Unicode Character Database files in testdata. Run it with go
+// generate after replacing those files with a newer version.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tables lists the tables to generate and the UCD property values each
+// one is the union of.
+var tables = []struct {
+	name       string
+	doc        string
+	properties []string
+}{
+	{"Digit", "Digit is the set of Unicode characters in category Nd (Number, decimal digit).", []string{"Nd"}},
+	{"Letter", "Letter is the set of Unicode letters, category L.", []string{"Lu", "Ll", "Lt", "Lm", "Lo"}},
+	{"Mark", "Mark is the set of Unicode mark characters, category M.", []string{"Mn", "Mc", "Me"}},
+	{"White_Space", "White_Space is the set of Unicode characters with property White_Space.", []string{"White_Space"}},
+	{"XID_Start", "XID_Start is the set of characters that may start an identifier.", []string{"XID_Start"}},
+	{"XID_Continue", "XID_Continue is the set of characters that may follow the first one of an identifier.", []string{"XID_Continue"}},
+}
+
+var files = []string{"DerivedGeneralCategory.txt", "PropList.txt", "DerivedCoreProperties.txt", "DerivedNormalizationProps.txt"}
+
+var versionLine = regexp.MustCompile(`^# \w+-(\d+\.\d+\.\d+)\.txt$`)
+
+func main() {
+	version := ""
+	sets := map[string]map[rune]bool{}
+
+	for _, file := range files {
+		fileVersion, err := parse("testdata/"+file, sets)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if version != "" && fileVersion != version {
+			log.Fatalf("%s is version %s, want %s", file, fileVersion, version)
+		}
+		version = fileVersion
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by gen.go from the files in testdata; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package helpers\n\n")
+	fmt.Fprintf(&out, "// Version is the Unicode edition the tables are derived from.\n")
+	fmt.Fprintf(&out, "const Version = %q\n\n", version)
+
+	latin1 := [256][]string{}
+	for _, table := range tables {
+		runes := []rune{}
+		for _, property := range table.properties {
+			if len(sets[property]) == 0 {
+				log.Fatalf("no code points with property %s", property)
+			}
+			for r := range sets[property] {
+				runes = append(runes, r)
+			}
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+		for _, r := range runes {
+			if r <= 0xFF {
+				latin1[r] = append(latin1[r], "p"+table.name)
+			}
+		}
+
+		fmt.Fprintf(&out, "// %s\nvar %s = %s\n\n", table.doc, table.name, rangeTable(runes))
+	}
+
+	fmt.Fprintf(&out, "// Bit masks for each code point under U+0100, for fast lookup.\nconst (\n")
+	for i, table := range tables {
+		if i == 0 {
+			fmt.Fprintf(&out, "p%s = 1 << iota\n", table.name)
+		} else {
+			fmt.Fprintf(&out, "p%s\n", table.name)
+		}
+	}
+	fmt.Fprintf(&out, ")\n\n")
+
+	fmt.Fprintf(&out, "var properties = [MaxLatin1 + 1]uint8{\n")
+	for r, names := range latin1 {
+		if len(names) > 0 {
+			fmt.Fprintf(&out, "0x%02X: %s, // %q\n", r, strings.Join(names, " | "), rune(r))
+		}
+	}
+	fmt.Fprintf(&out, "}\n")
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("tables.go", source, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := writeNormTables(version, sets["Full_Composition_Exclusion"]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeNormTables writes ../norm/tables.go from the canonical combining
+// classes and decompositions in UnicodeData.txt. Pairs are composed back
+// unless the precomposed character is a full composition exclusion.
+func writeNormTables(version string, exclusions map[rune]bool) error {
+	classes, decompositions, err := parseUnicodeData("testdata/UnicodeData.txt")
+	if err != nil {
+		return err
+	}
+	if len(exclusions) == 0 {
+		return fmt.Errorf("no code points with property Full_Composition_Exclusion")
+	}
+
+	sorted := func(set map[rune]bool) []rune {
+		runes := []rune{}
+		for r := range set {
+			runes = append(runes, r)
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+		return runes
+	}
+	decomposed, marks := map[rune]bool{}, map[rune]bool{}
+	for r := range decompositions {
+		decomposed[r] = true
+	}
+	for r := range classes {
+		marks[r] = true
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by ../helpers/gen.go from the files in ../helpers/testdata; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package norm\n\n")
+	fmt.Fprintf(&out, "// The tables below are taken from UnicodeData.txt and\n")
+	fmt.Fprintf(&out, "// DerivedNormalizationProps.txt of Unicode %s. Hangul syllables, which\n", version)
+	fmt.Fprintf(&out, "// UnicodeData.txt does not list one by one, are left as they are.\n\n")
+
+	fmt.Fprintf(&out, "// decompositions maps a character to its canonical decomposition, which\n// may itself decompose further.\n")
+	fmt.Fprintf(&out, "var decompositions = map[rune][]rune{\n")
+	for _, r := range sorted(decomposed) {
+		parts := []string{}
+		for _, part := range decompositions[r] {
+			parts = append(parts, fmt.Sprintf("0x%04X", part))
+		}
+		fmt.Fprintf(&out, "0x%04X: {%s},\n", r, strings.Join(parts, ", "))
+	}
+	fmt.Fprintf(&out, "}\n\n")
+
+	fmt.Fprintf(&out, "// combiningClasses holds the canonical combining class of every mark.\n// Runes missing from it are starters.\n")
+	fmt.Fprintf(&out, "var combiningClasses = map[rune]uint8{\n")
+	for _, r := range sorted(marks) {
+		fmt.Fprintf(&out, "0x%04X: %d,\n", r, classes[r])
+	}
+	fmt.Fprintf(&out, "}\n\n")
+
+	fmt.Fprintf(&out, "// compositions maps a starter and a following character back to the\n// precomposed one, leaving out composition exclusions.\n")
+	fmt.Fprintf(&out, "var compositions = map[[2]rune]rune{\n")
+	for _, r := range sorted(decomposed) {
+		if decomposition := decompositions[r]; len(decomposition) == 2 && !exclusions[r] {
+			fmt.Fprintf(&out, "{0x%04X, 0x%04X}: 0x%04X,\n", decomposition[0], decomposition[1], r)
+		}
+	}
+	fmt.Fprintf(&out, "}\n")
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile("../norm/tables.go", source, 0644)
+}
+
+// parseUnicodeData returns the nonzero canonical combining classes and
+// the canonical decompositions of UnicodeData.txt. Compatibility
+// decompositions, which start with a <tag>, are left out.
+func parseUnicodeData(path string) (map[rune]uint8, map[rune][]rune, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	classes, decompositions := map[rune]uint8{}, map[rune][]rune{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		if len(fields) < 6 {
+			return nil, nil, fmt.Errorf("%s:%d: malformed line", path, line)
+		}
+
+		r, _, err := codePoints(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+
+		class, err := strconv.ParseUint(fields[3], 10, 8)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		if class != 0 {
+			classes[r] = uint8(class)
+		}
+
+		if fields[5] == "" || strings.HasPrefix(fields[5], "<") {
+			continue
+		}
+		for _, part := range strings.Fields(fields[5]) {
+			decomposed, _, err := codePoints(part)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s:%d: %v", path, line, err)
+			}
+			decompositions[r] = append(decompositions[r], decomposed)
+		}
+	}
+
+	return classes, decompositions, scanner.Err()
+}
+
+// parse adds the code points of every "range ; property" line of a UCD
+// file to sets and returns the Unicode version named on its first line.
+func parse(path string, sets map[string]map[rune]bool) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	version := ""
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if line == 1 {
+			match := versionLine.FindStringSubmatch(text)
+			if match == nil {
+				return "", fmt.Errorf("%s: no version on the first line", path)
+			}
+			version = match[1]
+		}
+
+		if comment := strings.IndexByte(text, '#'); comment >= 0 {
+			text = text[:comment]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		if len(fields) < 2 {
+			return "", fmt.Errorf("%s:%d: malformed line", path, line)
+		}
+
+		lo, hi, err := codePoints(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return "", fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+
+		property := strings.TrimSpace(fields[1])
+		if sets[property] == nil {
+			sets[property] = map[rune]bool{}
+		}
+		for r := lo; r <= hi; r++ {
+			sets[property][r] = true
+		}
+	}
+
+	return version, scanner.Err()
+}
+
+// codePoints parses "0041" or "0041..005A".
+func codePoints(text string) (rune, rune, error) {
+	first, last, isRange := strings.Cut(text, "..")
+	lo, err := strconv.ParseUint(first, 16, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return rune(lo), rune(lo), nil
+	}
+
+	hi, err := strconv.ParseUint(last, 16, 32)
+	return rune(lo), rune(hi), err
+}
+
+// rangeTable renders sorted runes as a RangeTable literal, merging runs
+// of evenly spaced code points into one range with a stride.
+func rangeTable(runes []rune) string {
+	type span struct{ lo, hi, stride rune }
+	spans := []span{}
+	for i := 0; i < len(runes); {
+		current := span{runes[i], runes[i], 1}
+		j := i + 1
+		if j < len(runes) && (runes[i] > 0xFFFF) == (runes[j] > 0xFFFF) {
+			current.stride = runes[j] - runes[i]
+			for j < len(runes) && runes[j]-current.hi == current.stride && (runes[j] > 0xFFFF) == (runes[i] > 0xFFFF) {
+				current.hi = runes[j]
+				j++
+			}
+		}
+		spans = append(spans, current)
+		i = j
+	}
+
+	var r16, r32 strings.Builder
+	latinOffset := 0
+	for _, current := range spans {
+		if current.hi <= 0xFFFF {
+			fmt.Fprintf(&r16, "{0x%04x, 0x%04x, %d},\n", current.lo, current.hi, current.stride)
+			if current.hi <= 0xFF {
+				latinOffset++
+			}
+		} else {
+			fmt.Fprintf(&r32, "{0x%x, 0x%x, %d},\n", current.lo, current.hi, current.stride)
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "&RangeTable{\n")
+	if r16.Len() > 0 {
+		fmt.Fprintf(&out, "R16: []Range16{\n%s},\n", r16.String())
+	}
+	if r32.Len() > 0 {
+		fmt.Fprintf(&out, "R32: []Range32{\n%s},\n", r32.String())
+	}
+	if latinOffset > 0 {
+		fmt.Fprintf(&out, "LatinOffset: %d,\n", latinOffset)
+	}
+	fmt.Fprintf(&out, "}")
+
+	return out.String()
+}
@@ -2,12 +2,13 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package unicode provides data and functions to test some properties of
-// Unicode code points.
-// The properties are based on the character properties in the Unicode
-
+// Package helpers provides the Unicode properties the lexer needs. It is
+// a trimmed down copy of the standard unicode package whose tables are
+// generated by gen.go from the UCD files in testdata.
 package helpers
 
+//go:generate go run gen.go
+
 const (
 	MaxRune         = '\U0010FFFF' // Maximum valid Unicode code point.
 	ReplacementChar = '\uFFFD'     // Represents invalid code points.
@@ -47,1002 +48,6 @@ type Range32 struct {
 	Stride uint32
 }
 
-// Bit masks for each code point under U+0100, for fast lookup.
-const (
-	pC     = 1 << iota // a control character.
-	pP                 // a punctuation character.
-	pN                 // a numeral.
-	pS                 // a symbolic character.
-	pZ                 // a spacing character.
-	pLu                // an upper-case letter.
-	pLl                // a lower-case letter.
-	pp                 // a printable character according to Go's definition.
-	pg     = pp | pZ   // a graphical character according to the Unicode definition.
-	pLo    = pLl | pLu // a letter that is neither upper nor lower case.
-	pLmask = pLo
-)
-
-var properties = [MaxLatin1 + 1]uint8{
-	0x00: pC,       // '\x00'
-	0x01: pC,       // '\x01'
-	0x02: pC,       // '\x02'
-	0x03: pC,       // '\x03'
-	0x04: pC,       // '\x04'
-	0x05: pC,       // '\x05'
-	0x06: pC,       // '\x06'
-	0x07: pC,       // '\a'
-	0x08: pC,       // '\b'
-	0x09: pC,       // '\t'
-	0x0A: pC,       // '\n'
-	0x0B: pC,       // '\v'
-	0x0C: pC,       // '\f'
-	0x0D: pC,       // '\r'
-	0x0E: pC,       // '\x0e'
-	0x0F: pC,       // '\x0f'
-	0x10: pC,       // '\x10'
-	0x11: pC,       // '\x11'
-	0x12: pC,       // '\x12'
-	0x13: pC,       // '\x13'
-	0x14: pC,       // '\x14'
-	0x15: pC,       // '\x15'
-	0x16: pC,       // '\x16'
-	0x17: pC,       // '\x17'
-	0x18: pC,       // '\x18'
-	0x19: pC,       // '\x19'
-	0x1A: pC,       // '\x1a'
-	0x1B: pC,       // '\x1b'
-	0x1C: pC,       // '\x1c'
-	0x1D: pC,       // '\x1d'
-	0x1E: pC,       // '\x1e'
-	0x1F: pC,       // '\x1f'
-	0x20: pZ | pp,  // ' '
-	0x21: pP | pp,  // '!'
-	0x22: pP | pp,  // '"'
-	0x23: pP | pp,  // '#'
-	0x24: pS | pp,  // '$'
-	0x25: pP | pp,  // '%'
-	0x26: pP | pp,  // '&'
-	0x27: pP | pp,  // '\''
-	0x28: pP | pp,  // '('
-	0x29: pP | pp,  // ')'
-	0x2A: pP | pp,  // '*'
-	0x2B: pS | pp,  // '+'
-	0x2C: pP | pp,  // ','
-	0x2D: pP | pp,  // '-'
-	0x2E: pP | pp,  // '.'
-	0x2F: pP | pp,  // '/'
-	0x30: pN | pp,  // '0'
-	0x31: pN | pp,  // '1'
-	0x32: pN | pp,  // '2'
-	0x33: pN | pp,  // '3'
-	0x34: pN | pp,  // '4'
-	0x35: pN | pp,  // '5'
-	0x36: pN | pp,  // '6'
-	0x37: pN | pp,  // '7'
-	0x38: pN | pp,  // '8'
-	0x39: pN | pp,  // '9'
-	0x3A: pP | pp,  // ':'
-	0x3B: pP | pp,  // ';'
-	0x3C: pS | pp,  // '<'
-	0x3D: pS | pp,  // '='
-	0x3E: pS | pp,  // '>'
-	0x3F: pP | pp,  // '?'
-	0x40: pP | pp,  // '@'
-	0x41: pLu | pp, // 'A'
-	0x42: pLu | pp, // 'B'
-	0x43: pLu | pp, // 'C'
-	0x44: pLu | pp, // 'D'
-	0x45: pLu | pp, // 'E'
-	0x46: pLu | pp, // 'F'
-	0x47: pLu | pp, // 'G'
-	0x48: pLu | pp, // 'H'
-	0x49: pLu | pp, // 'I'
-	0x4A: pLu | pp, // 'J'
-	0x4B: pLu | pp, // 'K'
-	0x4C: pLu | pp, // 'L'
-	0x4D: pLu | pp, // 'M'
-	0x4E: pLu | pp, // 'N'
-	0x4F: pLu | pp, // 'O'
-	0x50: pLu | pp, // 'P'
-	0x51: pLu | pp, // 'Q'
-	0x52: pLu | pp, // 'R'
-	0x53: pLu | pp, // 'S'
-	0x54: pLu | pp, // 'T'
-	0x55: pLu | pp, // 'U'
-	0x56: pLu | pp, // 'V'
-	0x57: pLu | pp, // 'W'
-	0x58: pLu | pp, // 'X'
-	0x59: pLu | pp, // 'Y'
-	0x5A: pLu | pp, // 'Z'
-	0x5B: pP | pp,  // '['
-	0x5C: pP | pp,  // '\\'
-	0x5D: pP | pp,  // ']'
-	0x5E: pS | pp,  // '^'
-	0x5F: pP | pp,  // '_'
-	0x60: pS | pp,  // '`'
-	0x61: pLl | pp, // 'a'
-	0x62: pLl | pp, // 'b'
-	0x63: pLl | pp, // 'c'
-	0x64: pLl | pp, // 'd'
-	0x65: pLl | pp, // 'e'
-	0x66: pLl | pp, // 'f'
-	0x67: pLl | pp, // 'g'
-	0x68: pLl | pp, // 'h'
-	0x69: pLl | pp, // 'i'
-	0x6A: pLl | pp, // 'j'
-	0x6B: pLl | pp, // 'k'
-	0x6C: pLl | pp, // 'l'
-	0x6D: pLl | pp, // 'm'
-	0x6E: pLl | pp, // 'n'
-	0x6F: pLl | pp, // 'o'
-	0x70: pLl | pp, // 'p'
-	0x71: pLl | pp, // 'q'
-	0x72: pLl | pp, // 'r'
-	0x73: pLl | pp, // 's'
-	0x74: pLl | pp, // 't'
-	0x75: pLl | pp, // 'u'
-	0x76: pLl | pp, // 'v'
-	0x77: pLl | pp, // 'w'
-	0x78: pLl | pp, // 'x'
-	0x79: pLl | pp, // 'y'
-	0x7A: pLl | pp, // 'z'
-	0x7B: pP | pp,  // '{'
-	0x7C: pS | pp,  // '|'
-	0x7D: pP | pp,  // '}'
-	0x7E: pS | pp,  // '~'
-	0x7F: pC,       // '\x7f'
-	0x80: pC,       // '\u0080'
-	0x81: pC,       // '\u0081'
-	0x82: pC,       // '\u0082'
-	0x83: pC,       // '\u0083'
-	0x84: pC,       // '\u0084'
-	0x85: pC,       // '\u0085'
-	0x86: pC,       // '\u0086'
-	0x87: pC,       // '\u0087'
-	0x88: pC,       // '\u0088'
-	0x89: pC,       // '\u0089'
-	0x8A: pC,       // '\u008a'
-	0x8B: pC,       // '\u008b'
-	0x8C: pC,       // '\u008c'
-	0x8D: pC,       // '\u008d'
-	0x8E: pC,       // '\u008e'
-	0x8F: pC,       // '\u008f'
-	0x90: pC,       // '\u0090'
-	0x91: pC,       // '\u0091'
-	0x92: pC,       // '\u0092'
-	0x93: pC,       // '\u0093'
-	0x94: pC,       // '\u0094'
-	0x95: pC,       // '\u0095'
-	0x96: pC,       // '\u0096'
-	0x97: pC,       // '\u0097'
-	0x98: pC,       // '\u0098'
-	0x99: pC,       // '\u0099'
-	0x9A: pC,       // '\u009a'
-	0x9B: pC,       // '\u009b'
-	0x9C: pC,       // '\u009c'
-	0x9D: pC,       // '\u009d'
-	0x9E: pC,       // '\u009e'
-	0x9F: pC,       // '\u009f'
-	0xA0: pZ,       // '\u00a0'
-	0xA1: pP | pp,  // '¡'
-	0xA2: pS | pp,  // '¢'
-	0xA3: pS | pp,  // '£'
-	0xA4: pS | pp,  // '¤'
-	0xA5: pS | pp,  // '¥'
-	0xA6: pS | pp,  // '¦'
-	0xA7: pP | pp,  // '§'
-	0xA8: pS | pp,  // '¨'
-	0xA9: pS | pp,  // '©'
-	0xAA: pLo | pp, // 'ª'
-	0xAB: pP | pp,  // '«'
-	0xAC: pS | pp,  // '¬'
-	0xAD: 0,        // '\u00ad'
-	0xAE: pS | pp,  // '®'
-	0xAF: pS | pp,  // '¯'
-	0xB0: pS | pp,  // '°'
-	0xB1: pS | pp,  // '±'
-	0xB2: pN | pp,  // '²'
-	0xB3: pN | pp,  // '³'
-	0xB4: pS | pp,  // '´'
-	0xB5: pLl | pp, // 'µ'
-	0xB6: pP | pp,  // '¶'
-	0xB7: pP | pp,  // '·'
-	0xB8: pS | pp,  // '¸'
-	0xB9: pN | pp,  // '¹'
-	0xBA: pLo | pp, // 'º'
-	0xBB: pP | pp,  // '»'
-	0xBC: pN | pp,  // '¼'
-	0xBD: pN | pp,  // '½'
-	0xBE: pN | pp,  // '¾'
-	0xBF: pP | pp,  // '¿'
-	0xC0: pLu | pp, // 'À'
-	0xC1: pLu | pp, // 'Á'
-	0xC2: pLu | pp, // 'Â'
-	0xC3: pLu | pp, // 'Ã'
-	0xC4: pLu | pp, // 'Ä'
-	0xC5: pLu | pp, // 'Å'
-	0xC6: pLu | pp, // 'Æ'
-	0xC7: pLu | pp, // 'Ç'
-	0xC8: pLu | pp, // 'È'
-	0xC9: pLu | pp, // 'É'
-	0xCA: pLu | pp, // 'Ê'
-	0xCB: pLu | pp, // 'Ë'
-	0xCC: pLu | pp, // 'Ì'
-	0xCD: pLu | pp, // 'Í'
-	0xCE: pLu | pp, // 'Î'
-	0xCF: pLu | pp, // 'Ï'
-	0xD0: pLu | pp, // 'Ð'
-	0xD1: pLu | pp, // 'Ñ'
-	0xD2: pLu | pp, // 'Ò'
-	0xD3: pLu | pp, // 'Ó'
-	0xD4: pLu | pp, // 'Ô'
-	0xD5: pLu | pp, // 'Õ'
-	0xD6: pLu | pp, // 'Ö'
-	0xD7: pS | pp,  // '×'
-	0xD8: pLu | pp, // 'Ø'
-	0xD9: pLu | pp, // 'Ù'
-	0xDA: pLu | pp, // 'Ú'
-	0xDB: pLu | pp, // 'Û'
-	0xDC: pLu | pp, // 'Ü'
-	0xDD: pLu | pp, // 'Ý'
-	0xDE: pLu | pp, // 'Þ'
-	0xDF: pLl | pp, // 'ß'
-	0xE0: pLl | pp, // 'à'
-	0xE1: pLl | pp, // 'á'
-	0xE2: pLl | pp, // 'â'
-	0xE3: pLl | pp, // 'ã'
-	0xE4: pLl | pp, // 'ä'
-	0xE5: pLl | pp, // 'å'
-	0xE6: pLl | pp, // 'æ'
-	0xE7: pLl | pp, // 'ç'
-	0xE8: pLl | pp, // 'è'
-	0xE9: pLl | pp, // 'é'
-	0xEA: pLl | pp, // 'ê'
-	0xEB: pLl | pp, // 'ë'
-	0xEC: pLl | pp, // 'ì'
-	0xED: pLl | pp, // 'í'
-	0xEE: pLl | pp, // 'î'
-	0xEF: pLl | pp, // 'ï'
-	0xF0: pLl | pp, // 'ð'
-	0xF1: pLl | pp, // 'ñ'
-	0xF2: pLl | pp, // 'ò'
-	0xF3: pLl | pp, // 'ó'
-	0xF4: pLl | pp, // 'ô'
-	0xF5: pLl | pp, // 'õ'
-	0xF6: pLl | pp, // 'ö'
-	0xF7: pS | pp,  // '÷'
-	0xF8: pLl | pp, // 'ø'
-	0xF9: pLl | pp, // 'ù'
-	0xFA: pLl | pp, // 'ú'
-	0xFB: pLl | pp, // 'û'
-	0xFC: pLl | pp, // 'ü'
-	0xFD: pLl | pp, // 'ý'
-	0xFE: pLl | pp, // 'þ'
-	0xFF: pLl | pp, // 'ÿ'
-}
-
-var _Nd = &RangeTable{
-	R16: []Range16{
-		{0x0030, 0x0039, 1},
-		{0x0660, 0x0669, 1},
-		{0x06f0, 0x06f9, 1},
-		{0x07c0, 0x07c9, 1},
-		{0x0966, 0x096f, 1},
-		{0x09e6, 0x09ef, 1},
-		{0x0a66, 0x0a6f, 1},
-		{0x0ae6, 0x0aef, 1},
-		{0x0b66, 0x0b6f, 1},
-		{0x0be6, 0x0bef, 1},
-		{0x0c66, 0x0c6f, 1},
-		{0x0ce6, 0x0cef, 1},
-		{0x0d66, 0x0d6f, 1},
-		{0x0de6, 0x0def, 1},
-		{0x0e50, 0x0e59, 1},
-		{0x0ed0, 0x0ed9, 1},
-		{0x0f20, 0x0f29, 1},
-		{0x1040, 0x1049, 1},
-		{0x1090, 0x1099, 1},
-		{0x17e0, 0x17e9, 1},
-		{0x1810, 0x1819, 1},
-		{0x1946, 0x194f, 1},
-		{0x19d0, 0x19d9, 1},
-		{0x1a80, 0x1a89, 1},
-		{0x1a90, 0x1a99, 1},
-		{0x1b50, 0x1b59, 1},
-		{0x1bb0, 0x1bb9, 1},
-		{0x1c40, 0x1c49, 1},
-		{0x1c50, 0x1c59, 1},
-		{0xa620, 0xa629, 1},
-		{0xa8d0, 0xa8d9, 1},
-		{0xa900, 0xa909, 1},
-		{0xa9d0, 0xa9d9, 1},
-		{0xa9f0, 0xa9f9, 1},
-		{0xaa50, 0xaa59, 1},
-		{0xabf0, 0xabf9, 1},
-		{0xff10, 0xff19, 1},
-	},
-	R32: []Range32{
-		{0x104a0, 0x104a9, 1},
-		{0x10d30, 0x10d39, 1},
-		{0x11066, 0x1106f, 1},
-		{0x110f0, 0x110f9, 1},
-		{0x11136, 0x1113f, 1},
-		{0x111d0, 0x111d9, 1},
-		{0x112f0, 0x112f9, 1},
-		{0x11450, 0x11459, 1},
-		{0x114d0, 0x114d9, 1},
-		{0x11650, 0x11659, 1},
-		{0x116c0, 0x116c9, 1},
-		{0x11730, 0x11739, 1},
-		{0x118e0, 0x118e9, 1},
-		{0x11950, 0x11959, 1},
-		{0x11c50, 0x11c59, 1},
-		{0x11d50, 0x11d59, 1},
-		{0x11da0, 0x11da9, 1},
-		{0x11f50, 0x11f59, 1},
-		{0x16a60, 0x16a69, 1},
-		{0x16ac0, 0x16ac9, 1},
-		{0x16b50, 0x16b59, 1},
-		{0x1d7ce, 0x1d7ff, 1},
-		{0x1e140, 0x1e149, 1},
-		{0x1e2f0, 0x1e2f9, 1},
-		{0x1e4f0, 0x1e4f9, 1},
-		{0x1e950, 0x1e959, 1},
-		{0x1fbf0, 0x1fbf9, 1},
-	},
-	LatinOffset: 1,
-}
-
-var _L = &RangeTable{
-	R16: []Range16{
-		{0x0041, 0x005a, 1},
-		{0x0061, 0x007a, 1},
-		{0x00aa, 0x00b5, 11},
-		{0x00ba, 0x00c0, 6},
-		{0x00c1, 0x00d6, 1},
-		{0x00d8, 0x00f6, 1},
-		{0x00f8, 0x02c1, 1},
-		{0x02c6, 0x02d1, 1},
-		{0x02e0, 0x02e4, 1},
-		{0x02ec, 0x02ee, 2},
-		{0x0370, 0x0374, 1},
-		{0x0376, 0x0377, 1},
-		{0x037a, 0x037d, 1},
-		{0x037f, 0x0386, 7},
-		{0x0388, 0x038a, 1},
-		{0x038c, 0x038e, 2},
-		{0x038f, 0x03a1, 1},
-		{0x03a3, 0x03f5, 1},
-		{0x03f7, 0x0481, 1},
-		{0x048a, 0x052f, 1},
-		{0x0531, 0x0556, 1},
-		{0x0559, 0x0560, 7},
-		{0x0561, 0x0588, 1},
-		{0x05d0, 0x05ea, 1},
-		{0x05ef, 0x05f2, 1},
-		{0x0620, 0x064a, 1},
-		{0x066e, 0x066f, 1},
-		{0x0671, 0x06d3, 1},
-		{0x06d5, 0x06e5, 16},
-		{0x06e6, 0x06ee, 8},
-		{0x06ef, 0x06fa, 11},
-		{0x06fb, 0x06fc, 1},
-		{0x06ff, 0x0710, 17},
-		{0x0712, 0x072f, 1},
-		{0x074d, 0x07a5, 1},
-		{0x07b1, 0x07ca, 25},
-		{0x07cb, 0x07ea, 1},
-		{0x07f4, 0x07f5, 1},
-		{0x07fa, 0x0800, 6},
-		{0x0801, 0x0815, 1},
-		{0x081a, 0x0824, 10},
-		{0x0828, 0x0840, 24},
-		{0x0841, 0x0858, 1},
-		{0x0860, 0x086a, 1},
-		{0x0870, 0x0887, 1},
-		{0x0889, 0x088e, 1},
-		{0x08a0, 0x08c9, 1},
-		{0x0904, 0x0939, 1},
-		{0x093d, 0x0950, 19},
-		{0x0958, 0x0961, 1},
-		{0x0971, 0x0980, 1},
-		{0x0985, 0x098c, 1},
-		{0x098f, 0x0990, 1},
-		{0x0993, 0x09a8, 1},
-		{0x09aa, 0x09b0, 1},
-		{0x09b2, 0x09b6, 4},
-		{0x09b7, 0x09b9, 1},
-		{0x09bd, 0x09ce, 17},
-		{0x09dc, 0x09dd, 1},
-		{0x09df, 0x09e1, 1},
-		{0x09f0, 0x09f1, 1},
-		{0x09fc, 0x0a05, 9},
-		{0x0a06, 0x0a0a, 1},
-		{0x0a0f, 0x0a10, 1},
-		{0x0a13, 0x0a28, 1},
-		{0x0a2a, 0x0a30, 1},
-		{0x0a32, 0x0a33, 1},
-		{0x0a35, 0x0a36, 1},
-		{0x0a38, 0x0a39, 1},
-		{0x0a59, 0x0a5c, 1},
-		{0x0a5e, 0x0a72, 20},
-		{0x0a73, 0x0a74, 1},
-		{0x0a85, 0x0a8d, 1},
-		{0x0a8f, 0x0a91, 1},
-		{0x0a93, 0x0aa8, 1},
-		{0x0aaa, 0x0ab0, 1},
-		{0x0ab2, 0x0ab3, 1},
-		{0x0ab5, 0x0ab9, 1},
-		{0x0abd, 0x0ad0, 19},
-		{0x0ae0, 0x0ae1, 1},
-		{0x0af9, 0x0b05, 12},
-		{0x0b06, 0x0b0c, 1},
-		{0x0b0f, 0x0b10, 1},
-		{0x0b13, 0x0b28, 1},
-		{0x0b2a, 0x0b30, 1},
-		{0x0b32, 0x0b33, 1},
-		{0x0b35, 0x0b39, 1},
-		{0x0b3d, 0x0b5c, 31},
-		{0x0b5d, 0x0b5f, 2},
-		{0x0b60, 0x0b61, 1},
-		{0x0b71, 0x0b83, 18},
-		{0x0b85, 0x0b8a, 1},
-		{0x0b8e, 0x0b90, 1},
-		{0x0b92, 0x0b95, 1},
-		{0x0b99, 0x0b9a, 1},
-		{0x0b9c, 0x0b9e, 2},
-		{0x0b9f, 0x0ba3, 4},
-		{0x0ba4, 0x0ba8, 4},
-		{0x0ba9, 0x0baa, 1},
-		{0x0bae, 0x0bb9, 1},
-		{0x0bd0, 0x0c05, 53},
-		{0x0c06, 0x0c0c, 1},
-		{0x0c0e, 0x0c10, 1},
-		{0x0c12, 0x0c28, 1},
-		{0x0c2a, 0x0c39, 1},
-		{0x0c3d, 0x0c58, 27},
-		{0x0c59, 0x0c5a, 1},
-		{0x0c5d, 0x0c60, 3},
-		{0x0c61, 0x0c80, 31},
-		{0x0c85, 0x0c8c, 1},
-		{0x0c8e, 0x0c90, 1},
-		{0x0c92, 0x0ca8, 1},
-		{0x0caa, 0x0cb3, 1},
-		{0x0cb5, 0x0cb9, 1},
-		{0x0cbd, 0x0cdd, 32},
-		{0x0cde, 0x0ce0, 2},
-		{0x0ce1, 0x0cf1, 16},
-		{0x0cf2, 0x0d04, 18},
-		{0x0d05, 0x0d0c, 1},
-		{0x0d0e, 0x0d10, 1},
-		{0x0d12, 0x0d3a, 1},
-		{0x0d3d, 0x0d4e, 17},
-		{0x0d54, 0x0d56, 1},
-		{0x0d5f, 0x0d61, 1},
-		{0x0d7a, 0x0d7f, 1},
-		{0x0d85, 0x0d96, 1},
-		{0x0d9a, 0x0db1, 1},
-		{0x0db3, 0x0dbb, 1},
-		{0x0dbd, 0x0dc0, 3},
-		{0x0dc1, 0x0dc6, 1},
-		{0x0e01, 0x0e30, 1},
-		{0x0e32, 0x0e33, 1},
-		{0x0e40, 0x0e46, 1},
-		{0x0e81, 0x0e82, 1},
-		{0x0e84, 0x0e86, 2},
-		{0x0e87, 0x0e8a, 1},
-		{0x0e8c, 0x0ea3, 1},
-		{0x0ea5, 0x0ea7, 2},
-		{0x0ea8, 0x0eb0, 1},
-		{0x0eb2, 0x0eb3, 1},
-		{0x0ebd, 0x0ec0, 3},
-		{0x0ec1, 0x0ec4, 1},
-		{0x0ec6, 0x0edc, 22},
-		{0x0edd, 0x0edf, 1},
-		{0x0f00, 0x0f40, 64},
-		{0x0f41, 0x0f47, 1},
-		{0x0f49, 0x0f6c, 1},
-		{0x0f88, 0x0f8c, 1},
-		{0x1000, 0x102a, 1},
-		{0x103f, 0x1050, 17},
-		{0x1051, 0x1055, 1},
-		{0x105a, 0x105d, 1},
-		{0x1061, 0x1065, 4},
-		{0x1066, 0x106e, 8},
-		{0x106f, 0x1070, 1},
-		{0x1075, 0x1081, 1},
-		{0x108e, 0x10a0, 18},
-		{0x10a1, 0x10c5, 1},
-		{0x10c7, 0x10cd, 6},
-		{0x10d0, 0x10fa, 1},
-		{0x10fc, 0x1248, 1},
-		{0x124a, 0x124d, 1},
-		{0x1250, 0x1256, 1},
-		{0x1258, 0x125a, 2},
-		{0x125b, 0x125d, 1},
-		{0x1260, 0x1288, 1},
-		{0x128a, 0x128d, 1},
-		{0x1290, 0x12b0, 1},
-		{0x12b2, 0x12b5, 1},
-		{0x12b8, 0x12be, 1},
-		{0x12c0, 0x12c2, 2},
-		{0x12c3, 0x12c5, 1},
-		{0x12c8, 0x12d6, 1},
-		{0x12d8, 0x1310, 1},
-		{0x1312, 0x1315, 1},
-		{0x1318, 0x135a, 1},
-		{0x1380, 0x138f, 1},
-		{0x13a0, 0x13f5, 1},
-		{0x13f8, 0x13fd, 1},
-		{0x1401, 0x166c, 1},
-		{0x166f, 0x167f, 1},
-		{0x1681, 0x169a, 1},
-		{0x16a0, 0x16ea, 1},
-		{0x16f1, 0x16f8, 1},
-		{0x1700, 0x1711, 1},
-		{0x171f, 0x1731, 1},
-		{0x1740, 0x1751, 1},
-		{0x1760, 0x176c, 1},
-		{0x176e, 0x1770, 1},
-		{0x1780, 0x17b3, 1},
-		{0x17d7, 0x17dc, 5},
-		{0x1820, 0x1878, 1},
-		{0x1880, 0x1884, 1},
-		{0x1887, 0x18a8, 1},
-		{0x18aa, 0x18b0, 6},
-		{0x18b1, 0x18f5, 1},
-		{0x1900, 0x191e, 1},
-		{0x1950, 0x196d, 1},
-		{0x1970, 0x1974, 1},
-		{0x1980, 0x19ab, 1},
-		{0x19b0, 0x19c9, 1},
-		{0x1a00, 0x1a16, 1},
-		{0x1a20, 0x1a54, 1},
-		{0x1aa7, 0x1b05, 94},
-		{0x1b06, 0x1b33, 1},
-		{0x1b45, 0x1b4c, 1},
-		{0x1b83, 0x1ba0, 1},
-		{0x1bae, 0x1baf, 1},
-		{0x1bba, 0x1be5, 1},
-		{0x1c00, 0x1c23, 1},
-		{0x1c4d, 0x1c4f, 1},
-		{0x1c5a, 0x1c7d, 1},
-		{0x1c80, 0x1c88, 1},
-		{0x1c90, 0x1cba, 1},
-		{0x1cbd, 0x1cbf, 1},
-		{0x1ce9, 0x1cec, 1},
-		{0x1cee, 0x1cf3, 1},
-		{0x1cf5, 0x1cf6, 1},
-		{0x1cfa, 0x1d00, 6},
-		{0x1d01, 0x1dbf, 1},
-		{0x1e00, 0x1f15, 1},
-		{0x1f18, 0x1f1d, 1},
-		{0x1f20, 0x1f45, 1},
-		{0x1f48, 0x1f4d, 1},
-		{0x1f50, 0x1f57, 1},
-		{0x1f59, 0x1f5f, 2},
-		{0x1f60, 0x1f7d, 1},
-		{0x1f80, 0x1fb4, 1},
-		{0x1fb6, 0x1fbc, 1},
-		{0x1fbe, 0x1fc2, 4},
-		{0x1fc3, 0x1fc4, 1},
-		{0x1fc6, 0x1fcc, 1},
-		{0x1fd0, 0x1fd3, 1},
-		{0x1fd6, 0x1fdb, 1},
-		{0x1fe0, 0x1fec, 1},
-		{0x1ff2, 0x1ff4, 1},
-		{0x1ff6, 0x1ffc, 1},
-		{0x2071, 0x207f, 14},
-		{0x2090, 0x209c, 1},
-		{0x2102, 0x2107, 5},
-		{0x210a, 0x2113, 1},
-		{0x2115, 0x2119, 4},
-		{0x211a, 0x211d, 1},
-		{0x2124, 0x212a, 2},
-		{0x212b, 0x212d, 1},
-		{0x212f, 0x2139, 1},
-		{0x213c, 0x213f, 1},
-		{0x2145, 0x2149, 1},
-		{0x214e, 0x2183, 53},
-		{0x2184, 0x2c00, 2684},
-		{0x2c01, 0x2ce4, 1},
-		{0x2ceb, 0x2cee, 1},
-		{0x2cf2, 0x2cf3, 1},
-		{0x2d00, 0x2d25, 1},
-		{0x2d27, 0x2d2d, 6},
-		{0x2d30, 0x2d67, 1},
-		{0x2d6f, 0x2d80, 17},
-		{0x2d81, 0x2d96, 1},
-		{0x2da0, 0x2da6, 1},
-		{0x2da8, 0x2dae, 1},
-		{0x2db0, 0x2db6, 1},
-		{0x2db8, 0x2dbe, 1},
-		{0x2dc0, 0x2dc6, 1},
-		{0x2dc8, 0x2dce, 1},
-		{0x2dd0, 0x2dd6, 1},
-		{0x2dd8, 0x2dde, 1},
-		{0x2e2f, 0x3005, 470},
-		{0x3006, 0x3031, 43},
-		{0x3032, 0x3035, 1},
-		{0x303b, 0x303c, 1},
-		{0x3041, 0x3096, 1},
-		{0x309d, 0x309f, 1},
-		{0x30a1, 0x30fa, 1},
-		{0x30fc, 0x30ff, 1},
-		{0x3105, 0x312f, 1},
-		{0x3131, 0x318e, 1},
-		{0x31a0, 0x31bf, 1},
-		{0x31f0, 0x31ff, 1},
-		{0x3400, 0x4dbf, 1},
-		{0x4e00, 0xa48c, 1},
-		{0xa4d0, 0xa4fd, 1},
-		{0xa500, 0xa60c, 1},
-		{0xa610, 0xa61f, 1},
-		{0xa62a, 0xa62b, 1},
-		{0xa640, 0xa66e, 1},
-		{0xa67f, 0xa69d, 1},
-		{0xa6a0, 0xa6e5, 1},
-		{0xa717, 0xa71f, 1},
-		{0xa722, 0xa788, 1},
-		{0xa78b, 0xa7ca, 1},
-		{0xa7d0, 0xa7d1, 1},
-		{0xa7d3, 0xa7d5, 2},
-		{0xa7d6, 0xa7d9, 1},
-		{0xa7f2, 0xa801, 1},
-		{0xa803, 0xa805, 1},
-		{0xa807, 0xa80a, 1},
-		{0xa80c, 0xa822, 1},
-		{0xa840, 0xa873, 1},
-		{0xa882, 0xa8b3, 1},
-		{0xa8f2, 0xa8f7, 1},
-		{0xa8fb, 0xa8fd, 2},
-		{0xa8fe, 0xa90a, 12},
-		{0xa90b, 0xa925, 1},
-		{0xa930, 0xa946, 1},
-		{0xa960, 0xa97c, 1},
-		{0xa984, 0xa9b2, 1},
-		{0xa9cf, 0xa9e0, 17},
-		{0xa9e1, 0xa9e4, 1},
-		{0xa9e6, 0xa9ef, 1},
-		{0xa9fa, 0xa9fe, 1},
-		{0xaa00, 0xaa28, 1},
-		{0xaa40, 0xaa42, 1},
-		{0xaa44, 0xaa4b, 1},
-		{0xaa60, 0xaa76, 1},
-		{0xaa7a, 0xaa7e, 4},
-		{0xaa7f, 0xaaaf, 1},
-		{0xaab1, 0xaab5, 4},
-		{0xaab6, 0xaab9, 3},
-		{0xaaba, 0xaabd, 1},
-		{0xaac0, 0xaac2, 2},
-		{0xaadb, 0xaadd, 1},
-		{0xaae0, 0xaaea, 1},
-		{0xaaf2, 0xaaf4, 1},
-		{0xab01, 0xab06, 1},
-		{0xab09, 0xab0e, 1},
-		{0xab11, 0xab16, 1},
-		{0xab20, 0xab26, 1},
-		{0xab28, 0xab2e, 1},
-		{0xab30, 0xab5a, 1},
-		{0xab5c, 0xab69, 1},
-		{0xab70, 0xabe2, 1},
-		{0xac00, 0xd7a3, 1},
-		{0xd7b0, 0xd7c6, 1},
-		{0xd7cb, 0xd7fb, 1},
-		{0xf900, 0xfa6d, 1},
-		{0xfa70, 0xfad9, 1},
-		{0xfb00, 0xfb06, 1},
-		{0xfb13, 0xfb17, 1},
-		{0xfb1d, 0xfb1f, 2},
-		{0xfb20, 0xfb28, 1},
-		{0xfb2a, 0xfb36, 1},
-		{0xfb38, 0xfb3c, 1},
-		{0xfb3e, 0xfb40, 2},
-		{0xfb41, 0xfb43, 2},
-		{0xfb44, 0xfb46, 2},
-		{0xfb47, 0xfbb1, 1},
-		{0xfbd3, 0xfd3d, 1},
-		{0xfd50, 0xfd8f, 1},
-		{0xfd92, 0xfdc7, 1},
-		{0xfdf0, 0xfdfb, 1},
-		{0xfe70, 0xfe74, 1},
-		{0xfe76, 0xfefc, 1},
-		{0xff21, 0xff3a, 1},
-		{0xff41, 0xff5a, 1},
-		{0xff66, 0xffbe, 1},
-		{0xffc2, 0xffc7, 1},
-		{0xffca, 0xffcf, 1},
-		{0xffd2, 0xffd7, 1},
-		{0xffda, 0xffdc, 1},
-	},
-	R32: []Range32{
-		{0x10000, 0x1000b, 1},
-		{0x1000d, 0x10026, 1},
-		{0x10028, 0x1003a, 1},
-		{0x1003c, 0x1003d, 1},
-		{0x1003f, 0x1004d, 1},
-		{0x10050, 0x1005d, 1},
-		{0x10080, 0x100fa, 1},
-		{0x10280, 0x1029c, 1},
-		{0x102a0, 0x102d0, 1},
-		{0x10300, 0x1031f, 1},
-		{0x1032d, 0x10340, 1},
-		{0x10342, 0x10349, 1},
-		{0x10350, 0x10375, 1},
-		{0x10380, 0x1039d, 1},
-		{0x103a0, 0x103c3, 1},
-		{0x103c8, 0x103cf, 1},
-		{0x10400, 0x1049d, 1},
-		{0x104b0, 0x104d3, 1},
-		{0x104d8, 0x104fb, 1},
-		{0x10500, 0x10527, 1},
-		{0x10530, 0x10563, 1},
-		{0x10570, 0x1057a, 1},
-		{0x1057c, 0x1058a, 1},
-		{0x1058c, 0x10592, 1},
-		{0x10594, 0x10595, 1},
-		{0x10597, 0x105a1, 1},
-		{0x105a3, 0x105b1, 1},
-		{0x105b3, 0x105b9, 1},
-		{0x105bb, 0x105bc, 1},
-		{0x10600, 0x10736, 1},
-		{0x10740, 0x10755, 1},
-		{0x10760, 0x10767, 1},
-		{0x10780, 0x10785, 1},
-		{0x10787, 0x107b0, 1},
-		{0x107b2, 0x107ba, 1},
-		{0x10800, 0x10805, 1},
-		{0x10808, 0x1080a, 2},
-		{0x1080b, 0x10835, 1},
-		{0x10837, 0x10838, 1},
-		{0x1083c, 0x1083f, 3},
-		{0x10840, 0x10855, 1},
-		{0x10860, 0x10876, 1},
-		{0x10880, 0x1089e, 1},
-		{0x108e0, 0x108f2, 1},
-		{0x108f4, 0x108f5, 1},
-		{0x10900, 0x10915, 1},
-		{0x10920, 0x10939, 1},
-		{0x10980, 0x109b7, 1},
-		{0x109be, 0x109bf, 1},
-		{0x10a00, 0x10a10, 16},
-		{0x10a11, 0x10a13, 1},
-		{0x10a15, 0x10a17, 1},
-		{0x10a19, 0x10a35, 1},
-		{0x10a60, 0x10a7c, 1},
-		{0x10a80, 0x10a9c, 1},
-		{0x10ac0, 0x10ac7, 1},
-		{0x10ac9, 0x10ae4, 1},
-		{0x10b00, 0x10b35, 1},
-		{0x10b40, 0x10b55, 1},
-		{0x10b60, 0x10b72, 1},
-		{0x10b80, 0x10b91, 1},
-		{0x10c00, 0x10c48, 1},
-		{0x10c80, 0x10cb2, 1},
-		{0x10cc0, 0x10cf2, 1},
-		{0x10d00, 0x10d23, 1},
-		{0x10e80, 0x10ea9, 1},
-		{0x10eb0, 0x10eb1, 1},
-		{0x10f00, 0x10f1c, 1},
-		{0x10f27, 0x10f30, 9},
-		{0x10f31, 0x10f45, 1},
-		{0x10f70, 0x10f81, 1},
-		{0x10fb0, 0x10fc4, 1},
-		{0x10fe0, 0x10ff6, 1},
-		{0x11003, 0x11037, 1},
-		{0x11071, 0x11072, 1},
-		{0x11075, 0x11083, 14},
-		{0x11084, 0x110af, 1},
-		{0x110d0, 0x110e8, 1},
-		{0x11103, 0x11126, 1},
-		{0x11144, 0x11147, 3},
-		{0x11150, 0x11172, 1},
-		{0x11176, 0x11183, 13},
-		{0x11184, 0x111b2, 1},
-		{0x111c1, 0x111c4, 1},
-		{0x111da, 0x111dc, 2},
-		{0x11200, 0x11211, 1},
-		{0x11213, 0x1122b, 1},
-		{0x1123f, 0x11240, 1},
-		{0x11280, 0x11286, 1},
-		{0x11288, 0x1128a, 2},
-		{0x1128b, 0x1128d, 1},
-		{0x1128f, 0x1129d, 1},
-		{0x1129f, 0x112a8, 1},
-		{0x112b0, 0x112de, 1},
-		{0x11305, 0x1130c, 1},
-		{0x1130f, 0x11310, 1},
-		{0x11313, 0x11328, 1},
-		{0x1132a, 0x11330, 1},
-		{0x11332, 0x11333, 1},
-		{0x11335, 0x11339, 1},
-		{0x1133d, 0x11350, 19},
-		{0x1135d, 0x11361, 1},
-		{0x11400, 0x11434, 1},
-		{0x11447, 0x1144a, 1},
-		{0x1145f, 0x11461, 1},
-		{0x11480, 0x114af, 1},
-		{0x114c4, 0x114c5, 1},
-		{0x114c7, 0x11580, 185},
-		{0x11581, 0x115ae, 1},
-		{0x115d8, 0x115db, 1},
-		{0x11600, 0x1162f, 1},
-		{0x11644, 0x11680, 60},
-		{0x11681, 0x116aa, 1},
-		{0x116b8, 0x11700, 72},
-		{0x11701, 0x1171a, 1},
-		{0x11740, 0x11746, 1},
-		{0x11800, 0x1182b, 1},
-		{0x118a0, 0x118df, 1},
-		{0x118ff, 0x11906, 1},
-		{0x11909, 0x1190c, 3},
-		{0x1190d, 0x11913, 1},
-		{0x11915, 0x11916, 1},
-		{0x11918, 0x1192f, 1},
-		{0x1193f, 0x11941, 2},
-		{0x119a0, 0x119a7, 1},
-		{0x119aa, 0x119d0, 1},
-		{0x119e1, 0x119e3, 2},
-		{0x11a00, 0x11a0b, 11},
-		{0x11a0c, 0x11a32, 1},
-		{0x11a3a, 0x11a50, 22},
-		{0x11a5c, 0x11a89, 1},
-		{0x11a9d, 0x11ab0, 19},
-		{0x11ab1, 0x11af8, 1},
-		{0x11c00, 0x11c08, 1},
-		{0x11c0a, 0x11c2e, 1},
-		{0x11c40, 0x11c72, 50},
-		{0x11c73, 0x11c8f, 1},
-		{0x11d00, 0x11d06, 1},
-		{0x11d08, 0x11d09, 1},
-		{0x11d0b, 0x11d30, 1},
-		{0x11d46, 0x11d60, 26},
-		{0x11d61, 0x11d65, 1},
-		{0x11d67, 0x11d68, 1},
-		{0x11d6a, 0x11d89, 1},
-		{0x11d98, 0x11ee0, 328},
-		{0x11ee1, 0x11ef2, 1},
-		{0x11f02, 0x11f04, 2},
-		{0x11f05, 0x11f10, 1},
-		{0x11f12, 0x11f33, 1},
-		{0x11fb0, 0x12000, 80},
-		{0x12001, 0x12399, 1},
-		{0x12480, 0x12543, 1},
-		{0x12f90, 0x12ff0, 1},
-		{0x13000, 0x1342f, 1},
-		{0x13441, 0x13446, 1},
-		{0x14400, 0x14646, 1},
-		{0x16800, 0x16a38, 1},
-		{0x16a40, 0x16a5e, 1},
-		{0x16a70, 0x16abe, 1},
-		{0x16ad0, 0x16aed, 1},
-		{0x16b00, 0x16b2f, 1},
-		{0x16b40, 0x16b43, 1},
-		{0x16b63, 0x16b77, 1},
-		{0x16b7d, 0x16b8f, 1},
-		{0x16e40, 0x16e7f, 1},
-		{0x16f00, 0x16f4a, 1},
-		{0x16f50, 0x16f93, 67},
-		{0x16f94, 0x16f9f, 1},
-		{0x16fe0, 0x16fe1, 1},
-		{0x16fe3, 0x17000, 29},
-		{0x17001, 0x187f7, 1},
-		{0x18800, 0x18cd5, 1},
-		{0x18d00, 0x18d08, 1},
-		{0x1aff0, 0x1aff3, 1},
-		{0x1aff5, 0x1affb, 1},
-		{0x1affd, 0x1affe, 1},
-		{0x1b000, 0x1b122, 1},
-		{0x1b132, 0x1b150, 30},
-		{0x1b151, 0x1b152, 1},
-		{0x1b155, 0x1b164, 15},
-		{0x1b165, 0x1b167, 1},
-		{0x1b170, 0x1b2fb, 1},
-		{0x1bc00, 0x1bc6a, 1},
-		{0x1bc70, 0x1bc7c, 1},
-		{0x1bc80, 0x1bc88, 1},
-		{0x1bc90, 0x1bc99, 1},
-		{0x1d400, 0x1d454, 1},
-		{0x1d456, 0x1d49c, 1},
-		{0x1d49e, 0x1d49f, 1},
-		{0x1d4a2, 0x1d4a5, 3},
-		{0x1d4a6, 0x1d4a9, 3},
-		{0x1d4aa, 0x1d4ac, 1},
-		{0x1d4ae, 0x1d4b9, 1},
-		{0x1d4bb, 0x1d4bd, 2},
-		{0x1d4be, 0x1d4c3, 1},
-		{0x1d4c5, 0x1d505, 1},
-		{0x1d507, 0x1d50a, 1},
-		{0x1d50d, 0x1d514, 1},
-		{0x1d516, 0x1d51c, 1},
-		{0x1d51e, 0x1d539, 1},
-		{0x1d53b, 0x1d53e, 1},
-		{0x1d540, 0x1d544, 1},
-		{0x1d546, 0x1d54a, 4},
-		{0x1d54b, 0x1d550, 1},
-		{0x1d552, 0x1d6a5, 1},
-		{0x1d6a8, 0x1d6c0, 1},
-		{0x1d6c2, 0x1d6da, 1},
-		{0x1d6dc, 0x1d6fa, 1},
-		{0x1d6fc, 0x1d714, 1},
-		{0x1d716, 0x1d734, 1},
-		{0x1d736, 0x1d74e, 1},
-		{0x1d750, 0x1d76e, 1},
-		{0x1d770, 0x1d788, 1},
-		{0x1d78a, 0x1d7a8, 1},
-		{0x1d7aa, 0x1d7c2, 1},
-		{0x1d7c4, 0x1d7cb, 1},
-		{0x1df00, 0x1df1e, 1},
-		{0x1df25, 0x1df2a, 1},
-		{0x1e030, 0x1e06d, 1},
-		{0x1e100, 0x1e12c, 1},
-		{0x1e137, 0x1e13d, 1},
-		{0x1e14e, 0x1e290, 322},
-		{0x1e291, 0x1e2ad, 1},
-		{0x1e2c0, 0x1e2eb, 1},
-		{0x1e4d0, 0x1e4eb, 1},
-		{0x1e7e0, 0x1e7e6, 1},
-		{0x1e7e8, 0x1e7eb, 1},
-		{0x1e7ed, 0x1e7ee, 1},
-		{0x1e7f0, 0x1e7fe, 1},
-		{0x1e800, 0x1e8c4, 1},
-		{0x1e900, 0x1e943, 1},
-		{0x1e94b, 0x1ee00, 1205},
-		{0x1ee01, 0x1ee03, 1},
-		{0x1ee05, 0x1ee1f, 1},
-		{0x1ee21, 0x1ee22, 1},
-		{0x1ee24, 0x1ee27, 3},
-		{0x1ee29, 0x1ee32, 1},
-		{0x1ee34, 0x1ee37, 1},
-		{0x1ee39, 0x1ee3b, 2},
-		{0x1ee42, 0x1ee47, 5},
-		{0x1ee49, 0x1ee4d, 2},
-		{0x1ee4e, 0x1ee4f, 1},
-		{0x1ee51, 0x1ee52, 1},
-		{0x1ee54, 0x1ee57, 3},
-		{0x1ee59, 0x1ee61, 2},
-		{0x1ee62, 0x1ee64, 2},
-		{0x1ee67, 0x1ee6a, 1},
-		{0x1ee6c, 0x1ee72, 1},
-		{0x1ee74, 0x1ee77, 1},
-		{0x1ee79, 0x1ee7c, 1},
-		{0x1ee7e, 0x1ee80, 2},
-		{0x1ee81, 0x1ee89, 1},
-		{0x1ee8b, 0x1ee9b, 1},
-		{0x1eea1, 0x1eea3, 1},
-		{0x1eea5, 0x1eea9, 1},
-		{0x1eeab, 0x1eebb, 1},
-		{0x20000, 0x2a6df, 1},
-		{0x2a700, 0x2b739, 1},
-		{0x2b740, 0x2b81d, 1},
-		{0x2b820, 0x2cea1, 1},
-		{0x2ceb0, 0x2ebe0, 1},
-		{0x2f800, 0x2fa1d, 1},
-		{0x30000, 0x3134a, 1},
-		{0x31350, 0x323af, 1},
-	},
-	LatinOffset: 6,
-}
-
-var _White_Space = &RangeTable{
-	R16: []Range16{
-		{0x0009, 0x000d, 1},
-		{0x0020, 0x0085, 101},
-		{0x00a0, 0x1680, 5600},
-		{0x2000, 0x200a, 1},
-		{0x2028, 0x2029, 1},
-		{0x202f, 0x205f, 48},
-		{0x3000, 0x3000, 1},
-	},
-	LatinOffset: 2,
-}
-
-var (
-	Digit       = _Nd          // Nd is the set of Unicode characters in category Nd (Number, decimal digit).
-	Letter      = _L           // Letter/L is the set of Unicode letters, category L.
-	White_Space = _White_Space // White_Space is the set of Unicode characters with property White_Space.
-)
-
 // is16 reports whether r is in the sorted slice of 16-bit ranges.
 func is16(ranges []Range16, r uint16) bool {
 	if len(ranges) <= linearMax || r <= MaxLatin1 {
@@ -1120,31 +125,51 @@ func IsDigit(r rune) bool {
 // IsLetter reports whether the rune is a letter (category [L]).
 func IsLetter(r rune) bool {
 	if uint32(r) <= MaxLatin1 {
-		return properties[uint8(r)]&(pLmask) != 0
+		return properties[uint8(r)]&pLetter != 0
 	}
 	return isExcludingLatin(Letter, r)
 }
 
+// IsMark reports whether the rune is a mark character (category [M]),
+// such as a combining accent.
+func IsMark(r rune) bool {
+	if uint32(r) <= MaxLatin1 {
+		return properties[uint8(r)]&pMark != 0
+	}
+	return isExcludingLatin(Mark, r)
+}
+
 // IsSpace reports whether the rune is a space character as defined
 // by Unicode's White Space property; in the Latin-1 space
 // this is
 //
 //	'\t', '\n', '\v', '\f', '\r', ' ', U+0085 (NEL), U+00A0 (NBSP).
-//
-// Other definitions of spacing characters are set by category
-// Z and property [Pattern_White_Space].
 func IsSpace(r rune) bool {
-	// This property isn't the same as Z; special-case it.
 	if uint32(r) <= MaxLatin1 {
-		switch r {
-		case '\t', '\n', '\v', '\f', '\r', ' ', 0x85, 0xA0:
-			return true
-		}
-		return false
+		return properties[uint8(r)]&pWhite_Space != 0
 	}
 	return isExcludingLatin(White_Space, r)
 }
 
+// IsXIDStart reports whether the rune may start an identifier, as
+// defined by Unicode's XID_Start property.
+func IsXIDStart(r rune) bool {
+	if uint32(r) <= MaxLatin1 {
+		return properties[uint8(r)]&pXID_Start != 0
+	}
+	return isExcludingLatin(XID_Start, r)
+}
+
+// IsXIDContinue reports whether the rune may continue an identifier, as
+// defined by Unicode's XID_Continue property. It includes the digits,
+// the connector punctuation such as '_' and the combining marks.
+func IsXIDContinue(r rune) bool {
+	if uint32(r) <= MaxLatin1 {
+		return properties[uint8(r)]&pXID_Continue != 0
+	}
+	return isExcludingLatin(XID_Continue, r)
+}
+
 func isExcludingLatin(rangeTab *RangeTable, r rune) bool {
 	r16 := rangeTab.R16
 	// Compare as uint32 to correctly handle negative runes.
@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// loadProperties reads the "range ; property" lines of a UCD file in
+// testdata into a set of code points per property, as gen.go does.
+func loadProperties(t *testing.T, file string, sets map[string]map[rune]bool) {
+	t.Helper()
+
+	input, err := os.Open("testdata/" + file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer input.Close()
+
+	scanner := bufio.NewScanner(input)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		if len(fields) < 2 {
+			t.Fatalf("%s:%d: malformed line", file, line)
+		}
+
+		first, last, isRange := strings.Cut(strings.TrimSpace(fields[0]), "..")
+		if !isRange {
+			last = first
+		}
+		lo, err := strconv.ParseUint(first, 16, 32)
+		if err != nil {
+			t.Fatalf("%s:%d: %v", file, line, err)
+		}
+		hi, err := strconv.ParseUint(last, 16, 32)
+		if err != nil {
+			t.Fatalf("%s:%d: %v", file, line, err)
+		}
+
+		property := strings.TrimSpace(fields[1])
+		if sets[property] == nil {
+			sets[property] = map[rune]bool{}
+		}
+		for r := rune(lo); r <= rune(hi); r++ {
+			sets[property][r] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	sets := map[string]map[rune]bool{}
+	for _, file := range []string{"DerivedGeneralCategory.txt", "PropList.txt", "DerivedCoreProperties.txt"} {
+		loadProperties(t, file, sets)
+	}
+
+	predicates := []struct {
+		name       string
+		actual     func(rune) bool
+		properties []string
+	}{
+		{"IsLetter", IsLetter, []string{"Lu", "Ll", "Lt", "Lm", "Lo"}},
+		{"IsDigit", IsDigit, []string{"Nd"}},
+		{"IsSpace", IsSpace, []string{"White_Space"}},
+		{"IsMark", IsMark, []string{"Mn", "Mc", "Me"}},
+		{"IsXIDStart", IsXIDStart, []string{"XID_Start"}},
+		{"IsXIDContinue", IsXIDContinue, []string{"XID_Continue"}},
+	}
+
+	for _, predicate := range predicates {
+		failures := 0
+		for r := rune(-1); r <= unicode.MaxRune+1; r++ {
+			expected := false
+			for _, property := range predicate.properties {
+				expected = expected || sets[property][r]
+			}
+			if actual := predicate.actual(r); actual != expected {
+				t.Errorf("%s(%U) wrong. want=%t, got=%t", predicate.name, r, expected, actual)
+				if failures++; failures == 10 {
+					break
+				}
+			}
+		}
+	}
+}
+
+// TestStandardLibrary compares the properties the standard library also
+// has, which catches UCD files that do not match the version they name.
+func TestStandardLibrary(t *testing.T) {
+	if Version != unicode.Version {
+		t.Skipf("tables are Unicode %s, the standard library is %s", Version, unicode.Version)
+	}
+
+	predicates := []struct {
+		name     string
+		actual   func(rune) bool
+		expected func(rune) bool
+	}{
+		{"IsLetter", IsLetter, unicode.IsLetter},
+		{"IsDigit", IsDigit, unicode.IsDigit},
+		{"IsSpace", IsSpace, unicode.IsSpace},
+		{"IsMark", IsMark, unicode.IsMark},
+	}
+
+	for _, predicate := range predicates {
+		failures := 0
+		for r := rune(-1); r <= unicode.MaxRune+1; r++ {
+			if actual, expected := predicate.actual(r), predicate.expected(r); actual != expected {
+				t.Errorf("%s(%U) wrong. want=%t, got=%t", predicate.name, r, expected, actual)
+				if failures++; failures == 10 {
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,2667 @@
+// Code generated by gen.go from the files in testdata; DO NOT EDIT.
+
+package helpers
+
+// Version is the Unicode edition the tables are derived from.
+const Version = "17.0.0"
+
+// Digit is the set of Unicode characters in category Nd (Number, decimal digit).
+var Digit = &RangeTable{
+	R16: []Range16{
+		{0x0030, 0x0039, 1},
+		{0x0660, 0x0669, 1},
+		{0x06f0, 0x06f9, 1},
+		{0x07c0, 0x07c9, 1},
+		{0x0966, 0x096f, 1},
+		{0x09e6, 0x09ef, 1},
+		{0x0a66, 0x0a6f, 1},
+		{0x0ae6, 0x0aef, 1},
+		{0x0b66, 0x0b6f, 1},
+		{0x0be6, 0x0bef, 1},
+		{0x0c66, 0x0c6f, 1},
+		{0x0ce6, 0x0cef, 1},
+		{0x0d66, 0x0d6f, 1},
+		{0x0de6, 0x0def, 1},
+		{0x0e50, 0x0e59, 1},
+		{0x0ed0, 0x0ed9, 1},
+		{0x0f20, 0x0f29, 1},
+		{0x1040, 0x1049, 1},
+		{0x1090, 0x1099, 1},
+		{0x17e0, 0x17e9, 1},
+		{0x1810, 0x1819, 1},
+		{0x1946, 0x194f, 1},
+		{0x19d0, 0x19d9, 1},
+		{0x1a80, 0x1a89, 1},
+		{0x1a90, 0x1a99, 1},
+		{0x1b50, 0x1b59, 1},
+		{0x1bb0, 0x1bb9, 1},
+		{0x1c40, 0x1c49, 1},
+		{0x1c50, 0x1c59, 1},
+		{0xa620, 0xa629, 1},
+		{0xa8d0, 0xa8d9, 1},
+		{0xa900, 0xa909, 1},
+		{0xa9d0, 0xa9d9, 1},
+		{0xa9f0, 0xa9f9, 1},
+		{0xaa50, 0xaa59, 1},
+		{0xabf0, 0xabf9, 1},
+		{0xff10, 0xff19, 1},
+	},
+	R32: []Range32{
+		{0x104a0, 0x104a9, 1},
+		{0x10d30, 0x10d39, 1},
+		{0x10d40, 0x10d49, 1},
+		{0x11066, 0x1106f, 1},
+		{0x110f0, 0x110f9, 1},
+		{0x11136, 0x1113f, 1},
+		{0x111d0, 0x111d9, 1},
+		{0x112f0, 0x112f9, 1},
+		{0x11450, 0x11459, 1},
+		{0x114d0, 0x114d9, 1},
+		{0x11650, 0x11659, 1},
+		{0x116c0, 0x116c9, 1},
+		{0x116d0, 0x116e3, 1},
+		{0x11730, 0x11739, 1},
+		{0x118e0, 0x118e9, 1},
+		{0x11950, 0x11959, 1},
+		{0x11bf0, 0x11bf9, 1},
+		{0x11c50, 0x11c59, 1},
+		{0x11d50, 0x11d59, 1},
+		{0x11da0, 0x11da9, 1},
+		{0x11de0, 0x11de9, 1},
+		{0x11f50, 0x11f59, 1},
+		{0x16130, 0x16139, 1},
+		{0x16a60, 0x16a69, 1},
+		{0x16ac0, 0x16ac9, 1},
+		{0x16b50, 0x16b59, 1},
+		{0x16d70, 0x16d79, 1},
+		{0x1ccf0, 0x1ccf9, 1},
+		{0x1d7ce, 0x1d7ff, 1},
+		{0x1e140, 0x1e149, 1},
+		{0x1e2f0, 0x1e2f9, 1},
+		{0x1e4f0, 0x1e4f9, 1},
+		{0x1e5f1, 0x1e5fa, 1},
+		{0x1e950, 0x1e959, 1},
+		{0x1fbf0, 0x1fbf9, 1},
+	},
+	LatinOffset: 1,
+}
+
+// Letter is the set of Unicode letters, category L.
+var Letter = &RangeTable{
+	R16: []Range16{
+		{0x0041, 0x005a, 1},
+		{0x0061, 0x007a, 1},
+		{0x00aa, 0x00b5, 11},
+		{0x00ba, 0x00c0, 6},
+		{0x00c1, 0x00d6, 1},
+		{0x00d8, 0x00f6, 1},
+		{0x00f8, 0x02c1, 1},
+		{0x02c6, 0x02d1, 1},
+		{0x02e0, 0x02e4, 1},
+		{0x02ec, 0x02ee, 2},
+		{0x0370, 0x0374, 1},
+		{0x0376, 0x0377, 1},
+		{0x037a, 0x037d, 1},
+		{0x037f, 0x0386, 7},
+		{0x0388, 0x038a, 1},
+		{0x038c, 0x038e, 2},
+		{0x038f, 0x03a1, 1},
+		{0x03a3, 0x03f5, 1},
+		{0x03f7, 0x0481, 1},
+		{0x048a, 0x052f, 1},
+		{0x0531, 0x0556, 1},
+		{0x0559, 0x0560, 7},
+		{0x0561, 0x0588, 1},
+		{0x05d0, 0x05ea, 1},
+		{0x05ef, 0x05f2, 1},
+		{0x0620, 0x064a, 1},
+		{0x066e, 0x066f, 1},
+		{0x0671, 0x06d3, 1},
+		{0x06d5, 0x06e5, 16},
+		{0x06e6, 0x06ee, 8},
+		{0x06ef, 0x06fa, 11},
+		{0x06fb, 0x06fc, 1},
+		{0x06ff, 0x0710, 17},
+		{0x0712, 0x072f, 1},
+		{0x074d, 0x07a5, 1},
+		{0x07b1, 0x07ca, 25},
+		{0x07cb, 0x07ea, 1},
+		{0x07f4, 0x07f5, 1},
+		{0x07fa, 0x0800, 6},
+		{0x0801, 0x0815, 1},
+		{0x081a, 0x0824, 10},
+		{0x0828, 0x0840, 24},
+		{0x0841, 0x0858, 1},
+		{0x0860, 0x086a, 1},
+		{0x0870, 0x0887, 1},
+		{0x0889, 0x088f, 1},
+		{0x08a0, 0x08c9, 1},
+		{0x0904, 0x0939, 1},
+		{0x093d, 0x0950, 19},
+		{0x0958, 0x0961, 1},
+		{0x0971, 0x0980, 1},
+		{0x0985, 0x098c, 1},
+		{0x098f, 0x0990, 1},
+		{0x0993, 0x09a8, 1},
+		{0x09aa, 0x09b0, 1},
+		{0x09b2, 0x09b6, 4},
+		{0x09b7, 0x09b9, 1},
+		{0x09bd, 0x09ce, 17},
+		{0x09dc, 0x09dd, 1},
+		{0x09df, 0x09e1, 1},
+		{0x09f0, 0x09f1, 1},
+		{0x09fc, 0x0a05, 9},
+		{0x0a06, 0x0a0a, 1},
+		{0x0a0f, 0x0a10, 1},
+		{0x0a13, 0x0a28, 1},
+		{0x0a2a, 0x0a30, 1},
+		{0x0a32, 0x0a33, 1},
+		{0x0a35, 0x0a36, 1},
+		{0x0a38, 0x0a39, 1},
+		{0x0a59, 0x0a5c, 1},
+		{0x0a5e, 0x0a72, 20},
+		{0x0a73, 0x0a74, 1},
+		{0x0a85, 0x0a8d, 1},
+		{0x0a8f, 0x0a91, 1},
+		{0x0a93, 0x0aa8, 1},
+		{0x0aaa, 0x0ab0, 1},
+		{0x0ab2, 0x0ab3, 1},
+		{0x0ab5, 0x0ab9, 1},
+		{0x0abd, 0x0ad0, 19},
+		{0x0ae0, 0x0ae1, 1},
+		{0x0af9, 0x0b05, 12},
+		{0x0b06, 0x0b0c, 1},
+		{0x0b0f, 0x0b10, 1},
+		{0x0b13, 0x0b28, 1},
+		{0x0b2a, 0x0b30, 1},
+		{0x0b32, 0x0b33, 1},
+		{0x0b35, 0x0b39, 1},
+		{0x0b3d, 0x0b5c, 31},
+		{0x0b5d, 0x0b5f, 2},
+		{0x0b60, 0x0b61, 1},
+		{0x0b71, 0x0b83, 18},
+		{0x0b85, 0x0b8a, 1},
+		{0x0b8e, 0x0b90, 1},
+		{0x0b92, 0x0b95, 1},
+		{0x0b99, 0x0b9a, 1},
+		{0x0b9c, 0x0b9e, 2},
+		{0x0b9f, 0x0ba3, 4},
+		{0x0ba4, 0x0ba8, 4},
+		{0x0ba9, 0x0baa, 1},
+		{0x0bae, 0x0bb9, 1},
+		{0x0bd0, 0x0c05, 53},
+		{0x0c06, 0x0c0c, 1},
+		{0x0c0e, 0x0c10, 1},
+		{0x0c12, 0x0c28, 1},
+		{0x0c2a, 0x0c39, 1},
+		{0x0c3d, 0x0c58, 27},
+		{0x0c59, 0x0c5a, 1},
+		{0x0c5c, 0x0c5d, 1},
+		{0x0c60, 0x0c61, 1},
+		{0x0c80, 0x0c85, 5},
+		{0x0c86, 0x0c8c, 1},
+		{0x0c8e, 0x0c90, 1},
+		{0x0c92, 0x0ca8, 1},
+		{0x0caa, 0x0cb3, 1},
+		{0x0cb5, 0x0cb9, 1},
+		{0x0cbd, 0x0cdc, 31},
+		{0x0cdd, 0x0cde, 1},
+		{0x0ce0, 0x0ce1, 1},
+		{0x0cf1, 0x0cf2, 1},
+		{0x0d04, 0x0d0c, 1},
+		{0x0d0e, 0x0d10, 1},
+		{0x0d12, 0x0d3a, 1},
+		{0x0d3d, 0x0d4e, 17},
+		{0x0d54, 0x0d56, 1},
+		{0x0d5f, 0x0d61, 1},
+		{0x0d7a, 0x0d7f, 1},
+		{0x0d85, 0x0d96, 1},
+		{0x0d9a, 0x0db1, 1},
+		{0x0db3, 0x0dbb, 1},
+		{0x0dbd, 0x0dc0, 3},
+		{0x0dc1, 0x0dc6, 1},
+		{0x0e01, 0x0e30, 1},
+		{0x0e32, 0x0e33, 1},
+		{0x0e40, 0x0e46, 1},
+		{0x0e81, 0x0e82, 1},
+		{0x0e84, 0x0e86, 2},
+		{0x0e87, 0x0e8a, 1},
+		{0x0e8c, 0x0ea3, 1},
+		{0x0ea5, 0x0ea7, 2},
+		{0x0ea8, 0x0eb0, 1},
+		{0x0eb2, 0x0eb3, 1},
+		{0x0ebd, 0x0ec0, 3},
+		{0x0ec1, 0x0ec4, 1},
+		{0x0ec6, 0x0edc, 22},
+		{0x0edd, 0x0edf, 1},
+		{0x0f00, 0x0f40, 64},
+		{0x0f41, 0x0f47, 1},
+		{0x0f49, 0x0f6c, 1},
+		{0x0f88, 0x0f8c, 1},
+		{0x1000, 0x102a, 1},
+		{0x103f, 0x1050, 17},
+		{0x1051, 0x1055, 1},
+		{0x105a, 0x105d, 1},
+		{0x1061, 0x1065, 4},
+		{0x1066, 0x106e, 8},
+		{0x106f, 0x1070, 1},
+		{0x1075, 0x1081, 1},
+		{0x108e, 0x10a0, 18},
+		{0x10a1, 0x10c5, 1},
+		{0x10c7, 0x10cd, 6},
+		{0x10d0, 0x10fa, 1},
+		{0x10fc, 0x1248, 1},
+		{0x124a, 0x124d, 1},
+		{0x1250, 0x1256, 1},
+		{0x1258, 0x125a, 2},
+		{0x125b, 0x125d, 1},
+		{0x1260, 0x1288, 1},
+		{0x128a, 0x128d, 1},
+		{0x1290, 0x12b0, 1},
+		{0x12b2, 0x12b5, 1},
+		{0x12b8, 0x12be, 1},
+		{0x12c0, 0x12c2, 2},
+		{0x12c3, 0x12c5, 1},
+		{0x12c8, 0x12d6, 1},
+		{0x12d8, 0x1310, 1},
+		{0x1312, 0x1315, 1},
+		{0x1318, 0x135a, 1},
+		{0x1380, 0x138f, 1},
+		{0x13a0, 0x13f5, 1},
+		{0x13f8, 0x13fd, 1},
+		{0x1401, 0x166c, 1},
+		{0x166f, 0x167f, 1},
+		{0x1681, 0x169a, 1},
+		{0x16a0, 0x16ea, 1},
+		{0x16f1, 0x16f8, 1},
+		{0x1700, 0x1711, 1},
+		{0x171f, 0x1731, 1},
+		{0x1740, 0x1751, 1},
+		{0x1760, 0x176c, 1},
+		{0x176e, 0x1770, 1},
+		{0x1780, 0x17b3, 1},
+		{0x17d7, 0x17dc, 5},
+		{0x1820, 0x1878, 1},
+		{0x1880, 0x1884, 1},
+		{0x1887, 0x18a8, 1},
+		{0x18aa, 0x18b0, 6},
+		{0x18b1, 0x18f5, 1},
+		{0x1900, 0x191e, 1},
+		{0x1950, 0x196d, 1},
+		{0x1970, 0x1974, 1},
+		{0x1980, 0x19ab, 1},
+		{0x19b0, 0x19c9, 1},
+		{0x1a00, 0x1a16, 1},
+		{0x1a20, 0x1a54, 1},
+		{0x1aa7, 0x1b05, 94},
+		{0x1b06, 0x1b33, 1},
+		{0x1b45, 0x1b4c, 1},
+		{0x1b83, 0x1ba0, 1},
+		{0x1bae, 0x1baf, 1},
+		{0x1bba, 0x1be5, 1},
+		{0x1c00, 0x1c23, 1},
+		{0x1c4d, 0x1c4f, 1},
+		{0x1c5a, 0x1c7d, 1},
+		{0x1c80, 0x1c8a, 1},
+		{0x1c90, 0x1cba, 1},
+		{0x1cbd, 0x1cbf, 1},
+		{0x1ce9, 0x1cec, 1},
+		{0x1cee, 0x1cf3, 1},
+		{0x1cf5, 0x1cf6, 1},
+		{0x1cfa, 0x1d00, 6},
+		{0x1d01, 0x1dbf, 1},
+		{0x1e00, 0x1f15, 1},
+		{0x1f18, 0x1f1d, 1},
+		{0x1f20, 0x1f45, 1},
+		{0x1f48, 0x1f4d, 1},
+		{0x1f50, 0x1f57, 1},
+		{0x1f59, 0x1f5f, 2},
+		{0x1f60, 0x1f7d, 1},
+		{0x1f80, 0x1fb4, 1},
+		{0x1fb6, 0x1fbc, 1},
+		{0x1fbe, 0x1fc2, 4},
+		{0x1fc3, 0x1fc4, 1},
+		{0x1fc6, 0x1fcc, 1},
+		{0x1fd0, 0x1fd3, 1},
+		{0x1fd6, 0x1fdb, 1},
+		{0x1fe0, 0x1fec, 1},
+		{0x1ff2, 0x1ff4, 1},
+		{0x1ff6, 0x1ffc, 1},
+		{0x2071, 0x207f, 14},
+		{0x2090, 0x209c, 1},
+		{0x2102, 0x2107, 5},
+		{0x210a, 0x2113, 1},
+		{0x2115, 0x2119, 4},
+		{0x211a, 0x211d, 1},
+		{0x2124, 0x212a, 2},
+		{0x212b, 0x212d, 1},
+		{0x212f, 0x2139, 1},
+		{0x213c, 0x213f, 1},
+		{0x2145, 0x2149, 1},
+		{0x214e, 0x2183, 53},
+		{0x2184, 0x2c00, 2684},
+		{0x2c01, 0x2ce4, 1},
+		{0x2ceb, 0x2cee, 1},
+		{0x2cf2, 0x2cf3, 1},
+		{0x2d00, 0x2d25, 1},
+		{0x2d27, 0x2d2d, 6},
+		{0x2d30, 0x2d67, 1},
+		{0x2d6f, 0x2d80, 17},
+		{0x2d81, 0x2d96, 1},
+		{0x2da0, 0x2da6, 1},
+		{0x2da8, 0x2dae, 1},
+		{0x2db0, 0x2db6, 1},
+		{0x2db8, 0x2dbe, 1},
+		{0x2dc0, 0x2dc6, 1},
+		{0x2dc8, 0x2dce, 1},
+		{0x2dd0, 0x2dd6, 1},
+		{0x2dd8, 0x2dde, 1},
+		{0x2e2f, 0x3005, 470},
+		{0x3006, 0x3031, 43},
+		{0x3032, 0x3035, 1},
+		{0x303b, 0x303c, 1},
+		{0x3041, 0x3096, 1},
+		{0x309d, 0x309f, 1},
+		{0x30a1, 0x30fa, 1},
+		{0x30fc, 0x30ff, 1},
+		{0x3105, 0x312f, 1},
+		{0x3131, 0x318e, 1},
+		{0x31a0, 0x31bf, 1},
+		{0x31f0, 0x31ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0xa48c, 1},
+		{0xa4d0, 0xa4fd, 1},
+		{0xa500, 0xa60c, 1},
+		{0xa610, 0xa61f, 1},
+		{0xa62a, 0xa62b, 1},
+		{0xa640, 0xa66e, 1},
+		{0xa67f, 0xa69d, 1},
+		{0xa6a0, 0xa6e5, 1},
+		{0xa717, 0xa71f, 1},
+		{0xa722, 0xa788, 1},
+		{0xa78b, 0xa7dc, 1},
+		{0xa7f1, 0xa801, 1},
+		{0xa803, 0xa805, 1},
+		{0xa807, 0xa80a, 1},
+		{0xa80c, 0xa822, 1},
+		{0xa840, 0xa873, 1},
+		{0xa882, 0xa8b3, 1},
+		{0xa8f2, 0xa8f7, 1},
+		{0xa8fb, 0xa8fd, 2},
+		{0xa8fe, 0xa90a, 12},
+		{0xa90b, 0xa925, 1},
+		{0xa930, 0xa946, 1},
+		{0xa960, 0xa97c, 1},
+		{0xa984, 0xa9b2, 1},
+		{0xa9cf, 0xa9e0, 17},
+		{0xa9e1, 0xa9e4, 1},
+		{0xa9e6, 0xa9ef, 1},
+		{0xa9fa, 0xa9fe, 1},
+		{0xaa00, 0xaa28, 1},
+		{0xaa40, 0xaa42, 1},
+		{0xaa44, 0xaa4b, 1},
+		{0xaa60, 0xaa76, 1},
+		{0xaa7a, 0xaa7e, 4},
+		{0xaa7f, 0xaaaf, 1},
+		{0xaab1, 0xaab5, 4},
+		{0xaab6, 0xaab9, 3},
+		{0xaaba, 0xaabd, 1},
+		{0xaac0, 0xaac2, 2},
+		{0xaadb, 0xaadd, 1},
+		{0xaae0, 0xaaea, 1},
+		{0xaaf2, 0xaaf4, 1},
+		{0xab01, 0xab06, 1},
+		{0xab09, 0xab0e, 1},
+		{0xab11, 0xab16, 1},
+		{0xab20, 0xab26, 1},
+		{0xab28, 0xab2e, 1},
+		{0xab30, 0xab5a, 1},
+		{0xab5c, 0xab69, 1},
+		{0xab70, 0xabe2, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xd7b0, 0xd7c6, 1},
+		{0xd7cb, 0xd7fb, 1},
+		{0xf900, 0xfa6d, 1},
+		{0xfa70, 0xfad9, 1},
+		{0xfb00, 0xfb06, 1},
+		{0xfb13, 0xfb17, 1},
+		{0xfb1d, 0xfb1f, 2},
+		{0xfb20, 0xfb28, 1},
+		{0xfb2a, 0xfb36, 1},
+		{0xfb38, 0xfb3c, 1},
+		{0xfb3e, 0xfb40, 2},
+		{0xfb41, 0xfb43, 2},
+		{0xfb44, 0xfb46, 2},
+		{0xfb47, 0xfbb1, 1},
+		{0xfbd3, 0xfd3d, 1},
+		{0xfd50, 0xfd8f, 1},
+		{0xfd92, 0xfdc7, 1},
+		{0xfdf0, 0xfdfb, 1},
+		{0xfe70, 0xfe74, 1},
+		{0xfe76, 0xfefc, 1},
+		{0xff21, 0xff3a, 1},
+		{0xff41, 0xff5a, 1},
+		{0xff66, 0xffbe, 1},
+		{0xffc2, 0xffc7, 1},
+		{0xffca, 0xffcf, 1},
+		{0xffd2, 0xffd7, 1},
+		{0xffda, 0xffdc, 1},
+	},
+	R32: []Range32{
+		{0x10000, 0x1000b, 1},
+		{0x1000d, 0x10026, 1},
+		{0x10028, 0x1003a, 1},
+		{0x1003c, 0x1003d, 1},
+		{0x1003f, 0x1004d, 1},
+		{0x10050, 0x1005d, 1},
+		{0x10080, 0x100fa, 1},
+		{0x10280, 0x1029c, 1},
+		{0x102a0, 0x102d0, 1},
+		{0x10300, 0x1031f, 1},
+		{0x1032d, 0x10340, 1},
+		{0x10342, 0x10349, 1},
+		{0x10350, 0x10375, 1},
+		{0x10380, 0x1039d, 1},
+		{0x103a0, 0x103c3, 1},
+		{0x103c8, 0x103cf, 1},
+		{0x10400, 0x1049d, 1},
+		{0x104b0, 0x104d3, 1},
+		{0x104d8, 0x104fb, 1},
+		{0x10500, 0x10527, 1},
+		{0x10530, 0x10563, 1},
+		{0x10570, 0x1057a, 1},
+		{0x1057c, 0x1058a, 1},
+		{0x1058c, 0x10592, 1},
+		{0x10594, 0x10595, 1},
+		{0x10597, 0x105a1, 1},
+		{0x105a3, 0x105b1, 1},
+		{0x105b3, 0x105b9, 1},
+		{0x105bb, 0x105bc, 1},
+		{0x105c0, 0x105f3, 1},
+		{0x10600, 0x10736, 1},
+		{0x10740, 0x10755, 1},
+		{0x10760, 0x10767, 1},
+		{0x10780, 0x10785, 1},
+		{0x10787, 0x107b0, 1},
+		{0x107b2, 0x107ba, 1},
+		{0x10800, 0x10805, 1},
+		{0x10808, 0x1080a, 2},
+		{0x1080b, 0x10835, 1},
+		{0x10837, 0x10838, 1},
+		{0x1083c, 0x1083f, 3},
+		{0x10840, 0x10855, 1},
+		{0x10860, 0x10876, 1},
+		{0x10880, 0x1089e, 1},
+		{0x108e0, 0x108f2, 1},
+		{0x108f4, 0x108f5, 1},
+		{0x10900, 0x10915, 1},
+		{0x10920, 0x10939, 1},
+		{0x10940, 0x10959, 1},
+		{0x10980, 0x109b7, 1},
+		{0x109be, 0x109bf, 1},
+		{0x10a00, 0x10a10, 16},
+		{0x10a11, 0x10a13, 1},
+		{0x10a15, 0x10a17, 1},
+		{0x10a19, 0x10a35, 1},
+		{0x10a60, 0x10a7c, 1},
+		{0x10a80, 0x10a9c, 1},
+		{0x10ac0, 0x10ac7, 1},
+		{0x10ac9, 0x10ae4, 1},
+		{0x10b00, 0x10b35, 1},
+		{0x10b40, 0x10b55, 1},
+		{0x10b60, 0x10b72, 1},
+		{0x10b80, 0x10b91, 1},
+		{0x10c00, 0x10c48, 1},
+		{0x10c80, 0x10cb2, 1},
+		{0x10cc0, 0x10cf2, 1},
+		{0x10d00, 0x10d23, 1},
+		{0x10d4a, 0x10d65, 1},
+		{0x10d6f, 0x10d85, 1},
+		{0x10e80, 0x10ea9, 1},
+		{0x10eb0, 0x10eb1, 1},
+		{0x10ec2, 0x10ec7, 1},
+		{0x10f00, 0x10f1c, 1},
+		{0x10f27, 0x10f30, 9},
+		{0x10f31, 0x10f45, 1},
+		{0x10f70, 0x10f81, 1},
+		{0x10fb0, 0x10fc4, 1},
+		{0x10fe0, 0x10ff6, 1},
+		{0x11003, 0x11037, 1},
+		{0x11071, 0x11072, 1},
+		{0x11075, 0x11083, 14},
+		{0x11084, 0x110af, 1},
+		{0x110d0, 0x110e8, 1},
+		{0x11103, 0x11126, 1},
+		{0x11144, 0x11147, 3},
+		{0x11150, 0x11172, 1},
+		{0x11176, 0x11183, 13},
+		{0x11184, 0x111b2, 1},
+		{0x111c1, 0x111c4, 1},
+		{0x111da, 0x111dc, 2},
+		{0x11200, 0x11211, 1},
+		{0x11213, 0x1122b, 1},
+		{0x1123f, 0x11240, 1},
+		{0x11280, 0x11286, 1},
+		{0x11288, 0x1128a, 2},
+		{0x1128b, 0x1128d, 1},
+		{0x1128f, 0x1129d, 1},
+		{0x1129f, 0x112a8, 1},
+		{0x112b0, 0x112de, 1},
+		{0x11305, 0x1130c, 1},
+		{0x1130f, 0x11310, 1},
+		{0x11313, 0x11328, 1},
+		{0x1132a, 0x11330, 1},
+		{0x11332, 0x11333, 1},
+		{0x11335, 0x11339, 1},
+		{0x1133d, 0x11350, 19},
+		{0x1135d, 0x11361, 1},
+		{0x11380, 0x11389, 1},
+		{0x1138b, 0x1138e, 3},
+		{0x11390, 0x113b5, 1},
+		{0x113b7, 0x113d1, 26},
+		{0x113d3, 0x11400, 45},
+		{0x11401, 0x11434, 1},
+		{0x11447, 0x1144a, 1},
+		{0x1145f, 0x11461, 1},
+		{0x11480, 0x114af, 1},
+		{0x114c4, 0x114c5, 1},
+		{0x114c7, 0x11580, 185},
+		{0x11581, 0x115ae, 1},
+		{0x115d8, 0x115db, 1},
+		{0x11600, 0x1162f, 1},
+		{0x11644, 0x11680, 60},
+		{0x11681, 0x116aa, 1},
+		{0x116b8, 0x11700, 72},
+		{0x11701, 0x1171a, 1},
+		{0x11740, 0x11746, 1},
+		{0x11800, 0x1182b, 1},
+		{0x118a0, 0x118df, 1},
+		{0x118ff, 0x11906, 1},
+		{0x11909, 0x1190c, 3},
+		{0x1190d, 0x11913, 1},
+		{0x11915, 0x11916, 1},
+		{0x11918, 0x1192f, 1},
+		{0x1193f, 0x11941, 2},
+		{0x119a0, 0x119a7, 1},
+		{0x119aa, 0x119d0, 1},
+		{0x119e1, 0x119e3, 2},
+		{0x11a00, 0x11a0b, 11},
+		{0x11a0c, 0x11a32, 1},
+		{0x11a3a, 0x11a50, 22},
+		{0x11a5c, 0x11a89, 1},
+		{0x11a9d, 0x11ab0, 19},
+		{0x11ab1, 0x11af8, 1},
+		{0x11bc0, 0x11be0, 1},
+		{0x11c00, 0x11c08, 1},
+		{0x11c0a, 0x11c2e, 1},
+		{0x11c40, 0x11c72, 50},
+		{0x11c73, 0x11c8f, 1},
+		{0x11d00, 0x11d06, 1},
+		{0x11d08, 0x11d09, 1},
+		{0x11d0b, 0x11d30, 1},
+		{0x11d46, 0x11d60, 26},
+		{0x11d61, 0x11d65, 1},
+		{0x11d67, 0x11d68, 1},
+		{0x11d6a, 0x11d89, 1},
+		{0x11d98, 0x11db0, 24},
+		{0x11db1, 0x11ddb, 1},
+		{0x11ee0, 0x11ef2, 1},
+		{0x11f02, 0x11f04, 2},
+		{0x11f05, 0x11f10, 1},
+		{0x11f12, 0x11f33, 1},
+		{0x11fb0, 0x12000, 80},
+		{0x12001, 0x12399, 1},
+		{0x12480, 0x12543, 1},
+		{0x12f90, 0x12ff0, 1},
+		{0x13000, 0x1342f, 1},
+		{0x13441, 0x13446, 1},
+		{0x13460, 0x143fa, 1},
+		{0x14400, 0x14646, 1},
+		{0x16100, 0x1611d, 1},
+		{0x16800, 0x16a38, 1},
+		{0x16a40, 0x16a5e, 1},
+		{0x16a70, 0x16abe, 1},
+		{0x16ad0, 0x16aed, 1},
+		{0x16b00, 0x16b2f, 1},
+		{0x16b40, 0x16b43, 1},
+		{0x16b63, 0x16b77, 1},
+		{0x16b7d, 0x16b8f, 1},
+		{0x16d40, 0x16d6c, 1},
+		{0x16e40, 0x16e7f, 1},
+		{0x16ea0, 0x16eb8, 1},
+		{0x16ebb, 0x16ed3, 1},
+		{0x16f00, 0x16f4a, 1},
+		{0x16f50, 0x16f93, 67},
+		{0x16f94, 0x16f9f, 1},
+		{0x16fe0, 0x16fe1, 1},
+		{0x16fe3, 0x16ff2, 15},
+		{0x16ff3, 0x17000, 13},
+		{0x17001, 0x18cd5, 1},
+		{0x18cff, 0x18d1e, 1},
+		{0x18d80, 0x18df2, 1},
+		{0x1aff0, 0x1aff3, 1},
+		{0x1aff5, 0x1affb, 1},
+		{0x1affd, 0x1affe, 1},
+		{0x1b000, 0x1b122, 1},
+		{0x1b132, 0x1b150, 30},
+		{0x1b151, 0x1b152, 1},
+		{0x1b155, 0x1b164, 15},
+		{0x1b165, 0x1b167, 1},
+		{0x1b170, 0x1b2fb, 1},
+		{0x1bc00, 0x1bc6a, 1},
+		{0x1bc70, 0x1bc7c, 1},
+		{0x1bc80, 0x1bc88, 1},
+		{0x1bc90, 0x1bc99, 1},
+		{0x1d400, 0x1d454, 1},
+		{0x1d456, 0x1d49c, 1},
+		{0x1d49e, 0x1d49f, 1},
+		{0x1d4a2, 0x1d4a5, 3},
+		{0x1d4a6, 0x1d4a9, 3},
+		{0x1d4aa, 0x1d4ac, 1},
+		{0x1d4ae, 0x1d4b9, 1},
+		{0x1d4bb, 0x1d4bd, 2},
+		{0x1d4be, 0x1d4c3, 1},
+		{0x1d4c5, 0x1d505, 1},
+		{0x1d507, 0x1d50a, 1},
+		{0x1d50d, 0x1d514, 1},
+		{0x1d516, 0x1d51c, 1},
+		{0x1d51e, 0x1d539, 1},
+		{0x1d53b, 0x1d53e, 1},
+		{0x1d540, 0x1d544, 1},
+		{0x1d546, 0x1d54a, 4},
+		{0x1d54b, 0x1d550, 1},
+		{0x1d552, 0x1d6a5, 1},
+		{0x1d6a8, 0x1d6c0, 1},
+		{0x1d6c2, 0x1d6da, 1},
+		{0x1d6dc, 0x1d6fa, 1},
+		{0x1d6fc, 0x1d714, 1},
+		{0x1d716, 0x1d734, 1},
+		{0x1d736, 0x1d74e, 1},
+		{0x1d750, 0x1d76e, 1},
+		{0x1d770, 0x1d788, 1},
+		{0x1d78a, 0x1d7a8, 1},
+		{0x1d7aa, 0x1d7c2, 1},
+		{0x1d7c4, 0x1d7cb, 1},
+		{0x1df00, 0x1df1e, 1},
+		{0x1df25, 0x1df2a, 1},
+		{0x1e030, 0x1e06d, 1},
+		{0x1e100, 0x1e12c, 1},
+		{0x1e137, 0x1e13d, 1},
+		{0x1e14e, 0x1e290, 322},
+		{0x1e291, 0x1e2ad, 1},
+		{0x1e2c0, 0x1e2eb, 1},
+		{0x1e4d0, 0x1e4eb, 1},
+		{0x1e5d0, 0x1e5ed, 1},
+		{0x1e5f0, 0x1e6c0, 208},
+		{0x1e6c1, 0x1e6de, 1},
+		{0x1e6e0, 0x1e6e2, 1},
+		{0x1e6e4, 0x1e6e5, 1},
+		{0x1e6e7, 0x1e6ed, 1},
+		{0x1e6f0, 0x1e6f4, 1},
+		{0x1e6fe, 0x1e6ff, 1},
+		{0x1e7e0, 0x1e7e6, 1},
+		{0x1e7e8, 0x1e7eb, 1},
+		{0x1e7ed, 0x1e7ee, 1},
+		{0x1e7f0, 0x1e7fe, 1},
+		{0x1e800, 0x1e8c4, 1},
+		{0x1e900, 0x1e943, 1},
+		{0x1e94b, 0x1ee00, 1205},
+		{0x1ee01, 0x1ee03, 1},
+		{0x1ee05, 0x1ee1f, 1},
+		{0x1ee21, 0x1ee22, 1},
+		{0x1ee24, 0x1ee27, 3},
+		{0x1ee29, 0x1ee32, 1},
+		{0x1ee34, 0x1ee37, 1},
+		{0x1ee39, 0x1ee3b, 2},
+		{0x1ee42, 0x1ee47, 5},
+		{0x1ee49, 0x1ee4d, 2},
+		{0x1ee4e, 0x1ee4f, 1},
+		{0x1ee51, 0x1ee52, 1},
+		{0x1ee54, 0x1ee57, 3},
+		{0x1ee59, 0x1ee61, 2},
+		{0x1ee62, 0x1ee64, 2},
+		{0x1ee67, 0x1ee6a, 1},
+		{0x1ee6c, 0x1ee72, 1},
+		{0x1ee74, 0x1ee77, 1},
+		{0x1ee79, 0x1ee7c, 1},
+		{0x1ee7e, 0x1ee80, 2},
+		{0x1ee81, 0x1ee89, 1},
+		{0x1ee8b, 0x1ee9b, 1},
+		{0x1eea1, 0x1eea3, 1},
+		{0x1eea5, 0x1eea9, 1},
+		{0x1eeab, 0x1eebb, 1},
+		{0x20000, 0x2a6df, 1},
+		{0x2a700, 0x2b81d, 1},
+		{0x2b820, 0x2cead, 1},
+		{0x2ceb0, 0x2ebe0, 1},
+		{0x2ebf0, 0x2ee5d, 1},
+		{0x2f800, 0x2fa1d, 1},
+		{0x30000, 0x3134a, 1},
+		{0x31350, 0x33479, 1},
+	},
+	LatinOffset: 6,
+}
+
+// Mark is the set of Unicode mark characters, category M.
+var Mark = &RangeTable{
+	R16: []Range16{
+		{0x0300, 0x036f, 1},
+		{0x0483, 0x0489, 1},
+		{0x0591, 0x05bd, 1},
+		{0x05bf, 0x05c1, 2},
+		{0x05c2, 0x05c4, 2},
+		{0x05c5, 0x05c7, 2},
+		{0x0610, 0x061a, 1},
+		{0x064b, 0x065f, 1},
+		{0x0670, 0x06d6, 102},
+		{0x06d7, 0x06dc, 1},
+		{0x06df, 0x06e4, 1},
+		{0x06e7, 0x06e8, 1},
+		{0x06ea, 0x06ed, 1},
+		{0x0711, 0x0730, 31},
+		{0x0731, 0x074a, 1},
+		{0x07a6, 0x07b0, 1},
+		{0x07eb, 0x07f3, 1},
+		{0x07fd, 0x0816, 25},
+		{0x0817, 0x0819, 1},
+		{0x081b, 0x0823, 1},
+		{0x0825, 0x0827, 1},
+		{0x0829, 0x082d, 1},
+		{0x0859, 0x085b, 1},
+		{0x0897, 0x089f, 1},
+		{0x08ca, 0x08e1, 1},
+		{0x08e3, 0x0903, 1},
+		{0x093a, 0x093c, 1},
+		{0x093e, 0x094f, 1},
+		{0x0951, 0x0957, 1},
+		{0x0962, 0x0963, 1},
+		{0x0981, 0x0983, 1},
+		{0x09bc, 0x09be, 2},
+		{0x09bf, 0x09c4, 1},
+		{0x09c7, 0x09c8, 1},
+		{0x09cb, 0x09cd, 1},
+		{0x09d7, 0x09e2, 11},
+		{0x09e3, 0x09fe, 27},
+		{0x0a01, 0x0a03, 1},
+		{0x0a3c, 0x0a3e, 2},
+		{0x0a3f, 0x0a42, 1},
+		{0x0a47, 0x0a48, 1},
+		{0x0a4b, 0x0a4d, 1},
+		{0x0a51, 0x0a70, 31},
+		{0x0a71, 0x0a75, 4},
+		{0x0a81, 0x0a83, 1},
+		{0x0abc, 0x0abe, 2},
+		{0x0abf, 0x0ac5, 1},
+		{0x0ac7, 0x0ac9, 1},
+		{0x0acb, 0x0acd, 1},
+		{0x0ae2, 0x0ae3, 1},
+		{0x0afa, 0x0aff, 1},
+		{0x0b01, 0x0b03, 1},
+		{0x0b3c, 0x0b3e, 2},
+		{0x0b3f, 0x0b44, 1},
+		{0x0b47, 0x0b48, 1},
+		{0x0b4b, 0x0b4d, 1},
+		{0x0b55, 0x0b57, 1},
+		{0x0b62, 0x0b63, 1},
+		{0x0b82, 0x0bbe, 60},
+		{0x0bbf, 0x0bc2, 1},
+		{0x0bc6, 0x0bc8, 1},
+		{0x0bca, 0x0bcd, 1},
+		{0x0bd7, 0x0c00, 41},
+		{0x0c01, 0x0c04, 1},
+		{0x0c3c, 0x0c3e, 2},
+		{0x0c3f, 0x0c44, 1},
+		{0x0c46, 0x0c48, 1},
+		{0x0c4a, 0x0c4d, 1},
+		{0x0c55, 0x0c56, 1},
+		{0x0c62, 0x0c63, 1},
+		{0x0c81, 0x0c83, 1},
+		{0x0cbc, 0x0cbe, 2},
+		{0x0cbf, 0x0cc4, 1},
+		{0x0cc6, 0x0cc8, 1},
+		{0x0cca, 0x0ccd, 1},
+		{0x0cd5, 0x0cd6, 1},
+		{0x0ce2, 0x0ce3, 1},
+		{0x0cf3, 0x0d00, 13},
+		{0x0d01, 0x0d03, 1},
+		{0x0d3b, 0x0d3c, 1},
+		{0x0d3e, 0x0d44, 1},
+		{0x0d46, 0x0d48, 1},
+		{0x0d4a, 0x0d4d, 1},
+		{0x0d57, 0x0d62, 11},
+		{0x0d63, 0x0d81, 30},
+		{0x0d82, 0x0d83, 1},
+		{0x0dca, 0x0dcf, 5},
+		{0x0dd0, 0x0dd4, 1},
+		{0x0dd6, 0x0dd8, 2},
+		{0x0dd9, 0x0ddf, 1},
+		{0x0df2, 0x0df3, 1},
+		{0x0e31, 0x0e34, 3},
+		{0x0e35, 0x0e3a, 1},
+		{0x0e47, 0x0e4e, 1},
+		{0x0eb1, 0x0eb4, 3},
+		{0x0eb5, 0x0ebc, 1},
+		{0x0ec8, 0x0ece, 1},
+		{0x0f18, 0x0f19, 1},
+		{0x0f35, 0x0f39, 2},
+		{0x0f3e, 0x0f3f, 1},
+		{0x0f71, 0x0f84, 1},
+		{0x0f86, 0x0f87, 1},
+		{0x0f8d, 0x0f97, 1},
+		{0x0f99, 0x0fbc, 1},
+		{0x0fc6, 0x102b, 101},
+		{0x102c, 0x103e, 1},
+		{0x1056, 0x1059, 1},
+		{0x105e, 0x1060, 1},
+		{0x1062, 0x1064, 1},
+		{0x1067, 0x106d, 1},
+		{0x1071, 0x1074, 1},
+		{0x1082, 0x108d, 1},
+		{0x108f, 0x109a, 11},
+		{0x109b, 0x109d, 1},
+		{0x135d, 0x135f, 1},
+		{0x1712, 0x1715, 1},
+		{0x1732, 0x1734, 1},
+		{0x1752, 0x1753, 1},
+		{0x1772, 0x1773, 1},
+		{0x17b4, 0x17d3, 1},
+		{0x17dd, 0x180b, 46},
+		{0x180c, 0x180d, 1},
+		{0x180f, 0x1885, 118},
+		{0x1886, 0x18a9, 35},
+		{0x1920, 0x192b, 1},
+		{0x1930, 0x193b, 1},
+		{0x1a17, 0x1a1b, 1},
+		{0x1a55, 0x1a5e, 1},
+		{0x1a60, 0x1a7c, 1},
+		{0x1a7f, 0x1ab0, 49},
+		{0x1ab1, 0x1add, 1},
+		{0x1ae0, 0x1aeb, 1},
+		{0x1b00, 0x1b04, 1},
+		{0x1b34, 0x1b44, 1},
+		{0x1b6b, 0x1b73, 1},
+		{0x1b80, 0x1b82, 1},
+		{0x1ba1, 0x1bad, 1},
+		{0x1be6, 0x1bf3, 1},
+		{0x1c24, 0x1c37, 1},
+		{0x1cd0, 0x1cd2, 1},
+		{0x1cd4, 0x1ce8, 1},
+		{0x1ced, 0x1cf4, 7},
+		{0x1cf7, 0x1cf9, 1},
+		{0x1dc0, 0x1dff, 1},
+		{0x20d0, 0x20f0, 1},
+		{0x2cef, 0x2cf1, 1},
+		{0x2d7f, 0x2de0, 97},
+		{0x2de1, 0x2dff, 1},
+		{0x302a, 0x302f, 1},
+		{0x3099, 0x309a, 1},
+		{0xa66f, 0xa672, 1},
+		{0xa674, 0xa67d, 1},
+		{0xa69e, 0xa69f, 1},
+		{0xa6f0, 0xa6f1, 1},
+		{0xa802, 0xa806, 4},
+		{0xa80b, 0xa823, 24},
+		{0xa824, 0xa827, 1},
+		{0xa82c, 0xa880, 84},
+		{0xa881, 0xa8b4, 51},
+		{0xa8b5, 0xa8c5, 1},
+		{0xa8e0, 0xa8f1, 1},
+		{0xa8ff, 0xa926, 39},
+		{0xa927, 0xa92d, 1},
+		{0xa947, 0xa953, 1},
+		{0xa980, 0xa983, 1},
+		{0xa9b3, 0xa9c0, 1},
+		{0xa9e5, 0xaa29, 68},
+		{0xaa2a, 0xaa36, 1},
+		{0xaa43, 0xaa4c, 9},
+		{0xaa4d, 0xaa7b, 46},
+		{0xaa7c, 0xaa7d, 1},
+		{0xaab0, 0xaab2, 2},
+		{0xaab3, 0xaab4, 1},
+		{0xaab7, 0xaab8, 1},
+		{0xaabe, 0xaabf, 1},
+		{0xaac1, 0xaaeb, 42},
+		{0xaaec, 0xaaef, 1},
+		{0xaaf5, 0xaaf6, 1},
+		{0xabe3, 0xabea, 1},
+		{0xabec, 0xabed, 1},
+		{0xfb1e, 0xfe00, 738},
+		{0xfe01, 0xfe0f, 1},
+		{0xfe20, 0xfe2f, 1},
+	},
+	R32: []Range32{
+		{0x101fd, 0x102e0, 227},
+		{0x10376, 0x1037a, 1},
+		{0x10a01, 0x10a03, 1},
+		{0x10a05, 0x10a06, 1},
+		{0x10a0c, 0x10a0f, 1},
+		{0x10a38, 0x10a3a, 1},
+		{0x10a3f, 0x10ae5, 166},
+		{0x10ae6, 0x10d24, 574},
+		{0x10d25, 0x10d27, 1},
+		{0x10d69, 0x10d6d, 1},
+		{0x10eab, 0x10eac, 1},
+		{0x10efa, 0x10eff, 1},
+		{0x10f46, 0x10f50, 1},
+		{0x10f82, 0x10f85, 1},
+		{0x11000, 0x11002, 1},
+		{0x11038, 0x11046, 1},
+		{0x11070, 0x11073, 3},
+		{0x11074, 0x1107f, 11},
+		{0x11080, 0x11082, 1},
+		{0x110b0, 0x110ba, 1},
+		{0x110c2, 0x11100, 62},
+		{0x11101, 0x11102, 1},
+		{0x11127, 0x11134, 1},
+		{0x11145, 0x11146, 1},
+		{0x11173, 0x11180, 13},
+		{0x11181, 0x11182, 1},
+		{0x111b3, 0x111c0, 1},
+		{0x111c9, 0x111cc, 1},
+		{0x111ce, 0x111cf, 1},
+		{0x1122c, 0x11237, 1},
+		{0x1123e, 0x11241, 3},
+		{0x112df, 0x112ea, 1},
+		{0x11300, 0x11303, 1},
+		{0x1133b, 0x1133c, 1},
+		{0x1133e, 0x11344, 1},
+		{0x11347, 0x11348, 1},
+		{0x1134b, 0x1134d, 1},
+		{0x11357, 0x11362, 11},
+		{0x11363, 0x11366, 3},
+		{0x11367, 0x1136c, 1},
+		{0x11370, 0x11374, 1},
+		{0x113b8, 0x113c0, 1},
+		{0x113c2, 0x113c5, 3},
+		{0x113c7, 0x113ca, 1},
+		{0x113cc, 0x113d0, 1},
+		{0x113d2, 0x113e1, 15},
+		{0x113e2, 0x11435, 83},
+		{0x11436, 0x11446, 1},
+		{0x1145e, 0x114b0, 82},
+		{0x114b1, 0x114c3, 1},
+		{0x115af, 0x115b5, 1},
+		{0x115b8, 0x115c0, 1},
+		{0x115dc, 0x115dd, 1},
+		{0x11630, 0x11640, 1},
+		{0x116ab, 0x116b7, 1},
+		{0x1171d, 0x1172b, 1},
+		{0x1182c, 0x1183a, 1},
+		{0x11930, 0x11935, 1},
+		{0x11937, 0x11938, 1},
+		{0x1193b, 0x1193e, 1},
+		{0x11940, 0x11942, 2},
+		{0x11943, 0x119d1, 142},
+		{0x119d2, 0x119d7, 1},
+		{0x119da, 0x119e0, 1},
+		{0x119e4, 0x11a01, 29},
+		{0x11a02, 0x11a0a, 1},
+		{0x11a33, 0x11a39, 1},
+		{0x11a3b, 0x11a3e, 1},
+		{0x11a47, 0x11a51, 10},
+		{0x11a52, 0x11a5b, 1},
+		{0x11a8a, 0x11a99, 1},
+		{0x11b60, 0x11b67, 1},
+		{0x11c2f, 0x11c36, 1},
+		{0x11c38, 0x11c3f, 1},
+		{0x11c92, 0x11ca7, 1},
+		{0x11ca9, 0x11cb6, 1},
+		{0x11d31, 0x11d36, 1},
+		{0x11d3a, 0x11d3c, 2},
+		{0x11d3d, 0x11d3f, 2},
+		{0x11d40, 0x11d45, 1},
+		{0x11d47, 0x11d8a, 67},
+		{0x11d8b, 0x11d8e, 1},
+		{0x11d90, 0x11d91, 1},
+		{0x11d93, 0x11d97, 1},
+		{0x11ef3, 0x11ef6, 1},
+		{0x11f00, 0x11f01, 1},
+		{0x11f03, 0x11f34, 49},
+		{0x11f35, 0x11f3a, 1},
+		{0x11f3e, 0x11f42, 1},
+		{0x11f5a, 0x13440, 5350},
+		{0x13447, 0x13455, 1},
+		{0x1611e, 0x1612f, 1},
+		{0x16af0, 0x16af4, 1},
+		{0x16b30, 0x16b36, 1},
+		{0x16f4f, 0x16f51, 2},
+		{0x16f52, 0x16f87, 1},
+		{0x16f8f, 0x16f92, 1},
+		{0x16fe4, 0x16ff0, 12},
+		{0x16ff1, 0x1bc9d, 19628},
+		{0x1bc9e, 0x1cf00, 4706},
+		{0x1cf01, 0x1cf2d, 1},
+		{0x1cf30, 0x1cf46, 1},
+		{0x1d165, 0x1d169, 1},
+		{0x1d16d, 0x1d172, 1},
+		{0x1d17b, 0x1d182, 1},
+		{0x1d185, 0x1d18b, 1},
+		{0x1d1aa, 0x1d1ad, 1},
+		{0x1d242, 0x1d244, 1},
+		{0x1da00, 0x1da36, 1},
+		{0x1da3b, 0x1da6c, 1},
+		{0x1da75, 0x1da84, 15},
+		{0x1da9b, 0x1da9f, 1},
+		{0x1daa1, 0x1daaf, 1},
+		{0x1e000, 0x1e006, 1},
+		{0x1e008, 0x1e018, 1},
+		{0x1e01b, 0x1e021, 1},
+		{0x1e023, 0x1e024, 1},
+		{0x1e026, 0x1e02a, 1},
+		{0x1e08f, 0x1e130, 161},
+		{0x1e131, 0x1e136, 1},
+		{0x1e2ae, 0x1e2ec, 62},
+		{0x1e2ed, 0x1e2ef, 1},
+		{0x1e4ec, 0x1e4ef, 1},
+		{0x1e5ee, 0x1e5ef, 1},
+		{0x1e6e3, 0x1e6e6, 3},
+		{0x1e6ee, 0x1e6ef, 1},
+		{0x1e6f5, 0x1e8d0, 475},
+		{0x1e8d1, 0x1e8d6, 1},
+		{0x1e944, 0x1e94a, 1},
+		{0xe0100, 0xe01ef, 1},
+	},
+}
+
+// White_Space is the set of Unicode characters with property White_Space.
+var White_Space = &RangeTable{
+	R16: []Range16{
+		{0x0009, 0x000d, 1},
+		{0x0020, 0x0085, 101},
+		{0x00a0, 0x1680, 5600},
+		{0x2000, 0x200a, 1},
+		{0x2028, 0x2029, 1},
+		{0x202f, 0x205f, 48},
+		{0x3000, 0x3000, 1},
+	},
+	LatinOffset: 2,
+}
+
+// XID_Start is the set of characters that may start an identifier.
+var XID_Start = &RangeTable{
+	R16: []Range16{
+		{0x0041, 0x005a, 1},
+		{0x0061, 0x007a, 1},
+		{0x00aa, 0x00b5, 11},
+		{0x00ba, 0x00c0, 6},
+		{0x00c1, 0x00d6, 1},
+		{0x00d8, 0x00f6, 1},
+		{0x00f8, 0x02c1, 1},
+		{0x02c6, 0x02d1, 1},
+		{0x02e0, 0x02e4, 1},
+		{0x02ec, 0x02ee, 2},
+		{0x0370, 0x0374, 1},
+		{0x0376, 0x0377, 1},
+		{0x037b, 0x037d, 1},
+		{0x037f, 0x0386, 7},
+		{0x0388, 0x038a, 1},
+		{0x038c, 0x038e, 2},
+		{0x038f, 0x03a1, 1},
+		{0x03a3, 0x03f5, 1},
+		{0x03f7, 0x0481, 1},
+		{0x048a, 0x052f, 1},
+		{0x0531, 0x0556, 1},
+		{0x0559, 0x0560, 7},
+		{0x0561, 0x0588, 1},
+		{0x05d0, 0x05ea, 1},
+		{0x05ef, 0x05f2, 1},
+		{0x0620, 0x064a, 1},
+		{0x066e, 0x066f, 1},
+		{0x0671, 0x06d3, 1},
+		{0x06d5, 0x06e5, 16},
+		{0x06e6, 0x06ee, 8},
+		{0x06ef, 0x06fa, 11},
+		{0x06fb, 0x06fc, 1},
+		{0x06ff, 0x0710, 17},
+		{0x0712, 0x072f, 1},
+		{0x074d, 0x07a5, 1},
+		{0x07b1, 0x07ca, 25},
+		{0x07cb, 0x07ea, 1},
+		{0x07f4, 0x07f5, 1},
+		{0x07fa, 0x0800, 6},
+		{0x0801, 0x0815, 1},
+		{0x081a, 0x0824, 10},
+		{0x0828, 0x0840, 24},
+		{0x0841, 0x0858, 1},
+		{0x0860, 0x086a, 1},
+		{0x0870, 0x0887, 1},
+		{0x0889, 0x088f, 1},
+		{0x08a0, 0x08c9, 1},
+		{0x0904, 0x0939, 1},
+		{0x093d, 0x0950, 19},
+		{0x0958, 0x0961, 1},
+		{0x0971, 0x0980, 1},
+		{0x0985, 0x098c, 1},
+		{0x098f, 0x0990, 1},
+		{0x0993, 0x09a8, 1},
+		{0x09aa, 0x09b0, 1},
+		{0x09b2, 0x09b6, 4},
+		{0x09b7, 0x09b9, 1},
+		{0x09bd, 0x09ce, 17},
+		{0x09dc, 0x09dd, 1},
+		{0x09df, 0x09e1, 1},
+		{0x09f0, 0x09f1, 1},
+		{0x09fc, 0x0a05, 9},
+		{0x0a06, 0x0a0a, 1},
+		{0x0a0f, 0x0a10, 1},
+		{0x0a13, 0x0a28, 1},
+		{0x0a2a, 0x0a30, 1},
+		{0x0a32, 0x0a33, 1},
+		{0x0a35, 0x0a36, 1},
+		{0x0a38, 0x0a39, 1},
+		{0x0a59, 0x0a5c, 1},
+		{0x0a5e, 0x0a72, 20},
+		{0x0a73, 0x0a74, 1},
+		{0x0a85, 0x0a8d, 1},
+		{0x0a8f, 0x0a91, 1},
+		{0x0a93, 0x0aa8, 1},
+		{0x0aaa, 0x0ab0, 1},
+		{0x0ab2, 0x0ab3, 1},
+		{0x0ab5, 0x0ab9, 1},
+		{0x0abd, 0x0ad0, 19},
+		{0x0ae0, 0x0ae1, 1},
+		{0x0af9, 0x0b05, 12},
+		{0x0b06, 0x0b0c, 1},
+		{0x0b0f, 0x0b10, 1},
+		{0x0b13, 0x0b28, 1},
+		{0x0b2a, 0x0b30, 1},
+		{0x0b32, 0x0b33, 1},
+		{0x0b35, 0x0b39, 1},
+		{0x0b3d, 0x0b5c, 31},
+		{0x0b5d, 0x0b5f, 2},
+		{0x0b60, 0x0b61, 1},
+		{0x0b71, 0x0b83, 18},
+		{0x0b85, 0x0b8a, 1},
+		{0x0b8e, 0x0b90, 1},
+		{0x0b92, 0x0b95, 1},
+		{0x0b99, 0x0b9a, 1},
+		{0x0b9c, 0x0b9e, 2},
+		{0x0b9f, 0x0ba3, 4},
+		{0x0ba4, 0x0ba8, 4},
+		{0x0ba9, 0x0baa, 1},
+		{0x0bae, 0x0bb9, 1},
+		{0x0bd0, 0x0c05, 53},
+		{0x0c06, 0x0c0c, 1},
+		{0x0c0e, 0x0c10, 1},
+		{0x0c12, 0x0c28, 1},
+		{0x0c2a, 0x0c39, 1},
+		{0x0c3d, 0x0c58, 27},
+		{0x0c59, 0x0c5a, 1},
+		{0x0c5c, 0x0c5d, 1},
+		{0x0c60, 0x0c61, 1},
+		{0x0c80, 0x0c85, 5},
+		{0x0c86, 0x0c8c, 1},
+		{0x0c8e, 0x0c90, 1},
+		{0x0c92, 0x0ca8, 1},
+		{0x0caa, 0x0cb3, 1},
+		{0x0cb5, 0x0cb9, 1},
+		{0x0cbd, 0x0cdc, 31},
+		{0x0cdd, 0x0cde, 1},
+		{0x0ce0, 0x0ce1, 1},
+		{0x0cf1, 0x0cf2, 1},
+		{0x0d04, 0x0d0c, 1},
+		{0x0d0e, 0x0d10, 1},
+		{0x0d12, 0x0d3a, 1},
+		{0x0d3d, 0x0d4e, 17},
+		{0x0d54, 0x0d56, 1},
+		{0x0d5f, 0x0d61, 1},
+		{0x0d7a, 0x0d7f, 1},
+		{0x0d85, 0x0d96, 1},
+		{0x0d9a, 0x0db1, 1},
+		{0x0db3, 0x0dbb, 1},
+		{0x0dbd, 0x0dc0, 3},
+		{0x0dc1, 0x0dc6, 1},
+		{0x0e01, 0x0e30, 1},
+		{0x0e32, 0x0e40, 14},
+		{0x0e41, 0x0e46, 1},
+		{0x0e81, 0x0e82, 1},
+		{0x0e84, 0x0e86, 2},
+		{0x0e87, 0x0e8a, 1},
+		{0x0e8c, 0x0ea3, 1},
+		{0x0ea5, 0x0ea7, 2},
+		{0x0ea8, 0x0eb0, 1},
+		{0x0eb2, 0x0ebd, 11},
+		{0x0ec0, 0x0ec4, 1},
+		{0x0ec6, 0x0edc, 22},
+		{0x0edd, 0x0edf, 1},
+		{0x0f00, 0x0f40, 64},
+		{0x0f41, 0x0f47, 1},
+		{0x0f49, 0x0f6c, 1},
+		{0x0f88, 0x0f8c, 1},
+		{0x1000, 0x102a, 1},
+		{0x103f, 0x1050, 17},
+		{0x1051, 0x1055, 1},
+		{0x105a, 0x105d, 1},
+		{0x1061, 0x1065, 4},
+		{0x1066, 0x106e, 8},
+		{0x106f, 0x1070, 1},
+		{0x1075, 0x1081, 1},
+		{0x108e, 0x10a0, 18},
+		{0x10a1, 0x10c5, 1},
+		{0x10c7, 0x10cd, 6},
+		{0x10d0, 0x10fa, 1},
+		{0x10fc, 0x1248, 1},
+		{0x124a, 0x124d, 1},
+		{0x1250, 0x1256, 1},
+		{0x1258, 0x125a, 2},
+		{0x125b, 0x125d, 1},
+		{0x1260, 0x1288, 1},
+		{0x128a, 0x128d, 1},
+		{0x1290, 0x12b0, 1},
+		{0x12b2, 0x12b5, 1},
+		{0x12b8, 0x12be, 1},
+		{0x12c0, 0x12c2, 2},
+		{0x12c3, 0x12c5, 1},
+		{0x12c8, 0x12d6, 1},
+		{0x12d8, 0x1310, 1},
+		{0x1312, 0x1315, 1},
+		{0x1318, 0x135a, 1},
+		{0x1380, 0x138f, 1},
+		{0x13a0, 0x13f5, 1},
+		{0x13f8, 0x13fd, 1},
+		{0x1401, 0x166c, 1},
+		{0x166f, 0x167f, 1},
+		{0x1681, 0x169a, 1},
+		{0x16a0, 0x16ea, 1},
+		{0x16ee, 0x16f8, 1},
+		{0x1700, 0x1711, 1},
+		{0x171f, 0x1731, 1},
+		{0x1740, 0x1751, 1},
+		{0x1760, 0x176c, 1},
+		{0x176e, 0x1770, 1},
+		{0x1780, 0x17b3, 1},
+		{0x17d7, 0x17dc, 5},
+		{0x1820, 0x1878, 1},
+		{0x1880, 0x18a8, 1},
+		{0x18aa, 0x18b0, 6},
+		{0x18b1, 0x18f5, 1},
+		{0x1900, 0x191e, 1},
+		{0x1950, 0x196d, 1},
+		{0x1970, 0x1974, 1},
+		{0x1980, 0x19ab, 1},
+		{0x19b0, 0x19c9, 1},
+		{0x1a00, 0x1a16, 1},
+		{0x1a20, 0x1a54, 1},
+		{0x1aa7, 0x1b05, 94},
+		{0x1b06, 0x1b33, 1},
+		{0x1b45, 0x1b4c, 1},
+		{0x1b83, 0x1ba0, 1},
+		{0x1bae, 0x1baf, 1},
+		{0x1bba, 0x1be5, 1},
+		{0x1c00, 0x1c23, 1},
+		{0x1c4d, 0x1c4f, 1},
+		{0x1c5a, 0x1c7d, 1},
+		{0x1c80, 0x1c8a, 1},
+		{0x1c90, 0x1cba, 1},
+		{0x1cbd, 0x1cbf, 1},
+		{0x1ce9, 0x1cec, 1},
+		{0x1cee, 0x1cf3, 1},
+		{0x1cf5, 0x1cf6, 1},
+		{0x1cfa, 0x1d00, 6},
+		{0x1d01, 0x1dbf, 1},
+		{0x1e00, 0x1f15, 1},
+		{0x1f18, 0x1f1d, 1},
+		{0x1f20, 0x1f45, 1},
+		{0x1f48, 0x1f4d, 1},
+		{0x1f50, 0x1f57, 1},
+		{0x1f59, 0x1f5f, 2},
+		{0x1f60, 0x1f7d, 1},
+		{0x1f80, 0x1fb4, 1},
+		{0x1fb6, 0x1fbc, 1},
+		{0x1fbe, 0x1fc2, 4},
+		{0x1fc3, 0x1fc4, 1},
+		{0x1fc6, 0x1fcc, 1},
+		{0x1fd0, 0x1fd3, 1},
+		{0x1fd6, 0x1fdb, 1},
+		{0x1fe0, 0x1fec, 1},
+		{0x1ff2, 0x1ff4, 1},
+		{0x1ff6, 0x1ffc, 1},
+		{0x2071, 0x207f, 14},
+		{0x2090, 0x209c, 1},
+		{0x2102, 0x2107, 5},
+		{0x210a, 0x2113, 1},
+		{0x2115, 0x2118, 3},
+		{0x2119, 0x211d, 1},
+		{0x2124, 0x212a, 2},
+		{0x212b, 0x2139, 1},
+		{0x213c, 0x213f, 1},
+		{0x2145, 0x2149, 1},
+		{0x214e, 0x2160, 18},
+		{0x2161, 0x2188, 1},
+		{0x2c00, 0x2ce4, 1},
+		{0x2ceb, 0x2cee, 1},
+		{0x2cf2, 0x2cf3, 1},
+		{0x2d00, 0x2d25, 1},
+		{0x2d27, 0x2d2d, 6},
+		{0x2d30, 0x2d67, 1},
+		{0x2d6f, 0x2d80, 17},
+		{0x2d81, 0x2d96, 1},
+		{0x2da0, 0x2da6, 1},
+		{0x2da8, 0x2dae, 1},
+		{0x2db0, 0x2db6, 1},
+		{0x2db8, 0x2dbe, 1},
+		{0x2dc0, 0x2dc6, 1},
+		{0x2dc8, 0x2dce, 1},
+		{0x2dd0, 0x2dd6, 1},
+		{0x2dd8, 0x2dde, 1},
+		{0x3005, 0x3007, 1},
+		{0x3021, 0x3029, 1},
+		{0x3031, 0x3035, 1},
+		{0x3038, 0x303c, 1},
+		{0x3041, 0x3096, 1},
+		{0x309d, 0x309f, 1},
+		{0x30a1, 0x30fa, 1},
+		{0x30fc, 0x30ff, 1},
+		{0x3105, 0x312f, 1},
+		{0x3131, 0x318e, 1},
+		{0x31a0, 0x31bf, 1},
+		{0x31f0, 0x31ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0xa48c, 1},
+		{0xa4d0, 0xa4fd, 1},
+		{0xa500, 0xa60c, 1},
+		{0xa610, 0xa61f, 1},
+		{0xa62a, 0xa62b, 1},
+		{0xa640, 0xa66e, 1},
+		{0xa67f, 0xa69d, 1},
+		{0xa6a0, 0xa6ef, 1},
+		{0xa717, 0xa71f, 1},
+		{0xa722, 0xa788, 1},
+		{0xa78b, 0xa7dc, 1},
+		{0xa7f1, 0xa801, 1},
+		{0xa803, 0xa805, 1},
+		{0xa807, 0xa80a, 1},
+		{0xa80c, 0xa822, 1},
+		{0xa840, 0xa873, 1},
+		{0xa882, 0xa8b3, 1},
+		{0xa8f2, 0xa8f7, 1},
+		{0xa8fb, 0xa8fd, 2},
+		{0xa8fe, 0xa90a, 12},
+		{0xa90b, 0xa925, 1},
+		{0xa930, 0xa946, 1},
+		{0xa960, 0xa97c, 1},
+		{0xa984, 0xa9b2, 1},
+		{0xa9cf, 0xa9e0, 17},
+		{0xa9e1, 0xa9e4, 1},
+		{0xa9e6, 0xa9ef, 1},
+		{0xa9fa, 0xa9fe, 1},
+		{0xaa00, 0xaa28, 1},
+		{0xaa40, 0xaa42, 1},
+		{0xaa44, 0xaa4b, 1},
+		{0xaa60, 0xaa76, 1},
+		{0xaa7a, 0xaa7e, 4},
+		{0xaa7f, 0xaaaf, 1},
+		{0xaab1, 0xaab5, 4},
+		{0xaab6, 0xaab9, 3},
+		{0xaaba, 0xaabd, 1},
+		{0xaac0, 0xaac2, 2},
+		{0xaadb, 0xaadd, 1},
+		{0xaae0, 0xaaea, 1},
+		{0xaaf2, 0xaaf4, 1},
+		{0xab01, 0xab06, 1},
+		{0xab09, 0xab0e, 1},
+		{0xab11, 0xab16, 1},
+		{0xab20, 0xab26, 1},
+		{0xab28, 0xab2e, 1},
+		{0xab30, 0xab5a, 1},
+		{0xab5c, 0xab69, 1},
+		{0xab70, 0xabe2, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xd7b0, 0xd7c6, 1},
+		{0xd7cb, 0xd7fb, 1},
+		{0xf900, 0xfa6d, 1},
+		{0xfa70, 0xfad9, 1},
+		{0xfb00, 0xfb06, 1},
+		{0xfb13, 0xfb17, 1},
+		{0xfb1d, 0xfb1f, 2},
+		{0xfb20, 0xfb28, 1},
+		{0xfb2a, 0xfb36, 1},
+		{0xfb38, 0xfb3c, 1},
+		{0xfb3e, 0xfb40, 2},
+		{0xfb41, 0xfb43, 2},
+		{0xfb44, 0xfb46, 2},
+		{0xfb47, 0xfbb1, 1},
+		{0xfbd3, 0xfc5d, 1},
+		{0xfc64, 0xfd3d, 1},
+		{0xfd50, 0xfd8f, 1},
+		{0xfd92, 0xfdc7, 1},
+		{0xfdf0, 0xfdf9, 1},
+		{0xfe71, 0xfe73, 2},
+		{0xfe77, 0xfe7f, 2},
+		{0xfe80, 0xfefc, 1},
+		{0xff21, 0xff3a, 1},
+		{0xff41, 0xff5a, 1},
+		{0xff66, 0xff9d, 1},
+		{0xffa0, 0xffbe, 1},
+		{0xffc2, 0xffc7, 1},
+		{0xffca, 0xffcf, 1},
+		{0xffd2, 0xffd7, 1},
+		{0xffda, 0xffdc, 1},
+	},
+	R32: []Range32{
+		{0x10000, 0x1000b, 1},
+		{0x1000d, 0x10026, 1},
+		{0x10028, 0x1003a, 1},
+		{0x1003c, 0x1003d, 1},
+		{0x1003f, 0x1004d, 1},
+		{0x10050, 0x1005d, 1},
+		{0x10080, 0x100fa, 1},
+		{0x10140, 0x10174, 1},
+		{0x10280, 0x1029c, 1},
+		{0x102a0, 0x102d0, 1},
+		{0x10300, 0x1031f, 1},
+		{0x1032d, 0x1034a, 1},
+		{0x10350, 0x10375, 1},
+		{0x10380, 0x1039d, 1},
+		{0x103a0, 0x103c3, 1},
+		{0x103c8, 0x103cf, 1},
+		{0x103d1, 0x103d5, 1},
+		{0x10400, 0x1049d, 1},
+		{0x104b0, 0x104d3, 1},
+		{0x104d8, 0x104fb, 1},
+		{0x10500, 0x10527, 1},
+		{0x10530, 0x10563, 1},
+		{0x10570, 0x1057a, 1},
+		{0x1057c, 0x1058a, 1},
+		{0x1058c, 0x10592, 1},
+		{0x10594, 0x10595, 1},
+		{0x10597, 0x105a1, 1},
+		{0x105a3, 0x105b1, 1},
+		{0x105b3, 0x105b9, 1},
+		{0x105bb, 0x105bc, 1},
+		{0x105c0, 0x105f3, 1},
+		{0x10600, 0x10736, 1},
+		{0x10740, 0x10755, 1},
+		{0x10760, 0x10767, 1},
+		{0x10780, 0x10785, 1},
+		{0x10787, 0x107b0, 1},
+		{0x107b2, 0x107ba, 1},
+		{0x10800, 0x10805, 1},
+		{0x10808, 0x1080a, 2},
+		{0x1080b, 0x10835, 1},
+		{0x10837, 0x10838, 1},
+		{0x1083c, 0x1083f, 3},
+		{0x10840, 0x10855, 1},
+		{0x10860, 0x10876, 1},
+		{0x10880, 0x1089e, 1},
+		{0x108e0, 0x108f2, 1},
+		{0x108f4, 0x108f5, 1},
+		{0x10900, 0x10915, 1},
+		{0x10920, 0x10939, 1},
+		{0x10940, 0x10959, 1},
+		{0x10980, 0x109b7, 1},
+		{0x109be, 0x109bf, 1},
+		{0x10a00, 0x10a10, 16},
+		{0x10a11, 0x10a13, 1},
+		{0x10a15, 0x10a17, 1},
+		{0x10a19, 0x10a35, 1},
+		{0x10a60, 0x10a7c, 1},
+		{0x10a80, 0x10a9c, 1},
+		{0x10ac0, 0x10ac7, 1},
+		{0x10ac9, 0x10ae4, 1},
+		{0x10b00, 0x10b35, 1},
+		{0x10b40, 0x10b55, 1},
+		{0x10b60, 0x10b72, 1},
+		{0x10b80, 0x10b91, 1},
+		{0x10c00, 0x10c48, 1},
+		{0x10c80, 0x10cb2, 1},
+		{0x10cc0, 0x10cf2, 1},
+		{0x10d00, 0x10d23, 1},
+		{0x10d4a, 0x10d65, 1},
+		{0x10d6f, 0x10d85, 1},
+		{0x10e80, 0x10ea9, 1},
+		{0x10eb0, 0x10eb1, 1},
+		{0x10ec2, 0x10ec7, 1},
+		{0x10f00, 0x10f1c, 1},
+		{0x10f27, 0x10f30, 9},
+		{0x10f31, 0x10f45, 1},
+		{0x10f70, 0x10f81, 1},
+		{0x10fb0, 0x10fc4, 1},
+		{0x10fe0, 0x10ff6, 1},
+		{0x11003, 0x11037, 1},
+		{0x11071, 0x11072, 1},
+		{0x11075, 0x11083, 14},
+		{0x11084, 0x110af, 1},
+		{0x110d0, 0x110e8, 1},
+		{0x11103, 0x11126, 1},
+		{0x11144, 0x11147, 3},
+		{0x11150, 0x11172, 1},
+		{0x11176, 0x11183, 13},
+		{0x11184, 0x111b2, 1},
+		{0x111c1, 0x111c4, 1},
+		{0x111da, 0x111dc, 2},
+		{0x11200, 0x11211, 1},
+		{0x11213, 0x1122b, 1},
+		{0x1123f, 0x11240, 1},
+		{0x11280, 0x11286, 1},
+		{0x11288, 0x1128a, 2},
+		{0x1128b, 0x1128d, 1},
+		{0x1128f, 0x1129d, 1},
+		{0x1129f, 0x112a8, 1},
+		{0x112b0, 0x112de, 1},
+		{0x11305, 0x1130c, 1},
+		{0x1130f, 0x11310, 1},
+		{0x11313, 0x11328, 1},
+		{0x1132a, 0x11330, 1},
+		{0x11332, 0x11333, 1},
+		{0x11335, 0x11339, 1},
+		{0x1133d, 0x11350, 19},
+		{0x1135d, 0x11361, 1},
+		{0x11380, 0x11389, 1},
+		{0x1138b, 0x1138e, 3},
+		{0x11390, 0x113b5, 1},
+		{0x113b7, 0x113d1, 26},
+		{0x113d3, 0x11400, 45},
+		{0x11401, 0x11434, 1},
+		{0x11447, 0x1144a, 1},
+		{0x1145f, 0x11461, 1},
+		{0x11480, 0x114af, 1},
+		{0x114c4, 0x114c5, 1},
+		{0x114c7, 0x11580, 185},
+		{0x11581, 0x115ae, 1},
+		{0x115d8, 0x115db, 1},
+		{0x11600, 0x1162f, 1},
+		{0x11644, 0x11680, 60},
+		{0x11681, 0x116aa, 1},
+		{0x116b8, 0x11700, 72},
+		{0x11701, 0x1171a, 1},
+		{0x11740, 0x11746, 1},
+		{0x11800, 0x1182b, 1},
+		{0x118a0, 0x118df, 1},
+		{0x118ff, 0x11906, 1},
+		{0x11909, 0x1190c, 3},
+		{0x1190d, 0x11913, 1},
+		{0x11915, 0x11916, 1},
+		{0x11918, 0x1192f, 1},
+		{0x1193f, 0x11941, 2},
+		{0x119a0, 0x119a7, 1},
+		{0x119aa, 0x119d0, 1},
+		{0x119e1, 0x119e3, 2},
+		{0x11a00, 0x11a0b, 11},
+		{0x11a0c, 0x11a32, 1},
+		{0x11a3a, 0x11a50, 22},
+		{0x11a5c, 0x11a89, 1},
+		{0x11a9d, 0x11ab0, 19},
+		{0x11ab1, 0x11af8, 1},
+		{0x11bc0, 0x11be0, 1},
+		{0x11c00, 0x11c08, 1},
+		{0x11c0a, 0x11c2e, 1},
+		{0x11c40, 0x11c72, 50},
+		{0x11c73, 0x11c8f, 1},
+		{0x11d00, 0x11d06, 1},
+		{0x11d08, 0x11d09, 1},
+		{0x11d0b, 0x11d30, 1},
+		{0x11d46, 0x11d60, 26},
+		{0x11d61, 0x11d65, 1},
+		{0x11d67, 0x11d68, 1},
+		{0x11d6a, 0x11d89, 1},
+		{0x11d98, 0x11db0, 24},
+		{0x11db1, 0x11ddb, 1},
+		{0x11ee0, 0x11ef2, 1},
+		{0x11f02, 0x11f04, 2},
+		{0x11f05, 0x11f10, 1},
+		{0x11f12, 0x11f33, 1},
+		{0x11fb0, 0x12000, 80},
+		{0x12001, 0x12399, 1},
+		{0x12400, 0x1246e, 1},
+		{0x12480, 0x12543, 1},
+		{0x12f90, 0x12ff0, 1},
+		{0x13000, 0x1342f, 1},
+		{0x13441, 0x13446, 1},
+		{0x13460, 0x143fa, 1},
+		{0x14400, 0x14646, 1},
+		{0x16100, 0x1611d, 1},
+		{0x16800, 0x16a38, 1},
+		{0x16a40, 0x16a5e, 1},
+		{0x16a70, 0x16abe, 1},
+		{0x16ad0, 0x16aed, 1},
+		{0x16b00, 0x16b2f, 1},
+		{0x16b40, 0x16b43, 1},
+		{0x16b63, 0x16b77, 1},
+		{0x16b7d, 0x16b8f, 1},
+		{0x16d40, 0x16d6c, 1},
+		{0x16e40, 0x16e7f, 1},
+		{0x16ea0, 0x16eb8, 1},
+		{0x16ebb, 0x16ed3, 1},
+		{0x16f00, 0x16f4a, 1},
+		{0x16f50, 0x16f93, 67},
+		{0x16f94, 0x16f9f, 1},
+		{0x16fe0, 0x16fe1, 1},
+		{0x16fe3, 0x16ff2, 15},
+		{0x16ff3, 0x16ff6, 1},
+		{0x17000, 0x18cd5, 1},
+		{0x18cff, 0x18d1e, 1},
+		{0x18d80, 0x18df2, 1},
+		{0x1aff0, 0x1aff3, 1},
+		{0x1aff5, 0x1affb, 1},
+		{0x1affd, 0x1affe, 1},
+		{0x1b000, 0x1b122, 1},
+		{0x1b132, 0x1b150, 30},
+		{0x1b151, 0x1b152, 1},
+		{0x1b155, 0x1b164, 15},
+		{0x1b165, 0x1b167, 1},
+		{0x1b170, 0x1b2fb, 1},
+		{0x1bc00, 0x1bc6a, 1},
+		{0x1bc70, 0x1bc7c, 1},
+		{0x1bc80, 0x1bc88, 1},
+		{0x1bc90, 0x1bc99, 1},
+		{0x1d400, 0x1d454, 1},
+		{0x1d456, 0x1d49c, 1},
+		{0x1d49e, 0x1d49f, 1},
+		{0x1d4a2, 0x1d4a5, 3},
+		{0x1d4a6, 0x1d4a9, 3},
+		{0x1d4aa, 0x1d4ac, 1},
+		{0x1d4ae, 0x1d4b9, 1},
+		{0x1d4bb, 0x1d4bd, 2},
+		{0x1d4be, 0x1d4c3, 1},
+		{0x1d4c5, 0x1d505, 1},
+		{0x1d507, 0x1d50a, 1},
+		{0x1d50d, 0x1d514, 1},
+		{0x1d516, 0x1d51c, 1},
+		{0x1d51e, 0x1d539, 1},
+		{0x1d53b, 0x1d53e, 1},
+		{0x1d540, 0x1d544, 1},
+		{0x1d546, 0x1d54a, 4},
+		{0x1d54b, 0x1d550, 1},
+		{0x1d552, 0x1d6a5, 1},
+		{0x1d6a8, 0x1d6c0, 1},
+		{0x1d6c2, 0x1d6da, 1},
+		{0x1d6dc, 0x1d6fa, 1},
+		{0x1d6fc, 0x1d714, 1},
+		{0x1d716, 0x1d734, 1},
+		{0x1d736, 0x1d74e, 1},
+		{0x1d750, 0x1d76e, 1},
+		{0x1d770, 0x1d788, 1},
+		{0x1d78a, 0x1d7a8, 1},
+		{0x1d7aa, 0x1d7c2, 1},
+		{0x1d7c4, 0x1d7cb, 1},
+		{0x1df00, 0x1df1e, 1},
+		{0x1df25, 0x1df2a, 1},
+		{0x1e030, 0x1e06d, 1},
+		{0x1e100, 0x1e12c, 1},
+		{0x1e137, 0x1e13d, 1},
+		{0x1e14e, 0x1e290, 322},
+		{0x1e291, 0x1e2ad, 1},
+		{0x1e2c0, 0x1e2eb, 1},
+		{0x1e4d0, 0x1e4eb, 1},
+		{0x1e5d0, 0x1e5ed, 1},
+		{0x1e5f0, 0x1e6c0, 208},
+		{0x1e6c1, 0x1e6de, 1},
+		{0x1e6e0, 0x1e6e2, 1},
+		{0x1e6e4, 0x1e6e5, 1},
+		{0x1e6e7, 0x1e6ed, 1},
+		{0x1e6f0, 0x1e6f4, 1},
+		{0x1e6fe, 0x1e6ff, 1},
+		{0x1e7e0, 0x1e7e6, 1},
+		{0x1e7e8, 0x1e7eb, 1},
+		{0x1e7ed, 0x1e7ee, 1},
+		{0x1e7f0, 0x1e7fe, 1},
+		{0x1e800, 0x1e8c4, 1},
+		{0x1e900, 0x1e943, 1},
+		{0x1e94b, 0x1ee00, 1205},
+		{0x1ee01, 0x1ee03, 1},
+		{0x1ee05, 0x1ee1f, 1},
+		{0x1ee21, 0x1ee22, 1},
+		{0x1ee24, 0x1ee27, 3},
+		{0x1ee29, 0x1ee32, 1},
+		{0x1ee34, 0x1ee37, 1},
+		{0x1ee39, 0x1ee3b, 2},
+		{0x1ee42, 0x1ee47, 5},
+		{0x1ee49, 0x1ee4d, 2},
+		{0x1ee4e, 0x1ee4f, 1},
+		{0x1ee51, 0x1ee52, 1},
+		{0x1ee54, 0x1ee57, 3},
+		{0x1ee59, 0x1ee61, 2},
+		{0x1ee62, 0x1ee64, 2},
+		{0x1ee67, 0x1ee6a, 1},
+		{0x1ee6c, 0x1ee72, 1},
+		{0x1ee74, 0x1ee77, 1},
+		{0x1ee79, 0x1ee7c, 1},
+		{0x1ee7e, 0x1ee80, 2},
+		{0x1ee81, 0x1ee89, 1},
+		{0x1ee8b, 0x1ee9b, 1},
+		{0x1eea1, 0x1eea3, 1},
+		{0x1eea5, 0x1eea9, 1},
+		{0x1eeab, 0x1eebb, 1},
+		{0x20000, 0x2a6df, 1},
+		{0x2a700, 0x2b81d, 1},
+		{0x2b820, 0x2cead, 1},
+		{0x2ceb0, 0x2ebe0, 1},
+		{0x2ebf0, 0x2ee5d, 1},
+		{0x2f800, 0x2fa1d, 1},
+		{0x30000, 0x3134a, 1},
+		{0x31350, 0x33479, 1},
+	},
+	LatinOffset: 6,
+}
+
+// XID_Continue is the set of characters that may follow the first one of an identifier.
+var XID_Continue = &RangeTable{
+	R16: []Range16{
+		{0x0030, 0x0039, 1},
+		{0x0041, 0x005a, 1},
+		{0x005f, 0x0061, 2},
+		{0x0062, 0x007a, 1},
+		{0x00aa, 0x00b5, 11},
+		{0x00b7, 0x00ba, 3},
+		{0x00c0, 0x00d6, 1},
+		{0x00d8, 0x00f6, 1},
+		{0x00f8, 0x02c1, 1},
+		{0x02c6, 0x02d1, 1},
+		{0x02e0, 0x02e4, 1},
+		{0x02ec, 0x02ee, 2},
+		{0x0300, 0x0374, 1},
+		{0x0376, 0x0377, 1},
+		{0x037b, 0x037d, 1},
+		{0x037f, 0x0386, 7},
+		{0x0387, 0x038a, 1},
+		{0x038c, 0x038e, 2},
+		{0x038f, 0x03a1, 1},
+		{0x03a3, 0x03f5, 1},
+		{0x03f7, 0x0481, 1},
+		{0x0483, 0x0487, 1},
+		{0x048a, 0x052f, 1},
+		{0x0531, 0x0556, 1},
+		{0x0559, 0x0560, 7},
+		{0x0561, 0x0588, 1},
+		{0x0591, 0x05bd, 1},
+		{0x05bf, 0x05c1, 2},
+		{0x05c2, 0x05c4, 2},
+		{0x05c5, 0x05c7, 2},
+		{0x05d0, 0x05ea, 1},
+		{0x05ef, 0x05f2, 1},
+		{0x0610, 0x061a, 1},
+		{0x0620, 0x0669, 1},
+		{0x066e, 0x06d3, 1},
+		{0x06d5, 0x06dc, 1},
+		{0x06df, 0x06e8, 1},
+		{0x06ea, 0x06fc, 1},
+		{0x06ff, 0x0710, 17},
+		{0x0711, 0x074a, 1},
+		{0x074d, 0x07b1, 1},
+		{0x07c0, 0x07f5, 1},
+		{0x07fa, 0x0800, 3},
+		{0x0801, 0x082d, 1},
+		{0x0840, 0x085b, 1},
+		{0x0860, 0x086a, 1},
+		{0x0870, 0x0887, 1},
+		{0x0889, 0x088f, 1},
+		{0x0897, 0x08e1, 1},
+		{0x08e3, 0x0963, 1},
+		{0x0966, 0x096f, 1},
+		{0x0971, 0x0983, 1},
+		{0x0985, 0x098c, 1},
+		{0x098f, 0x0990, 1},
+		{0x0993, 0x09a8, 1},
+		{0x09aa, 0x09b0, 1},
+		{0x09b2, 0x09b6, 4},
+		{0x09b7, 0x09b9, 1},
+		{0x09bc, 0x09c4, 1},
+		{0x09c7, 0x09c8, 1},
+		{0x09cb, 0x09ce, 1},
+		{0x09d7, 0x09dc, 5},
+		{0x09dd, 0x09df, 2},
+		{0x09e0, 0x09e3, 1},
+		{0x09e6, 0x09f1, 1},
+		{0x09fc, 0x09fe, 2},
+		{0x0a01, 0x0a03, 1},
+		{0x0a05, 0x0a0a, 1},
+		{0x0a0f, 0x0a10, 1},
+		{0x0a13, 0x0a28, 1},
+		{0x0a2a, 0x0a30, 1},
+		{0x0a32, 0x0a33, 1},
+		{0x0a35, 0x0a36, 1},
+		{0x0a38, 0x0a39, 1},
+		{0x0a3c, 0x0a3e, 2},
+		{0x0a3f, 0x0a42, 1},
+		{0x0a47, 0x0a48, 1},
+		{0x0a4b, 0x0a4d, 1},
+		{0x0a51, 0x0a59, 8},
+		{0x0a5a, 0x0a5c, 1},
+		{0x0a5e, 0x0a66, 8},
+		{0x0a67, 0x0a75, 1},
+		{0x0a81, 0x0a83, 1},
+		{0x0a85, 0x0a8d, 1},
+		{0x0a8f, 0x0a91, 1},
+		{0x0a93, 0x0aa8, 1},
+		{0x0aaa, 0x0ab0, 1},
+		{0x0ab2, 0x0ab3, 1},
+		{0x0ab5, 0x0ab9, 1},
+		{0x0abc, 0x0ac5, 1},
+		{0x0ac7, 0x0ac9, 1},
+		{0x0acb, 0x0acd, 1},
+		{0x0ad0, 0x0ae0, 16},
+		{0x0ae1, 0x0ae3, 1},
+		{0x0ae6, 0x0aef, 1},
+		{0x0af9, 0x0aff, 1},
+		{0x0b01, 0x0b03, 1},
+		{0x0b05, 0x0b0c, 1},
+		{0x0b0f, 0x0b10, 1},
+		{0x0b13, 0x0b28, 1},
+		{0x0b2a, 0x0b30, 1},
+		{0x0b32, 0x0b33, 1},
+		{0x0b35, 0x0b39, 1},
+		{0x0b3c, 0x0b44, 1},
+		{0x0b47, 0x0b48, 1},
+		{0x0b4b, 0x0b4d, 1},
+		{0x0b55, 0x0b57, 1},
+		{0x0b5c, 0x0b5d, 1},
+		{0x0b5f, 0x0b63, 1},
+		{0x0b66, 0x0b6f, 1},
+		{0x0b71, 0x0b82, 17},
+		{0x0b83, 0x0b85, 2},
+		{0x0b86, 0x0b8a, 1},
+		{0x0b8e, 0x0b90, 1},
+		{0x0b92, 0x0b95, 1},
+		{0x0b99, 0x0b9a, 1},
+		{0x0b9c, 0x0b9e, 2},
+		{0x0b9f, 0x0ba3, 4},
+		{0x0ba4, 0x0ba8, 4},
+		{0x0ba9, 0x0baa, 1},
+		{0x0bae, 0x0bb9, 1},
+		{0x0bbe, 0x0bc2, 1},
+		{0x0bc6, 0x0bc8, 1},
+		{0x0bca, 0x0bcd, 1},
+		{0x0bd0, 0x0bd7, 7},
+		{0x0be6, 0x0bef, 1},
+		{0x0c00, 0x0c0c, 1},
+		{0x0c0e, 0x0c10, 1},
+		{0x0c12, 0x0c28, 1},
+		{0x0c2a, 0x0c39, 1},
+		{0x0c3c, 0x0c44, 1},
+		{0x0c46, 0x0c48, 1},
+		{0x0c4a, 0x0c4d, 1},
+		{0x0c55, 0x0c56, 1},
+		{0x0c58, 0x0c5a, 1},
+		{0x0c5c, 0x0c5d, 1},
+		{0x0c60, 0x0c63, 1},
+		{0x0c66, 0x0c6f, 1},
+		{0x0c80, 0x0c83, 1},
+		{0x0c85, 0x0c8c, 1},
+		{0x0c8e, 0x0c90, 1},
+		{0x0c92, 0x0ca8, 1},
+		{0x0caa, 0x0cb3, 1},
+		{0x0cb5, 0x0cb9, 1},
+		{0x0cbc, 0x0cc4, 1},
+		{0x0cc6, 0x0cc8, 1},
+		{0x0cca, 0x0ccd, 1},
+		{0x0cd5, 0x0cd6, 1},
+		{0x0cdc, 0x0cde, 1},
+		{0x0ce0, 0x0ce3, 1},
+		{0x0ce6, 0x0cef, 1},
+		{0x0cf1, 0x0cf3, 1},
+		{0x0d00, 0x0d0c, 1},
+		{0x0d0e, 0x0d10, 1},
+		{0x0d12, 0x0d44, 1},
+		{0x0d46, 0x0d48, 1},
+		{0x0d4a, 0x0d4e, 1},
+		{0x0d54, 0x0d57, 1},
+		{0x0d5f, 0x0d63, 1},
+		{0x0d66, 0x0d6f, 1},
+		{0x0d7a, 0x0d7f, 1},
+		{0x0d81, 0x0d83, 1},
+		{0x0d85, 0x0d96, 1},
+		{0x0d9a, 0x0db1, 1},
+		{0x0db3, 0x0dbb, 1},
+		{0x0dbd, 0x0dc0, 3},
+		{0x0dc1, 0x0dc6, 1},
+		{0x0dca, 0x0dcf, 5},
+		{0x0dd0, 0x0dd4, 1},
+		{0x0dd6, 0x0dd8, 2},
+		{0x0dd9, 0x0ddf, 1},
+		{0x0de6, 0x0def, 1},
+		{0x0df2, 0x0df3, 1},
+		{0x0e01, 0x0e3a, 1},
+		{0x0e40, 0x0e4e, 1},
+		{0x0e50, 0x0e59, 1},
+		{0x0e81, 0x0e82, 1},
+		{0x0e84, 0x0e86, 2},
+		{0x0e87, 0x0e8a, 1},
+		{0x0e8c, 0x0ea3, 1},
+		{0x0ea5, 0x0ea7, 2},
+		{0x0ea8, 0x0ebd, 1},
+		{0x0ec0, 0x0ec4, 1},
+		{0x0ec6, 0x0ec8, 2},
+		{0x0ec9, 0x0ece, 1},
+		{0x0ed0, 0x0ed9, 1},
+		{0x0edc, 0x0edf, 1},
+		{0x0f00, 0x0f18, 24},
+		{0x0f19, 0x0f20, 7},
+		{0x0f21, 0x0f29, 1},
+		{0x0f35, 0x0f39, 2},
+		{0x0f3e, 0x0f47, 1},
+		{0x0f49, 0x0f6c, 1},
+		{0x0f71, 0x0f84, 1},
+		{0x0f86, 0x0f97, 1},
+		{0x0f99, 0x0fbc, 1},
+		{0x0fc6, 0x1000, 58},
+		{0x1001, 0x1049, 1},
+		{0x1050, 0x109d, 1},
+		{0x10a0, 0x10c5, 1},
+		{0x10c7, 0x10cd, 6},
+		{0x10d0, 0x10fa, 1},
+		{0x10fc, 0x1248, 1},
+		{0x124a, 0x124d, 1},
+		{0x1250, 0x1256, 1},
+		{0x1258, 0x125a, 2},
+		{0x125b, 0x125d, 1},
+		{0x1260, 0x1288, 1},
+		{0x128a, 0x128d, 1},
+		{0x1290, 0x12b0, 1},
+		{0x12b2, 0x12b5, 1},
+		{0x12b8, 0x12be, 1},
+		{0x12c0, 0x12c2, 2},
+		{0x12c3, 0x12c5, 1},
+		{0x12c8, 0x12d6, 1},
+		{0x12d8, 0x1310, 1},
+		{0x1312, 0x1315, 1},
+		{0x1318, 0x135a, 1},
+		{0x135d, 0x135f, 1},
+		{0x1369, 0x1371, 1},
+		{0x1380, 0x138f, 1},
+		{0x13a0, 0x13f5, 1},
+		{0x13f8, 0x13fd, 1},
+		{0x1401, 0x166c, 1},
+		{0x166f, 0x167f, 1},
+		{0x1681, 0x169a, 1},
+		{0x16a0, 0x16ea, 1},
+		{0x16ee, 0x16f8, 1},
+		{0x1700, 0x1715, 1},
+		{0x171f, 0x1734, 1},
+		{0x1740, 0x1753, 1},
+		{0x1760, 0x176c, 1},
+		{0x176e, 0x1770, 1},
+		{0x1772, 0x1773, 1},
+		{0x1780, 0x17d3, 1},
+		{0x17d7, 0x17dc, 5},
+		{0x17dd, 0x17e0, 3},
+		{0x17e1, 0x17e9, 1},
+		{0x180b, 0x180d, 1},
+		{0x180f, 0x1819, 1},
+		{0x1820, 0x1878, 1},
+		{0x1880, 0x18aa, 1},
+		{0x18b0, 0x18f5, 1},
+		{0x1900, 0x191e, 1},
+		{0x1920, 0x192b, 1},
+		{0x1930, 0x193b, 1},
+		{0x1946, 0x196d, 1},
+		{0x1970, 0x1974, 1},
+		{0x1980, 0x19ab, 1},
+		{0x19b0, 0x19c9, 1},
+		{0x19d0, 0x19da, 1},
+		{0x1a00, 0x1a1b, 1},
+		{0x1a20, 0x1a5e, 1},
+		{0x1a60, 0x1a7c, 1},
+		{0x1a7f, 0x1a89, 1},
+		{0x1a90, 0x1a99, 1},
+		{0x1aa7, 0x1ab0, 9},
+		{0x1ab1, 0x1abd, 1},
+		{0x1abf, 0x1add, 1},
+		{0x1ae0, 0x1aeb, 1},
+		{0x1b00, 0x1b4c, 1},
+		{0x1b50, 0x1b59, 1},
+		{0x1b6b, 0x1b73, 1},
+		{0x1b80, 0x1bf3, 1},
+		{0x1c00, 0x1c37, 1},
+		{0x1c40, 0x1c49, 1},
+		{0x1c4d, 0x1c7d, 1},
+		{0x1c80, 0x1c8a, 1},
+		{0x1c90, 0x1cba, 1},
+		{0x1cbd, 0x1cbf, 1},
+		{0x1cd0, 0x1cd2, 1},
+		{0x1cd4, 0x1cfa, 1},
+		{0x1d00, 0x1f15, 1},
+		{0x1f18, 0x1f1d, 1},
+		{0x1f20, 0x1f45, 1},
+		{0x1f48, 0x1f4d, 1},
+		{0x1f50, 0x1f57, 1},
+		{0x1f59, 0x1f5f, 2},
+		{0x1f60, 0x1f7d, 1},
+		{0x1f80, 0x1fb4, 1},
+		{0x1fb6, 0x1fbc, 1},
+		{0x1fbe, 0x1fc2, 4},
+		{0x1fc3, 0x1fc4, 1},
+		{0x1fc6, 0x1fcc, 1},
+		{0x1fd0, 0x1fd3, 1},
+		{0x1fd6, 0x1fdb, 1},
+		{0x1fe0, 0x1fec, 1},
+		{0x1ff2, 0x1ff4, 1},
+		{0x1ff6, 0x1ffc, 1},
+		{0x200c, 0x200d, 1},
+		{0x203f, 0x2040, 1},
+		{0x2054, 0x2071, 29},
+		{0x207f, 0x2090, 17},
+		{0x2091, 0x209c, 1},
+		{0x20d0, 0x20dc, 1},
+		{0x20e1, 0x20e5, 4},
+		{0x20e6, 0x20f0, 1},
+		{0x2102, 0x2107, 5},
+		{0x210a, 0x2113, 1},
+		{0x2115, 0x2118, 3},
+		{0x2119, 0x211d, 1},
+		{0x2124, 0x212a, 2},
+		{0x212b, 0x2139, 1},
+		{0x213c, 0x213f, 1},
+		{0x2145, 0x2149, 1},
+		{0x214e, 0x2160, 18},
+		{0x2161, 0x2188, 1},
+		{0x2c00, 0x2ce4, 1},
+		{0x2ceb, 0x2cf3, 1},
+		{0x2d00, 0x2d25, 1},
+		{0x2d27, 0x2d2d, 6},
+		{0x2d30, 0x2d67, 1},
+		{0x2d6f, 0x2d7f, 16},
+		{0x2d80, 0x2d96, 1},
+		{0x2da0, 0x2da6, 1},
+		{0x2da8, 0x2dae, 1},
+		{0x2db0, 0x2db6, 1},
+		{0x2db8, 0x2dbe, 1},
+		{0x2dc0, 0x2dc6, 1},
+		{0x2dc8, 0x2dce, 1},
+		{0x2dd0, 0x2dd6, 1},
+		{0x2dd8, 0x2dde, 1},
+		{0x2de0, 0x2dff, 1},
+		{0x3005, 0x3007, 1},
+		{0x3021, 0x302f, 1},
+		{0x3031, 0x3035, 1},
+		{0x3038, 0x303c, 1},
+		{0x3041, 0x3096, 1},
+		{0x3099, 0x309a, 1},
+		{0x309d, 0x309f, 1},
+		{0x30a1, 0x30ff, 1},
+		{0x3105, 0x312f, 1},
+		{0x3131, 0x318e, 1},
+		{0x31a0, 0x31bf, 1},
+		{0x31f0, 0x31ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0xa48c, 1},
+		{0xa4d0, 0xa4fd, 1},
+		{0xa500, 0xa60c, 1},
+		{0xa610, 0xa62b, 1},
+		{0xa640, 0xa66f, 1},
+		{0xa674, 0xa67d, 1},
+		{0xa67f, 0xa6f1, 1},
+		{0xa717, 0xa71f, 1},
+		{0xa722, 0xa788, 1},
+		{0xa78b, 0xa7dc, 1},
+		{0xa7f1, 0xa827, 1},
+		{0xa82c, 0xa840, 20},
+		{0xa841, 0xa873, 1},
+		{0xa880, 0xa8c5, 1},
+		{0xa8d0, 0xa8d9, 1},
+		{0xa8e0, 0xa8f7, 1},
+		{0xa8fb, 0xa8fd, 2},
+		{0xa8fe, 0xa92d, 1},
+		{0xa930, 0xa953, 1},
+		{0xa960, 0xa97c, 1},
+		{0xa980, 0xa9c0, 1},
+		{0xa9cf, 0xa9d9, 1},
+		{0xa9e0, 0xa9fe, 1},
+		{0xaa00, 0xaa36, 1},
+		{0xaa40, 0xaa4d, 1},
+		{0xaa50, 0xaa59, 1},
+		{0xaa60, 0xaa76, 1},
+		{0xaa7a, 0xaac2, 1},
+		{0xaadb, 0xaadd, 1},
+		{0xaae0, 0xaaef, 1},
+		{0xaaf2, 0xaaf6, 1},
+		{0xab01, 0xab06, 1},
+		{0xab09, 0xab0e, 1},
+		{0xab11, 0xab16, 1},
+		{0xab20, 0xab26, 1},
+		{0xab28, 0xab2e, 1},
+		{0xab30, 0xab5a, 1},
+		{0xab5c, 0xab69, 1},
+		{0xab70, 0xabea, 1},
+		{0xabec, 0xabed, 1},
+		{0xabf0, 0xabf9, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xd7b0, 0xd7c6, 1},
+		{0xd7cb, 0xd7fb, 1},
+		{0xf900, 0xfa6d, 1},
+		{0xfa70, 0xfad9, 1},
+		{0xfb00, 0xfb06, 1},
+		{0xfb13, 0xfb17, 1},
+		{0xfb1d, 0xfb28, 1},
+		{0xfb2a, 0xfb36, 1},
+		{0xfb38, 0xfb3c, 1},
+		{0xfb3e, 0xfb40, 2},
+		{0xfb41, 0xfb43, 2},
+		{0xfb44, 0xfb46, 2},
+		{0xfb47, 0xfbb1, 1},
+		{0xfbd3, 0xfc5d, 1},
+		{0xfc64, 0xfd3d, 1},
+		{0xfd50, 0xfd8f, 1},
+		{0xfd92, 0xfdc7, 1},
+		{0xfdf0, 0xfdf9, 1},
+		{0xfe00, 0xfe0f, 1},
+		{0xfe20, 0xfe2f, 1},
+		{0xfe33, 0xfe34, 1},
+		{0xfe4d, 0xfe4f, 1},
+		{0xfe71, 0xfe73, 2},
+		{0xfe77, 0xfe7f, 2},
+		{0xfe80, 0xfefc, 1},
+		{0xff10, 0xff19, 1},
+		{0xff21, 0xff3a, 1},
+		{0xff3f, 0xff41, 2},
+		{0xff42, 0xff5a, 1},
+		{0xff65, 0xffbe, 1},
+		{0xffc2, 0xffc7, 1},
+		{0xffca, 0xffcf, 1},
+		{0xffd2, 0xffd7, 1},
+		{0xffda, 0xffdc, 1},
+	},
+	R32: []Range32{
+		{0x10000, 0x1000b, 1},
+		{0x1000d, 0x10026, 1},
+		{0x10028, 0x1003a, 1},
+		{0x1003c, 0x1003d, 1},
+		{0x1003f, 0x1004d, 1},
+		{0x10050, 0x1005d, 1},
+		{0x10080, 0x100fa, 1},
+		{0x10140, 0x10174, 1},
+		{0x101fd, 0x10280, 131},
+		{0x10281, 0x1029c, 1},
+		{0x102a0, 0x102d0, 1},
+		{0x102e0, 0x10300, 32},
+		{0x10301, 0x1031f, 1},
+		{0x1032d, 0x1034a, 1},
+		{0x10350, 0x1037a, 1},
+		{0x10380, 0x1039d, 1},
+		{0x103a0, 0x103c3, 1},
+		{0x103c8, 0x103cf, 1},
+		{0x103d1, 0x103d5, 1},
+		{0x10400, 0x1049d, 1},
+		{0x104a0, 0x104a9, 1},
+		{0x104b0, 0x104d3, 1},
+		{0x104d8, 0x104fb, 1},
+		{0x10500, 0x10527, 1},
+		{0x10530, 0x10563, 1},
+		{0x10570, 0x1057a, 1},
+		{0x1057c, 0x1058a, 1},
+		{0x1058c, 0x10592, 1},
+		{0x10594, 0x10595, 1},
+		{0x10597, 0x105a1, 1},
+		{0x105a3, 0x105b1, 1},
+		{0x105b3, 0x105b9, 1},
+		{0x105bb, 0x105bc, 1},
+		{0x105c0, 0x105f3, 1},
+		{0x10600, 0x10736, 1},
+		{0x10740, 0x10755, 1},
+		{0x10760, 0x10767, 1},
+		{0x10780, 0x10785, 1},
+		{0x10787, 0x107b0, 1},
+		{0x107b2, 0x107ba, 1},
+		{0x10800, 0x10805, 1},
+		{0x10808, 0x1080a, 2},
+		{0x1080b, 0x10835, 1},
+		{0x10837, 0x10838, 1},
+		{0x1083c, 0x1083f, 3},
+		{0x10840, 0x10855, 1},
+		{0x10860, 0x10876, 1},
+		{0x10880, 0x1089e, 1},
+		{0x108e0, 0x108f2, 1},
+		{0x108f4, 0x108f5, 1},
+		{0x10900, 0x10915, 1},
+		{0x10920, 0x10939, 1},
+		{0x10940, 0x10959, 1},
+		{0x10980, 0x109b7, 1},
+		{0x109be, 0x109bf, 1},
+		{0x10a00, 0x10a03, 1},
+		{0x10a05, 0x10a06, 1},
+		{0x10a0c, 0x10a13, 1},
+		{0x10a15, 0x10a17, 1},
+		{0x10a19, 0x10a35, 1},
+		{0x10a38, 0x10a3a, 1},
+		{0x10a3f, 0x10a60, 33},
+		{0x10a61, 0x10a7c, 1},
+		{0x10a80, 0x10a9c, 1},
+		{0x10ac0, 0x10ac7, 1},
+		{0x10ac9, 0x10ae6, 1},
+		{0x10b00, 0x10b35, 1},
+		{0x10b40, 0x10b55, 1},
+		{0x10b60, 0x10b72, 1},
+		{0x10b80, 0x10b91, 1},
+		{0x10c00, 0x10c48, 1},
+		{0x10c80, 0x10cb2, 1},
+		{0x10cc0, 0x10cf2, 1},
+		{0x10d00, 0x10d27, 1},
+		{0x10d30, 0x10d39, 1},
+		{0x10d40, 0x10d65, 1},
+		{0x10d69, 0x10d6d, 1},
+		{0x10d6f, 0x10d85, 1},
+		{0x10e80, 0x10ea9, 1},
+		{0x10eab, 0x10eac, 1},
+		{0x10eb0, 0x10eb1, 1},
+		{0x10ec2, 0x10ec7, 1},
+		{0x10efa, 0x10f1c, 1},
+		{0x10f27, 0x10f30, 9},
+		{0x10f31, 0x10f50, 1},
+		{0x10f70, 0x10f85, 1},
+		{0x10fb0, 0x10fc4, 1},
+		{0x10fe0, 0x10ff6, 1},
+		{0x11000, 0x11046, 1},
+		{0x11066, 0x11075, 1},
+		{0x1107f, 0x110ba, 1},
+		{0x110c2, 0x110d0, 14},
+		{0x110d1, 0x110e8, 1},
+		{0x110f0, 0x110f9, 1},
+		{0x11100, 0x11134, 1},
+		{0x11136, 0x1113f, 1},
+		{0x11144, 0x11147, 1},
+		{0x11150, 0x11173, 1},
+		{0x11176, 0x11180, 10},
+		{0x11181, 0x111c4, 1},
+		{0x111c9, 0x111cc, 1},
+		{0x111ce, 0x111da, 1},
+		{0x111dc, 0x11200, 36},
+		{0x11201, 0x11211, 1},
+		{0x11213, 0x11237, 1},
+		{0x1123e, 0x11241, 1},
+		{0x11280, 0x11286, 1},
+		{0x11288, 0x1128a, 2},
+		{0x1128b, 0x1128d, 1},
+		{0x1128f, 0x1129d, 1},
+		{0x1129f, 0x112a8, 1},
+		{0x112b0, 0x112ea, 1},
+		{0x112f0, 0x112f9, 1},
+		{0x11300, 0x11303, 1},
+		{0x11305, 0x1130c, 1},
+		{0x1130f, 0x11310, 1},
+		{0x11313, 0x11328, 1},
+		{0x1132a, 0x11330, 1},
+		{0x11332, 0x11333, 1},
+		{0x11335, 0x11339, 1},
+		{0x1133b, 0x11344, 1},
+		{0x11347, 0x11348, 1},
+		{0x1134b, 0x1134d, 1},
+		{0x11350, 0x11357, 7},
+		{0x1135d, 0x11363, 1},
+		{0x11366, 0x1136c, 1},
+		{0x11370, 0x11374, 1},
+		{0x11380, 0x11389, 1},
+		{0x1138b, 0x1138e, 3},
+		{0x11390, 0x113b5, 1},
+		{0x113b7, 0x113c0, 1},
+		{0x113c2, 0x113c5, 3},
+		{0x113c7, 0x113ca, 1},
+		{0x113cc, 0x113d3, 1},
+		{0x113e1, 0x113e2, 1},
+		{0x11400, 0x1144a, 1},
+		{0x11450, 0x11459, 1},
+		{0x1145e, 0x11461, 1},
+		{0x11480, 0x114c5, 1},
+		{0x114c7, 0x114d0, 9},
+		{0x114d1, 0x114d9, 1},
+		{0x11580, 0x115b5, 1},
+		{0x115b8, 0x115c0, 1},
+		{0x115d8, 0x115dd, 1},
+		{0x11600, 0x11640, 1},
+		{0x11644, 0x11650, 12},
+		{0x11651, 0x11659, 1},
+		{0x11680, 0x116b8, 1},
+		{0x116c0, 0x116c9, 1},
+		{0x116d0, 0x116e3, 1},
+		{0x11700, 0x1171a, 1},
+		{0x1171d, 0x1172b, 1},
+		{0x11730, 0x11739, 1},
+		{0x11740, 0x11746, 1},
+		{0x11800, 0x1183a, 1},
+		{0x118a0, 0x118e9, 1},
+		{0x118ff, 0x11906, 1},
+		{0x11909, 0x1190c, 3},
+		{0x1190d, 0x11913, 1},
+		{0x11915, 0x11916, 1},
+		{0x11918, 0x11935, 1},
+		{0x11937, 0x11938, 1},
+		{0x1193b, 0x11943, 1},
+		{0x11950, 0x11959, 1},
+		{0x119a0, 0x119a7, 1},
+		{0x119aa, 0x119d7, 1},
+		{0x119da, 0x119e1, 1},
+		{0x119e3, 0x119e4, 1},
+		{0x11a00, 0x11a3e, 1},
+		{0x11a47, 0x11a50, 9},
+		{0x11a51, 0x11a99, 1},
+		{0x11a9d, 0x11ab0, 19},
+		{0x11ab1, 0x11af8, 1},
+		{0x11b60, 0x11b67, 1},
+		{0x11bc0, 0x11be0, 1},
+		{0x11bf0, 0x11bf9, 1},
+		{0x11c00, 0x11c08, 1},
+		{0x11c0a, 0x11c36, 1},
+		{0x11c38, 0x11c40, 1},
+		{0x11c50, 0x11c59, 1},
+		{0x11c72, 0x11c8f, 1},
+		{0x11c92, 0x11ca7, 1},
+		{0x11ca9, 0x11cb6, 1},
+		{0x11d00, 0x11d06, 1},
+		{0x11d08, 0x11d09, 1},
+		{0x11d0b, 0x11d36, 1},
+		{0x11d3a, 0x11d3c, 2},
+		{0x11d3d, 0x11d3f, 2},
+		{0x11d40, 0x11d47, 1},
+		{0x11d50, 0x11d59, 1},
+		{0x11d60, 0x11d65, 1},
+		{0x11d67, 0x11d68, 1},
+		{0x11d6a, 0x11d8e, 1},
+		{0x11d90, 0x11d91, 1},
+		{0x11d93, 0x11d98, 1},
+		{0x11da0, 0x11da9, 1},
+		{0x11db0, 0x11ddb, 1},
+		{0x11de0, 0x11de9, 1},
+		{0x11ee0, 0x11ef6, 1},
+		{0x11f00, 0x11f10, 1},
+		{0x11f12, 0x11f3a, 1},
+		{0x11f3e, 0x11f42, 1},
+		{0x11f50, 0x11f5a, 1},
+		{0x11fb0, 0x12000, 80},
+		{0x12001, 0x12399, 1},
+		{0x12400, 0x1246e, 1},
+		{0x12480, 0x12543, 1},
+		{0x12f90, 0x12ff0, 1},
+		{0x13000, 0x1342f, 1},
+		{0x13440, 0x13455, 1},
+		{0x13460, 0x143fa, 1},
+		{0x14400, 0x14646, 1},
+		{0x16100, 0x16139, 1},
+		{0x16800, 0x16a38, 1},
+		{0x16a40, 0x16a5e, 1},
+		{0x16a60, 0x16a69, 1},
+		{0x16a70, 0x16abe, 1},
+		{0x16ac0, 0x16ac9, 1},
+		{0x16ad0, 0x16aed, 1},
+		{0x16af0, 0x16af4, 1},
+		{0x16b00, 0x16b36, 1},
+		{0x16b40, 0x16b43, 1},
+		{0x16b50, 0x16b59, 1},
+		{0x16b63, 0x16b77, 1},
+		{0x16b7d, 0x16b8f, 1},
+		{0x16d40, 0x16d6c, 1},
+		{0x16d70, 0x16d79, 1},
+		{0x16e40, 0x16e7f, 1},
+		{0x16ea0, 0x16eb8, 1},
+		{0x16ebb, 0x16ed3, 1},
+		{0x16f00, 0x16f4a, 1},
+		{0x16f4f, 0x16f87, 1},
+		{0x16f8f, 0x16f9f, 1},
+		{0x16fe0, 0x16fe1, 1},
+		{0x16fe3, 0x16fe4, 1},
+		{0x16ff0, 0x16ff6, 1},
+		{0x17000, 0x18cd5, 1},
+		{0x18cff, 0x18d1e, 1},
+		{0x18d80, 0x18df2, 1},
+		{0x1aff0, 0x1aff3, 1},
+		{0x1aff5, 0x1affb, 1},
+		{0x1affd, 0x1affe, 1},
+		{0x1b000, 0x1b122, 1},
+		{0x1b132, 0x1b150, 30},
+		{0x1b151, 0x1b152, 1},
+		{0x1b155, 0x1b164, 15},
+		{0x1b165, 0x1b167, 1},
+		{0x1b170, 0x1b2fb, 1},
+		{0x1bc00, 0x1bc6a, 1},
+		{0x1bc70, 0x1bc7c, 1},
+		{0x1bc80, 0x1bc88, 1},
+		{0x1bc90, 0x1bc99, 1},
+		{0x1bc9d, 0x1bc9e, 1},
+		{0x1ccf0, 0x1ccf9, 1},
+		{0x1cf00, 0x1cf2d, 1},
+		{0x1cf30, 0x1cf46, 1},
+		{0x1d165, 0x1d169, 1},
+		{0x1d16d, 0x1d172, 1},
+		{0x1d17b, 0x1d182, 1},
+		{0x1d185, 0x1d18b, 1},
+		{0x1d1aa, 0x1d1ad, 1},
+		{0x1d242, 0x1d244, 1},
+		{0x1d400, 0x1d454, 1},
+		{0x1d456, 0x1d49c, 1},
+		{0x1d49e, 0x1d49f, 1},
+		{0x1d4a2, 0x1d4a5, 3},
+		{0x1d4a6, 0x1d4a9, 3},
+		{0x1d4aa, 0x1d4ac, 1},
+		{0x1d4ae, 0x1d4b9, 1},
+		{0x1d4bb, 0x1d4bd, 2},
+		{0x1d4be, 0x1d4c3, 1},
+		{0x1d4c5, 0x1d505, 1},
+		{0x1d507, 0x1d50a, 1},
+		{0x1d50d, 0x1d514, 1},
+		{0x1d516, 0x1d51c, 1},
+		{0x1d51e, 0x1d539, 1},
+		{0x1d53b, 0x1d53e, 1},
+		{0x1d540, 0x1d544, 1},
+		{0x1d546, 0x1d54a, 4},
+		{0x1d54b, 0x1d550, 1},
+		{0x1d552, 0x1d6a5, 1},
+		{0x1d6a8, 0x1d6c0, 1},
+		{0x1d6c2, 0x1d6da, 1},
+		{0x1d6dc, 0x1d6fa, 1},
+		{0x1d6fc, 0x1d714, 1},
+		{0x1d716, 0x1d734, 1},
+		{0x1d736, 0x1d74e, 1},
+		{0x1d750, 0x1d76e, 1},
+		{0x1d770, 0x1d788, 1},
+		{0x1d78a, 0x1d7a8, 1},
+		{0x1d7aa, 0x1d7c2, 1},
+		{0x1d7c4, 0x1d7cb, 1},
+		{0x1d7ce, 0x1d7ff, 1},
+		{0x1da00, 0x1da36, 1},
+		{0x1da3b, 0x1da6c, 1},
+		{0x1da75, 0x1da84, 15},
+		{0x1da9b, 0x1da9f, 1},
+		{0x1daa1, 0x1daaf, 1},
+		{0x1df00, 0x1df1e, 1},
+		{0x1df25, 0x1df2a, 1},
+		{0x1e000, 0x1e006, 1},
+		{0x1e008, 0x1e018, 1},
+		{0x1e01b, 0x1e021, 1},
+		{0x1e023, 0x1e024, 1},
+		{0x1e026, 0x1e02a, 1},
+		{0x1e030, 0x1e06d, 1},
+		{0x1e08f, 0x1e100, 113},
+		{0x1e101, 0x1e12c, 1},
+		{0x1e130, 0x1e13d, 1},
+		{0x1e140, 0x1e149, 1},
+		{0x1e14e, 0x1e290, 322},
+		{0x1e291, 0x1e2ae, 1},
+		{0x1e2c0, 0x1e2f9, 1},
+		{0x1e4d0, 0x1e4f9, 1},
+		{0x1e5d0, 0x1e5fa, 1},
+		{0x1e6c0, 0x1e6de, 1},
+		{0x1e6e0, 0x1e6f5, 1},
+		{0x1e6fe, 0x1e6ff, 1},
+		{0x1e7e0, 0x1e7e6, 1},
+		{0x1e7e8, 0x1e7eb, 1},
+		{0x1e7ed, 0x1e7ee, 1},
+		{0x1e7f0, 0x1e7fe, 1},
+		{0x1e800, 0x1e8c4, 1},
+		{0x1e8d0, 0x1e8d6, 1},
+		{0x1e900, 0x1e94b, 1},
+		{0x1e950, 0x1e959, 1},
+		{0x1ee00, 0x1ee03, 1},
+		{0x1ee05, 0x1ee1f, 1},
+		{0x1ee21, 0x1ee22, 1},
+		{0x1ee24, 0x1ee27, 3},
+		{0x1ee29, 0x1ee32, 1},
+		{0x1ee34, 0x1ee37, 1},
+		{0x1ee39, 0x1ee3b, 2},
+		{0x1ee42, 0x1ee47, 5},
+		{0x1ee49, 0x1ee4d, 2},
+		{0x1ee4e, 0x1ee4f, 1},
+		{0x1ee51, 0x1ee52, 1},
+		{0x1ee54, 0x1ee57, 3},
+		{0x1ee59, 0x1ee61, 2},
+		{0x1ee62, 0x1ee64, 2},
+		{0x1ee67, 0x1ee6a, 1},
+		{0x1ee6c, 0x1ee72, 1},
+		{0x1ee74, 0x1ee77, 1},
+		{0x1ee79, 0x1ee7c, 1},
+		{0x1ee7e, 0x1ee80, 2},
+		{0x1ee81, 0x1ee89, 1},
+		{0x1ee8b, 0x1ee9b, 1},
+		{0x1eea1, 0x1eea3, 1},
+		{0x1eea5, 0x1eea9, 1},
+		{0x1eeab, 0x1eebb, 1},
+		{0x1fbf0, 0x1fbf9, 1},
+		{0x20000, 0x2a6df, 1},
+		{0x2a700, 0x2b81d, 1},
+		{0x2b820, 0x2cead, 1},
+		{0x2ceb0, 0x2ebe0, 1},
+		{0x2ebf0, 0x2ee5d, 1},
+		{0x2f800, 0x2fa1d, 1},
+		{0x30000, 0x3134a, 1},
+		{0x31350, 0x33479, 1},
+		{0xe0100, 0xe01ef, 1},
+	},
+	LatinOffset: 8,
+}
+
+// Bit masks for each code point under U+0100, for fast lookup.
+const (
+	pDigit = 1 << iota
+	pLetter
+	pMark
+	pWhite_Space
+	pXID_Start
+	pXID_Continue
+)
+
+var properties = [MaxLatin1 + 1]uint8{
+	0x09: pWhite_Space,                         // '\t'
+	0x0A: pWhite_Space,                         // '\n'
+	0x0B: pWhite_Space,                         // '\v'
+	0x0C: pWhite_Space,                         // '\f'
+	0x0D: pWhite_Space,                         // '\r'
+	0x20: pWhite_Space,                         // ' '
+	0x30: pDigit | pXID_Continue,               // '0'
+	0x31: pDigit | pXID_Continue,               // '1'
+	0x32: pDigit | pXID_Continue,               // '2'
+	0x33: pDigit | pXID_Continue,               // '3'
+	0x34: pDigit | pXID_Continue,               // '4'
+	0x35: pDigit | pXID_Continue,               // '5'
+	0x36: pDigit | pXID_Continue,               // '6'
+	0x37: pDigit | pXID_Continue,               // '7'
+	0x38: pDigit | pXID_Continue,               // '8'
+	0x39: pDigit | pXID_Continue,               // '9'
+	0x41: pLetter | pXID_Start | pXID_Continue, // 'A'
+	0x42: pLetter | pXID_Start | pXID_Continue, // 'B'
+	0x43: pLetter | pXID_Start | pXID_Continue, // 'C'
+	0x44: pLetter | pXID_Start | pXID_Continue, // 'D'
+	0x45: pLetter | pXID_Start | pXID_Continue, // 'E'
+	0x46: pLetter | pXID_Start | pXID_Continue, // 'F'
+	0x47: pLetter | pXID_Start | pXID_Continue, // 'G'
+	0x48: pLetter | pXID_Start | pXID_Continue, // 'H'
+	0x49: pLetter | pXID_Start | pXID_Continue, // 'I'
+	0x4A: pLetter | pXID_Start | pXID_Continue, // 'J'
+	0x4B: pLetter | pXID_Start | pXID_Continue, // 'K'
+	0x4C: pLetter | pXID_Start | pXID_Continue, // 'L'
+	0x4D: pLetter | pXID_Start | pXID_Continue, // 'M'
+	0x4E: pLetter | pXID_Start | pXID_Continue, // 'N'
+	0x4F: pLetter | pXID_Start | pXID_Continue, // 'O'
+	0x50: pLetter | pXID_Start | pXID_Continue, // 'P'
+	0x51: pLetter | pXID_Start | pXID_Continue, // 'Q'
+	0x52: pLetter | pXID_Start | pXID_Continue, // 'R'
+	0x53: pLetter | pXID_Start | pXID_Continue, // 'S'
+	0x54: pLetter | pXID_Start | pXID_Continue, // 'T'
+	0x55: pLetter | pXID_Start | pXID_Continue, // 'U'
+	0x56: pLetter | pXID_Start | pXID_Continue, // 'V'
+	0x57: pLetter | pXID_Start | pXID_Continue, // 'W'
+	0x58: pLetter | pXID_Start | pXID_Continue, // 'X'
+	0x59: pLetter | pXID_Start | pXID_Continue, // 'Y'
+	0x5A: pLetter | pXID_Start | pXID_Continue, // 'Z'
+	0x5F: pXID_Continue,                        // '_'
+	0x61: pLetter | pXID_Start | pXID_Continue, // 'a'
+	0x62: pLetter | pXID_Start | pXID_Continue, // 'b'
+	0x63: pLetter | pXID_Start | pXID_Continue, // 'c'
+	0x64: pLetter | pXID_Start | pXID_Continue, // 'd'
+	0x65: pLetter | pXID_Start | pXID_Continue, // 'e'
+	0x66: pLetter | pXID_Start | pXID_Continue, // 'f'
+	0x67: pLetter | pXID_Start | pXID_Continue, // 'g'
+	0x68: pLetter | pXID_Start | pXID_Continue, // 'h'
+	0x69: pLetter | pXID_Start | pXID_Continue, // 'i'
+	0x6A: pLetter | pXID_Start | pXID_Continue, // 'j'
+	0x6B: pLetter | pXID_Start | pXID_Continue, // 'k'
+	0x6C: pLetter | pXID_Start | pXID_Continue, // 'l'
+	0x6D: pLetter | pXID_Start | pXID_Continue, // 'm'
+	0x6E: pLetter | pXID_Start | pXID_Continue, // 'n'
+	0x6F: pLetter | pXID_Start | pXID_Continue, // 'o'
+	0x70: pLetter | pXID_Start | pXID_Continue, // 'p'
+	0x71: pLetter | pXID_Start | pXID_Continue, // 'q'
+	0x72: pLetter | pXID_Start | pXID_Continue, // 'r'
+	0x73: pLetter | pXID_Start | pXID_Continue, // 's'
+	0x74: pLetter | pXID_Start | pXID_Continue, // 't'
+	0x75: pLetter | pXID_Start | pXID_Continue, // 'u'
+	0x76: pLetter | pXID_Start | pXID_Continue, // 'v'
+	0x77: pLetter | pXID_Start | pXID_Continue, // 'w'
+	0x78: pLetter | pXID_Start | pXID_Continue, // 'x'
+	0x79: pLetter | pXID_Start | pXID_Continue, // 'y'
+	0x7A: pLetter | pXID_Start | pXID_Continue, // 'z'
+	0x85: pWhite_Space,                         // '\u0085'
+	0xA0: pWhite_Space,                         // '\u00a0'
+	0xAA: pLetter | pXID_Start | pXID_Continue, // 'ª'
+	0xB5: pLetter | pXID_Start | pXID_Continue, // 'µ'
+	0xB7: pXID_Continue,                        // '·'
+	0xBA: pLetter | pXID_Start | pXID_Continue, // 'º'
+	0xC0: pLetter | pXID_Start | pXID_Continue, // 'À'
+	0xC1: pLetter | pXID_Start | pXID_Continue, // 'Á'
+	0xC2: pLetter | pXID_Start | pXID_Continue, // 'Â'
+	0xC3: pLetter | pXID_Start | pXID_Continue, // 'Ã'
+	0xC4: pLetter | pXID_Start | pXID_Continue, // 'Ä'
+	0xC5: pLetter | pXID_Start | pXID_Continue, // 'Å'
+	0xC6: pLetter | pXID_Start | pXID_Continue, // 'Æ'
+	0xC7: pLetter | pXID_Start | pXID_Continue, // 'Ç'
+	0xC8: pLetter | pXID_Start | pXID_Continue, // 'È'
+	0xC9: pLetter | pXID_Start | pXID_Continue, // 'É'
+	0xCA: pLetter | pXID_Start | pXID_Continue, // 'Ê'
+	0xCB: pLetter | pXID_Start | pXID_Continue, // 'Ë'
+	0xCC: pLetter | pXID_Start | pXID_Continue, // 'Ì'
+	0xCD: pLetter | pXID_Start | pXID_Continue, // 'Í'
+	0xCE: pLetter | pXID_Start | pXID_Continue, // 'Î'
+	0xCF: pLetter | pXID_Start | pXID_Continue, // 'Ï'
+	0xD0: pLetter | pXID_Start | pXID_Continue, // 'Ð'
+	0xD1: pLetter | pXID_Start | pXID_Continue, // 'Ñ'
+	0xD2: pLetter | pXID_Start | pXID_Continue, // 'Ò'
+	0xD3: pLetter | pXID_Start | pXID_Continue, // 'Ó'
+	0xD4: pLetter | pXID_Start | pXID_Continue, // 'Ô'
+	0xD5: pLetter | pXID_Start | pXID_Continue, // 'Õ'
+	0xD6: pLetter | pXID_Start | pXID_Continue, // 'Ö'
+	0xD8: pLetter | pXID_Start | pXID_Continue, // 'Ø'
+	0xD9: pLetter | pXID_Start | pXID_Continue, // 'Ù'
+	0xDA: pLetter | pXID_Start | pXID_Continue, // 'Ú'
+	0xDB: pLetter | pXID_Start | pXID_Continue, // 'Û'
+	0xDC: pLetter | pXID_Start | pXID_Continue, // 'Ü'
+	0xDD: pLetter | pXID_Start | pXID_Continue, // 'Ý'
+	0xDE: pLetter | pXID_Start | pXID_Continue, // 'Þ'
+	0xDF: pLetter | pXID_Start | pXID_Continue, // 'ß'
+	0xE0: pLetter | pXID_Start | pXID_Continue, // 'à'
+	0xE1: pLetter | pXID_Start | pXID_Continue, // 'á'
+	0xE2: pLetter | pXID_Start | pXID_Continue, // 'â'
+	0xE3: pLetter | pXID_Start | pXID_Continue, // 'ã'
+	0xE4: pLetter | pXID_Start | pXID_Continue, // 'ä'
+	0xE5: pLetter | pXID_Start | pXID_Continue, // 'å'
+	0xE6: pLetter | pXID_Start | pXID_Continue, // 'æ'
+	0xE7: pLetter | pXID_Start | pXID_Continue, // 'ç'
+	0xE8: pLetter | pXID_Start | pXID_Continue, // 'è'
+	0xE9: pLetter | pXID_Start | pXID_Continue, // 'é'
+	0xEA: pLetter | pXID_Start | pXID_Continue, // 'ê'
+	0xEB: pLetter | pXID_Start | pXID_Continue, // 'ë'
+	0xEC: pLetter | pXID_Start | pXID_Continue, // 'ì'
+	0xED: pLetter | pXID_Start | pXID_Continue, // 'í'
+	0xEE: pLetter | pXID_Start | pXID_Continue, // 'î'
+	0xEF: pLetter | pXID_Start | pXID_Continue, // 'ï'
+	0xF0: pLetter | pXID_Start | pXID_Continue, // 'ð'
+	0xF1: pLetter | pXID_Start | pXID_Continue, // 'ñ'
+	0xF2: pLetter | pXID_Start | pXID_Continue, // 'ò'
+	0xF3: pLetter | pXID_Start | pXID_Continue, // 'ó'
+	0xF4: pLetter | pXID_Start | pXID_Continue, // 'ô'
+	0xF5: pLetter | pXID_Start | pXID_Continue, // 'õ'
+	0xF6: pLetter | pXID_Start | pXID_Continue, // 'ö'
+	0xF8: pLetter | pXID_Start | pXID_Continue, // 'ø'
+	0xF9: pLetter | pXID_Start | pXID_Continue, // 'ù'
+	0xFA: pLetter | pXID_Start | pXID_Continue, // 'ú'
+	0xFB: pLetter | pXID_Start | pXID_Continue, // 'û'
+	0xFC: pLetter | pXID_Start | pXID_Continue, // 'ü'
+	0xFD: pLetter | pXID_Start | pXID_Continue, // 'ý'
+	0xFE: pLetter | pXID_Start | pXID_Continue, // 'þ'
+	0xFF: pLetter | pXID_Start | pXID_Continue, // 'ÿ'
+}
@@ -0,0 +1,135 @@
+// Package highlight classifies alipp tokens into semantic categories for
+// syntax highlighting. It runs the real lexer, so Cyrillic identifiers
+// and keywords are recognised exactly as the language sees them.
+package highlight
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+type Category string
+
+const (
+	Keyword     Category = "keyword"
+	Identifier  Category = "identifier"
+	Number      Category = "number"
+	String      Category = "string"
+	Operator    Category = "operator"
+	Punctuation Category = "punctuation"
+	Invalid     Category = "invalid"
+)
+
+var categories = map[token.TokenType]Category{
+	token.IDENT:       Identifier,
+	token.INT:         Number,
+	token.FLOAT:       Number,
+	token.STRING:      String,
+	token.ILLEGAL:     Invalid,
+	token.ASSIGN:      Operator,
+	token.PLUS:        Operator,
+	token.MINUS:       Operator,
+	token.EXCLAMATION: Operator,
+	token.ASTERISK:    Operator,
+	token.SLASH:       Operator,
+	token.LT:          Operator,
+	token.GT:          Operator,
+	token.EQ:          Operator,
+	token.NOT_EQ:      Operator,
+	token.ARROW:       Operator,
+	token.COMMA:       Punctuation,
+	token.SEMICOLON:   Punctuation,
+	token.COLON:       Punctuation,
+	token.LPAREN:      Punctuation,
+	token.RPAREN:      Punctuation,
+	token.LBRACE:      Punctuation,
+	token.RBRACE:      Punctuation,
+	token.LBRACKET:    Punctuation,
+	token.RBRACKET:    Punctuation,
+	token.DOT:         Punctuation,
+	token.ELLIPSIS:    Punctuation,
+}
+
+// Classify returns the category of a token type.
+func Classify(tokenType token.TokenType) Category {
+	if category, ok := categories[tokenType]; ok {
+		return category
+	}
+
+	if token.IsKeyword(tokenType) {
+		return Keyword
+	}
+
+	return Invalid
+}
+
+// Span is a classified token. Start and End are rune offsets into the
+// source, End being exclusive.
+type Span struct {
+	Category Category
+	Token    token.Token
+	Start    int
+	End      int
+}
+
+// Spans lexes source and returns one span per token, in source order.
+// Keywords are spelled as in dictionary unless source has a "тил"
+// comment; a nil dictionary stands for the default one.
+func Spans(source string, dictionary *token.Dictionary) []Span {
+	lineStarts := []int{0}
+	offset := 0
+	for _, r := range source {
+		offset++
+		if r == '\n' {
+			lineStarts = append(lineStarts, offset)
+		}
+	}
+
+	runes := []rune(source)
+	spans := []Span{}
+	lexerInstance := lexer.New(source)
+	if dictionary != nil {
+		lexerInstance.UseDictionary(dictionary)
+	}
+	for tok := lexerInstance.NextToken(); tok.Type != token.EOF; tok = lexerInstance.NextToken() {
+		start := lineStarts[tok.Line-1] + tok.Column - 1
+		end := start + utf8.RuneCountInString(tok.Literal)
+		if tok.Type == token.STRING {
+			// The literal leaves out the quotes; the closing one is
+			// missing when the string is unterminated.
+			end++
+			if end < len(runes) && runes[end] == '"' {
+				end++
+			}
+		}
+
+		spans = append(spans, Span{
+			Category: Classify(tok.Type),
+			Token:    tok,
+			Start:    start,
+			End:      end,
+		})
+	}
+
+	return spans
+}
+
+// render copies source, passing every token through wrap and every gap
+// between tokens through gap.
+func render(source string, dictionary *token.Dictionary, wrap func(Category, string) string, gap func(string) string) string {
+	var out strings.Builder
+
+	runes := []rune(source)
+	position := 0
+	for _, span := range Spans(source, dictionary) {
+		out.WriteString(gap(string(runes[position:span.Start])))
+		out.WriteString(wrap(span.Category, string(runes[span.Start:span.End])))
+		position = span.End
+	}
+	out.WriteString(gap(string(runes[position:])))
+
+	return out.String()
+}
@@ -0,0 +1,79 @@
+package highlight
+
+import (
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+func TestSpans(t *testing.T) {
+	input := "сакта өлчөм = 10;\nэгер (өлчөм < 5) { кайтар \"кичине\"; } @ \"бүтпөгөн"
+
+	tests := []struct {
+		expectedCategory Category
+		expectedText     string
+	}{
+		{Keyword, "сакта"},
+		{Identifier, "өлчөм"},
+		{Operator, "="},
+		{Number, "10"},
+		{Punctuation, ";"},
+		{Keyword, "эгер"},
+		{Punctuation, "("},
+		{Identifier, "өлчөм"},
+		{Operator, "<"},
+		{Number, "5"},
+		{Punctuation, ")"},
+		{Punctuation, "{"},
+		{Keyword, "кайтар"},
+		{String, "\"кичине\""},
+		{Punctuation, ";"},
+		{Punctuation, "}"},
+		{Invalid, "@"},
+		{String, "\"бүтпөгөн"},
+	}
+
+	runes := []rune(input)
+	spans := Spans(input, nil)
+	if len(spans) != len(tests) {
+		t.Fatalf("wrong number of spans. want=%d, got=%d", len(tests), len(spans))
+	}
+
+	for i, tt := range tests {
+		span := spans[i]
+		if span.Category != tt.expectedCategory {
+			t.Errorf("spans[%d] - category wrong. want=%q, got=%q", i, tt.expectedCategory, span.Category)
+		}
+
+		if text := string(runes[span.Start:span.End]); text != tt.expectedText {
+			t.Errorf("spans[%d] - range wrong. want=%q, got=%q", i, tt.expectedText, text)
+		}
+	}
+}
+
+func TestANSI(t *testing.T) {
+	got := ANSI("сакта x = 5;", nil)
+	want := "\x1b[1;35mсакта\x1b[0m \x1b[36mx\x1b[0m \x1b[37m=\x1b[0m \x1b[33m5\x1b[0m\x1b[90m;\x1b[0m"
+
+	if got != want {
+		t.Errorf("ANSI output wrong.\nwant=%q\ngot= %q", want, got)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	got := HTML("a < b\n", nil)
+	want := `<span class="alipp-identifier">a</span> <span class="alipp-operator">&lt;</span> <span class="alipp-identifier">b</span>` + "\n"
+
+	if got != want {
+		t.Errorf("HTML output wrong.\nwant=%q\ngot= %q", want, got)
+	}
+}
+
+func TestSpansDictionary(t *testing.T) {
+	kazakh, _ := token.LookupDictionary("kk")
+	spans := Spans("сақта сакта", kazakh)
+
+	if len(spans) != 2 || spans[0].Category != Keyword || spans[1].Category != Identifier {
+		t.Errorf("Kazakh keywords not highlighted. got=%+v", spans)
+	}
+}
@@ -0,0 +1,38 @@
+package highlight
+
+import (
+	"html"
+
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+const ansiReset = "\x1b[0m"
+
+var ansiColors = map[Category]string{
+	Keyword:     "\x1b[1;35m",
+	Identifier:  "\x1b[36m",
+	Number:      "\x1b[33m",
+	String:      "\x1b[32m",
+	Operator:    "\x1b[37m",
+	Punctuation: "\x1b[90m",
+	Invalid:     "\x1b[4;31m",
+}
+
+// ANSI returns source with escape sequences colouring each token for a
+// terminal. Whitespace is copied unchanged.
+func ANSI(source string, dictionary *token.Dictionary) string {
+	return render(source, dictionary, func(category Category, text string) string {
+		return ansiColors[category] + text + ansiReset
+	}, func(text string) string {
+		return text
+	})
+}
+
+// HTML returns source escaped for HTML with every token wrapped in
+// <span class="alipp-CATEGORY">. The caller supplies the surrounding
+// <pre> element and the stylesheet.
+func HTML(source string, dictionary *token.Dictionary) string {
+	return render(source, dictionary, func(category Category, text string) string {
+		return `<span class="alipp-` + string(category) + `">` + html.EscapeString(text) + `</span>`
+	}, html.EscapeString)
+}
@@ -1,20 +1,105 @@
 package lexer
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
 	unicode "github.com/asanoviskhak/alipp/src/helpers"
 
+	"github.com/asanoviskhak/alipp/src/norm"
 	"github.com/asanoviskhak/alipp/src/token"
 )
 
+// Lexer decodes UTF-8 as it goes, either from input or, when created by
+// NewReader, from reader. position and readPosition are byte offsets of
+// ch and of the rune after it.
 type Lexer struct {
-	input        []rune
+	input        string
+	reader       *bufio.Reader
 	position     int
 	readPosition int
 	ch           rune
+
+	// line and column locate ch in the input, both 1-based.
+	line   int
+	column int
+
+	errors []Error
+	failed bool
+	// invalid is the undecodable byte ch stands for when ch is
+	// utf8.RuneError read from a single byte.
+	invalid byte
+
+	// dictionary spells the keywords. A "// тил: <name>" comment before
+	// the first token replaces it; pragma is the name's position then.
+	dictionary *token.Dictionary
+	pragma     *token.Token
+	started    bool
+}
+
+// Error reports input the lexer could not decode. The offending bytes
+// are lexed as ILLEGAL tokens.
+type Error struct {
+	Message string
+	Line    int
+	Column  int
+	Offset  int
+}
+
+// UseDictionary sets the keyword dictionary for input without a "тил"
+// comment.
+func (lexerInstance *Lexer) UseDictionary(dictionary *token.Dictionary) {
+	if lexerInstance.pragma == nil {
+		lexerInstance.dictionary = dictionary
+	}
+}
+
+// Dictionary returns the keyword dictionary in use.
+func (lexerInstance *Lexer) Dictionary() *token.Dictionary {
+	return lexerInstance.dictionary
+}
+
+// Pragma returns the dictionary name of the "тил" comment, positioned
+// where the name starts, once the lexer has read past it. The token is
+// ILLEGAL when there is no dictionary of that name.
+func (lexerInstance *Lexer) Pragma() (token.Token, bool) {
+	if lexerInstance.pragma == nil {
+		return token.Token{}, false
+	}
+
+	return *lexerInstance.pragma, true
+}
+
+// lookupIdent looks up the NFC form of ident, so a keyword typed with
+// combining marks is still a keyword.
+func (lexerInstance *Lexer) lookupIdent(ident string) token.TokenType {
+	return lexerInstance.dictionary.Lookup(norm.NFC(ident))
+}
+
+// Started reports whether the lexer is past the first token of the
+// input, which is where a "тил" comment would have been.
+func (lexerInstance *Lexer) Started() bool {
+	return lexerInstance.started
+}
+
+// Errors returns the invalid UTF-8 sequences and read errors met so far.
+func (lexerInstance *Lexer) Errors() []Error {
+	return lexerInstance.errors
 }
 
+// isLetter reports whether ch may start an identifier.
 func isLetter(ch rune) bool {
-	return unicode.IsLetter(ch) || ch == '_'
+	return unicode.IsXIDStart(ch) || ch == '_'
+}
+
+// isIdentifierPart reports whether ch may continue an identifier: digits
+// and underscores are allowed after the first rune, and so are combining
+// marks such as the breve of a decomposed й.
+func isIdentifierPart(ch rune) bool {
+	return unicode.IsXIDContinue(ch) || unicode.IsMark(ch)
 }
 
 func isDigit(ch rune) bool {
@@ -22,49 +107,175 @@ func isDigit(ch rune) bool {
 }
 
 func (lexerInstance *Lexer) readChar() {
-	if lexerInstance.readPosition >= len(lexerInstance.input) {
-		lexerInstance.ch = 0
+	if lexerInstance.ch == '\n' {
+		lexerInstance.line += 1
+		lexerInstance.column = 1
 	} else {
-		lexerInstance.ch = lexerInstance.input[lexerInstance.readPosition]
+		lexerInstance.column += 1
+	}
+
+	ch, width, invalid := lexerInstance.decode()
+	lexerInstance.invalid = invalid
+	if width == 1 && ch == utf8.RuneError {
+		lexerInstance.errors = append(lexerInstance.errors, Error{
+			Message: fmt.Sprintf("invalid UTF-8 byte %#x", invalid),
+			Line:    lexerInstance.line,
+			Column:  lexerInstance.column,
+			Offset:  lexerInstance.readPosition,
+		})
+	}
+	if lexerInstance.reader != nil {
+		lexerInstance.reader.Discard(width)
 	}
+
+	lexerInstance.ch = ch
 	lexerInstance.position = lexerInstance.readPosition
-	lexerInstance.readPosition += 1
+	lexerInstance.readPosition += width
 }
 
+// decode returns the rune after ch without consuming it, its width in
+// bytes and the first of those bytes. At the end of input the rune is 0
+// and the width 0. The reader is never asked for more than one rune
+// ahead.
+func (lexerInstance *Lexer) decode() (rune, int, byte) {
+	if lexerInstance.reader == nil {
+		if lexerInstance.readPosition >= len(lexerInstance.input) {
+			return 0, 0, 0
+		}
+		ch, width := utf8.DecodeRuneInString(lexerInstance.input[lexerInstance.readPosition:])
+		return ch, width, lexerInstance.input[lexerInstance.readPosition]
+	}
+
+	bytes, err := lexerInstance.reader.Peek(utf8.UTFMax)
+	if len(bytes) == 0 {
+		if err != nil && err != io.EOF && !lexerInstance.failed {
+			lexerInstance.failed = true
+			lexerInstance.errors = append(lexerInstance.errors, Error{
+				Message: err.Error(),
+				Line:    lexerInstance.line,
+				Column:  lexerInstance.column,
+				Offset:  lexerInstance.readPosition,
+			})
+		}
+		return 0, 0, 0
+	}
+
+	ch, width := utf8.DecodeRune(bytes)
+	return ch, width, bytes[0]
+}
+
+// consumeWhitespace skips whitespace and line comments.
 func (lexerInstance *Lexer) consumeWhitespace() {
-	if unicode.IsSpace(lexerInstance.ch) {
+	for {
+		for unicode.IsSpace(lexerInstance.ch) {
+			lexerInstance.readChar()
+		}
+		if lexerInstance.ch != '/' || lexerInstance.peekChar() != '/' {
+			return
+		}
+		lexerInstance.readComment()
+	}
+}
+
+// readComment skips a line comment, which may be the "тил" pragma when
+// no token has been read yet.
+func (lexerInstance *Lexer) readComment() {
+	lexerInstance.readChar()
+	lexerInstance.readChar()
+	for lexerInstance.ch == ' ' || lexerInstance.ch == '\t' {
 		lexerInstance.readChar()
 	}
+
+	line, column, offset := lexerInstance.line, lexerInstance.column, lexerInstance.position
+	text := lexerInstance.readWhile(func(ch rune) bool {
+		return ch != '\n' && ch != 0
+	})
+	if lexerInstance.started || lexerInstance.pragma != nil || !strings.HasPrefix(text, pragmaPrefix) {
+		return
+	}
+
+	name := strings.TrimLeft(text[len(pragmaPrefix):], " \t")
+	skipped := len(text) - len(name)
+	name = strings.TrimRight(name, " \t\r")
+
+	pragma := token.Token{
+		Type:    token.ILLEGAL,
+		Literal: name,
+		Line:    line,
+		Column:  column + utf8.RuneCountInString(text[:skipped]),
+		Offset:  offset + skipped,
+	}
+	lexerInstance.pragma = &pragma
+
+	if dictionary, ok := token.LookupDictionary(name); ok {
+		pragma.Type = token.IDENT
+		lexerInstance.dictionary = dictionary
+	}
 }
 
+const pragmaPrefix = "тил:"
+
 func (lexerInstance *Lexer) readIdentifier() string {
-	position := lexerInstance.position
-	for isLetter(lexerInstance.ch) {
-		lexerInstance.readChar()
+	return lexerInstance.readWhile(isIdentifierPart)
+}
+
+// readNumber reads an integer, or a float when a dot and a digit follow
+// it. A dot followed by anything else is left for a member expression.
+func (lexerInstance *Lexer) readNumber() (token.TokenType, string) {
+	literal := lexerInstance.readWhile(isDigit)
+	if lexerInstance.ch != '.' || !isDigit(lexerInstance.peekChar()) {
+		return token.INT, literal
 	}
-	return string(lexerInstance.input[position:lexerInstance.position])
+
+	lexerInstance.readChar()
+	return token.FLOAT, literal + "." + lexerInstance.readWhile(isDigit)
 }
 
-func (lexerInstance *Lexer) readNumber() string {
-	position := lexerInstance.position
-	for isDigit(lexerInstance.ch) {
+// readWhile consumes runes as long as accept returns true. A string
+// input is sliced, while text from a reader has to be collected.
+func (lexerInstance *Lexer) readWhile(accept func(rune) bool) string {
+	if lexerInstance.reader == nil {
+		position := lexerInstance.position
+		for accept(lexerInstance.ch) {
+			lexerInstance.readChar()
+		}
+		return lexerInstance.input[position:lexerInstance.position]
+	}
+
+	// Undecodable bytes are kept as they are, like slicing would.
+	var text strings.Builder
+	for accept(lexerInstance.ch) {
+		if lexerInstance.ch == utf8.RuneError && lexerInstance.readPosition-lexerInstance.position == 1 {
+			text.WriteByte(lexerInstance.invalid)
+		} else {
+			text.WriteRune(lexerInstance.ch)
+		}
 		lexerInstance.readChar()
 	}
-	return string(lexerInstance.input[position:lexerInstance.position])
+	return text.String()
+}
+
+// readString reads up to the closing quote, which NextToken consumes.
+// An unterminated string runs to the end of input.
+func (lexerInstance *Lexer) readString() string {
+	lexerInstance.readChar()
+	return lexerInstance.readWhile(func(ch rune) bool {
+		return ch != '"' && ch != 0
+	})
 }
 
 func (lexerInstance *Lexer) peekChar() rune {
-	if lexerInstance.readPosition >= len(lexerInstance.input) {
-		return 0
-	} else {
-		return lexerInstance.input[lexerInstance.readPosition]
-	}
+	ch, _, _ := lexerInstance.decode()
+	return ch
 }
 
 func (lexerInstance *Lexer) NextToken() token.Token {
 	var tok token.Token
 
 	lexerInstance.consumeWhitespace()
+	lexerInstance.started = true
+	line, column, offset := lexerInstance.line, lexerInstance.column, lexerInstance.position
+
 	switch lexerInstance.ch {
 	case '=':
 		if lexerInstance.peekChar() == '=' {
@@ -72,6 +283,11 @@ func (lexerInstance *Lexer) NextToken() token.Token {
 			lexerInstance.readChar()
 			literal := string(ch) + string(lexerInstance.ch)
 			tok = token.Token{Type: token.EQ, Literal: literal}
+		} else if lexerInstance.peekChar() == '>' {
+			ch := lexerInstance.ch
+			lexerInstance.readChar()
+			literal := string(ch) + string(lexerInstance.ch)
+			tok = token.Token{Type: token.ARROW, Literal: literal}
 		} else {
 			tok = newToken(token.ASSIGN, lexerInstance.ch)
 		}
@@ -104,21 +320,43 @@ func (lexerInstance *Lexer) NextToken() token.Token {
 		tok = newToken(token.RPAREN, lexerInstance.ch)
 	case ',':
 		tok = newToken(token.COMMA, lexerInstance.ch)
+	case ':':
+		tok = newToken(token.COLON, lexerInstance.ch)
 	case '{':
 		tok = newToken(token.LBRACE, lexerInstance.ch)
 	case '}':
 		tok = newToken(token.RBRACE, lexerInstance.ch)
+	case '[':
+		tok = newToken(token.LBRACKET, lexerInstance.ch)
+	case ']':
+		tok = newToken(token.RBRACKET, lexerInstance.ch)
+	case '.':
+		if lexerInstance.peekChar() == '.' {
+			lexerInstance.readChar()
+			if lexerInstance.peekChar() == '.' {
+				lexerInstance.readChar()
+				tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+			} else {
+				tok = token.Token{Type: token.ILLEGAL, Literal: ".."}
+			}
+		} else {
+			tok = newToken(token.DOT, lexerInstance.ch)
+		}
+	case '"':
+		tok.Type = token.STRING
+		tok.Literal = lexerInstance.readString()
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
 	default:
 		if isLetter(lexerInstance.ch) {
 			tok.Literal = lexerInstance.readIdentifier()
-			tok.Type = token.LookupIdent(tok.Literal)
+			tok.Type = lexerInstance.lookupIdent(tok.Literal)
+			tok.Line, tok.Column, tok.Offset = line, column, offset
 			return tok
 		} else if isDigit(lexerInstance.ch) {
-			tok.Type = token.INT
-			tok.Literal = lexerInstance.readNumber()
+			tok.Type, tok.Literal = lexerInstance.readNumber()
+			tok.Line, tok.Column, tok.Offset = line, column, offset
 			return tok
 		} else {
 			tok = newToken(token.ILLEGAL, lexerInstance.ch)
@@ -127,6 +365,7 @@ func (lexerInstance *Lexer) NextToken() token.Token {
 	// Before returning the token we advance our pointers into the
 	// input so when we call NextToken() again the lexerInstance.ch field is already updated.
 	lexerInstance.readChar()
+	tok.Line, tok.Column, tok.Offset = line, column, offset
 	return tok
 }
 
@@ -135,9 +374,38 @@ func newToken(tokenType token.TokenType, ch rune) token.Token {
 }
 
 func New(input string) *Lexer {
-	// We convert to runes so we can support UTF-8 characters.
-	runes := []rune(input)
-	lexerInstance := &Lexer{input: runes}
+	lexerInstance := &Lexer{input: input, line: 1, dictionary: token.Kyrgyz}
+	lexerInstance.readChar()
+	return lexerInstance
+}
+
+// NewAt returns a lexer that starts reading input at the given byte
+// offset, which is at the given 1-based line and column. Token positions
+// are reported relative to the whole input.
+func NewAt(input string, offset int, line int, column int) *Lexer {
+	// The "тил" comment precedes the first token, so reading up to that
+	// token finds it.
+	head := New(input)
+	head.consumeWhitespace()
+
+	lexerInstance := &Lexer{
+		input:        input,
+		readPosition: offset,
+		line:         line,
+		column:       column - 1,
+		dictionary:   head.dictionary,
+		pragma:       head.pragma,
+		started:      offset > head.position,
+	}
+	lexerInstance.readChar()
+	return lexerInstance
+}
+
+// NewReader returns a lexer that decodes input from reader on the fly
+// instead of holding all of it in memory. It produces the same tokens as
+// New would for the same bytes.
+func NewReader(reader io.Reader) *Lexer {
+	lexerInstance := &Lexer{reader: bufio.NewReader(reader), line: 1, dictionary: token.Kyrgyz}
 	lexerInstance.readChar()
 	return lexerInstance
 }
@@ -1,7 +1,14 @@
 package lexer
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/asanoviskhak/alipp/src/token"
 )
@@ -20,6 +27,10 @@ func TestNextToken(testing *testing.T) {
 }
 10 == 10;
 10 != 9;
+"салам"
+"салам дүйнө"
+салыштыр (x) { _ => 1 }
+[a, ...b] ..
 `
 
 	tests := []struct {
@@ -99,6 +110,24 @@ func TestNextToken(testing *testing.T) {
 		{token.NOT_EQ, "!="},
 		{token.INT, "9"},
 		{token.SEMICOLON, ";"},
+		{token.STRING, "салам"},
+		{token.STRING, "салам дүйнө"},
+		{token.MATCH, "салыштыр"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "_"},
+		{token.ARROW, "=>"},
+		{token.INT, "1"},
+		{token.RBRACE, "}"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "b"},
+		{token.RBRACKET, "]"},
+		{token.ILLEGAL, ".."},
 		{token.EOF, ""},
 	}
 
@@ -117,3 +146,328 @@ func TestNextToken(testing *testing.T) {
 		}
 	}
 }
+
+func TestTokenPositions(testing *testing.T) {
+	input := "сакта x = 5;\n  кайтар x;"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+		expectedOffset  int
+	}{
+		{"сакта", 1, 1, 0},
+		{"x", 1, 7, 11},
+		{"=", 1, 9, 13},
+		{"5", 1, 11, 15},
+		{";", 1, 12, 16},
+		{"кайтар", 2, 3, 20},
+		{"x", 2, 10, 33},
+		{";", 2, 11, 34},
+	}
+
+	lexerInstance := New(input)
+
+	for index, tokenTest := range tests {
+		tokenNext := lexerInstance.NextToken()
+		if tokenNext.Literal != tokenTest.expectedLiteral {
+			testing.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				index, tokenTest.expectedLiteral, tokenNext.Literal)
+		}
+
+		if tokenNext.Line != tokenTest.expectedLine || tokenNext.Column != tokenTest.expectedColumn {
+			testing.Fatalf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				index, tokenTest.expectedLine, tokenTest.expectedColumn, tokenNext.Line, tokenNext.Column)
+		}
+
+		if tokenNext.Offset != tokenTest.expectedOffset {
+			testing.Fatalf("tests[%d] - offset wrong. expected=%d, got=%d",
+				index, tokenTest.expectedOffset, tokenNext.Offset)
+		}
+	}
+}
+
+func TestNewAt(testing *testing.T) {
+	input := "сакта x = 5;\n  кайтар x;"
+
+	expected := []token.Token{}
+	full := New(input)
+	for tok := full.NextToken(); tok.Type != token.EOF; tok = full.NextToken() {
+		if tok.Offset >= 18 {
+			expected = append(expected, tok)
+		}
+	}
+
+	resumed := NewAt(input, 18, 2, 1)
+	for index, want := range expected {
+		if got := resumed.NextToken(); got != want {
+			testing.Fatalf("tests[%d] - resumed lexer diverged. expected=%+v, got=%+v", index, want, got)
+		}
+	}
+}
+
+func allTokens(lexerInstance *Lexer) []token.Token {
+	tokens := []token.Token{}
+	for {
+		tok := lexerInstance.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			return tokens
+		}
+	}
+}
+
+func TestReaderMatchesString(testing *testing.T) {
+	fragments := []string{
+		"сакта", "x", " ", "\n", "\t", "=", "==", "!", "!=", "ө", "ү", "ң", "123", "функ", "(", ")",
+		"{", "}", ";", ",", "+", "-", "*", "/", "<", ">", "@", "𝔘", "\xff", "\xd0", "\xe2\x82", "\x80", "\"",
+	}
+	random := rand.New(rand.NewSource(7))
+
+	for i := 0; i < 300; i++ {
+		var input strings.Builder
+		for j := random.Intn(40); j >= 0; j-- {
+			input.WriteString(fragments[random.Intn(len(fragments))])
+		}
+
+		readers := map[string]func(io.Reader) io.Reader{
+			"whole":    func(r io.Reader) io.Reader { return r },
+			"one byte": iotest.OneByteReader,
+			"half":     iotest.HalfReader,
+		}
+		for name, wrap := range readers {
+			fromString := New(input.String())
+			fromReader := NewReader(wrap(strings.NewReader(input.String())))
+
+			want, got := allTokens(fromString), allTokens(fromReader)
+			if !reflect.DeepEqual(want, got) {
+				testing.Fatalf("%s reader: token streams differ for %q.\nwant=%v\ngot= %v", name, input.String(), want, got)
+			}
+
+			if fmt.Sprint(fromString.Errors()) != fmt.Sprint(fromReader.Errors()) {
+				testing.Fatalf("%s reader: errors differ for %q.\nwant=%v\ngot= %v", name, input.String(), fromString.Errors(), fromReader.Errors())
+			}
+		}
+	}
+}
+
+func TestInvalidUTF8(testing *testing.T) {
+	input := "сакта x\xff = \n\xd0;"
+
+	lexerInstance := NewReader(strings.NewReader(input))
+	tokens := allTokens(lexerInstance)
+
+	if tokens[2].Type != token.ILLEGAL || tokens[2].Offset != 12 {
+		testing.Errorf("invalid byte not lexed as ILLEGAL at offset 12. got=%+v", tokens[2])
+	}
+
+	expected := []Error{
+		{Message: "invalid UTF-8 byte 0xff", Line: 1, Column: 8, Offset: 12},
+		{Message: "invalid UTF-8 byte 0xd0", Line: 2, Column: 1, Offset: 17},
+	}
+	if fmt.Sprint(lexerInstance.Errors()) != fmt.Sprint(expected) {
+		testing.Errorf("errors wrong.\nwant=%v\ngot= %v", expected, lexerInstance.Errors())
+	}
+}
+
+func TestReaderError(testing *testing.T) {
+	failure := errors.New("диск иштебей калды")
+	lexerInstance := NewReader(io.MultiReader(strings.NewReader("x + "), iotest.ErrReader(failure)))
+
+	tokens := allTokens(lexerInstance)
+	if len(tokens) != 3 || tokens[2].Type != token.EOF {
+		testing.Fatalf("read error should end the token stream. got=%v", tokens)
+	}
+
+	errors := lexerInstance.Errors()
+	if len(errors) != 1 || errors[0].Message != failure.Error() || errors[0].Offset != 4 {
+		testing.Errorf("read error not reported. got=%v", errors)
+	}
+}
+
+func TestLatinKeywords(testing *testing.T) {
+	input := "sakta x = funk(a) { eger (a) { kaitar tuura; } je { kaitar kata; } }; sakta y = funktsiya() {};"
+
+	expected := []token.TokenType{
+		token.LET, token.IDENT, token.ASSIGN, token.FUNCTION, token.LPAREN, token.IDENT, token.RPAREN, token.LBRACE,
+		token.IF, token.LPAREN, token.IDENT, token.RPAREN, token.LBRACE, token.RETURN, token.TRUE, token.SEMICOLON, token.RBRACE,
+		token.ELSE, token.LBRACE, token.RETURN, token.FALSE, token.SEMICOLON, token.RBRACE, token.RBRACE, token.SEMICOLON,
+		token.LET, token.IDENT, token.ASSIGN, token.FUNCTION, token.LPAREN, token.RPAREN, token.LBRACE, token.RBRACE, token.SEMICOLON,
+	}
+
+	latin, _ := token.LookupDictionary("ky-latn")
+	lexerInstance := New(input)
+	lexerInstance.UseDictionary(latin)
+	for index, tokenType := range expected {
+		if tok := lexerInstance.NextToken(); tok.Type != tokenType {
+			testing.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q (%q)", index, tokenType, tok.Type, tok.Literal)
+		}
+	}
+
+	if tok := New("sakta").NextToken(); tok.Type != token.IDENT {
+		testing.Errorf("Latin keywords must be opt-in. got=%q", tok.Type)
+	}
+
+	lexerInstance = New("сакта")
+	lexerInstance.UseDictionary(latin)
+	if tok := lexerInstance.NextToken(); tok.Type != token.IDENT {
+		testing.Errorf("Cyrillic keywords are not in the Latin dictionary. got=%q", tok.Type)
+	}
+}
+
+func TestComments(testing *testing.T) {
+	input := "// түшүндүрмө\nx / y // дагы\n//\n5"
+
+	expected := []token.Token{
+		{Type: token.IDENT, Literal: "x", Line: 2, Column: 1},
+		{Type: token.SLASH, Literal: "/", Line: 2, Column: 3},
+		{Type: token.IDENT, Literal: "y", Line: 2, Column: 5},
+		{Type: token.INT, Literal: "5", Line: 4, Column: 1},
+		{Type: token.EOF, Literal: "", Line: 4, Column: 2},
+	}
+
+	lexerInstance := New(input)
+	for index, expectedToken := range expected {
+		tok := lexerInstance.NextToken()
+		tok.Offset = 0
+		if tok != expectedToken {
+			testing.Fatalf("tests[%d] - token wrong. expected=%+v, got=%+v", index, expectedToken, tok)
+		}
+	}
+}
+
+func TestPragma(testing *testing.T) {
+	tests := []struct {
+		input          string
+		expectedTypes  []token.TokenType
+		expectedPragma token.Token
+	}{
+		{
+			"// тил: kk\nсақта x = ақиқат; сакта",
+			[]token.TokenType{token.LET, token.IDENT, token.ASSIGN, token.TRUE, token.SEMICOLON, token.IDENT},
+			token.Token{Type: token.IDENT, Literal: "kk", Line: 1, Column: 9, Offset: 11},
+		},
+		{
+			"\n  //тил:uz  \nsaqla",
+			[]token.TokenType{token.LET},
+			token.Token{Type: token.IDENT, Literal: "uz", Line: 2, Column: 9, Offset: 12},
+		},
+		{
+			"// тил: xx\nсакта",
+			[]token.TokenType{token.LET},
+			token.Token{Type: token.ILLEGAL, Literal: "xx", Line: 1, Column: 9, Offset: 11},
+		},
+		{
+			"сакта\n// тил: kk\nсақта",
+			[]token.TokenType{token.LET, token.IDENT},
+			token.Token{},
+		},
+	}
+
+	for _, tt := range tests {
+		lexerInstance := New(tt.input)
+		for index, tokenType := range tt.expectedTypes {
+			if tok := lexerInstance.NextToken(); tok.Type != tokenType {
+				testing.Fatalf("%q: tests[%d] - tokentype wrong. expected=%q, got=%q", tt.input, index, tokenType, tok.Type)
+			}
+		}
+
+		if pragma, _ := lexerInstance.Pragma(); pragma != tt.expectedPragma {
+			testing.Errorf("%q: pragma wrong. expected=%+v, got=%+v", tt.input, tt.expectedPragma, pragma)
+		}
+	}
+}
+
+func TestDictionary(testing *testing.T) {
+	kazakh, _ := token.LookupDictionary("kk")
+
+	lexerInstance := New("сақта x; сакта")
+	lexerInstance.UseDictionary(kazakh)
+	for _, tokenType := range []token.TokenType{token.LET, token.IDENT, token.SEMICOLON, token.IDENT} {
+		if tok := lexerInstance.NextToken(); tok.Type != tokenType {
+			testing.Fatalf("tokentype wrong. expected=%q, got=%q", tokenType, tok.Type)
+		}
+	}
+
+	// The comment in the file wins over the caller's choice.
+	lexerInstance = New("// тил: ky\nсакта")
+	lexerInstance.UseDictionary(kazakh)
+	if tok := lexerInstance.NextToken(); tok.Type != token.LET {
+		testing.Errorf("tokentype wrong. expected=%q, got=%q", token.LET, tok.Type)
+	}
+
+	// A lexer resuming after the comment still uses its dictionary.
+	input := "// тил: kk\nсақта x = 1; қайтар x;"
+	lexerInstance = NewAt(input, strings.Index(input, "қайтар"), 2, 14)
+	if tok := lexerInstance.NextToken(); tok.Type != token.RETURN {
+		testing.Errorf("tokentype wrong. expected=%q, got=%q", token.RETURN, tok.Type)
+	}
+}
+
+func TestCombiningMarks(testing *testing.T) {
+	// кайтар with its й typed as и and a combining breve, then an
+	// identifier that ends in a mark.
+	input := "ка\u0438\u0306тар ы\u0301;"
+
+	expected := []token.Token{
+		{Type: token.RETURN, Literal: "ка\u0438\u0306тар", Line: 1, Column: 1, Offset: 0},
+		{Type: token.IDENT, Literal: "ы\u0301", Line: 1, Column: 9, Offset: 15},
+		{Type: token.SEMICOLON, Literal: ";", Line: 1, Column: 11, Offset: 19},
+	}
+
+	lexerInstance := New(input)
+	for index, expectedToken := range expected {
+		if tok := lexerInstance.NextToken(); tok != expectedToken {
+			testing.Fatalf("tests[%d] - token wrong. expected=%+v, got=%+v", index, expectedToken, tok)
+		}
+	}
+}
+
+func TestIdentifierCharacters(testing *testing.T) {
+	input := "x1 _эки 2ч ай·"
+
+	expected := []token.Token{
+		{Type: token.IDENT, Literal: "x1"},
+		{Type: token.IDENT, Literal: "_эки"},
+		{Type: token.INT, Literal: "2"},
+		{Type: token.IDENT, Literal: "ч"},
+		{Type: token.IDENT, Literal: "ай·"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lexerInstance := New(input)
+	for index, expectedToken := range expected {
+		tok := lexerInstance.NextToken()
+		if tok.Type != expectedToken.Type || tok.Literal != expectedToken.Literal {
+			testing.Fatalf("tests[%d] - token wrong. expected=%q %q, got=%q %q", index, expectedToken.Type, expectedToken.Literal, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestNumbers(testing *testing.T) {
+	input := "3.14 5.узундук 7. 0.5.x 99999999999999999999"
+
+	expected := []token.Token{
+		{Type: token.FLOAT, Literal: "3.14"},
+		{Type: token.INT, Literal: "5"},
+		{Type: token.DOT, Literal: "."},
+		{Type: token.IDENT, Literal: "узундук"},
+		{Type: token.INT, Literal: "7"},
+		{Type: token.DOT, Literal: "."},
+		{Type: token.FLOAT, Literal: "0.5"},
+		{Type: token.DOT, Literal: "."},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.INT, Literal: "99999999999999999999"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lexerInstance := New(input)
+
+	for index, expectedToken := range expected {
+		tokenNext := lexerInstance.NextToken()
+		if tokenNext.Type != expectedToken.Type || tokenNext.Literal != expectedToken.Literal {
+			testing.Fatalf("tests[%d] - token wrong. expected=%s %q, got=%s %q",
+				index, expectedToken.Type, expectedToken.Literal, tokenNext.Type, tokenNext.Literal)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+// Package loader finds the files a program imports, parses each of them
+// once and puts them in the order they run.
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/parser"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+// Extension is the extension of alipp source files. Import paths may
+// leave it out.
+const Extension = ".alipp"
+
+// PathVariable is the environment variable that lists directories to add
+// to the search path, separated like PATH.
+const PathVariable = "ALIPP_PATH"
+
+// Module is a parsed source file.
+type Module struct {
+	// Path is the absolute path of the file. Name is the one errors show:
+	// relative to the working directory when the file is under it.
+	Path    string
+	Name    string
+	Source  string
+	Program *ast.Program
+	// Imports holds the module each import path of the program names.
+	Imports map[string]*Module
+	// Exports lists the names the program exports, in order.
+	Exports []string
+	// Warnings are what the parser found suspicious but not wrong, such
+	// as a салыштыр without an arm for ката.
+	Warnings []Error
+}
+
+// Error is a problem found while loading a module, at Line and Column
+// of the file called File. Line is 0 when the whole file is at fault.
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err Error) Error() string {
+	if err.Line == 0 {
+		return fmt.Sprintf("%s: %s", err.File, err.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", err.File, err.Line, err.Column, err.Message)
+}
+
+// Package is a dependency of the program. An import of its name loads
+// Entry, and one of its name followed by /path loads that path within
+// Directory.
+type Package struct {
+	Directory string
+	Entry     string
+}
+
+// Loader loads modules and keeps them, so that every file is read and
+// parsed once however many modules import it.
+type Loader struct {
+	// SearchPath lists the directories searched, in order, for import
+	// paths that start with neither ./ nor ../.
+	SearchPath []string
+	// Packages holds the packages imports can name by their name, before
+	// the search path is tried.
+	Packages map[string]Package
+	// Dictionary spells the keywords of files without a "тил" comment.
+	// When it is nil they are Kyrgyz.
+	Dictionary *token.Dictionary
+	// Reserved lists the names no keyword may be spelled as, such as the
+	// builtins of the runtime. A file whose dictionary spells one of them
+	// as a keyword is an error.
+	Reserved []string
+
+	modules map[string]*Module
+	// loading is the chain of imports being followed, to find cycles.
+	loading []*Module
+	loaded  map[*Module]bool
+	order   []*Module
+	errors  []Error
+}
+
+// New returns a loader that looks for modules in searchPath.
+func New(searchPath []string) *Loader {
+	return &Loader{SearchPath: searchPath, modules: map[string]*Module{}}
+}
+
+// DefaultSearchPath returns the directories listed in PathVariable.
+func DefaultSearchPath() []string {
+	return filepath.SplitList(os.Getenv(PathVariable))
+}
+
+// Load loads the file at path and every module it imports, directly or
+// not. It returns them in the order they run, each after the modules it
+// imports and the one at path last, or the errors that keep them from
+// running.
+func (loader *Loader) Load(path string) ([]*Module, []Error) {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return nil, []Error{{File: path, Message: err.Error()}}
+	}
+
+	return loader.run(func() *Module {
+		return loader.visit(absolute, nil, nil)
+	})
+}
+
+// LoadSource is Load for a program that was not read from a file, such
+// as standard input. It is called name, and imports paths relative to
+// directory.
+func (loader *Loader) LoadSource(name string, directory string, source string) ([]*Module, []Error) {
+	absolute, err := filepath.Abs(filepath.Join(directory, name))
+	if err != nil {
+		return nil, []Error{{File: name, Message: err.Error()}}
+	}
+
+	return loader.run(func() *Module {
+		module := loader.parse(absolute, name, source)
+		if module == nil {
+			return nil
+		}
+		return loader.follow(module)
+	})
+}
+
+func (loader *Loader) run(load func() *Module) ([]*Module, []Error) {
+	loader.loaded = map[*Module]bool{}
+	loader.order, loader.errors = nil, nil
+
+	load()
+	if len(loader.errors) > 0 {
+		return nil, loader.errors
+	}
+
+	return loader.order, nil
+}
+
+// visit loads the module at path, which statement of the module from
+// imports, and the modules it imports in turn.
+func (loader *Loader) visit(path string, from *Module, statement *ast.ImportStatement) *Module {
+	for i, module := range loader.loading {
+		if module.Path != path {
+			continue
+		}
+
+		names := []string{}
+		for _, link := range loader.loading[i:] {
+			names = append(names, link.Name)
+		}
+		loader.addError(from, statement.Path, fmt.Sprintf("циклдүү импорт: %s -> %s", strings.Join(names, " -> "), module.Name))
+		return nil
+	}
+
+	module, ok := loader.modules[path]
+	if !ok {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			var pathError *fs.PathError
+			if errors.As(err, &pathError) {
+				err = pathError.Err
+			}
+
+			if statement == nil {
+				loader.errors = append(loader.errors, Error{File: displayName(path), Message: err.Error()})
+			} else {
+				loader.addError(from, statement.Path, fmt.Sprintf("%s окулбай калды: %s", displayName(path), err))
+			}
+			return nil
+		}
+
+		if module = loader.parse(path, displayName(path), string(source)); module == nil {
+			return nil
+		}
+	}
+
+	return loader.follow(module)
+}
+
+// follow loads the modules module imports and then adds it to the order.
+func (loader *Loader) follow(module *Module) *Module {
+	if loader.loaded[module] {
+		return module
+	}
+
+	loader.loading = append(loader.loading, module)
+	for _, statement := range module.Program.Statements {
+		statement, ok := statement.(*ast.ImportStatement)
+		if !ok {
+			continue
+		}
+
+		path, err := loader.Resolve(filepath.Dir(module.Path), statement.Path.Value)
+		if err != nil {
+			loader.addError(module, statement.Path, err.Error())
+			continue
+		}
+
+		imported := loader.visit(path, module, statement)
+		if imported == nil {
+			continue
+		}
+		module.Imports[statement.Path.Value] = imported
+
+		for _, name := range statement.Names {
+			if !exports(imported, name.Value) {
+				loader.addError(module, name, fmt.Sprintf("%s модулу %s экспорттобойт", imported.Name, name.Value))
+			}
+		}
+	}
+	loader.loading = loader.loading[:len(loader.loading)-1]
+
+	loader.loaded[module] = true
+	loader.order = append(loader.order, module)
+	return module
+}
+
+// parse parses source and keeps the module, unless it has errors.
+func (loader *Loader) parse(path string, name string, source string) *Module {
+	lexerInstance := lexer.New(source)
+	if loader.Dictionary != nil {
+		lexerInstance.UseDictionary(loader.Dictionary)
+	}
+	parserInstance := parser.NewParser(lexerInstance)
+	program := parserInstance.ParseProgram()
+
+	module := &Module{Path: path, Name: name, Source: source, Program: program, Imports: map[string]*Module{}}
+	for _, warning := range parserInstance.Warnings() {
+		module.Warnings = append(module.Warnings, Error{File: name, Line: warning.Token.Line, Column: warning.Token.Column, Message: warning.Message})
+	}
+	for _, parserError := range parserInstance.ErrorList() {
+		loader.errors = append(loader.errors, Error{File: name, Line: parserError.Token.Line, Column: parserError.Token.Column, Message: parserError.Message})
+	}
+	if len(parserInstance.ErrorList()) > 0 {
+		return nil
+	}
+	if err := lexerInstance.Dictionary().Validate(loader.Reserved); err != nil {
+		// The error is the "тил" comment's, or the whole file's when the
+		// dictionary is the loader's.
+		pragma, ok := lexerInstance.Pragma()
+		if !ok {
+			pragma = token.Token{Line: 1, Column: 1}
+		}
+		loader.errors = append(loader.errors, Error{File: name, Line: pragma.Line, Column: pragma.Column, Message: err.Error()})
+		return nil
+	}
+
+	errorCount := len(loader.errors)
+	loader.bindings(module)
+	if len(loader.errors) > errorCount {
+		return nil
+	}
+
+	loader.modules[path] = module
+	return module
+}
+
+// bindings collects the exports of module. It also rejects binding an
+// imported or extern name twice, as JavaScript does.
+func (loader *Loader) bindings(module *Module) {
+	// rebound holds, for each imported or extern name, the error for
+	// binding it again.
+	rebound := map[string]string{}
+	for _, statement := range module.Program.Statements {
+		switch statement := statement.(type) {
+		case *ast.ImportStatement:
+			for _, name := range statement.Names {
+				if _, ok := rebound[name.Value]; ok {
+					loader.addError(module, name, fmt.Sprintf("%s эки жолу импорттолду", name.Value))
+				}
+				rebound[name.Value] = fmt.Sprintf("%s импорттолгон, аны кайра сактоого болбойт", name.Value)
+			}
+		case *ast.ExternStatement:
+			for _, extern := range statement.Names {
+				if _, ok := rebound[extern.Name.Value]; ok {
+					loader.addError(module, extern.Name, fmt.Sprintf("%s эки жолу жарыяланды", extern.Name.Value))
+				}
+				rebound[extern.Name.Value] = fmt.Sprintf("%s тышкы JavaScript'ти билдирет, аны кайра сактоого болбойт", extern.Name.Value)
+			}
+		case *ast.ExportStatement:
+			names := []*ast.Identifier{statement.Statement.Name}
+			if statement.Statement.Pattern != nil {
+				names = ast.Bindings(statement.Statement.Pattern)
+			}
+			for _, name := range names {
+				if !exports(module, name.Value) {
+					module.Exports = append(module.Exports, name.Value)
+				}
+			}
+		}
+	}
+
+	for _, statement := range module.Program.Statements {
+		ast.Inspect(statement, func(node ast.Node) bool {
+			var names []*ast.Identifier
+			switch node := node.(type) {
+			case *ast.LetStatement:
+				names = []*ast.Identifier{node.Name}
+				if node.Pattern != nil {
+					names = ast.Bindings(node.Pattern)
+				}
+			case *ast.TryStatement:
+				names = []*ast.Identifier{node.Parameter}
+			case *ast.FunctionLiteral:
+				return false
+			}
+
+			for _, name := range names {
+				if name != nil && rebound[name.Value] != "" {
+					loader.addError(module, name, rebound[name.Value])
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Resolve returns the file an import of path names in a module in
+// directory. Paths that start with ./ or ../ are relative to directory,
+// absolute paths stand for themselves, and any other path names a
+// package or is looked up in each directory of the search path in turn.
+// The extension may be left out.
+func (loader *Loader) Resolve(directory string, path string) (string, error) {
+	native := filepath.FromSlash(path)
+
+	switch {
+	case strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../"):
+		if found, ok := find(filepath.Join(directory, native)); ok {
+			return found, nil
+		}
+	case filepath.IsAbs(native):
+		if found, ok := find(native); ok {
+			return found, nil
+		}
+	default:
+		name, rest, _ := strings.Cut(path, "/")
+		if dependency, ok := loader.Packages[name]; ok {
+			if rest == "" {
+				rest = dependency.Entry
+			}
+			if found, ok := find(filepath.Join(dependency.Directory, filepath.FromSlash(rest))); ok {
+				return found, nil
+			}
+			break
+		}
+		for _, searched := range loader.SearchPath {
+			absolute, err := filepath.Abs(searched)
+			if err != nil {
+				continue
+			}
+			if found, ok := find(filepath.Join(absolute, native)); ok {
+				return found, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%s модулу табылган жок", path)
+}
+
+// find returns the file at path, trying the extension first when path
+// lacks it.
+func find(path string) (string, bool) {
+	candidates := []string{path}
+	if filepath.Ext(path) != Extension {
+		candidates = []string{path + Extension, path}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return filepath.Clean(candidate), true
+		}
+	}
+
+	return "", false
+}
+
+func exports(module *Module, name string) bool {
+	for _, exported := range module.Exports {
+		if exported == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (loader *Loader) addError(module *Module, node ast.Node, message string) {
+	span := ast.SpanOf(node)
+	loader.errors = append(loader.errors, Error{File: module.Name, Line: span.Start.Line, Column: span.Start.Column, Message: message})
+}
+
+// displayName returns path relative to the working directory when it is
+// under it.
+func displayName(path string) string {
+	if directory, err := os.Getwd(); err == nil {
+		if relative, err := filepath.Rel(directory, path); err == nil && !strings.HasPrefix(relative, "..") {
+			return relative
+		}
+	}
+
+	return path
+}
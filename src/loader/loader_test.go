@@ -0,0 +1,248 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+// writeFiles writes files, by their slash-separated paths, under a new
+// directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	directory := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(directory, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return directory
+}
+
+func names(modules []*Module) string {
+	list := []string{}
+	for _, module := range modules {
+		list = append(list, filepath.Base(module.Path))
+	}
+
+	return strings.Join(list, " ")
+}
+
+func TestLoadOrder(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"main.alipp":         `импорт { a } "./a"; импорт { b, c } "./lib/b.alipp"; a + b`,
+		"a.alipp":            `импорт { c } "./lib/b"; экспорт сакта a = c;`,
+		"lib/b.alipp":        `импорт { d } "d"; экспорт сакта b = 2; экспорт сакта c = d; экспорт сакта b = 3;`,
+		"search/d.alipp":     `экспорт сакта d = 4;`,
+		"search/d/x.alipp":   `экспорт сакта x = 5;`,
+		"shadowed/d.alipp":   `экспорт сакта e = 6;`,
+		"lib/unused.alipp":   `сакта бул = (;`,
+		"search/lib/b.alipp": ``,
+	})
+
+	loader := New([]string{filepath.Join(directory, "search"), filepath.Join(directory, "shadowed")})
+	modules, errors := loader.Load(filepath.Join(directory, "main.alipp"))
+	if len(errors) > 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+
+	if order := names(modules); order != "d.alipp b.alipp a.alipp main.alipp" {
+		t.Errorf("wrong order. got=%q", order)
+	}
+	if exports := strings.Join(modules[1].Exports, " "); exports != "b c" {
+		t.Errorf("wrong exports. got=%q", exports)
+	}
+
+	main := modules[3]
+	if main.Imports["./a"] != modules[2] || main.Imports["./lib/b.alipp"] != modules[1] || modules[2].Imports["./lib/b"] != modules[1] {
+		t.Errorf("imports do not share the loaded modules")
+	}
+
+	again, errors := loader.Load(filepath.Join(directory, "a.alipp"))
+	if len(errors) > 0 || len(again) != 3 || again[0] != modules[0] || again[2] != modules[2] {
+		t.Errorf("modules were not kept. got=%q, %v", names(again), errors)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"a.alipp":          ``,
+		"b":                ``,
+		"lib/c.alipp":      ``,
+		"search/c.alipp":   ``,
+		"search/d/e.alipp": ``,
+		"pkg/main.alipp":   ``,
+		"pkg/f/g.alipp":    ``,
+		"search/pkg.alipp": ``,
+	})
+	loader := New([]string{filepath.Join(directory, "search")})
+	loader.Packages = map[string]Package{"pkg": {Directory: filepath.Join(directory, "pkg"), Entry: "main.alipp"}}
+	lib := filepath.Join(directory, "lib")
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"../a", "a.alipp"},
+		{"../a.alipp", "a.alipp"},
+		{"../b", "b"},
+		{"./c", "lib/c.alipp"},
+		{"c", "search/c.alipp"},
+		{"d/e", "search/d/e.alipp"},
+		{filepath.Join(directory, "a"), "a.alipp"},
+		{"pkg", "pkg/main.alipp"},
+		{"pkg/f/g", "pkg/f/g.alipp"},
+		{"pkg/h", ""},
+		{"./a", ""},
+		{"a", ""},
+		{"d", ""},
+	}
+
+	for _, test := range tests {
+		resolved, err := loader.Resolve(lib, test.path)
+		if test.expected == "" {
+			if err == nil || err.Error() != test.path+" модулу табылган жок" {
+				t.Errorf("%s: wrong error. got=%q, %v", test.path, resolved, err)
+			}
+			continue
+		}
+
+		if expected := filepath.Join(directory, filepath.FromSlash(test.expected)); resolved != expected || err != nil {
+			t.Errorf("%s: want=%q, got=%q, %v", test.path, expected, resolved, err)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"a.alipp":       `импорт { b } "./b"; экспорт сакта a = 1;`,
+		"b.alipp":       `импорт { c } "./c"; экспорт сакта b = 1;`,
+		"c.alipp":       `импорт { a } "./a"; экспорт сакта c = 1;`,
+		"missing.alipp": "импорт { жок } \"./d\";\nимпорт { d } \"./жок\";",
+		"d.alipp":       `экспорт сакта d = 1;`,
+		"twice.alipp":   `импорт { d } "./d"; импорт { d } "./d";`,
+		"rebind.alipp":  `импорт { d } "./d"; эгер (туура) { сакта d = 2; } сакта f = функ(d) { сакта d = 3; d };`,
+		"caught.alipp":  `импорт { d } "./d"; аракет { 1 } кармоо (d) { d }`,
+		"unpack.alipp":  `импорт { d } "./d"; сакта [x, { d }] = [1, {"d": 2}];`,
+		"broken.alipp":  `импорт { d } "./d"; сакта = 1;`,
+		"imports.alipp": `импорт { x } "./broken";`,
+		"extern.alipp":  `тышкы Math; тышкы { Math } "m"; сакта Math = 1;`,
+	})
+	name := func(file string) string {
+		return displayName(filepath.Join(directory, file))
+	}
+
+	tests := []struct {
+		file     string
+		expected []string
+	}{
+		{"a.alipp", []string{name("c.alipp") + ":1:14: циклдүү импорт: " + name("a.alipp") + " -> " + name("b.alipp") + " -> " + name("c.alipp") + " -> " + name("a.alipp")}},
+		{"missing.alipp", []string{
+			name("missing.alipp") + ":1:10: " + name("d.alipp") + " модулу жок экспорттобойт",
+			name("missing.alipp") + ":2:14: ./жок модулу табылган жок",
+		}},
+		{"twice.alipp", []string{name("twice.alipp") + ":1:30: d эки жолу импорттолду"}},
+		{"rebind.alipp", []string{name("rebind.alipp") + ":1:42: d импорттолгон, аны кайра сактоого болбойт"}},
+		{"caught.alipp", []string{name("caught.alipp") + ":1:42: d импорттолгон, аны кайра сактоого болбойт"}},
+		{"unpack.alipp", []string{name("unpack.alipp") + ":1:33: d импорттолгон, аны кайра сактоого болбойт"}},
+		{"imports.alipp", []string{name("broken.alipp") + ":1:27: expected next token to be ИДЕНТИФИКАТОР, got = instead"}},
+		{"extern.alipp", []string{
+			name("extern.alipp") + ":1:21: Math эки жолу жарыяланды",
+			name("extern.alipp") + ":1:39: Math тышкы JavaScript'ти билдирет, аны кайра сактоого болбойт",
+		}},
+		{"none.alipp", []string{name("none.alipp") + ": no such file or directory"}},
+	}
+
+	for _, test := range tests {
+		modules, errors := New(nil).Load(filepath.Join(directory, test.file))
+		if modules != nil {
+			t.Errorf("%s: modules returned with errors", test.file)
+		}
+
+		messages := []string{}
+		for _, err := range errors {
+			messages = append(messages, err.Error())
+		}
+		if strings.Join(messages, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("%s: wrong errors.\nwant=%q\ngot= %q", test.file, test.expected, messages)
+		}
+	}
+}
+
+func TestLoadSource(t *testing.T) {
+	directory := writeFiles(t, map[string]string{"a.alipp": `экспорт сакта a = 1;`})
+
+	modules, errors := New(nil).LoadSource("<stdin>", directory, `импорт { a } "./a"; a`)
+	if len(errors) > 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+	if len(modules) != 2 || modules[1].Name != "<stdin>" || modules[1].Imports["./a"] != modules[0] {
+		t.Errorf("wrong modules. got=%q", names(modules))
+	}
+
+	if _, errors := New(nil).LoadSource("<stdin>", directory, "сакта"); len(errors) != 1 || errors[0].File != "<stdin>" {
+		t.Errorf("wrong errors. got=%v", errors)
+	}
+}
+
+func TestLoadDictionary(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"a.alipp": `экспорт сақта a = 1;`,
+		"b.alipp": "// тил: ky\nимпорт { a } \"./a\"; сакта b = a;",
+	})
+
+	loaderInstance := New(nil)
+	loaderInstance.Dictionary, _ = token.LookupDictionary("kk")
+	if _, errors := loaderInstance.Load(filepath.Join(directory, "b.alipp")); len(errors) > 0 {
+		t.Errorf("unexpected errors: %v", errors)
+	}
+
+	if _, errors := New(nil).Load(filepath.Join(directory, "a.alipp")); len(errors) == 0 {
+		t.Errorf("Kazakh keywords read without the dictionary")
+	}
+}
+
+func TestLoadReserved(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"a.alipp": `экспорт сақта a = 1;`,
+		"b.alipp": "// тил: kk\nсақта b = 1;",
+	})
+
+	loaderInstance := New(nil)
+	loaderInstance.Dictionary, _ = token.LookupDictionary("kk")
+	loaderInstance.Reserved = []string{"сақта"}
+	_, errors := loaderInstance.Load(filepath.Join(directory, "a.alipp"))
+	if len(errors) != 1 || errors[0].Line != 1 || errors[0].Column != 1 || !strings.Contains(errors[0].Message, `"сақта"`) {
+		t.Errorf("wrong errors for the loader's dictionary. got=%v", errors)
+	}
+
+	loaderInstance = New(nil)
+	loaderInstance.Reserved = []string{"сақта"}
+	_, errors = loaderInstance.Load(filepath.Join(directory, "b.alipp"))
+	if len(errors) != 1 || errors[0].Line != 1 || errors[0].Column != 9 {
+		t.Errorf("wrong errors for the тил comment. got=%v", errors)
+	}
+}
+
+func TestLoadWarnings(t *testing.T) {
+	directory := writeFiles(t, map[string]string{"a.alipp": "сакта b = туура;\nсалыштыр (b) { туура => 1 }"})
+
+	modules, errors := New(nil).Load(filepath.Join(directory, "a.alipp"))
+	if len(errors) > 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+
+	expected := modules[0].Name + ":2:1: салыштыр has no arm for ката"
+	if len(modules[0].Warnings) != 1 || modules[0].Warnings[0].Error() != expected {
+		t.Errorf("wrong warnings. want=%q, got=%v", expected, modules[0].Warnings)
+	}
+}
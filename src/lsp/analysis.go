@@ -0,0 +1,468 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/parser"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+type definitionKind int
+
+const (
+	variableDefinition definitionKind = iota
+	functionDefinition
+	parameterDefinition
+	// patternDefinition is a name a салыштыр pattern binds; names that
+	// сакта and parameters take apart are variables and parameters.
+	patternDefinition
+)
+
+// definition is a name introduced by a сакта binding or a function
+// parameter.
+type definition struct {
+	name     *ast.Identifier
+	kind     definitionKind
+	node     ast.Node // the let statement or the function literal
+	children []*definition
+}
+
+// occurrence links an identifier in the source to the binding it refers
+// to. Definitions are occurrences of themselves.
+type occurrence struct {
+	identifier *ast.Identifier
+	definition *definition
+}
+
+// document is the analysed state of one open file. It is rebuilt on
+// every change from tree, which only reparses what the change touched.
+type document struct {
+	uri         string
+	lines       []string
+	tree        *parser.Tree
+	program     *ast.Program
+	errors      []parser.Error
+	warnings    []parser.Error
+	definitions []*definition
+	occurrences []occurrence
+	// dictionary spells the keywords offered for completion.
+	dictionary *token.Dictionary
+}
+
+func newDocument(uri string, text string, dictionary *token.Dictionary) *document {
+	return analyse(uri, parser.ParseTreeWithDictionary(text, dictionary))
+}
+
+// edit reparses tree with the text of change in place of its range.
+// tree must not be used afterwards.
+func edit(tree *parser.Tree, change textDocumentContentChange) *parser.Tree {
+	lines := strings.Split(tree.Source, "\n")
+	start, end := offset(lines, change.Range.Start), offset(lines, change.Range.End)
+	if end < start {
+		start, end = end, start
+	}
+
+	return tree.Reparse(parser.Edit{Start: start, End: end, Text: change.Text})
+}
+
+func analyse(uri string, tree *parser.Tree) *document {
+	doc := &document{
+		uri:        uri,
+		lines:      strings.Split(tree.Source, "\n"),
+		tree:       tree,
+		program:    tree.Program,
+		errors:     tree.Errors(),
+		warnings:   tree.Warnings(),
+		dictionary: tree.Dictionary(),
+	}
+
+	resolver := &resolver{doc: doc, scopes: []map[string]*definition{{}}}
+	for _, statement := range tree.Program.Statements {
+		resolver.statement(statement, &doc.definitions)
+	}
+
+	return doc
+}
+
+// resolver binds identifiers to definitions. Only function literals open
+// a new scope, matching how bindings behave at runtime.
+type resolver struct {
+	doc    *document
+	scopes []map[string]*definition
+}
+
+func (resolver *resolver) define(def *definition) {
+	resolver.scopes[len(resolver.scopes)-1][def.name.Value] = def
+	resolver.doc.occurrences = append(resolver.doc.occurrences, occurrence{def.name, def})
+}
+
+func (resolver *resolver) lookup(name string) *definition {
+	for i := len(resolver.scopes) - 1; i >= 0; i-- {
+		if def, ok := resolver.scopes[i][name]; ok {
+			return def
+		}
+	}
+
+	return nil
+}
+
+func (resolver *resolver) statement(statement ast.Statement, owner *[]*definition) {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		if statement.Pattern != nil {
+			resolver.expression(statement.Value, owner)
+			*owner = append(*owner, resolver.pattern(statement.Pattern, variableDefinition, statement, owner)...)
+			return
+		}
+		def := &definition{name: statement.Name, kind: variableDefinition, node: statement}
+		if _, ok := statement.Value.(*ast.FunctionLiteral); ok {
+			def.kind = functionDefinition
+		}
+
+		// Defining the name first lets a function refer to itself.
+		resolver.define(def)
+		*owner = append(*owner, def)
+		resolver.expression(statement.Value, &def.children)
+	case *ast.ReturnStatement:
+		resolver.expression(statement.ReturnValue, owner)
+	case *ast.ExpressionStatement:
+		resolver.expression(statement.Expression, owner)
+	case *ast.TryStatement:
+		if statement.Block != nil {
+			resolver.statement(statement.Block, owner)
+		}
+		// The caught value is bound in the enclosing scope, like сакта.
+		if statement.Parameter != nil {
+			resolver.define(&definition{name: statement.Parameter, kind: parameterDefinition, node: statement})
+		}
+		if statement.Catch != nil {
+			resolver.statement(statement.Catch, owner)
+		}
+		if statement.Finally != nil {
+			resolver.statement(statement.Finally, owner)
+		}
+	case *ast.ThrowStatement:
+		resolver.expression(statement.Value, owner)
+	case *ast.ImportStatement:
+		for _, name := range statement.Names {
+			def := &definition{name: name, kind: variableDefinition, node: statement}
+			resolver.define(def)
+			*owner = append(*owner, def)
+		}
+	case *ast.ExternStatement:
+		for _, extern := range statement.Names {
+			def := &definition{name: extern.Name, kind: variableDefinition, node: statement}
+			resolver.define(def)
+			*owner = append(*owner, def)
+		}
+	case *ast.ExportStatement:
+		resolver.statement(statement.Statement, owner)
+	case *ast.BlockStatement:
+		for _, inner := range statement.Statements {
+			resolver.statement(inner, owner)
+		}
+	}
+}
+
+func (resolver *resolver) expression(expression ast.Expression, owner *[]*definition) {
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		if def := resolver.lookup(expression.Value); def != nil {
+			resolver.doc.occurrences = append(resolver.doc.occurrences, occurrence{expression, def})
+		}
+	case *ast.PrefixExpression:
+		resolver.expression(expression.Right, owner)
+	case *ast.InfixExpression:
+		resolver.expression(expression.Left, owner)
+		resolver.expression(expression.Right, owner)
+	case *ast.IfExpression:
+		resolver.expression(expression.Condition, owner)
+		if expression.Consequence != nil {
+			resolver.statement(expression.Consequence, owner)
+		}
+		if expression.Alternative != nil {
+			resolver.statement(expression.Alternative, owner)
+		}
+	case *ast.MatchExpression:
+		// Each arm is a scope of its own for the names its pattern binds.
+		resolver.expression(expression.Subject, owner)
+		for _, arm := range expression.Arms {
+			resolver.scopes = append(resolver.scopes, map[string]*definition{})
+			resolver.pattern(arm.Pattern, patternDefinition, expression, owner)
+			if arm.Guard != nil {
+				resolver.expression(arm.Guard, owner)
+			}
+			resolver.expression(arm.Body, owner)
+			resolver.scopes = resolver.scopes[:len(resolver.scopes)-1]
+		}
+	case *ast.FunctionLiteral:
+		resolver.scopes = append(resolver.scopes, map[string]*definition{})
+		for _, parameter := range expression.Parameters {
+			resolver.pattern(parameter, parameterDefinition, expression, owner)
+		}
+		if expression.Body != nil {
+			resolver.statement(expression.Body, owner)
+		}
+		resolver.scopes = resolver.scopes[:len(resolver.scopes)-1]
+	case *ast.CallExpression:
+		resolver.expression(expression.Function, owner)
+		for _, argument := range expression.Arguments {
+			resolver.expression(argument, owner)
+		}
+	case *ast.ArrayLiteral:
+		for _, element := range expression.Elements {
+			resolver.expression(element, owner)
+		}
+	case *ast.HashLiteral:
+		for i, key := range expression.Keys {
+			resolver.expression(key, owner)
+			resolver.expression(expression.Values[i], owner)
+		}
+	case *ast.IndexExpression:
+		resolver.expression(expression.Left, owner)
+		resolver.expression(expression.Index, owner)
+	case *ast.MemberExpression:
+		// The property names a member, not a binding in scope.
+		resolver.expression(expression.Object, owner)
+	}
+}
+
+// pattern defines the names pattern binds, of the kind given and
+// introduced by node, and returns their definitions.
+func (resolver *resolver) pattern(pattern ast.Pattern, kind definitionKind, node ast.Node, owner *[]*definition) []*definition {
+	defined := []*definition{}
+	switch pattern := pattern.(type) {
+	case *ast.LiteralPattern:
+		resolver.expression(pattern.Value, owner)
+	case *ast.BindingPattern:
+		def := &definition{name: pattern.Name, kind: kind, node: node}
+		resolver.define(def)
+		defined = append(defined, def)
+	case *ast.DefaultPattern:
+		resolver.expression(pattern.Default, owner)
+		defined = append(defined, resolver.pattern(pattern.Pattern, kind, node, owner)...)
+	case *ast.ArrayPattern:
+		for _, element := range pattern.Elements {
+			defined = append(defined, resolver.pattern(element, kind, node, owner)...)
+		}
+		if pattern.Rest != nil {
+			def := &definition{name: pattern.Rest, kind: kind, node: node}
+			resolver.define(def)
+			defined = append(defined, def)
+		}
+	case *ast.HashPattern:
+		for _, value := range pattern.Values {
+			defined = append(defined, resolver.pattern(value, kind, node, owner)...)
+		}
+	}
+
+	return defined
+}
+
+// position converts a 1-based line and rune column into an LSP position,
+// which counts UTF-16 code units.
+func (doc *document) position(line int, column int) Position {
+	if line < 1 || line > len(doc.lines) {
+		return Position{Line: max(line-1, 0), Character: max(column-1, 0)}
+	}
+
+	character := 0
+	runes := []rune(doc.lines[line-1])
+	for i := 0; i < column-1 && i < len(runes); i++ {
+		character += len(utf16.Encode([]rune{runes[i]}))
+	}
+
+	return Position{Line: line - 1, Character: character}
+}
+
+// column converts an LSP position back into a 1-based rune column.
+func (doc *document) column(position Position) int {
+	if position.Line < 0 || position.Line >= len(doc.lines) {
+		return position.Character + 1
+	}
+
+	units := 0
+	column := 1
+	for _, r := range doc.lines[position.Line] {
+		if units >= position.Character {
+			break
+		}
+		units += len(utf16.Encode([]rune{r}))
+		column++
+	}
+
+	return column
+}
+
+// offset converts an LSP position into a byte offset of the text split
+// into lines. A position past the end of a line is at its end.
+func offset(lines []string, position Position) int {
+	offset := 0
+	for i := 0; i < position.Line && i < len(lines); i++ {
+		offset += len(lines[i]) + 1
+	}
+	if position.Line < 0 {
+		return 0
+	}
+	if position.Line >= len(lines) {
+		return offset - 1
+	}
+
+	units := 0
+	for _, r := range lines[position.Line] {
+		if units >= position.Character {
+			break
+		}
+		units += len(utf16.Encode([]rune{r}))
+		offset += utf8.RuneLen(r)
+	}
+
+	return offset
+}
+
+func (doc *document) tokenRange(tok token.Token) Range {
+	length := max(utf8.RuneCountInString(tok.Literal), 1)
+
+	return Range{
+		Start: doc.position(tok.Line, tok.Column),
+		End:   doc.position(tok.Line, tok.Column+length),
+	}
+}
+
+func (doc *document) diagnostics() []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	for _, parserError := range doc.errors {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    doc.tokenRange(parserError.Token),
+			Severity: severityError,
+			Source:   "alipp",
+			Message:  parserError.Message,
+		})
+	}
+	for _, warning := range doc.warnings {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    doc.tokenRange(warning.Token),
+			Severity: severityWarning,
+			Source:   "alipp",
+			Message:  warning.Message,
+		})
+	}
+
+	return diagnostics
+}
+
+// occurrenceAt finds the identifier under the cursor.
+func (doc *document) occurrenceAt(position Position) *occurrence {
+	line := position.Line + 1
+	column := doc.column(position)
+
+	for i := range doc.occurrences {
+		tok := doc.occurrences[i].identifier.Token
+		length := utf8.RuneCountInString(tok.Literal)
+		if tok.Line == line && column >= tok.Column && column <= tok.Column+length {
+			return &doc.occurrences[i]
+		}
+	}
+
+	return nil
+}
+
+func (doc *document) hover(position Position) *Hover {
+	found := doc.occurrenceAt(position)
+	if found == nil {
+		return nil
+	}
+
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: "```alipp\n" + found.definition.signature() + "\n```"},
+		Range:    doc.tokenRange(found.identifier.Token),
+	}
+}
+
+func (doc *document) definition(position Position) *Location {
+	found := doc.occurrenceAt(position)
+	if found == nil {
+		return nil
+	}
+
+	return &Location{URI: doc.uri, Range: doc.tokenRange(found.definition.name.Token)}
+}
+
+func (doc *document) symbols() []DocumentSymbol {
+	return doc.symbolsOf(doc.definitions)
+}
+
+func (doc *document) symbolsOf(definitions []*definition) []DocumentSymbol {
+	symbols := []DocumentSymbol{}
+
+	for _, def := range definitions {
+		nameRange := doc.tokenRange(def.name.Token)
+		symbol := DocumentSymbol{
+			Name:           def.name.Value,
+			Detail:         def.signature(),
+			Kind:           symbolKindVariable,
+			Range:          nameRange,
+			SelectionRange: nameRange,
+		}
+		if def.kind == functionDefinition {
+			symbol.Kind = symbolKindFunction
+		}
+		if len(def.children) > 0 {
+			symbol.Children = doc.symbolsOf(def.children)
+		}
+
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols
+}
+
+func (doc *document) completions() []CompletionItem {
+	items := []CompletionItem{}
+
+	for _, keyword := range doc.dictionary.Keywords() {
+		items = append(items, CompletionItem{Label: keyword, Kind: completionKindKeyword})
+	}
+
+	seen := map[string]bool{}
+	for _, found := range doc.occurrences {
+		def := found.definition
+		if found.identifier != def.name || seen[def.name.Value] {
+			continue
+		}
+		seen[def.name.Value] = true
+
+		item := CompletionItem{Label: def.name.Value, Kind: completionKindVariable, Detail: def.signature()}
+		if def.kind == functionDefinition {
+			item.Kind = completionKindFunction
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// signature is the one-line summary shown on hover.
+func (def *definition) signature() string {
+	switch def.kind {
+	case functionDefinition:
+		function := def.node.(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+		parameters := []string{}
+		for _, parameter := range function.Parameters {
+			parameters = append(parameters, parameter.String())
+		}
+		return fmt.Sprintf("%s %s = %s(%s)", def.node.TokenLiteral(), def.name.Value,
+			function.TokenLiteral(), strings.Join(parameters, ", "))
+	case parameterDefinition:
+		return fmt.Sprintf("(параметр) %s", def.name.Value)
+	case patternDefinition:
+		return fmt.Sprintf("(үлгү) %s", def.name.Value)
+	default:
+		return def.node.String()
+	}
+}
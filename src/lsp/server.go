@@ -0,0 +1,198 @@
+// Package lsp implements a Language Server Protocol server for alipp that
+// talks JSON-RPC over a pair of streams, normally stdin and stdout.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/asanoviskhak/alipp/src/parser"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+type Server struct {
+	// Dictionary spells the keywords of documents without a "тил"
+	// comment. When it is nil they are Kyrgyz.
+	Dictionary *token.Dictionary
+
+	reader *bufio.Reader
+	writer io.Writer
+
+	documents map[string]*document
+	shutdown  bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		reader:    bufio.NewReader(in),
+		writer:    out,
+		documents: map[string]*document{},
+	}
+}
+
+// Run serves requests until the client sends "exit" or closes the input.
+func (server *Server) Run() error {
+	for {
+		body, err := readMessage(server.reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var request message
+		if err := json.Unmarshal(body, &request); err != nil {
+			if err := server.replyError(nil, codeParseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if request.Method == "exit" {
+			return nil
+		}
+
+		if err := server.handle(&request); err != nil {
+			return err
+		}
+	}
+}
+
+func (server *Server) handle(request *message) error {
+	if server.shutdown && request.ID != nil {
+		return server.replyError(request.ID, codeInvalidRequest, "server is shutting down")
+	}
+
+	switch request.Method {
+	case "initialize":
+		result := initializeResult{ServerInfo: serverInfo{Name: "alipp"}}
+		result.Capabilities.TextDocumentSync = textDocumentSyncIncremental
+		result.Capabilities.HoverProvider = true
+		result.Capabilities.DefinitionProvider = true
+		result.Capabilities.DocumentSymbolProvider = true
+		return server.reply(request.ID, result)
+	case "initialized":
+		return nil
+	case "shutdown":
+		server.shutdown = true
+		return server.reply(request.ID, nil)
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil
+		}
+		return server.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(request.Params, &params); err != nil || len(params.ContentChanges) == 0 {
+			return nil
+		}
+		return server.change(params.TextDocument.URI, params.ContentChanges)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(request.Params, &params); err == nil {
+			delete(server.documents, params.TextDocument.URI)
+		}
+		return nil
+	case "textDocument/hover":
+		return server.withPosition(request, func(doc *document, position Position) interface{} {
+			return doc.hover(position)
+		})
+	case "textDocument/definition":
+		return server.withPosition(request, func(doc *document, position Position) interface{} {
+			return doc.definition(position)
+		})
+	case "textDocument/completion":
+		return server.withPosition(request, func(doc *document, position Position) interface{} {
+			return doc.completions()
+		})
+	case "textDocument/documentSymbol":
+		var params documentSymbolParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return server.replyError(request.ID, codeInvalidParams, err.Error())
+		}
+		doc, ok := server.documents[params.TextDocument.URI]
+		if !ok {
+			return server.reply(request.ID, []DocumentSymbol{})
+		}
+		return server.reply(request.ID, doc.symbols())
+	default:
+		if request.ID == nil {
+			// Unknown notifications are ignored.
+			return nil
+		}
+		return server.replyError(request.ID, codeMethodNotFound, fmt.Sprintf("method not found: %s", request.Method))
+	}
+}
+
+func (server *Server) withPosition(request *message, answer func(*document, Position) interface{}) error {
+	var params textDocumentPositionParams
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return server.replyError(request.ID, codeInvalidParams, err.Error())
+	}
+
+	doc, ok := server.documents[params.TextDocument.URI]
+	if !ok {
+		return server.reply(request.ID, nil)
+	}
+
+	return server.reply(request.ID, answer(doc, params.Position))
+}
+
+// update analyses a document and publishes its diagnostics.
+func (server *Server) update(uri string, text string) error {
+	return server.publish(newDocument(uri, text, server.Dictionary))
+}
+
+// change applies changes to a document in order, each to the text the
+// one before it left, and re-analyses it once they are all made.
+func (server *Server) change(uri string, changes []textDocumentContentChange) error {
+	var tree *parser.Tree
+	if doc, ok := server.documents[uri]; ok {
+		tree = doc.tree
+	}
+	for _, change := range changes {
+		switch {
+		case change.Range == nil:
+			tree = parser.ParseTreeWithDictionary(change.Text, server.Dictionary)
+		case tree != nil:
+			tree = edit(tree, change)
+		}
+	}
+	if tree == nil {
+		// An edit of a document that was never opened has nothing to apply to.
+		return nil
+	}
+
+	return server.publish(analyse(uri, tree))
+}
+
+// publish stores doc and publishes its diagnostics.
+func (server *Server) publish(doc *document) error {
+	server.documents[doc.uri] = doc
+
+	return server.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         doc.uri,
+		Diagnostics: doc.diagnostics(),
+	})
+}
+
+func (server *Server) reply(id *json.RawMessage, result interface{}) error {
+	return writeMessage(server.writer, response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (server *Server) replyError(id *json.RawMessage, code int, text string) error {
+	return writeMessage(server.writer, errorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   responseError{Code: code, Message: text},
+	})
+}
+
+func (server *Server) notify(method string, params interface{}) error {
+	return writeMessage(server.writer, notification{JSONRPC: "2.0", Method: method, Params: params})
+}
@@ -0,0 +1,339 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testURI = "file:///тест.alipp"
+
+// client scripts a session: requests are queued up front, the server runs
+// to completion and the replies are read back in order.
+type client struct {
+	input  bytes.Buffer
+	nextID int
+}
+
+func (client *client) request(method string, params interface{}) int {
+	client.nextID++
+	client.send(map[string]interface{}{"jsonrpc": "2.0", "id": client.nextID, "method": method, "params": params})
+	return client.nextID
+}
+
+func (client *client) notify(method string, params interface{}) {
+	client.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (client *client) send(value interface{}) {
+	if err := writeMessage(&client.input, value); err != nil {
+		panic(err)
+	}
+}
+
+type reply struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+func (client *client) run(t *testing.T) []reply {
+	var output bytes.Buffer
+	if err := NewServer(&client.input, &output).Run(); err != nil {
+		t.Fatalf("server.Run() returned error: %v", err)
+	}
+
+	replies := []reply{}
+	reader := bufio.NewReader(&output)
+	for reader.Buffered() > 0 || output.Len() > 0 {
+		body, err := readMessage(reader)
+		if err != nil {
+			t.Fatalf("could not read server output: %v", err)
+		}
+		var r reply
+		if err := json.Unmarshal(body, &r); err != nil {
+			t.Fatalf("server sent invalid JSON %s: %v", body, err)
+		}
+		replies = append(replies, r)
+	}
+
+	return replies
+}
+
+func find(t *testing.T, replies []reply, id int) reply {
+	for _, r := range replies {
+		if r.ID != nil && *r.ID == id {
+			return r
+		}
+	}
+
+	t.Fatalf("no reply for request %d", id)
+	return reply{}
+}
+
+func open(client *client, text string) {
+	client.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": testURI, "languageId": "alipp", "version": 1, "text": text},
+	})
+}
+
+func at(line int, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": testURI},
+		"position":     map[string]int{"line": line, "character": character},
+	}
+}
+
+const program = `сакта кош = функ(a, b) {
+  сакта жыйынтык = a + b;
+  кайтар жыйынтык;
+};
+сакта он = кош(3, 7);
+`
+
+func TestInitializeAndShutdown(t *testing.T) {
+	client := &client{}
+	initialize := client.request("initialize", map[string]interface{}{"capabilities": map[string]interface{}{}})
+	shutdown := client.request("shutdown", nil)
+	late := client.request("textDocument/hover", at(0, 0))
+	client.notify("exit", nil)
+
+	replies := client.run(t)
+
+	var result initializeResult
+	if err := json.Unmarshal(find(t, replies, initialize).Result, &result); err != nil {
+		t.Fatalf("initialize result is invalid: %v", err)
+	}
+	if !result.Capabilities.HoverProvider || !result.Capabilities.DefinitionProvider || !result.Capabilities.DocumentSymbolProvider {
+		t.Errorf("capabilities not advertised. got=%+v", result.Capabilities)
+	}
+
+	if r := find(t, replies, shutdown); r.Error != nil || string(r.Result) != "null" {
+		t.Errorf("shutdown reply wrong. got result=%s error=%v", r.Result, r.Error)
+	}
+
+	if r := find(t, replies, late); r.Error == nil || r.Error.Code != codeInvalidRequest {
+		t.Errorf("request after shutdown should fail. got=%+v", r)
+	}
+}
+
+func TestDiagnosticsPublishedOnChange(t *testing.T) {
+	client := &client{}
+	open(client, "сакта = 5;")
+	client.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": testURI, "version": 2},
+		"contentChanges": []map[string]string{{"text": "сакта x = 5;"}},
+	})
+
+	replies := client.run(t)
+	if len(replies) != 2 {
+		t.Fatalf("expected 2 diagnostics notifications. got=%d", len(replies))
+	}
+
+	var first publishDiagnosticsParams
+	json.Unmarshal(replies[0].Params, &first)
+	if replies[0].Method != "textDocument/publishDiagnostics" || len(first.Diagnostics) == 0 {
+		t.Fatalf("expected diagnostics for broken document. got=%s", replies[0].Params)
+	}
+
+	want := Range{Start: Position{0, 6}, End: Position{0, 7}}
+	if first.Diagnostics[0].Range != want {
+		t.Errorf("diagnostic range wrong. want=%+v, got=%+v", want, first.Diagnostics[0].Range)
+	}
+
+	var second publishDiagnosticsParams
+	json.Unmarshal(replies[1].Params, &second)
+	if len(second.Diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared. got=%+v", second.Diagnostics)
+	}
+}
+
+func TestIncrementalChanges(t *testing.T) {
+	client := &client{}
+	open(client, program)
+	client.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": testURI, "version": 2},
+		"contentChanges": []map[string]interface{}{
+			{"range": Range{Start: Position{0, 0}, End: Position{0, 0}}, "text": "сакта = 1;\n"},
+			{"range": Range{Start: Position{2, 19}, End: Position{2, 24}}, "text": "a * b"},
+		},
+	})
+	hoverParameter := client.request("textDocument/hover", at(2, 23))
+	definitionCall := client.request("textDocument/definition", at(5, 11))
+
+	replies := client.run(t)
+
+	var params publishDiagnosticsParams
+	json.Unmarshal(replies[1].Params, &params)
+	want := Range{Start: Position{0, 6}, End: Position{0, 7}}
+	if len(params.Diagnostics) != 1 || params.Diagnostics[0].Range != want {
+		t.Errorf("diagnostics after the edits wrong. got=%+v", params.Diagnostics)
+	}
+
+	var hover Hover
+	json.Unmarshal(find(t, replies, hoverParameter).Result, &hover)
+	if !strings.Contains(hover.Contents.Value, "(параметр) b") {
+		t.Errorf("hover on edited line wrong. got=%q", hover.Contents.Value)
+	}
+
+	var location Location
+	json.Unmarshal(find(t, replies, definitionCall).Result, &location)
+	if location.Range.Start != (Position{1, 6}) {
+		t.Errorf("definition after the edits wrong. got=%+v", location.Range)
+	}
+}
+
+func TestWarningDiagnostics(t *testing.T) {
+	client := &client{}
+	open(client, "сaкта x = 5;")
+
+	replies := client.run(t)
+
+	var params publishDiagnosticsParams
+	json.Unmarshal(replies[0].Params, &params)
+
+	var warning *Diagnostic
+	for i := range params.Diagnostics {
+		if params.Diagnostics[i].Severity == severityWarning {
+			warning = &params.Diagnostics[i]
+		}
+	}
+	if warning == nil {
+		t.Fatalf("expected a warning. got=%+v", params.Diagnostics)
+	}
+
+	want := Range{Start: Position{0, 0}, End: Position{0, 5}}
+	if warning.Range != want || !strings.Contains(warning.Message, `"сакта"`) {
+		t.Errorf("warning wrong. got=%+v", *warning)
+	}
+}
+
+func TestHoverAndDefinition(t *testing.T) {
+	client := &client{}
+	open(client, program)
+	hoverCall := client.request("textDocument/hover", at(4, 11))
+	hoverParameter := client.request("textDocument/hover", at(1, 19))
+	definitionCall := client.request("textDocument/definition", at(4, 11))
+	definitionLocal := client.request("textDocument/definition", at(2, 10))
+	nothing := client.request("textDocument/definition", at(3, 0))
+
+	replies := client.run(t)
+
+	var hover Hover
+	json.Unmarshal(find(t, replies, hoverCall).Result, &hover)
+	if !strings.Contains(hover.Contents.Value, "сакта кош = функ(a, b)") {
+		t.Errorf("hover on function call wrong. got=%q", hover.Contents.Value)
+	}
+
+	json.Unmarshal(find(t, replies, hoverParameter).Result, &hover)
+	if !strings.Contains(hover.Contents.Value, "(параметр) a") {
+		t.Errorf("hover on parameter wrong. got=%q", hover.Contents.Value)
+	}
+
+	tests := []struct {
+		id   int
+		want Range
+	}{
+		{definitionCall, Range{Start: Position{0, 6}, End: Position{0, 9}}},
+		{definitionLocal, Range{Start: Position{1, 8}, End: Position{1, 16}}},
+	}
+	for _, tt := range tests {
+		var location Location
+		json.Unmarshal(find(t, replies, tt.id).Result, &location)
+		if location.URI != testURI || location.Range != tt.want {
+			t.Errorf("definition for request %d wrong. want=%+v, got=%+v", tt.id, tt.want, location)
+		}
+	}
+
+	if result := string(find(t, replies, nothing).Result); result != "null" {
+		t.Errorf("definition outside identifiers should be null. got=%s", result)
+	}
+}
+
+func TestMatchBindings(t *testing.T) {
+	client := &client{}
+	open(client, "сакта n = 1;\nсалыштыр (n) { [n] => n, _ => n }")
+	hoverBound := client.request("textDocument/hover", at(1, 22))
+	definitionBound := client.request("textDocument/definition", at(1, 22))
+	definitionOuter := client.request("textDocument/definition", at(1, 30))
+
+	replies := client.run(t)
+
+	var hover Hover
+	json.Unmarshal(find(t, replies, hoverBound).Result, &hover)
+	if !strings.Contains(hover.Contents.Value, "(үлгү) n") {
+		t.Errorf("hover on pattern binding wrong. got=%q", hover.Contents.Value)
+	}
+
+	tests := []struct {
+		id   int
+		want Range
+	}{
+		{definitionBound, Range{Start: Position{1, 16}, End: Position{1, 17}}},
+		{definitionOuter, Range{Start: Position{0, 6}, End: Position{0, 7}}},
+	}
+	for _, tt := range tests {
+		var location Location
+		json.Unmarshal(find(t, replies, tt.id).Result, &location)
+		if location.URI != testURI || location.Range != tt.want {
+			t.Errorf("definition for request %d wrong. want=%+v, got=%+v", tt.id, tt.want, location)
+		}
+	}
+}
+
+func TestDocumentSymbolsAndCompletion(t *testing.T) {
+	client := &client{}
+	open(client, program)
+	symbols := client.request("textDocument/documentSymbol", map[string]interface{}{
+		"textDocument": map[string]string{"uri": testURI},
+	})
+	completion := client.request("textDocument/completion", at(5, 0))
+
+	replies := client.run(t)
+
+	var documentSymbols []DocumentSymbol
+	json.Unmarshal(find(t, replies, symbols).Result, &documentSymbols)
+	got := []string{}
+	for _, symbol := range documentSymbols {
+		entry := fmt.Sprintf("%s:%d", symbol.Name, symbol.Kind)
+		for _, child := range symbol.Children {
+			entry += fmt.Sprintf("[%s:%d]", child.Name, child.Kind)
+		}
+		got = append(got, entry)
+	}
+	want := []string{"кош:12[жыйынтык:13]", "он:13"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("document symbols wrong. want=%v, got=%v", want, got)
+	}
+
+	var items []CompletionItem
+	json.Unmarshal(find(t, replies, completion).Result, &items)
+	labels := map[string]int{}
+	for _, item := range items {
+		labels[item.Label] = item.Kind
+	}
+	for _, keyword := range []string{"сакта", "функция", "кайтар", "эгер"} {
+		if labels[keyword] != completionKindKeyword {
+			t.Errorf("keyword %q missing from completion", keyword)
+		}
+	}
+	if labels["кош"] != completionKindFunction {
+		t.Errorf("function кош missing from completion. got=%v", labels)
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	client := &client{}
+	id := client.request("workspace/symbol", map[string]string{"query": ""})
+
+	replies := client.run(t)
+
+	if r := find(t, replies, id); r.Error == nil || r.Error.Code != codeMethodNotFound {
+		t.Errorf("unknown method should fail with %d. got=%+v", codeMethodNotFound, r)
+	}
+}
@@ -0,0 +1,345 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LockName is the name of the lockfile next to a manifest.
+const LockName = "alipp.lock"
+
+const (
+	pathSource     = "path+"
+	registrySource = "registry"
+)
+
+// Package is a package that a manifest depends on, directly or through
+// other packages.
+type Package struct {
+	Name    string
+	Version string
+	// Source is "path+" and the directory of the package relative to the
+	// manifest being resolved, or "registry" for packages copied into its
+	// vendor directory.
+	Source string
+	// Hash is "sha256:" and the hash of the files of the package.
+	Hash string
+	// Directory is where the package is on disk.
+	Directory string
+	Entry     string
+}
+
+// DefaultRegistry is the registry that is used when neither a flag nor
+// ALIPP_REGISTRY names one: .alipp/registry in the home directory.
+func DefaultRegistry() string {
+	if registry := os.Getenv(RegistryVariable); registry != "" {
+		return registry
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".alipp", "registry")
+}
+
+// Resolve finds every package that manifest depends on. A dependency
+// with a path is used where it is; one with a version is copied from
+// <registry>/<name>/<version> into the vendor directory of manifest, or
+// taken from there when the registry does not have it. Packages share
+// one namespace, so two different packages with the same name are an
+// error.
+func Resolve(manifest *Manifest, registry string) ([]Package, error) {
+	resolved := map[string]*Package{}
+	type pending struct {
+		dependency Dependency
+		from       *Manifest
+		// vendored is set for the dependencies of registry packages,
+		// whose paths would mean nothing once they are copied.
+		vendored bool
+	}
+	queue := []pending{}
+	for _, dependency := range manifest.Dependencies {
+		queue = append(queue, pending{dependency, manifest, false})
+	}
+
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		dependency := next.dependency
+
+		var directory, source string
+		if dependency.Path != "" && next.vendored {
+			return nil, fmt.Errorf("реестрдеги %s пакети %s пакетин path менен колдоно албайт", next.from.Name, dependency.Name)
+		}
+		if dependency.Path != "" {
+			directory = filepath.Clean(filepath.Join(next.from.Directory, filepath.FromSlash(dependency.Path)))
+			relative, err := filepath.Rel(manifest.Directory, directory)
+			if err != nil {
+				return nil, err
+			}
+			source = pathSource + filepath.ToSlash(relative)
+		} else {
+			directory = filepath.Join(manifest.Directory, VendorDirectory, dependency.Name)
+			source = registrySource
+		}
+
+		if existing, ok := resolved[dependency.Name]; ok {
+			if existing.Source != source || (dependency.Version != "" && existing.Version != dependency.Version) {
+				return nil, fmt.Errorf("%s пакети эки башка жерден керек: %s жана %s", dependency.Name,
+					describe(*existing), describe(Package{Source: source, Version: dependency.Version}))
+			}
+			continue
+		}
+
+		if dependency.Version != "" {
+			if err := vendor(registry, dependency, directory); err != nil {
+				return nil, err
+			}
+		}
+
+		dependencyManifest, err := Read(directory)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s пакетинин %s файлы табылган жок", dependency.Name, filepath.Join(directory, FileName))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if dependencyManifest.Name != dependency.Name {
+			return nil, fmt.Errorf("%s пакетинин ордунда %s пакети турат: %s", dependency.Name, dependencyManifest.Name, directory)
+		}
+		if dependency.Version != "" && dependencyManifest.Version != dependency.Version {
+			return nil, fmt.Errorf("%s@%s керек, бирок %s ичинде %s бар", dependency.Name, dependency.Version, directory, dependencyManifest.Version)
+		}
+
+		hash, err := Hash(directory)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved[dependency.Name] = &Package{
+			Name:      dependency.Name,
+			Version:   dependencyManifest.Version,
+			Source:    source,
+			Hash:      hash,
+			Directory: directory,
+			Entry:     dependencyManifest.Entry,
+		}
+		for _, inner := range dependencyManifest.Dependencies {
+			queue = append(queue, pending{inner, dependencyManifest, dependency.Version != ""})
+		}
+	}
+
+	packages := []Package{}
+	for _, found := range resolved {
+		packages = append(packages, *found)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+
+	return packages, nil
+}
+
+func describe(found Package) string {
+	if found.Source == registrySource {
+		return "реестр, " + found.Version
+	}
+
+	return strings.TrimPrefix(found.Source, pathSource)
+}
+
+// vendor copies a dependency from the registry into directory. A copy
+// that is already there is kept when the registry does not have the
+// package, so that vendored packages work without a registry.
+func vendor(registry string, dependency Dependency, directory string) error {
+	from := filepath.Join(registry, dependency.Name, dependency.Version)
+	if info, err := os.Stat(from); registry == "" || err != nil || !info.IsDir() {
+		if _, err := os.Stat(filepath.Join(directory, FileName)); err == nil {
+			return nil
+		}
+		return fmt.Errorf("%s@%s реестрден табылган жок (%s)", dependency.Name, dependency.Version, from)
+	}
+
+	if err := os.RemoveAll(directory); err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(from, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(directory, relative)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0644)
+	})
+}
+
+// Hash hashes the files of the package in directory, in order of their
+// paths. Its vendor directory, lockfile and hidden files are left out,
+// since they are not part of what the package is.
+func Hash(directory string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(directory, path)
+		if err != nil || relative == "." {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+		if strings.HasPrefix(entry.Name(), ".") || relative == VendorDirectory || relative == LockName {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		hash.Write([]byte(relative))
+		hash.Write([]byte{0})
+		hash.Write(content)
+		hash.Write([]byte{0})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WriteLock writes the lockfile of manifest.
+func WriteLock(manifest *Manifest, packages []Package) error {
+	var out strings.Builder
+	out.WriteString("# Бул файлды `alipp deps` жазат, аны колго өзгөртпөңүз.\n")
+	for _, found := range packages {
+		fmt.Fprintf(&out, "\n[[package]]\nname = %s\nversion = %s\nsource = %s\nhash = %s\n",
+			quoteTOML(found.Name), quoteTOML(found.Version), quoteTOML(found.Source), quoteTOML(found.Hash))
+	}
+
+	return os.WriteFile(filepath.Join(manifest.Directory, LockName), []byte(out.String()), 0644)
+}
+
+// ReadLock reads the lockfile of manifest.
+func ReadLock(manifest *Manifest) ([]Package, error) {
+	path := filepath.Join(manifest.Directory, LockName)
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := parseTOML(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	tables, _ := document["package"].([]map[string]interface{})
+	if len(document) > 1 || (len(document) == 1 && tables == nil) {
+		return nil, fmt.Errorf("%s: [[package]] гана болушу керек", path)
+	}
+
+	packages := []Package{}
+	for _, table := range tables {
+		found := Package{}
+		fields := map[string]*string{"name": &found.Name, "version": &found.Version, "source": &found.Source, "hash": &found.Hash}
+		for key, value := range table {
+			text, ok := value.(string)
+			if fields[key] == nil || !ok {
+				return nil, fmt.Errorf("%s: туура эмес ачкыч: %s", path, key)
+			}
+			*fields[key] = text
+		}
+
+		switch {
+		case found.Source == registrySource:
+			found.Directory = filepath.Join(manifest.Directory, VendorDirectory, found.Name)
+		case strings.HasPrefix(found.Source, pathSource):
+			found.Directory = filepath.Join(manifest.Directory, filepath.FromSlash(strings.TrimPrefix(found.Source, pathSource)))
+		default:
+			return nil, fmt.Errorf("%s: %s пакетинин булагы түшүнүксүз: %q", path, found.Name, found.Source)
+		}
+		packages = append(packages, found)
+	}
+
+	return packages, nil
+}
+
+// Locked returns the packages that the lockfile of manifest records,
+// after checking that it covers the dependencies of manifest and that
+// none of the packages changed since it was written.
+func Locked(manifest *Manifest) ([]Package, error) {
+	stale := func(reason string) error {
+		return fmt.Errorf("%s: %s; `alipp deps` буйругун иштетиңиз", filepath.Join(manifest.Directory, LockName), reason)
+	}
+
+	packages, err := ReadLock(manifest)
+	if errors.Is(err, fs.ErrNotExist) {
+		if len(manifest.Dependencies) == 0 {
+			return nil, nil
+		}
+		return nil, stale("файл жок")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]*Package{}
+	for i := range packages {
+		byName[packages[i].Name] = &packages[i]
+	}
+	for _, dependency := range manifest.Dependencies {
+		found, ok := byName[dependency.Name]
+		if !ok || (dependency.Version != "" && (found.Source != registrySource || found.Version != dependency.Version)) ||
+			(dependency.Path != "" && found.Source == registrySource) {
+			return nil, stale(dependency.Name + " пакети жазылган эмес")
+		}
+	}
+
+	for i := range packages {
+		found := &packages[i]
+		packageManifest, err := Read(found.Directory)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, stale(found.Name + " пакети табылган жок")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := Hash(found.Directory)
+		if err != nil {
+			return nil, err
+		}
+		if hash != found.Hash {
+			return nil, stale(found.Name + " пакети өзгөрдү")
+		}
+		found.Entry = packageManifest.Entry
+	}
+
+	return packages, nil
+}
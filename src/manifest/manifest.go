@@ -0,0 +1,268 @@
+// Package manifest reads alipp.toml, which describes a package and the
+// packages it depends on, and alipp.lock, which records what those
+// resolved to. Dependencies come from local paths, or from a registry
+// kept in a local directory, from which they are copied into the vendor
+// directory of the package. Nothing is fetched over the network.
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// FileName is the name of the manifest in the directory of a package.
+	FileName = "alipp.toml"
+	// DefaultEntry is the entry point of a package whose manifest does not
+	// name one.
+	DefaultEntry = "негизги.alipp"
+	// VendorDirectory holds the packages copied from the registry.
+	VendorDirectory = "vendor"
+	// RegistryVariable is the environment variable that names the
+	// registry directory.
+	RegistryVariable = "ALIPP_REGISTRY"
+)
+
+// Manifest is a parsed alipp.toml.
+type Manifest struct {
+	Name    string
+	Version string
+	// Entry is the file, relative to Directory, that importing the
+	// package by its name loads.
+	Entry        string
+	Dependencies []Dependency
+	// Directory is the directory the manifest is in.
+	Directory string
+}
+
+// Dependency is a package a manifest depends on: the one in the local
+// directory Path, relative to the manifest, or the one with Version in
+// the registry.
+type Dependency struct {
+	Name    string
+	Path    string
+	Version string
+}
+
+// Read reads the manifest in directory.
+func Read(directory string) (*Manifest, error) {
+	path := filepath.Join(directory, FileName)
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	manifest.Directory = directory
+
+	return manifest, nil
+}
+
+// Find reads the manifest in directory or the nearest directory above
+// it, and returns nil when there is none.
+func Find(directory string) (*Manifest, error) {
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		manifest, err := Read(directory)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return manifest, err
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return nil, nil
+		}
+		directory = parent
+	}
+}
+
+// Init writes a manifest for a package called name in directory, and an
+// empty entry point unless there is one. It fails if directory already
+// has a manifest.
+func Init(directory string, name string) (*Manifest, error) {
+	if !validName(name) {
+		return nil, fmt.Errorf("%q пакеттин аты боло албайт", name)
+	}
+
+	manifest := &Manifest{Name: name, Version: "0.1.0", Entry: DefaultEntry, Directory: directory}
+	path := filepath.Join(directory, FileName)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("%s бар", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.WriteString(manifest.String()); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	entry := filepath.Join(directory, manifest.Entry)
+	if _, err := os.Stat(entry); errors.Is(err, fs.ErrNotExist) {
+		if err := os.WriteFile(entry, []byte("көрсөтүү(\"Салам, Дүйнө!\");\n"), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// String writes the manifest in the format Read reads.
+func (manifest *Manifest) String() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "[package]\nname = %s\nversion = %s\nentry = %s\n\n[dependencies]\n",
+		quoteTOML(manifest.Name), quoteTOML(manifest.Version), quoteTOML(manifest.Entry))
+	for _, dependency := range manifest.Dependencies {
+		if dependency.Path != "" {
+			fmt.Fprintf(&out, "%s = { path = %s }\n", keyTOML(dependency.Name), quoteTOML(dependency.Path))
+		} else {
+			fmt.Fprintf(&out, "%s = { version = %s }\n", keyTOML(dependency.Name), quoteTOML(dependency.Version))
+		}
+	}
+
+	return out.String()
+}
+
+func parse(source string) (*Manifest, error) {
+	document, err := parseTOML(source)
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range document {
+		if key != "package" && key != "dependencies" {
+			return nil, fmt.Errorf("белгисиз таблица: %s", key)
+		}
+	}
+
+	manifest := &Manifest{Entry: DefaultEntry}
+	table, ok := document["package"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("[package] таблицасы керек")
+	}
+	for key, value := range table {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("package.%s сап болушу керек", key)
+		}
+
+		switch key {
+		case "name":
+			manifest.Name = text
+		case "version":
+			manifest.Version = text
+		case "entry":
+			manifest.Entry = text
+		default:
+			return nil, fmt.Errorf("белгисиз ачкыч: package.%s", key)
+		}
+	}
+
+	switch {
+	case !validName(manifest.Name):
+		return nil, fmt.Errorf("package.name туура эмес: %q", manifest.Name)
+	case !validVersion(manifest.Version):
+		return nil, fmt.Errorf("package.version X.Y.Z түрүндө болушу керек: %q", manifest.Version)
+	case manifest.Entry == "" || filepath.IsAbs(manifest.Entry):
+		return nil, fmt.Errorf("package.entry пакеттин ичиндеги файл болушу керек: %q", manifest.Entry)
+	}
+
+	dependencies, ok := document["dependencies"].(map[string]interface{})
+	if !ok && document["dependencies"] != nil {
+		return nil, fmt.Errorf("dependencies таблица болушу керек")
+	}
+	for name, value := range dependencies {
+		dependency, err := parseDependency(name, value)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Dependencies = append(manifest.Dependencies, dependency)
+	}
+	sort.Slice(manifest.Dependencies, func(i, j int) bool {
+		return manifest.Dependencies[i].Name < manifest.Dependencies[j].Name
+	})
+
+	return manifest, nil
+}
+
+func parseDependency(name string, value interface{}) (Dependency, error) {
+	dependency := Dependency{Name: name}
+	if !validName(name) {
+		return dependency, fmt.Errorf("%q пакеттин аты боло албайт", name)
+	}
+
+	table, ok := value.(map[string]interface{})
+	if !ok {
+		return dependency, fmt.Errorf("dependencies.%s { path = \"...\" } же { version = \"...\" } болушу керек", name)
+	}
+	for key, value := range table {
+		text, ok := value.(string)
+		if !ok {
+			return dependency, fmt.Errorf("dependencies.%s.%s сап болушу керек", name, key)
+		}
+
+		switch key {
+		case "path":
+			dependency.Path = text
+		case "version":
+			dependency.Version = text
+		default:
+			return dependency, fmt.Errorf("белгисиз ачкыч: dependencies.%s.%s", name, key)
+		}
+	}
+
+	switch {
+	case (dependency.Path == "") == (dependency.Version == ""):
+		return dependency, fmt.Errorf("dependencies.%s үчүн path же version керек, экөө тең эмес", name)
+	case dependency.Version != "" && !validVersion(dependency.Version):
+		return dependency, fmt.Errorf("dependencies.%s.version X.Y.Z түрүндө болушу керек: %q", name, dependency.Version)
+	}
+
+	return dependency, nil
+}
+
+// validName reports whether name can name a package, which imports use
+// as the first part of a path.
+func validName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	for _, r := range name {
+		if !isBareKeyRune(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validVersion accepts versions of three numbers such as 1.12.0.
+func validVersion(version string) bool {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,209 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files, by their slash-separated paths, under a new
+// directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	directory := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(directory, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return directory
+}
+
+func TestRead(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"alipp.toml": `[package]
+name = "колдонмо"
+version = "1.2.3"
+
+[dependencies]
+жардам = { path = "../жардам" }
+сап = { version = "0.1.0" }
+`,
+		"ичинде/файл.alipp": ``,
+	})
+
+	manifest, err := Find(filepath.Join(directory, "ичинде"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Manifest{
+		Name:    "колдонмо",
+		Version: "1.2.3",
+		Entry:   DefaultEntry,
+		Dependencies: []Dependency{
+			{Name: "жардам", Path: "../жардам"},
+			{Name: "сап", Version: "0.1.0"},
+		},
+		Directory: directory,
+	}
+	if !reflect.DeepEqual(manifest, expected) {
+		t.Errorf("want=%#v, got=%#v", expected, manifest)
+	}
+
+	again, err := parse(manifest.String())
+	if err != nil || !reflect.DeepEqual(again.Dependencies, expected.Dependencies) || again.Name != expected.Name {
+		t.Errorf("written manifest did not read back. got=%#v, %v", again, err)
+	}
+
+	if none, err := Find(t.TempDir()); none != nil || err != nil {
+		t.Errorf("found a manifest where there is none. got=%#v, %v", none, err)
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "[package] таблицасы керек"},
+		{"[package]\nname = \"a\"\nversion = \"1.0\"", `package.version X.Y.Z түрүндө болушу керек: "1.0"`},
+		{"[package]\nname = \"a b\"\nversion = \"1.0.0\"", `package.name туура эмес: "a b"`},
+		{"[package]\nname = 1", "package.name сап болушу керек"},
+		{"[package]\nname = \"a\"\nversion = \"1.0.0\"\nauthor = \"b\"", "белгисиз ачкыч: package.author"},
+		{"[package]\nname = \"a\"\nversion = \"1.0.0\"\nentry = \"/a\"", `package.entry пакеттин ичиндеги файл болушу керек: "/a"`},
+		{"[tools]", "белгисиз таблица: tools"},
+		{"[package]\nname = \"a\"\nversion = \"1.0.0\"\n[dependencies]\nb = \"1.0.0\"", `dependencies.b { path = "..." } же { version = "..." } болушу керек`},
+		{"[package]\nname = \"a\"\nversion = \"1.0.0\"\n[dependencies]\nb = { path = \"b\", version = \"1.0.0\" }", "dependencies.b үчүн path же version керек, экөө тең эмес"},
+		{"[package]\nname = \"a\"\nversion = \"1.0.0\"\n[dependencies]\nb = { version = \"1\" }", `dependencies.b.version X.Y.Z түрүндө болушу керек: "1"`},
+	}
+
+	for _, test := range tests {
+		_, err := parse(test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%q: want=%q, got=%v", test.input, test.expected, err)
+		}
+	}
+}
+
+func TestInit(t *testing.T) {
+	directory := writeFiles(t, map[string]string{"бар/негизги.alipp": "1"})
+
+	for _, name := range []string{"жаңы", "бар"} {
+		path := filepath.Join(directory, name)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Init(path, name); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		manifest, err := Read(path)
+		if err != nil || manifest.Name != name || manifest.Version != "0.1.0" {
+			t.Errorf("%s: wrong manifest. got=%#v, %v", name, manifest, err)
+		}
+	}
+
+	if entry, _ := os.ReadFile(filepath.Join(directory, "бар", DefaultEntry)); string(entry) != "1" {
+		t.Errorf("an existing entry point was overwritten. got=%q", entry)
+	}
+	if _, err := Init(filepath.Join(directory, "бар"), "бар"); err == nil || !strings.HasSuffix(err.Error(), "alipp.toml бар") {
+		t.Errorf("wrong error for an existing manifest. got=%v", err)
+	}
+	if _, err := Init(directory, "a/b"); err == nil || err.Error() != `"a/b" пакеттин аты боло албайт` {
+		t.Errorf("wrong error for a bad name. got=%v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"app/alipp.toml":                 "[package]\nname = \"app\"\nversion = \"0.1.0\"\n[dependencies]\nb = { path = \"../b\" }\nc = { version = \"1.0.0\" }\n",
+		"b/alipp.toml":                   "[package]\nname = \"b\"\nversion = \"0.2.0\"\nentry = \"b.alipp\"\n[dependencies]\nc = { version = \"1.0.0\" }\n",
+		"b/b.alipp":                      "экспорт сакта b = 1;",
+		"registry/c/1.0.0/alipp.toml":    "[package]\nname = \"c\"\nversion = \"1.0.0\"\n",
+		"registry/c/1.0.0/негизги.alipp": "экспорт сакта c = 1;",
+		"registry/c/2.0.0/alipp.toml":    "[package]\nname = \"c\"\nversion = \"2.0.0\"\n",
+	})
+	registry := filepath.Join(directory, "registry")
+	app, err := Read(filepath.Join(directory, "app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packages, err := Resolve(app, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 2 || packages[0].Source != "path+../b" || packages[0].Entry != "b.alipp" ||
+		packages[1].Source != "registry" || packages[1].Directory != filepath.Join(app.Directory, "vendor", "c") {
+		t.Fatalf("wrong packages. got=%#v", packages)
+	}
+	if vendored, err := os.ReadFile(filepath.Join(app.Directory, "vendor", "c", DefaultEntry)); string(vendored) != "экспорт сакта c = 1;" {
+		t.Errorf("c was not vendored. got=%q, %v", vendored, err)
+	}
+
+	// The vendored copy is enough without the registry.
+	again, err := Resolve(app, filepath.Join(directory, "жок"))
+	if err != nil || !reflect.DeepEqual(again, packages) {
+		t.Errorf("vendored packages did not resolve the same. got=%#v, %v", again, err)
+	}
+
+	if err := WriteLock(app, packages); err != nil {
+		t.Fatal(err)
+	}
+	locked, err := Locked(app)
+	if err != nil || !reflect.DeepEqual(locked, packages) {
+		t.Errorf("lockfile did not read back. got=%#v, %v", locked, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(directory, "b", "b.alipp"), []byte("экспорт сакта b = 2;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(app.Directory, LockName)
+	if _, err := Locked(app); err == nil || err.Error() != lockPath+": b пакети өзгөрдү; `alipp deps` буйругун иштетиңиз" {
+		t.Errorf("wrong error for a changed package. got=%v", err)
+	}
+
+	app.Dependencies = append(app.Dependencies, Dependency{Name: "d", Path: "../d"})
+	if _, err := Locked(app); err == nil || err.Error() != lockPath+": d пакети жазылган эмес; `alipp deps` буйругун иштетиңиз" {
+		t.Errorf("wrong error for a dependency missing from the lockfile. got=%v", err)
+	}
+
+	app.Dependencies = []Dependency{{Name: "c", Version: "2.0.0"}, {Name: "b", Path: "../b"}}
+	if _, err := Resolve(app, registry); err == nil || err.Error() != "c пакети эки башка жерден керек: реестр, 2.0.0 жана реестр, 1.0.0" {
+		t.Errorf("wrong error for a conflict. got=%v", err)
+	}
+
+	app.Dependencies = []Dependency{{Name: "e", Version: "1.0.0"}}
+	expected := "e@1.0.0 реестрден табылган жок (" + filepath.Join(registry, "e", "1.0.0") + ")"
+	if _, err := Resolve(app, registry); err == nil || err.Error() != expected {
+		t.Errorf("wrong error for a missing package. got=%v", err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	first := writeFiles(t, map[string]string{"a.alipp": "1", "b/c.alipp": "2"})
+	second := writeFiles(t, map[string]string{"a.alipp": "1", "b/c.alipp": "2", ".git/x": "3", "vendor/d/e": "4", LockName: "5"})
+	third := writeFiles(t, map[string]string{"a.alipp": "1", "b/c.alipp": "3"})
+
+	hashes := []string{}
+	for _, directory := range []string{first, second, third} {
+		hash, err := Hash(directory)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if hashes[0] != hashes[1] || hashes[0] == hashes[2] || !strings.HasPrefix(hashes[0], "sha256:") {
+		t.Errorf("wrong hashes. got=%q", hashes)
+	}
+}
@@ -0,0 +1,313 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tomlError is a syntax error on a line of a TOML file.
+type tomlError struct {
+	Line    int
+	Message string
+}
+
+func (err *tomlError) Error() string {
+	return fmt.Sprintf("%d-сап: %s", err.Line, err.Message)
+}
+
+// parseTOML reads the part of TOML that manifests and lockfiles use:
+// tables, arrays of tables, and keys whose values are strings, integers,
+// booleans, arrays or inline tables written on one line. Bare keys may
+// be made of any letters, so that package names need no quotes.
+func parseTOML(source string) (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	table := root
+
+	for i, line := range strings.Split(source, "\n") {
+		number := i + 1
+		text := strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			array := strings.HasPrefix(text, "[[")
+			header := strings.TrimPrefix(text, "[")
+			closing := "]"
+			if array {
+				header = strings.TrimPrefix(header, "[")
+				closing = "]]"
+			}
+
+			name, rest, err := parseKey(header)
+			if err != nil {
+				return nil, &tomlError{Line: number, Message: err.Error()}
+			}
+			rest = strings.TrimSpace(rest)
+			if !strings.HasPrefix(rest, closing) || !isBlank(rest[len(closing):]) {
+				return nil, &tomlError{Line: number, Message: fmt.Sprintf("таблицанын аты %s менен бүтүшү керек", closing)}
+			}
+
+			table = map[string]interface{}{}
+			switch existing := root[name].(type) {
+			case nil:
+				if array {
+					root[name] = []map[string]interface{}{table}
+				} else {
+					root[name] = table
+				}
+			case []map[string]interface{}:
+				if !array {
+					return nil, &tomlError{Line: number, Message: fmt.Sprintf("%s эки жолу аныкталды", name)}
+				}
+				root[name] = append(existing, table)
+			default:
+				return nil, &tomlError{Line: number, Message: fmt.Sprintf("%s эки жолу аныкталды", name)}
+			}
+			continue
+		}
+
+		key, value, rest, err := parsePair(text)
+		if err == nil && !isBlank(rest) {
+			err = fmt.Errorf("маанинин артында күтүлбөгөн текст: %s", strings.TrimSpace(rest))
+		}
+		if err == nil {
+			err = set(table, key, value)
+		}
+		if err != nil {
+			return nil, &tomlError{Line: number, Message: err.Error()}
+		}
+	}
+
+	return root, nil
+}
+
+func set(table map[string]interface{}, key string, value interface{}) error {
+	if _, ok := table[key]; ok {
+		return fmt.Errorf("%s эки жолу аныкталды", key)
+	}
+
+	table[key] = value
+	return nil
+}
+
+// isBlank reports whether only spaces and a comment are left.
+func isBlank(rest string) bool {
+	rest = strings.TrimSpace(rest)
+	return rest == "" || strings.HasPrefix(rest, "#")
+}
+
+func parsePair(text string) (string, interface{}, string, error) {
+	key, rest, err := parseKey(text)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "=") {
+		return "", nil, "", fmt.Errorf("%s ачкычынан кийин = керек", key)
+	}
+
+	value, rest, err := parseValue(strings.TrimSpace(rest[1:]))
+	return key, value, rest, err
+}
+
+// parseKey reads a bare or quoted key.
+func parseKey(text string) (string, string, error) {
+	text = strings.TrimLeft(text, " \t")
+	if strings.HasPrefix(text, "\"") || strings.HasPrefix(text, "'") {
+		return parseString(text)
+	}
+
+	end := 0
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !isBareKeyRune(r) {
+			break
+		}
+		end += size
+	}
+	if end == 0 {
+		return "", "", fmt.Errorf("ачкыч керек")
+	}
+
+	return text[:end], text[end:], nil
+}
+
+func isBareKeyRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || r == '_' || r == '-'
+}
+
+func parseValue(text string) (interface{}, string, error) {
+	switch {
+	case strings.HasPrefix(text, "\"") || strings.HasPrefix(text, "'"):
+		return parseString(text)
+	case strings.HasPrefix(text, "["):
+		return parseArray(text[1:])
+	case strings.HasPrefix(text, "{"):
+		return parseInlineTable(text[1:])
+	case strings.HasPrefix(text, "true"):
+		return true, text[len("true"):], nil
+	case strings.HasPrefix(text, "false"):
+		return false, text[len("false"):], nil
+	}
+
+	end := 0
+	for end < len(text) && strings.ContainsRune("+-0123456789_", rune(text[end])) {
+		end++
+	}
+	number, err := strconv.ParseInt(strings.ReplaceAll(text[:end], "_", ""), 10, 64)
+	if end == 0 || err != nil {
+		return nil, "", fmt.Errorf("маани түшүнүксүз: %s", text)
+	}
+
+	return number, text[end:], nil
+}
+
+// parseString reads a basic string, with escapes, or a literal string.
+func parseString(text string) (string, string, error) {
+	if strings.HasPrefix(text, "'") {
+		end := strings.IndexByte(text[1:], '\'')
+		if end < 0 {
+			return "", "", fmt.Errorf("сап жабылган жок")
+		}
+		return text[1 : end+1], text[end+2:], nil
+	}
+
+	var out strings.Builder
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			return out.String(), text[i+1:], nil
+		case '\\':
+			if i+1 == len(text) {
+				return "", "", fmt.Errorf("сап жабылган жок")
+			}
+			i++
+			switch text[i] {
+			case '"', '\\':
+				out.WriteByte(text[i])
+			case 'n':
+				out.WriteByte('\n')
+			case 't':
+				out.WriteByte('\t')
+			case 'r':
+				out.WriteByte('\r')
+			case 'u', 'U':
+				size := 4
+				if text[i] == 'U' {
+					size = 8
+				}
+				code, err := strconv.ParseUint(text[i+1:min(i+1+size, len(text))], 16, 32)
+				if err != nil || i+1+size > len(text) || !utf8.ValidRune(rune(code)) {
+					return "", "", fmt.Errorf("туура эмес \\%c белгиси", text[i])
+				}
+				out.WriteRune(rune(code))
+				i += size
+			default:
+				return "", "", fmt.Errorf("туура эмес \\%c белгиси", text[i])
+			}
+		default:
+			out.WriteByte(text[i])
+		}
+	}
+
+	return "", "", fmt.Errorf("сап жабылган жок")
+}
+
+func parseArray(text string) ([]interface{}, string, error) {
+	values := []interface{}{}
+	for {
+		text = strings.TrimSpace(text)
+		if strings.HasPrefix(text, "]") {
+			return values, text[1:], nil
+		}
+
+		value, rest, err := parseValue(text)
+		if err != nil {
+			return nil, "", err
+		}
+		values = append(values, value)
+
+		rest = strings.TrimSpace(rest)
+		switch {
+		case strings.HasPrefix(rest, ","):
+			text = rest[1:]
+		case strings.HasPrefix(rest, "]"):
+			return values, rest[1:], nil
+		default:
+			return nil, "", fmt.Errorf("тизме ] менен бүтүшү керек")
+		}
+	}
+}
+
+func parseInlineTable(text string) (map[string]interface{}, string, error) {
+	table := map[string]interface{}{}
+	if rest := strings.TrimSpace(text); strings.HasPrefix(rest, "}") {
+		return table, rest[1:], nil
+	}
+
+	for {
+		key, value, rest, err := parsePair(text)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := set(table, key, value); err != nil {
+			return nil, "", err
+		}
+
+		rest = strings.TrimSpace(rest)
+		switch {
+		case strings.HasPrefix(rest, ","):
+			text = rest[1:]
+		case strings.HasPrefix(rest, "}"):
+			return table, rest[1:], nil
+		default:
+			return nil, "", fmt.Errorf("таблица } менен бүтүшү керек")
+		}
+	}
+}
+
+// quoteTOML writes value as a TOML basic string.
+func quoteTOML(value string) string {
+	var out strings.Builder
+	out.WriteByte('"')
+	for _, r := range value {
+		switch {
+		case r == '"' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '\n':
+			out.WriteString("\\n")
+		case r == '\t':
+			out.WriteString("\\t")
+		case r == '\r':
+			out.WriteString("\\r")
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&out, "\\u%04X", r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+	out.WriteByte('"')
+
+	return out.String()
+}
+
+// keyTOML writes key bare when TOML itself allows it, which is only for
+// ASCII letters, digits, _ and -.
+func keyTOML(key string) string {
+	for _, r := range key {
+		if r > unicode.MaxASCII || !isBareKeyRune(r) {
+			return quoteTOML(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+
+	return key
+}
@@ -0,0 +1,87 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected map[string]interface{}
+	}{
+		{
+			"# комментарий\nаты = \"сап\" # комментарий\nсан = 1_000\nтуура = true\n",
+			map[string]interface{}{"аты": "сап", "сан": int64(1000), "туура": true},
+		},
+		{
+			"a = 'C:\\жол'\nb = \"\\\"\\t\\u0416\"\n\"ачкыч менен\" = []\nc = [1, \"x\", [false],]\n",
+			map[string]interface{}{"a": `C:\жол`, "b": "\"\tЖ", "ачкыч менен": []interface{}{}, "c": []interface{}{int64(1), "x", []interface{}{false}}},
+		},
+		{
+			"[package]\nname = \"a\"\n\n[dependencies]\nb = { path = \"../b\" }\nc = {}\n",
+			map[string]interface{}{
+				"package":      map[string]interface{}{"name": "a"},
+				"dependencies": map[string]interface{}{"b": map[string]interface{}{"path": "../b"}, "c": map[string]interface{}{}},
+			},
+		},
+		{
+			"[[package]]\nname = \"a\"\n[[package]]\nname = \"b\"\n",
+			map[string]interface{}{"package": []map[string]interface{}{{"name": "a"}, {"name": "b"}}},
+		},
+	}
+
+	for _, test := range tests {
+		document, err := parseTOML(test.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(document, test.expected) {
+			t.Errorf("%q: want=%#v, got=%#v", test.input, test.expected, document)
+		}
+	}
+}
+
+func TestTOMLErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a = 1\na = 2", "2-сап: a эки жолу аныкталды"},
+		{"[a]\n[a]", "2-сап: a эки жолу аныкталды"},
+		{"[a]\n[[a]]", "2-сап: a эки жолу аныкталды"},
+		{"[a", "1-сап: таблицанын аты ] менен бүтүшү керек"},
+		{"a = \"ачык", "1-сап: сап жабылган жок"},
+		{"a = \"\\q\"", "1-сап: туура эмес \\q белгиси"},
+		{"a = 1 2", "1-сап: маанинин артында күтүлбөгөн текст: 2"},
+		{"a = [1 2]", "1-сап: тизме ] менен бүтүшү керек"},
+		{"a = { b = 1 c = 2 }", "1-сап: таблица } менен бүтүшү керек"},
+		{"a = жок", "1-сап: маани түшүнүксүз: жок"},
+		{"a", "1-сап: a ачкычынан кийин = керек"},
+		{"= 1", "1-сап: ачкыч керек"},
+	}
+
+	for _, test := range tests {
+		_, err := parseTOML(test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%q: want=%q, got=%v", test.input, test.expected, err)
+		}
+	}
+}
+
+func TestWriteTOML(t *testing.T) {
+	for _, value := range []string{"", "сап", "\"\\\n\t\r\x01"} {
+		document, err := parseTOML("a = " + quoteTOML(value))
+		if err != nil || document["a"] != value {
+			t.Errorf("%q did not read back. got=%#v, %v", value, document["a"], err)
+		}
+	}
+
+	tests := map[string]string{"a-b_1": "a-b_1", "сап": `"сап"`, "a b": `"a b"`, "": `""`}
+	for key, expected := range tests {
+		if written := keyTOML(key); written != expected {
+			t.Errorf("keyTOML(%q): want=%s, got=%s", key, expected, written)
+		}
+	}
+}
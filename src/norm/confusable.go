@@ -0,0 +1,48 @@
+package norm
+
+import (
+	"strings"
+	"unicode"
+)
+
+// lookalikes maps letters onto the Cyrillic letter they are easily
+// mistaken for. It covers the Latin letters found on the same keys of
+// common keyboard layouts, and the Altai letters ӧ and ӱ that are often
+// typed instead of ө and ү.
+var lookalikes = map[rune]rune{
+	'a': 'а', 'c': 'с', 'e': 'е', 'o': 'о', 'p': 'р', 'x': 'х', 'y': 'у',
+	'k': 'к', 'h': 'һ', 'i': 'і', 'j': 'ј', 's': 'ѕ',
+	'A': 'А', 'B': 'В', 'C': 'С', 'E': 'Е', 'H': 'Н', 'K': 'К', 'M': 'М',
+	'O': 'О', 'P': 'Р', 'T': 'Т', 'X': 'Х', 'Y': 'У',
+	'ö': 'ө', 'ӧ': 'ө', 'ÿ': 'ү', 'ӱ': 'ү',
+}
+
+// Skeleton returns the NFC form of text with every lookalike letter
+// replaced, so that two identifiers that look the same have the same
+// skeleton.
+func Skeleton(text string) string {
+	return strings.Map(func(r rune) rune {
+		if lookalike, ok := lookalikes[r]; ok {
+			return lookalike
+		}
+		return r
+	}, NFC(text))
+}
+
+// MixesScripts reports whether text has both Latin and Cyrillic letters.
+func MixesScripts(text string) bool {
+	latin, cyrillic := false, false
+	for _, r := range text {
+		switch {
+		case 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z':
+			latin = true
+		case 'А' <= r && r <= 'я':
+			cyrillic = true
+		case r >= 0xC0:
+			latin = latin || unicode.Is(unicode.Latin, r)
+			cyrillic = cyrillic || unicode.Is(unicode.Cyrillic, r)
+		}
+	}
+
+	return latin && cyrillic
+}
@@ -0,0 +1,126 @@
+// Package norm normalizes identifiers so that text that looks the same is
+// compared the same: NFC composes letters typed as a base letter and a
+// combining mark, and Skeleton maps lookalike letters of other scripts
+// onto the Cyrillic ones the language is written in.
+package norm
+
+import (
+	"sort"
+	"unicode"
+)
+
+// composesBackward holds the second character of every composition, which
+// includes a few starters such as the Tamil length mark.
+var composesBackward = func() map[rune]bool {
+	runes := map[rune]bool{}
+	for pair := range compositions {
+		runes[pair[1]] = true
+	}
+	return runes
+}()
+
+// NFC returns text in Unicode Normalization Form C.
+func NFC(text string) string {
+	if IsNFC(text) {
+		return text
+	}
+
+	runes := []rune{}
+	for _, r := range text {
+		runes = decompose(runes, r)
+	}
+	reorder(runes)
+
+	return string(compose(runes))
+}
+
+// IsNFC reports whether NFC would return text unchanged. It errs on the
+// safe side: text with a mark in it is always normalized again.
+func IsNFC(text string) bool {
+	for _, r := range text {
+		// ASCII and the basic Cyrillic letters neither decompose nor
+		// combine, which covers most identifiers.
+		if r < 0xC0 || 'А' <= r && r <= 'я' {
+			continue
+		}
+		if decomposition, ok := decompositions[r]; ok {
+			// A precomposed letter is fine unless a mark follows it,
+			// or it is a composition exclusion.
+			if len(decomposition) != 2 || compositions[[2]rune(decomposition)] != r {
+				return false
+			}
+			continue
+		}
+		if combiningClasses[r] != 0 || composesBackward[r] || unicode.Is(unicode.Mn, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func decompose(runes []rune, r rune) []rune {
+	decomposition, ok := decompositions[r]
+	if !ok {
+		return append(runes, r)
+	}
+
+	for _, part := range decomposition {
+		runes = decompose(runes, part)
+	}
+
+	return runes
+}
+
+// reorder sorts every run of marks by combining class, keeping marks of
+// the same class in their order.
+func reorder(runes []rune) {
+	for start := 0; start < len(runes); {
+		if combiningClasses[runes[start]] == 0 {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(runes) && combiningClasses[runes[end]] != 0 {
+			end++
+		}
+
+		marks := runes[start:end]
+		sort.SliceStable(marks, func(i, j int) bool {
+			return combiningClasses[marks[i]] < combiningClasses[marks[j]]
+		})
+		start = end
+	}
+}
+
+// compose joins each starter with the marks that follow it, as long as
+// no mark of the same or a lower class sits between them.
+func compose(runes []rune) []rune {
+	composed := make([]rune, 0, len(runes))
+	starter := -1
+	// lastClass is the class of the last rune kept after the starter,
+	// -1 when there is none.
+	lastClass := -1
+
+	for _, r := range runes {
+		class := int(combiningClasses[r])
+		blocked := lastClass != -1 && (lastClass == 0 || lastClass >= class)
+
+		if starter >= 0 && !blocked {
+			if precomposed, ok := compositions[[2]rune{composed[starter], r}]; ok {
+				composed[starter] = precomposed
+				continue
+			}
+		}
+
+		composed = append(composed, r)
+		if class == 0 {
+			starter, lastClass = len(composed)-1, -1
+		} else {
+			lastClass = class
+		}
+	}
+
+	return composed
+}
@@ -0,0 +1,98 @@
+package norm
+
+import (
+	"testing"
+)
+
+func TestNFC(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"сакта", "сакта"},
+		// и and a combining breve are й.
+		{"ка\u0438\u0306тар", "кайтар"},
+		// е and a combining diaeresis are ё.
+		{"\u0435\u0308лка", "ёлка"},
+		{"\u0415\u0308лка", "Ёлка"},
+		// о and a combining diaeresis are the Altai ӧ, not ө.
+		{"о\u0308", "ӧ"},
+		// Marks are put in canonical order before composing: the
+		// dot below (class 220) comes before the circumflex (230).
+		{"e\u0302\u0323", "ệ"},
+		{"ê\u0323", "ệ"},
+		// The ogonek (202) composes past the dot above (230), which stays.
+		{"a\u0307\u0328", "\u0105\u0307"},
+		{"\u0301a", "\u0301a"},
+		{"x + 5", "x + 5"},
+	}
+
+	for _, tt := range tests {
+		if actual := NFC(tt.input); actual != tt.expected {
+			t.Errorf("NFC(%+q) wrong. want=%+q, got=%+q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestNFCTables(t *testing.T) {
+	// Every precomposed character that is not a composition exclusion is
+	// its own NFC form, and so is its decomposition.
+	for pair, r := range compositions {
+		if actual := NFC(string(r)); actual != string(r) {
+			t.Errorf("NFC(%+q) wrong. got=%+q", r, actual)
+		}
+		if actual := NFC(string(pair[:])); actual != string(r) {
+			t.Errorf("NFC(%+q) wrong. want=%+q, got=%+q", string(pair[:]), r, actual)
+		}
+	}
+
+	// Exclusions decompose and stay that way.
+	for r, decomposition := range decompositions {
+		if len(decomposition) == 2 && compositions[[2]rune(decomposition)] == r {
+			continue
+		}
+		if actual := NFC(string(r)); actual == string(r) {
+			t.Errorf("NFC(%+q) left the composition exclusion as it is", r)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		first  string
+		second string
+		same   bool
+	}{
+		{"сакта", "сaкта", true},
+		{"кайтар", "кaйтaр", true},
+		{"өлчөм", "ӧлчӧм", true},
+		{"Ойын", "Oйын", true},
+		{"сакта", "сактоо", false},
+		{"ү", "у", false},
+	}
+
+	for _, tt := range tests {
+		if same := Skeleton(tt.first) == Skeleton(tt.second); same != tt.same {
+			t.Errorf("Skeleton(%q) == Skeleton(%q) is %t, want %t", tt.first, tt.second, same, tt.same)
+		}
+	}
+}
+
+func TestMixesScripts(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"сакта", false},
+		{"sakta", false},
+		{"сaкта", true},
+		{"x_саны", true},
+		{"_", false},
+	}
+
+	for _, tt := range tests {
+		if actual := MixesScripts(tt.input); actual != tt.expected {
+			t.Errorf("MixesScripts(%q) wrong. want=%t, got=%t", tt.input, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package object
+
+// Environment binds names to values. Function calls get an environment
+// enclosed by the one the function was defined in.
+type Environment struct {
+	store map[string]Object
+	outer *Environment
+
+	// file and importer are set on the top level of a module.
+	file     string
+	importer Importer
+}
+
+// Importer returns the *Module holding the values the module at path
+// exports, or an *Error when it cannot be imported.
+type Importer func(path string) Object
+
+func NewEnvironment() *Environment {
+	return &Environment{store: map[string]Object{}}
+}
+
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	environment := NewEnvironment()
+	environment.outer = outer
+	return environment
+}
+
+// Get looks name up in this environment and then in the enclosing ones.
+func (environment *Environment) Get(name string) (Object, bool) {
+	value, ok := environment.store[name]
+	if !ok && environment.outer != nil {
+		return environment.outer.Get(name)
+	}
+
+	return value, ok
+}
+
+// Set binds name in this environment, shadowing any outer binding.
+func (environment *Environment) Set(name string, value Object) Object {
+	environment.store[name] = value
+	return value
+}
+
+// SetModule makes environment the top level of the module read from
+// file, whose import statements importer resolves.
+func (environment *Environment) SetModule(file string, importer Importer) {
+	environment.file = file
+	environment.importer = importer
+}
+
+// File returns the file of the module environment belongs to, or "" when
+// the program was not loaded as a module.
+func (environment *Environment) File() string {
+	for ; environment != nil; environment = environment.outer {
+		if environment.file != "" {
+			return environment.file
+		}
+	}
+
+	return ""
+}
+
+// Importer returns the importer of the module environment belongs to,
+// or nil.
+func (environment *Environment) Importer() Importer {
+	for ; environment != nil; environment = environment.outer {
+		if environment.importer != nil {
+			return environment.importer
+		}
+	}
+
+	return nil
+}
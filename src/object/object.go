@@ -0,0 +1,310 @@
+// Package object defines the values alipp programs compute with.
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+)
+
+type ObjectType string
+
+const (
+	INTEGER_OBJ      = "БҮТҮН_САН"
+	FLOAT_OBJ        = "БӨЛЧӨК_САН"
+	BOOLEAN_OBJ      = "ЛОГИКАЛЫК"
+	STRING_OBJ       = "САП"
+	NULL_OBJ         = "БОШ"
+	RETURN_VALUE_OBJ = "КАЙТАРУУ_МААНИСИ"
+	ERROR_OBJ        = "КАТА"
+	FUNCTION_OBJ     = "ФУНКЦИЯ"
+	BUILTIN_OBJ      = "КУРУЛГАН_ФУНКЦИЯ"
+	ARRAY_OBJ        = "ТИЗМЕ"
+	HASH_OBJ         = "СӨЗДҮК"
+	MODULE_OBJ       = "МОДУЛЬ"
+)
+
+type Object interface {
+	Type() ObjectType
+	Inspect() string
+}
+
+// There is only one null and one value of each boolean, so they can be
+// compared by pointer.
+var (
+	NULL  = &Null{}
+	TRUE  = &Boolean{Value: true}
+	FALSE = &Boolean{Value: false}
+)
+
+// NativeBoolean returns TRUE or FALSE.
+func NativeBoolean(value bool) *Boolean {
+	if value {
+		return TRUE
+	}
+	return FALSE
+}
+
+// Integer has no upper limit. Values that do not fit an int64 are kept
+// in Big, and Value is then unused.
+type Integer struct {
+	Value int64
+	Big   *big.Int
+}
+
+// NewInteger returns value as an Integer, in Value when it fits.
+func NewInteger(value *big.Int) *Integer {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+	return &Integer{Big: value}
+}
+
+// BigValue returns the value as a big.Int the caller may modify.
+func (integer *Integer) BigValue() *big.Int {
+	if integer.Big != nil {
+		return new(big.Int).Set(integer.Big)
+	}
+	return big.NewInt(integer.Value)
+}
+
+// Float64 returns the nearest float to the value.
+func (integer *Integer) Float64() float64 {
+	if integer.Big != nil {
+		value, _ := new(big.Float).SetInt(integer.Big).Float64()
+		return value
+	}
+	return float64(integer.Value)
+}
+
+func (integer *Integer) Type() ObjectType { return INTEGER_OBJ }
+func (integer *Integer) Inspect() string {
+	if integer.Big != nil {
+		return integer.Big.String()
+	}
+	return fmt.Sprintf("%d", integer.Value)
+}
+
+type Float struct {
+	Value float64
+}
+
+func (float *Float) Type() ObjectType { return FLOAT_OBJ }
+
+// Inspect writes floats the way JavaScript does, so both backends print
+// the same: without an exponent from 1e-6 up to 1e21.
+func (float *Float) Inspect() string {
+	switch {
+	case math.IsNaN(float.Value):
+		return "NaN"
+	case math.IsInf(float.Value, 1):
+		return "Infinity"
+	case math.IsInf(float.Value, -1):
+		return "-Infinity"
+	}
+
+	if float.Value == 0 {
+		// JavaScript prints -0 as 0 too.
+		return "0"
+	}
+	if magnitude := math.Abs(float.Value); magnitude >= 1e-6 && magnitude < 1e21 {
+		return strconv.FormatFloat(float.Value, 'f', -1, 64)
+	}
+	// Go writes at least two exponent digits, JavaScript as few as needed.
+	text := strconv.FormatFloat(float.Value, 'g', -1, 64)
+	return strings.NewReplacer("e+0", "e+", "e-0", "e-").Replace(text)
+}
+
+type Boolean struct {
+	Value bool
+}
+
+func (boolean *Boolean) Type() ObjectType { return BOOLEAN_OBJ }
+func (boolean *Boolean) Inspect() string {
+	if boolean.Value {
+		return "туура"
+	}
+	return "ката"
+}
+
+type String struct {
+	Value string
+}
+
+func (str *String) Type() ObjectType { return STRING_OBJ }
+func (str *String) Inspect() string  { return str.Value }
+
+type Null struct{}
+
+func (null *Null) Type() ObjectType { return NULL_OBJ }
+func (null *Null) Inspect() string  { return "бош" }
+
+// ReturnValue wraps the value of a кайтар statement while it unwinds to
+// the enclosing function call.
+type ReturnValue struct {
+	Value Object
+}
+
+func (returnValue *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
+func (returnValue *ReturnValue) Inspect() string  { return returnValue.Value.Inspect() }
+
+// Error is a runtime error. It stops the evaluation of the program up
+// to an аракет statement. Span is where it happened, and Stack lists the
+// calls it left on the way out, innermost first. Value is what an ыргыт
+// statement threw, and nil for the errors of the runtime.
+type Error struct {
+	Message string
+	Span    ast.Span
+	// File is the module Span is in, "" for a program not loaded as one.
+	File  string
+	Stack []Frame
+	Value Object
+}
+
+// Frame is a call of the function named Function made at Position of
+// File. The name is empty for a function never bound with сакта.
+type Frame struct {
+	Function string
+	File     string
+	Position ast.Position
+}
+
+func (err *Error) Type() ObjectType { return ERROR_OBJ }
+func (err *Error) Inspect() string  { return "КАТА: " + err.Message }
+
+// Function is a function literal closed over Env. Name is the name a
+// сакта statement first bound it to, for stack traces.
+type Function struct {
+	Name       string
+	Parameters []ast.Pattern
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (function *Function) Type() ObjectType { return FUNCTION_OBJ }
+func (function *Function) Inspect() string {
+	params := []string{}
+	for _, p := range function.Parameters {
+		params = append(params, p.String())
+	}
+
+	return "функция(" + strings.Join(params, ", ") + ") {\n" + function.Body.String() + "\n}"
+}
+
+// BuiltinFunction implements a function in Go. It returns an *Error for
+// wrong arguments.
+type BuiltinFunction func(args ...Object) Object
+
+type Builtin struct {
+	Name     string
+	Function BuiltinFunction
+}
+
+func (builtin *Builtin) Type() ObjectType { return BUILTIN_OBJ }
+func (builtin *Builtin) Inspect() string  { return "курулган функция " + builtin.Name }
+
+type Array struct {
+	Elements []Object
+}
+
+func (array *Array) Type() ObjectType { return ARRAY_OBJ }
+func (array *Array) Inspect() string {
+	elements := []string{}
+	for _, element := range array.Elements {
+		elements = append(elements, element.Inspect())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// HashKey identifies a key of a hash by its type and value, so that
+// equal strings or integers find the same pair.
+type HashKey struct {
+	Type  ObjectType
+	Value string
+}
+
+// Hashable is implemented by the values that can be keys of a hash.
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func (integer *Integer) HashKey() HashKey {
+	return HashKey{Type: INTEGER_OBJ, Value: integer.Inspect()}
+}
+func (boolean *Boolean) HashKey() HashKey {
+	return HashKey{Type: BOOLEAN_OBJ, Value: boolean.Inspect()}
+}
+func (str *String) HashKey() HashKey { return HashKey{Type: STRING_OBJ, Value: str.Value} }
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash maps keys to values and remembers the order in which the keys
+// were first added.
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	Keys  []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: map[HashKey]HashPair{}}
+}
+
+// Set binds key to value, keeping the key's place if it is already
+// bound.
+func (hash *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if _, ok := hash.Pairs[hashKey]; !ok {
+		hash.Keys = append(hash.Keys, hashKey)
+	}
+	hash.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+func (hash *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := hash.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+func (hash *Hash) Type() ObjectType { return HASH_OBJ }
+func (hash *Hash) Inspect() string {
+	pairs := []string{}
+	for _, key := range hash.Keys {
+		pair := hash.Pairs[key]
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// Module is a named set of members read with the dot operator, such as
+// сап.узундук, or by indexing it with their names.
+type Module struct {
+	Name    string
+	Members map[string]Object
+}
+
+func (module *Module) Type() ObjectType { return MODULE_OBJ }
+func (module *Module) Inspect() string {
+	names := make([]string, 0, len(module.Members))
+	for name := range module.Members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out bytes.Buffer
+	out.WriteString("модуль " + module.Name + " {")
+	out.WriteString(strings.Join(names, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+// Edit replaces the bytes Start up to End of a source with Text.
+type Edit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// Tree is a parsed source that can be updated incrementally. Editors
+// re-parse on every keystroke, so instead of starting over Reparse only
+// parses the top-level statements an edit can have changed and reuses
+// the rest of the previous tree.
+type Tree struct {
+	Source  string
+	Program *ast.Program
+
+	// spans runs parallel to Program.Statements.
+	spans []span
+	// dictionary spells the keywords of a source without a "тил"
+	// comment. When it is nil they are Kyrgyz.
+	dictionary *token.Dictionary
+}
+
+type span struct {
+	first    token.Token
+	last     token.Token
+	errors   []Error
+	warnings []Error
+}
+
+// start is the position lexing restarts from when nothing can be reused.
+var start = token.Token{Line: 1, Column: 1}
+
+func ParseTree(source string) *Tree {
+	return ParseTreeWithDictionary(source, nil)
+}
+
+// ParseTreeWithDictionary is ParseTree for a source whose keywords are
+// spelled in dictionary unless its "тил" comment says otherwise.
+func ParseTreeWithDictionary(source string, dictionary *token.Dictionary) *Tree {
+	tree := &Tree{Source: source, Program: &ast.Program{Statements: []ast.Statement{}}, dictionary: dictionary}
+	tree.parseFrom(tree.lexer(start), nil)
+
+	return tree
+}
+
+// Dictionary returns the keyword dictionary the source is parsed with.
+func (tree *Tree) Dictionary() *token.Dictionary {
+	return tree.lexer(start).Dictionary()
+}
+
+// lexer returns a lexer of the source starting at the position of from.
+func (tree *Tree) lexer(from token.Token) *lexer.Lexer {
+	lexerInstance := lexer.NewAt(tree.Source, from.Offset, from.Line, from.Column)
+	if tree.dictionary != nil {
+		lexerInstance.UseDictionary(tree.dictionary)
+	}
+
+	return lexerInstance
+}
+
+// Errors returns the syntax errors of every statement in source order.
+func (tree *Tree) Errors() []Error {
+	errors := []Error{}
+	for _, span := range tree.spans {
+		errors = append(errors, span.errors...)
+	}
+
+	return errors
+}
+
+// Warnings returns the warnings of every statement in source order.
+func (tree *Tree) Warnings() []Error {
+	warnings := []Error{}
+	for _, span := range tree.spans {
+		warnings = append(warnings, span.warnings...)
+	}
+
+	return warnings
+}
+
+// parseFrom parses statements until the end of input, or until resume
+// reports that the statement starting at the current token can be taken
+// from the previous tree.
+func (tree *Tree) parseFrom(lexerInstance *lexer.Lexer, resume func(token.Token) bool) {
+	parser := NewParser(lexerInstance)
+
+	// Errors found before the first statement, such as an unknown
+	// dictionary, belong to it.
+	errorCount, warningCount := 0, 0
+	for !parser.currentTokenIs(token.EOF) && !parser.gaveUp {
+		if resume != nil && resume(parser.currentToken) {
+			return
+		}
+
+		first := parser.currentToken
+		statement, _ := parser.recoverStatement(first, parser.parseStatement())
+
+		tree.Program.Statements = append(tree.Program.Statements, statement)
+		tree.spans = append(tree.spans, span{
+			first:    first,
+			last:     parser.currentToken,
+			errors:   parser.errors[errorCount:len(parser.errors):len(parser.errors)],
+			warnings: parser.warnings[warningCount:len(parser.warnings):len(parser.warnings)],
+		})
+		errorCount, warningCount = len(parser.errors), len(parser.warnings)
+
+		parser.nextToken()
+	}
+}
+
+// Reparse applies edit and returns the updated tree. Statements after
+// the edit are reused with their positions updated in place, so the
+// receiver must not be used afterwards.
+func (tree *Tree) Reparse(edit Edit) *Tree {
+	source := tree.Source[:edit.Start] + edit.Text + tree.Source[edit.End:]
+	if pragma(source) != pragma(tree.Source) {
+		// Every keyword may be spelled differently now.
+		return ParseTreeWithDictionary(source, tree.dictionary)
+	}
+	updated := &Tree{Source: source, Program: &ast.Program{}, dictionary: tree.dictionary}
+
+	// A statement depends on its own tokens and on the token after it,
+	// which decided where it ended. The statement right before the first
+	// one touching the edit is therefore parsed again as well.
+	affected := sort.Search(len(tree.spans), func(i int) bool {
+		last := tree.spans[i].last
+		return last.Offset+len(last.Literal) >= edit.Start
+	})
+	kept := max(affected-1, 0)
+	restart := start
+	if affected > 0 {
+		restart = tree.spans[kept].first
+	}
+
+	updated.Program.Statements = append(make([]ast.Statement, 0, len(tree.spans)), tree.Program.Statements[:kept]...)
+	updated.spans = append(make([]span, 0, len(tree.spans)), tree.spans[:kept]...)
+
+	editEnd := edit.Start + len(edit.Text)
+	delta := editEnd - edit.End
+	reused := -1
+
+	updated.parseFrom(updated.lexer(restart), func(next token.Token) bool {
+		if next.Offset < editEnd {
+			return false
+		}
+
+		// Past the edit the text is unchanged, so a statement that began
+		// at the same place before the edit parses the same way again.
+		old := next.Offset - delta
+		i := sort.Search(len(tree.spans), func(i int) bool { return tree.spans[i].first.Offset >= old })
+		if i < len(tree.spans) && tree.spans[i].first.Offset == old {
+			reused = i
+			return true
+		}
+
+		return false
+	})
+
+	if reused < 0 {
+		return updated
+	}
+
+	oldLine, oldColumn := advance(tree.Source, restart, edit.End)
+	newLine, newColumn := advance(source, restart, editEnd)
+	shift := func(tok *token.Token) {
+		// A closing token missing after a syntax error stays unset.
+		if tok.Type == "" {
+			return
+		}
+		if tok.Line == oldLine {
+			tok.Column += newColumn - oldColumn
+		}
+		tok.Line += newLine - oldLine
+		tok.Offset += delta
+	}
+
+	for i := reused; i < len(tree.spans); i++ {
+		ast.Inspect(tree.Program.Statements[i], func(node ast.Node) bool {
+			ast.Tokens(node, shift)
+			return true
+		})
+
+		span := tree.spans[i]
+		shift(&span.first)
+		shift(&span.last)
+		for j := range span.errors {
+			shift(&span.errors[j].Token)
+		}
+		for j := range span.warnings {
+			shift(&span.warnings[j].Token)
+		}
+
+		updated.Program.Statements = append(updated.Program.Statements, tree.Program.Statements[i])
+		updated.spans = append(updated.spans, span)
+	}
+
+	return updated
+}
+
+// advance returns the line and column of the byte offset in source,
+// counting from the known position from.
+func advance(source string, from token.Token, offset int) (int, int) {
+	line, column := from.Line, from.Column
+	for _, r := range source[from.Offset:offset] {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return line, column
+}
+
+// pragma returns the dictionary name given by the "тил" comment of
+// source, if any.
+func pragma(source string) string {
+	name, _ := lexer.NewAt(source, 0, 1, 1).Pragma()
+	return name.Literal
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+const incrementalInput = `сакта кош = функ(a, b) {
+  a + b;
+};
+сакта он = кош(3, 7);
+эгер (он > 5) { кайтар туура; } же { кайтар ката; }
+сакта ж = -он * 2;
+`
+
+func treeTokens(program *ast.Program) []token.Token {
+	tokens := []token.Token{}
+	ast.Inspect(program, func(node ast.Node) bool {
+		ast.Tokens(node, func(tok *token.Token) { tokens = append(tokens, *tok) })
+		return true
+	})
+
+	return tokens
+}
+
+func checkSameTree(t *testing.T, description string, got *Tree, want *Tree) {
+	t.Helper()
+
+	if got.Program.String() != want.Program.String() {
+		t.Fatalf("%s: program differs.\nwant=%q\ngot= %q", description, want.Program.String(), got.Program.String())
+	}
+
+	wantTokens, gotTokens := treeTokens(want.Program), treeTokens(got.Program)
+	if fmt.Sprint(gotTokens) != fmt.Sprint(wantTokens) {
+		t.Fatalf("%s: token positions differ.\nwant=%v\ngot= %v", description, wantTokens, gotTokens)
+	}
+
+	if fmt.Sprint(got.Errors()) != fmt.Sprint(want.Errors()) {
+		t.Fatalf("%s: errors differ.\nwant=%v\ngot= %v", description, want.Errors(), got.Errors())
+	}
+
+	if fmt.Sprint(got.Warnings()) != fmt.Sprint(want.Warnings()) {
+		t.Fatalf("%s: warnings differ.\nwant=%v\ngot= %v", description, want.Warnings(), got.Warnings())
+	}
+}
+
+func TestReparse(t *testing.T) {
+	tests := []struct {
+		description string
+		old         string
+		new         string
+	}{
+		{"change a number", "3, 7", "3, 70"},
+		{"rename a binding", "сакта он =", "сакта онбир ="},
+		{"break a statement", "сакта ж =", "сакта ="},
+		{"join lines", "};\nсакта он", "}; сакта он"},
+		{"split a line", "{ кайтар туура; }", "{\nкайтар\nтуура; }"},
+		{"extend the previous statement", "};\n", "} + 1;\n"},
+		{"append at the end", "* 2;\n", "* 2;\nж;\n"},
+		{"insert at the start", "сакта кош", "сакта а = 1; сакта кош"},
+	}
+
+	for _, tt := range tests {
+		start := strings.Index(incrementalInput, tt.old)
+		edit := Edit{Start: start, End: start + len(tt.old), Text: tt.new}
+
+		got := ParseTree(incrementalInput).Reparse(edit)
+		want := ParseTree(strings.Replace(incrementalInput, tt.old, tt.new, 1))
+
+		checkSameTree(t, tt.description, got, want)
+	}
+}
+
+func TestReparsePragma(t *testing.T) {
+	input := "// тил: kk\nсақта x = 1;\nсакта y = 2;\nқайтар x;\n"
+
+	tests := []struct {
+		description string
+		old         string
+		new         string
+	}{
+		{"switch the dictionary", "kk", "ky"},
+		{"break the dictionary", "kk", "xx"},
+		{"drop the comment", "// тил: kk\n", ""},
+		{"comment out the first statement", "сақта x", "// сақта x"},
+		{"edit after the comment", "2;", "3;"},
+		{"edit the last statement", "қайтар x", "қайтар y"},
+	}
+
+	for _, tt := range tests {
+		start := strings.Index(input, tt.old)
+		edit := Edit{Start: start, End: start + len(tt.old), Text: tt.new}
+
+		got := ParseTree(input).Reparse(edit)
+		want := ParseTree(strings.Replace(input, tt.old, tt.new, 1))
+
+		checkSameTree(t, tt.description, got, want)
+	}
+}
+
+func TestReparseRandomEdits(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	snippets := []string{"", " ", "\n", ";", "}", "{", "(", "сакта ", "x", "+ 1", "функ(a) { a }", "эгер", "ө", "// ", "сaкта", "y"}
+
+	tree := ParseTree(incrementalInput)
+	for i := 0; i < 500; i++ {
+		runes := []rune(tree.Source)
+		from := random.Intn(len(runes) + 1)
+		to := min(from+random.Intn(4), len(runes))
+		start := len(string(runes[:from]))
+		end := len(string(runes[:to]))
+		edit := Edit{Start: start, End: end, Text: snippets[random.Intn(len(snippets))]}
+
+		description := fmt.Sprintf("edit %d %+v of %q", i, edit, tree.Source)
+		tree = tree.Reparse(edit)
+		checkSameTree(t, description, tree, ParseTree(tree.Source))
+	}
+}
+
+// generate returns a valid program of the given number of lines.
+func generate(lines int) string {
+	name := func(i int) string { return fmt.Sprintf("ф%d", i) }
+
+	var out strings.Builder
+	for i := 0; i < lines/5; i++ {
+		fmt.Fprintf(&out, "сакта %s = функ(a, b) {\n  сакта c = a * %d + b;\n  эгер (c > 10) { кайтар c; } же { кайтар -c; }\n};\n%s(%d, 2);\n", name(i), i, name(i), i)
+	}
+
+	return out.String()
+}
+
+func TestGenerate(t *testing.T) {
+	source := generate(1000)
+
+	tree := ParseTree(source)
+	if len(tree.Errors()) != 0 {
+		t.Fatalf("generated program has errors: %v", tree.Errors()[0])
+	}
+
+	if lines := strings.Count(source, "\n"); lines != 1000 {
+		t.Errorf("generated program has %d lines, want 1000", lines)
+	}
+}
+
+func BenchmarkParseTree(b *testing.B) {
+	source := generate(50000)
+	middle := strings.Index(source[len(source)/2:], "a * ") + len(source)/2 + len("a * ")
+	edited := source[:middle] + "1" + source[middle:]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParseTree(edited)
+	}
+}
+
+func BenchmarkReparse(b *testing.B) {
+	source := generate(50000)
+	middle := strings.Index(source[len(source)/2:], "a * ") + len(source)/2 + len("a * ")
+	tree := ParseTree(source)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Typing a digit and deleting it again keeps the tree the same size.
+		if i%2 == 0 {
+			tree = tree.Reparse(Edit{Start: middle, End: middle, Text: "1"})
+		} else {
+			tree = tree.Reparse(Edit{Start: middle, End: middle + 1})
+		}
+	}
+}
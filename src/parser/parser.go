@@ -1,11 +1,16 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/asanoviskhak/alipp/src/ast"
 	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/norm"
 	"github.com/asanoviskhak/alipp/src/token"
 )
 
@@ -20,7 +25,22 @@ type Parser struct {
 	currentToken token.Token
 	peekToken    token.Token
 
-	errors []string
+	errors   []Error
+	warnings []Error
+	// keywordSkeletons maps the skeleton of every keyword to its
+	// spelling, for spotting identifiers that only look like one.
+	keywordSkeletons map[string]string
+
+	// panicking is set when a syntax error is reported and cleared once
+	// the parser has skipped to a point where it can resume. Errors are
+	// not reported while panicking, which keeps one mistake from
+	// producing a cascade of follow-up errors.
+	panicking      bool
+	lastErrorToken token.Token
+	gaveUp         bool
+
+	// depth counts the block statements being parsed.
+	depth int
 
 	prefixParseFunctions map[token.TokenType]prefixParseFunction
 	infixParseFunctions  map[token.TokenType]infixParseFunction
@@ -41,6 +61,7 @@ const (
 	PRODUCT     // *
 	PREFIX      // -X or !X
 	CALL        // myFunction(X)
+	INDEX       // array[index] or module.member
 )
 
 var precedences = map[token.TokenType]int{
@@ -52,20 +73,49 @@ var precedences = map[token.TokenType]int{
 	token.MINUS:    SUM,
 	token.SLASH:    PRODUCT,
 	token.ASTERISK: PRODUCT,
+	token.LPAREN:   CALL,
+	token.LBRACKET: INDEX,
+	token.DOT:      INDEX,
+}
+
+// MaxErrors is the number of syntax errors after which the parser stops.
+const MaxErrors = 25
+
+// Error is a syntax error together with the token it was reported at.
+type Error struct {
+	Message string
+	Token   token.Token
 }
 
 func NewParser(lexerInstance *lexer.Lexer) *Parser {
-	parser := &Parser{lexerInstance: lexerInstance, errors: []string{}}
+	parser := &Parser{lexerInstance: lexerInstance, errors: []Error{}}
+	resumed := lexerInstance.Started()
 
 	parser.nextToken()
 	parser.nextToken()
 
+	// A parser that starts at a later statement leaves the "тил" comment
+	// to whoever parsed the first one.
+	if pragma, ok := lexerInstance.Pragma(); ok && pragma.Type == token.ILLEGAL && !resumed {
+		parser.errors = append(parser.errors, Error{Message: fmt.Sprintf("unknown keyword dictionary %q", pragma.Literal), Token: pragma})
+	}
+
 	parser.prefixParseFunctions = make(map[token.TokenType]prefixParseFunction)
 	parser.registerPrefix(token.IDENT, parser.parseIdentifier)
 	parser.registerPrefix(token.INT, parser.parseIntegerLiteral)
+	parser.registerPrefix(token.FLOAT, parser.parseFloatLiteral)
+	parser.registerPrefix(token.STRING, parser.parseStringLiteral)
 
 	parser.registerPrefix(token.EXCLAMATION, parser.parsePrefixExpression)
 	parser.registerPrefix(token.MINUS, parser.parsePrefixExpression)
+	parser.registerPrefix(token.TRUE, parser.parseBoolean)
+	parser.registerPrefix(token.FALSE, parser.parseBoolean)
+	parser.registerPrefix(token.LPAREN, parser.parseGroupedExpression)
+	parser.registerPrefix(token.IF, parser.parseIfExpression)
+	parser.registerPrefix(token.MATCH, parser.parseMatchExpression)
+	parser.registerPrefix(token.FUNCTION, parser.parseFunctionLiteral)
+	parser.registerPrefix(token.LBRACKET, parser.parseArrayLiteral)
+	parser.registerPrefix(token.LBRACE, parser.parseHashLiteral)
 
 	parser.infixParseFunctions = make(map[token.TokenType]infixParseFunction)
 	parser.registerInfix(token.PLUS, parser.parseInfixExpression)
@@ -76,18 +126,53 @@ func NewParser(lexerInstance *lexer.Lexer) *Parser {
 	parser.registerInfix(token.NOT_EQ, parser.parseInfixExpression)
 	parser.registerInfix(token.LT, parser.parseInfixExpression)
 	parser.registerInfix(token.GT, parser.parseInfixExpression)
+	parser.registerInfix(token.LPAREN, parser.parseCallExpression)
+	parser.registerInfix(token.LBRACKET, parser.parseIndexExpression)
+	parser.registerInfix(token.DOT, parser.parseMemberExpression)
 
 	return parser
 }
 
 func (parser *Parser) Errors() []string {
+	messages := make([]string, 0, len(parser.errors))
+	for _, parserError := range parser.errors {
+		messages = append(messages, parserError.Message)
+	}
+
+	return messages
+}
+
+// Warnings returns identifiers that parse fine but are probably not
+// what the author meant.
+func (parser *Parser) Warnings() []Error {
+	return parser.warnings
+}
+
+// ErrorList returns the syntax errors with their positions, for tooling
+// that needs to point at the offending token.
+func (parser *Parser) ErrorList() []Error {
 	return parser.errors
 }
 
+func (parser *Parser) addError(tok token.Token, message string) {
+	if parser.panicking || parser.gaveUp {
+		return
+	}
+
+	parser.panicking = true
+	parser.lastErrorToken = tok
+	parser.errors = append(parser.errors, Error{Message: message, Token: tok})
+
+	if len(parser.errors) == MaxErrors {
+		parser.gaveUp = true
+		parser.errors = append(parser.errors, Error{Message: "too many errors", Token: tok})
+	}
+}
+
 func (parser *Parser) peekError(tokenType token.TokenType) {
 	msg := fmt.Sprintf("expected next token to be %s, got %s instead",
 		tokenType, parser.peekToken.Type)
-	parser.errors = append(parser.errors, msg)
+	parser.addError(parser.peekToken, msg)
 }
 
 func (parser *Parser) peekPrecedence() int {
@@ -109,14 +194,38 @@ func (parser *Parser) currentPrecedence() int {
 func (parser *Parser) nextToken() {
 	parser.currentToken = parser.peekToken
 	parser.peekToken = parser.lexerInstance.NextToken()
+
+	if parser.currentToken.Type == token.IDENT {
+		parser.checkIdentifier(parser.currentToken)
+	}
+}
+
+// checkIdentifier warns about an identifier that looks like a keyword,
+// such as сакта typed with a Latin a, or that mixes scripts and so may
+// look like another identifier.
+func (parser *Parser) checkIdentifier(tok token.Token) {
+	if parser.keywordSkeletons == nil {
+		// The dictionary is settled once the first token is read.
+		parser.keywordSkeletons = map[string]string{}
+		for _, keyword := range parser.lexerInstance.Dictionary().Keywords() {
+			parser.keywordSkeletons[norm.Skeleton(keyword)] = keyword
+		}
+	}
+
+	if keyword, ok := parser.keywordSkeletons[norm.Skeleton(tok.Literal)]; ok {
+		parser.warnings = append(parser.warnings, Error{Message: fmt.Sprintf("identifier %q looks like the keyword %q", tok.Literal, keyword), Token: tok})
+	} else if norm.MixesScripts(tok.Literal) {
+		parser.warnings = append(parser.warnings, Error{Message: fmt.Sprintf("identifier %q mixes Latin and Cyrillic letters", tok.Literal), Token: tok})
+	}
 }
 
 func (parser *Parser) ParseProgram() *ast.Program {
 	program := &ast.Program{}
 	program.Statements = []ast.Statement{}
 
-	for !parser.currentTokenIs(token.EOF) {
-		statement := parser.parseStatement()
+	for !parser.currentTokenIs(token.EOF) && !parser.gaveUp {
+		start := parser.currentToken
+		statement, _ := parser.recoverStatement(start, parser.parseStatement())
 
 		if statement != nil {
 			program.Statements = append(program.Statements, statement)
@@ -127,12 +236,89 @@ func (parser *Parser) ParseProgram() *ast.Program {
 	return program
 }
 
+// recoverStatement is called after each statement. If the statement
+// reported a syntax error, the parser skips ahead to a synchronisation
+// point: a semicolon, a closing brace or the keyword starting the next
+// statement. A statement that could not be parsed at all is replaced by
+// an ast.BadStatement covering the skipped tokens. The second result
+// reports that the error was at the closing brace of the enclosing
+// block, which the caller must not skip.
+func (parser *Parser) recoverStatement(start token.Token, statement ast.Statement) (ast.Statement, bool) {
+	if !parser.panicking {
+		return statement, false
+	}
+
+	atBlockEnd := parser.synchronize()
+	parser.panicking = false
+
+	if statement == nil {
+		statement = &ast.BadStatement{Token: start, End: parser.currentToken}
+	}
+
+	return statement, atBlockEnd
+}
+
+func (parser *Parser) synchronize() bool {
+	for !parser.currentTokenIs(token.SEMICOLON) && !parser.currentTokenIs(token.EOF) {
+		if parser.depth > 0 && parser.currentTokenIs(token.RBRACE) && parser.currentToken == parser.lastErrorToken {
+			return true
+		}
+
+		// Only a block can be closed by a brace, at the top level it is
+		// skipped along with the rest of the bad statement.
+		closesBlock := parser.depth > 0 && parser.peekTokenIs(token.RBRACE)
+		if closesBlock || parser.peekTokenIs(token.EOF) || startsStatement(parser.peekToken.Type) {
+			return false
+		}
+
+		parser.nextToken()
+	}
+
+	return false
+}
+
+func startsStatement(tokenType token.TokenType) bool {
+	switch tokenType {
+	case token.LET, token.RETURN, token.IF, token.MATCH, token.TRY, token.THROW, token.IMPORT, token.EXPORT, token.EXTERN:
+		return true
+	}
+
+	return false
+}
+
 func (parser *Parser) parseStatement() ast.Statement {
+	// The nil checks keep a failed parse from turning into a non-nil
+	// interface that wraps a nil pointer.
 	switch parser.currentToken.Type {
 	case token.LET:
-		return parser.parseLetStatement()
+		if statement := parser.parseLetStatement(); statement != nil {
+			return statement
+		}
+		return nil
 	case token.RETURN:
 		return parser.parseReturnStatement()
+	case token.TRY:
+		if statement := parser.parseTryStatement(); statement != nil {
+			return statement
+		}
+		return nil
+	case token.THROW:
+		return parser.parseThrowStatement()
+	case token.IMPORT:
+		if statement := parser.parseImportStatement(); statement != nil {
+			return statement
+		}
+		return nil
+	case token.EXPORT:
+		if statement := parser.parseExportStatement(); statement != nil {
+			return statement
+		}
+		return nil
+	case token.EXTERN:
+		if statement := parser.parseExternStatement(); statement != nil {
+			return statement
+		}
+		return nil
 	default:
 		return parser.parseExpressionStatement()
 	}
@@ -141,19 +327,35 @@ func (parser *Parser) parseStatement() ast.Statement {
 func (parser *Parser) parseLetStatement() *ast.LetStatement {
 	statement := &ast.LetStatement{Token: parser.currentToken}
 
-	if !parser.expectPeek(token.IDENT) {
+	if parser.peekTokenIs(token.LBRACKET) || parser.peekTokenIs(token.LBRACE) {
+		parser.nextToken()
+		if statement.Pattern = parser.parsePattern(true); statement.Pattern == nil {
+			return nil
+		}
+		parser.checkBindings(statement.Pattern)
+	} else if !parser.expectPeek(token.IDENT) {
 		return nil
+	} else {
+		statement.Name = parser.identifier()
 	}
 
-	statement.Name = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+	if parser.peekTokenIs(token.COLON) {
+		parser.nextToken()
+		parser.nextToken()
+		if statement.Type = parser.parseType(); statement.Type == nil {
+			return nil
+		}
+	}
 
 	if !parser.expectPeek(token.ASSIGN) {
 		return nil
 	}
 
-	// TODO: Skipping the expression until we encounter semicolon
+	parser.nextToken()
+
+	statement.Value = parser.parseExpression(LOWEST)
 
-	for !parser.currentTokenIs(token.SEMICOLON) {
+	if parser.peekTokenIs(token.SEMICOLON) {
 		parser.nextToken()
 	}
 
@@ -161,7 +363,13 @@ func (parser *Parser) parseLetStatement() *ast.LetStatement {
 }
 
 func (parser *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+	return parser.identifier()
+}
+
+// identifier names the current token by the NFC form of its literal, so
+// names typed with and without combining marks are the same name.
+func (parser *Parser) identifier() *ast.Identifier {
+	return &ast.Identifier{Token: parser.currentToken, Value: norm.NFC(parser.currentToken.Literal)}
 }
 
 func (parser *Parser) currentTokenIs(tokenType token.TokenType) bool {
@@ -186,14 +394,224 @@ func (parser *Parser) parseReturnStatement() *ast.ReturnStatement {
 	statement := &ast.ReturnStatement{Token: parser.currentToken}
 	parser.nextToken()
 
-	// TODO: skipping the expression until semicolon is encountered
-	for !parser.currentTokenIs(token.SEMICOLON) {
+	statement.ReturnValue = parser.parseExpression(LOWEST)
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+	}
+
+	return statement
+}
+
+func (parser *Parser) parseTryStatement() *ast.TryStatement {
+	statement := &ast.TryStatement{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.LBRACE) {
+		return nil
+	}
+	statement.Block = parser.parseBlockStatement()
+
+	if parser.peekTokenIs(token.CATCH) {
 		parser.nextToken()
+
+		if parser.peekTokenIs(token.LPAREN) {
+			parser.nextToken()
+			if !parser.expectPeek(token.IDENT) {
+				return nil
+			}
+			statement.Parameter = parser.identifier()
+			if !parser.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+
+		if !parser.expectPeek(token.LBRACE) {
+			return nil
+		}
+		statement.Catch = parser.parseBlockStatement()
+	}
+
+	if parser.peekTokenIs(token.FINALLY) {
+		parser.nextToken()
+		if !parser.expectPeek(token.LBRACE) {
+			return nil
+		}
+		statement.Finally = parser.parseBlockStatement()
+	}
+
+	if statement.Catch == nil && statement.Finally == nil {
+		parser.addError(parser.peekToken, fmt.Sprintf("expected next token to be %s or %s, got %s instead",
+			token.CATCH, token.FINALLY, parser.peekToken.Type))
+		return nil
 	}
 
 	return statement
 }
 
+func (parser *Parser) parseThrowStatement() *ast.ThrowStatement {
+	statement := &ast.ThrowStatement{Token: parser.currentToken}
+	parser.nextToken()
+
+	statement.Value = parser.parseExpression(LOWEST)
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+	}
+
+	return statement
+}
+
+// parseImportStatement parses a list of names in braces and the path of
+// the file they come from. Like exports, imports belong to the top level
+// of a file.
+func (parser *Parser) parseImportStatement() *ast.ImportStatement {
+	statement := &ast.ImportStatement{Token: parser.currentToken}
+	if !parser.atTopLevel() {
+		return nil
+	}
+
+	if !parser.expectPeek(token.LBRACE) {
+		return nil
+	}
+	statement.Names = []*ast.Identifier{}
+	for !parser.peekTokenIs(token.RBRACE) {
+		if !parser.expectPeek(token.IDENT) {
+			return nil
+		}
+		statement.Names = append(statement.Names, parser.identifier())
+
+		if !parser.peekTokenIs(token.RBRACE) && !parser.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	parser.nextToken()
+
+	if !parser.expectPeek(token.STRING) {
+		return nil
+	}
+	statement.Path = &ast.StringLiteral{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+	}
+
+	return statement
+}
+
+func (parser *Parser) parseExportStatement() *ast.ExportStatement {
+	statement := &ast.ExportStatement{Token: parser.currentToken}
+	if !parser.atTopLevel() {
+		return nil
+	}
+
+	if !parser.expectPeek(token.LET) {
+		return nil
+	}
+	if statement.Statement = parser.parseLetStatement(); statement.Statement == nil {
+		return nil
+	}
+
+	return statement
+}
+
+// parseExternStatement parses one name of a JavaScript global, or a
+// list of names in braces and the JavaScript module that exports them.
+// A name may be followed by = and the JavaScript it stands for.
+func (parser *Parser) parseExternStatement() *ast.ExternStatement {
+	statement := &ast.ExternStatement{Token: parser.currentToken}
+	if !parser.atTopLevel() {
+		return nil
+	}
+
+	if !parser.peekTokenIs(token.LBRACE) {
+		name := parser.parseExternName(true)
+		if name == nil {
+			return nil
+		}
+		statement.Names = []*ast.ExternName{name}
+	} else {
+		parser.nextToken()
+		statement.Names = []*ast.ExternName{}
+		for !parser.peekTokenIs(token.RBRACE) {
+			name := parser.parseExternName(false)
+			if name == nil {
+				return nil
+			}
+			statement.Names = append(statement.Names, name)
+
+			if !parser.peekTokenIs(token.RBRACE) && !parser.expectPeek(token.COMMA) {
+				return nil
+			}
+		}
+		parser.nextToken()
+
+		if !parser.expectPeek(token.STRING) {
+			return nil
+		}
+		statement.Module = &ast.StringLiteral{Token: parser.currentToken, Value: parser.currentToken.Literal}
+	}
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+	}
+
+	return statement
+}
+
+// parseExternName parses a name and what it stands for: a global, which
+// may be a path such as console.log, or else an export of a module.
+func (parser *Parser) parseExternName(global bool) *ast.ExternName {
+	if !parser.expectPeek(token.IDENT) {
+		return nil
+	}
+	name := &ast.ExternName{Name: parser.identifier()}
+	if !parser.peekTokenIs(token.ASSIGN) {
+		return name
+	}
+	parser.nextToken()
+
+	if !parser.expectPeek(token.STRING) {
+		return nil
+	}
+	name.JavaScript = &ast.StringLiteral{Token: parser.currentToken, Value: parser.currentToken.Literal}
+	parts := strings.Split(name.JavaScript.Value, ".")
+	if !global && len(parts) > 1 {
+		parser.addError(parser.currentToken, fmt.Sprintf("%q is not the name of an export", name.JavaScript.Value))
+		return nil
+	}
+	for _, part := range parts {
+		if !isJavaScriptName(part) {
+			parser.addError(parser.currentToken, fmt.Sprintf("%q is not a JavaScript name", name.JavaScript.Value))
+			return nil
+		}
+	}
+
+	return name
+}
+
+// isJavaScriptName reports whether name is a JavaScript identifier.
+func isJavaScriptName(name string) bool {
+	for i, r := range name {
+		start := r == '_' || r == '$' || unicode.IsLetter(r)
+		if !start && (i == 0 || !(unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Pc))) {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+// atTopLevel reports an error unless the current token is outside every
+// block.
+func (parser *Parser) atTopLevel() bool {
+	if parser.depth == 0 {
+		return true
+	}
+
+	parser.addError(parser.currentToken, fmt.Sprintf("%s is only allowed at the top level", parser.currentToken.Literal))
+	return false
+}
+
 func (parser *Parser) registerPrefix(tokenType token.TokenType, function prefixParseFunction) {
 	parser.prefixParseFunctions[tokenType] = function
 }
@@ -215,7 +633,7 @@ func (parser *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 
 func (parser *Parser) noPrefixParseFnError(tokenType token.TokenType) {
 	message := fmt.Sprintf("no prefix parse function for %s found", tokenType)
-	parser.errors = append(parser.errors, message)
+	parser.addError(parser.currentToken, message)
 }
 
 func (parser *Parser) parseExpression(precedence int) ast.Expression {
@@ -223,7 +641,7 @@ func (parser *Parser) parseExpression(precedence int) ast.Expression {
 
 	if prefix == nil {
 		parser.noPrefixParseFnError(parser.currentToken.Type)
-		return nil
+		return &ast.BadExpression{Token: parser.currentToken}
 	}
 
 	leftExpression := prefix()
@@ -247,11 +665,17 @@ func (parser *Parser) parseIntegerLiteral() ast.Expression {
 	literal := &ast.IntegerLiteral{Token: parser.currentToken}
 	value, error := strconv.ParseInt(parser.currentToken.Literal, 0, 64)
 
+	if errors.Is(error, strconv.ErrRange) {
+		// Integers have no upper limit, so a long literal is fine.
+		if literal.Big, _ = new(big.Int).SetString(parser.currentToken.Literal, 0); literal.Big != nil {
+			return literal
+		}
+	}
 	if error != nil {
 		message := fmt.Sprintf("wasn't able to parse %q as integer", parser.currentToken.Literal)
-		parser.errors = append(parser.errors, message)
+		parser.addError(parser.currentToken, message)
 
-		return nil
+		return &ast.BadExpression{Token: parser.currentToken}
 	}
 
 	literal.Value = value
@@ -259,6 +683,22 @@ func (parser *Parser) parseIntegerLiteral() ast.Expression {
 	return literal
 }
 
+func (parser *Parser) parseFloatLiteral() ast.Expression {
+	value, error := strconv.ParseFloat(parser.currentToken.Literal, 64)
+	if error != nil {
+		message := fmt.Sprintf("wasn't able to parse %q as float", parser.currentToken.Literal)
+		parser.addError(parser.currentToken, message)
+
+		return &ast.BadExpression{Token: parser.currentToken}
+	}
+
+	return &ast.FloatLiteral{Token: parser.currentToken, Value: value}
+}
+
+func (parser *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{Token: parser.currentToken, Value: parser.currentToken.Literal}
+}
+
 func (parser *Parser) parsePrefixExpression() ast.Expression {
 	expression := &ast.PrefixExpression{
 		Token:    parser.currentToken,
@@ -285,3 +725,537 @@ func (parser *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 
 	return expression
 }
+
+func (parser *Parser) parseBoolean() ast.Expression {
+	return &ast.Boolean{Token: parser.currentToken, Value: parser.currentTokenIs(token.TRUE)}
+}
+
+func (parser *Parser) parseGroupedExpression() ast.Expression {
+	start := parser.currentToken
+	parser.nextToken()
+
+	expression := parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.RPAREN) {
+		return &ast.BadExpression{Token: start}
+	}
+
+	return expression
+}
+
+func (parser *Parser) parseIfExpression() ast.Expression {
+	expression := &ast.IfExpression{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.LPAREN) {
+		return &ast.BadExpression{Token: expression.Token}
+	}
+
+	parser.nextToken()
+	expression.Condition = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.RPAREN) {
+		return &ast.BadExpression{Token: expression.Token}
+	}
+
+	if !parser.expectPeek(token.LBRACE) {
+		return &ast.BadExpression{Token: expression.Token}
+	}
+
+	expression.Consequence = parser.parseBlockStatement()
+
+	if parser.peekTokenIs(token.ELSE) {
+		parser.nextToken()
+
+		if !parser.expectPeek(token.LBRACE) {
+			return &ast.BadExpression{Token: expression.Token}
+		}
+
+		expression.Alternative = parser.parseBlockStatement()
+	}
+
+	return expression
+}
+
+// parseMatchExpression parses салыштыр (subject) { arms }, where each
+// arm is a pattern, эгер and a guard if it has one, => and the value.
+func (parser *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.LPAREN) {
+		return &ast.BadExpression{Token: expression.Token}
+	}
+	parser.nextToken()
+	expression.Subject = parser.parseExpression(LOWEST)
+	if !parser.expectPeek(token.RPAREN) || !parser.expectPeek(token.LBRACE) {
+		return &ast.BadExpression{Token: expression.Token}
+	}
+
+	for !parser.peekTokenIs(token.RBRACE) {
+		parser.nextToken()
+		arm := &ast.MatchArm{Pattern: parser.parsePattern(false)}
+		if arm.Pattern == nil {
+			return &ast.BadExpression{Token: expression.Token}
+		}
+		parser.checkBindings(arm.Pattern)
+
+		if parser.peekTokenIs(token.IF) {
+			parser.nextToken()
+			arm.IfToken = parser.currentToken
+			parser.nextToken()
+			arm.Guard = parser.parseExpression(LOWEST)
+		}
+		if !parser.expectPeek(token.ARROW) {
+			return &ast.BadExpression{Token: expression.Token}
+		}
+		arm.Arrow = parser.currentToken
+		parser.nextToken()
+		arm.Body = parser.parseExpression(LOWEST)
+		expression.Arms = append(expression.Arms, arm)
+
+		if !parser.peekTokenIs(token.RBRACE) && !parser.expectPeek(token.COMMA) {
+			return &ast.BadExpression{Token: expression.Token}
+		}
+	}
+	parser.nextToken()
+	expression.End = parser.currentToken
+
+	if len(expression.Arms) == 0 {
+		parser.addError(expression.End, fmt.Sprintf("expected a pattern, got %s instead", expression.End.Type))
+		return &ast.BadExpression{Token: expression.Token}
+	}
+	parser.checkBooleanMatch(expression)
+
+	return expression
+}
+
+// parsePattern parses the pattern of an arm of салыштыр: a literal, a
+// name to bind, _, or brackets or braces of patterns. The patterns of
+// сакта and of parameters, which are destructuring, have no literals
+// but may give their elements default values.
+func (parser *Parser) parsePattern(destructuring bool) ast.Pattern {
+	switch parser.currentToken.Type {
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE:
+		if !destructuring {
+			return &ast.LiteralPattern{Value: parser.prefixParseFunctions[parser.currentToken.Type]()}
+		}
+	case token.MINUS:
+		if !destructuring && (parser.peekTokenIs(token.INT) || parser.peekTokenIs(token.FLOAT)) {
+			return &ast.LiteralPattern{Value: parser.parsePrefixExpression()}
+		}
+	case token.IDENT:
+		if parser.currentToken.Literal == "_" {
+			return &ast.WildcardPattern{Token: parser.currentToken}
+		}
+		return &ast.BindingPattern{Name: parser.identifier()}
+	case token.LBRACKET:
+		pattern := &ast.ArrayPattern{Token: parser.currentToken, Elements: []ast.Pattern{}}
+		for !parser.peekTokenIs(token.RBRACKET) {
+			parser.nextToken()
+			if parser.currentTokenIs(token.ELLIPSIS) {
+				pattern.Ellipsis = parser.currentToken
+				if !parser.expectPeek(token.IDENT) {
+					return nil
+				}
+				pattern.Rest = parser.identifier()
+				break
+			}
+			element := parser.parseElementPattern(destructuring)
+			if element == nil {
+				return nil
+			}
+			pattern.Elements = append(pattern.Elements, element)
+
+			if !parser.peekTokenIs(token.RBRACKET) && !parser.expectPeek(token.COMMA) {
+				return nil
+			}
+		}
+		if !parser.expectPeek(token.RBRACKET) {
+			return nil
+		}
+		pattern.End = parser.currentToken
+		return pattern
+	case token.LBRACE:
+		pattern := &ast.HashPattern{Token: parser.currentToken, Keys: []ast.Expression{}, Values: []ast.Pattern{}}
+		for !parser.peekTokenIs(token.RBRACE) {
+			parser.nextToken()
+			var key ast.Expression
+			if parser.currentTokenIs(token.IDENT) && !parser.peekTokenIs(token.COLON) {
+				// {аты} is {"аты": аты}.
+				key = &ast.StringLiteral{Token: parser.currentToken, Value: norm.NFC(parser.currentToken.Literal)}
+			} else {
+				literal, ok := parser.parsePattern(false).(*ast.LiteralPattern)
+				if !ok || parser.currentTokenIs(token.FLOAT) {
+					parser.addError(parser.currentToken, fmt.Sprintf("expected a literal key, got %s instead", parser.currentToken.Type))
+					return nil
+				}
+				if !parser.expectPeek(token.COLON) {
+					return nil
+				}
+				parser.nextToken()
+				key = literal.Value
+			}
+			value := parser.parseElementPattern(destructuring)
+			if value == nil {
+				return nil
+			}
+			pattern.Keys = append(pattern.Keys, key)
+			pattern.Values = append(pattern.Values, value)
+
+			if !parser.peekTokenIs(token.RBRACE) && !parser.expectPeek(token.COMMA) {
+				return nil
+			}
+		}
+		parser.nextToken()
+		pattern.End = parser.currentToken
+		return pattern
+	}
+
+	if destructuring {
+		parser.addError(parser.currentToken, fmt.Sprintf("expected a name, got %s instead", parser.currentToken.Type))
+		return nil
+	}
+	parser.addError(parser.currentToken, fmt.Sprintf("expected a pattern, got %s instead", parser.currentToken.Type))
+	return nil
+}
+
+// parseElementPattern parses the pattern of an element of a list or
+// hash pattern, with = and its default value after it when there is one.
+func (parser *Parser) parseElementPattern(destructuring bool) ast.Pattern {
+	pattern := parser.parsePattern(destructuring)
+	if pattern == nil || !destructuring {
+		return pattern
+	}
+
+	return parser.parseDefault(pattern)
+}
+
+// parseDefault parses the default value that may follow pattern, after
+// =, and returns pattern with it.
+func (parser *Parser) parseDefault(pattern ast.Pattern) ast.Pattern {
+	if !parser.peekTokenIs(token.ASSIGN) {
+		return pattern
+	}
+
+	parser.nextToken()
+	defaultPattern := &ast.DefaultPattern{Pattern: pattern, Assign: parser.currentToken}
+	parser.nextToken()
+	defaultPattern.Default = parser.parseExpression(LOWEST)
+
+	return defaultPattern
+}
+
+// checkBindings reports a name that a pattern binds more than once.
+func (parser *Parser) checkBindings(pattern ast.Pattern) {
+	bound := map[string]bool{}
+	for _, name := range ast.Bindings(pattern) {
+		if bound[name.Value] {
+			parser.addError(name.Token, fmt.Sprintf("%s is bound twice in one pattern", name.Value))
+		}
+		bound[name.Value] = true
+	}
+}
+
+// checkBooleanMatch warns about a салыштыр that matches booleans without
+// an arm for one of them, which fails when the subject is that boolean.
+func (parser *Parser) checkBooleanMatch(expression *ast.MatchExpression) {
+	matchesBooleans := false
+	covered := map[bool]bool{}
+	for _, arm := range expression.Arms {
+		switch pattern := arm.Pattern.(type) {
+		case *ast.LiteralPattern:
+			if boolean, ok := pattern.Value.(*ast.Boolean); ok {
+				matchesBooleans = true
+				covered[boolean.Value] = covered[boolean.Value] || arm.Guard == nil
+			}
+		case *ast.BindingPattern, *ast.WildcardPattern:
+			if arm.Guard == nil {
+				return
+			}
+		}
+	}
+	if !matchesBooleans {
+		return
+	}
+
+	dictionary := parser.lexerInstance.Dictionary()
+	for _, value := range []bool{true, false} {
+		if covered[value] {
+			continue
+		}
+		missing := dictionary.Spelling(token.FALSE)
+		if value {
+			missing = dictionary.Spelling(token.TRUE)
+		}
+		parser.warnings = append(parser.warnings, Error{Message: fmt.Sprintf("%s has no arm for %s", expression.Token.Literal, missing), Token: expression.Token})
+	}
+}
+
+func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: parser.currentToken}
+	block.Statements = []ast.Statement{}
+
+	parser.depth++
+	defer func() { parser.depth-- }()
+
+	parser.nextToken()
+
+	for !parser.currentTokenIs(token.RBRACE) && !parser.currentTokenIs(token.EOF) && !parser.gaveUp {
+		statement, atBlockEnd := parser.recoverStatement(parser.currentToken, parser.parseStatement())
+		if statement != nil {
+			block.Statements = append(block.Statements, statement)
+		}
+		if atBlockEnd {
+			break
+		}
+		parser.nextToken()
+	}
+	if parser.currentTokenIs(token.RBRACE) {
+		block.End = parser.currentToken
+	}
+
+	return block
+}
+
+func (parser *Parser) parseFunctionLiteral() ast.Expression {
+	literal := &ast.FunctionLiteral{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.LPAREN) {
+		return &ast.BadExpression{Token: literal.Token}
+	}
+
+	literal.Parameters, literal.ParameterTypes = parser.parseFunctionParameters()
+
+	if parser.peekTokenIs(token.COLON) {
+		parser.nextToken()
+		parser.nextToken()
+		if literal.ReturnType = parser.parseType(); literal.ReturnType == nil {
+			return &ast.BadExpression{Token: literal.Token}
+		}
+	}
+
+	if !parser.expectPeek(token.LBRACE) {
+		return &ast.BadExpression{Token: literal.Token}
+	}
+
+	literal.Body = parser.parseBlockStatement()
+
+	return literal
+}
+
+// parseFunctionParameters returns the parameters, which are names or
+// list and hash patterns, and their annotations, which are nil when
+// none is annotated.
+func (parser *Parser) parseFunctionParameters() ([]ast.Pattern, []ast.TypeExpression) {
+	parameters := []ast.Pattern{}
+	types := []ast.TypeExpression{}
+	annotated := false
+
+	if parser.peekTokenIs(token.RPAREN) {
+		parser.nextToken()
+		return parameters, nil
+	}
+
+	for {
+		if parser.peekTokenIs(token.LBRACKET) || parser.peekTokenIs(token.LBRACE) {
+			parser.nextToken()
+			pattern := parser.parsePattern(true)
+			if pattern == nil {
+				return nil, nil
+			}
+			parser.checkBindings(pattern)
+			parameters = append(parameters, pattern)
+		} else if !parser.expectPeek(token.IDENT) {
+			return nil, nil
+		} else {
+			parameters = append(parameters, &ast.BindingPattern{Name: parser.identifier()})
+		}
+
+		var parameterType ast.TypeExpression
+		if parser.peekTokenIs(token.COLON) {
+			parser.nextToken()
+			parser.nextToken()
+			if parameterType = parser.parseType(); parameterType == nil {
+				return nil, nil
+			}
+			annotated = true
+		}
+		types = append(types, parameterType)
+		// The default comes after the annotation: функ(n: Сан = 1).
+		parameters[len(parameters)-1] = parser.parseDefault(parameters[len(parameters)-1])
+
+		if !parser.peekTokenIs(token.COMMA) {
+			break
+		}
+		parser.nextToken()
+	}
+
+	if !parser.expectPeek(token.RPAREN) {
+		return nil, nil
+	}
+	if !annotated {
+		types = nil
+	}
+
+	return parameters, types
+}
+
+// parseType parses the type annotation at the current token: a name,
+// with types in brackets after it for the types that take them, or
+// функ with the types of the parameters and of the result.
+func (parser *Parser) parseType() ast.TypeExpression {
+	switch parser.currentToken.Type {
+	case token.IDENT:
+		namedType := &ast.NamedType{Token: parser.currentToken, Name: norm.NFC(parser.currentToken.Literal)}
+		if !parser.peekTokenIs(token.LBRACKET) {
+			return namedType
+		}
+		parser.nextToken()
+		namedType.Arguments = parser.parseTypeList(token.RBRACKET)
+		if namedType.Arguments == nil {
+			return nil
+		}
+		namedType.End = parser.currentToken
+		return namedType
+	case token.FUNCTION:
+		functionType := &ast.FunctionType{Token: parser.currentToken}
+		if !parser.expectPeek(token.LPAREN) {
+			return nil
+		}
+		if functionType.Parameters = parser.parseTypeList(token.RPAREN); functionType.Parameters == nil {
+			return nil
+		}
+		if !parser.expectPeek(token.COLON) {
+			return nil
+		}
+		parser.nextToken()
+		if functionType.Result = parser.parseType(); functionType.Result == nil {
+			return nil
+		}
+		return functionType
+	default:
+		parser.addError(parser.currentToken, fmt.Sprintf("expected a type, got %s instead", parser.currentToken.Type))
+		return nil
+	}
+}
+
+// parseTypeList parses types separated by commas up to end, which may
+// come at once after function types but not after a type name.
+func (parser *Parser) parseTypeList(end token.TokenType) []ast.TypeExpression {
+	types := []ast.TypeExpression{}
+	if end == token.RPAREN && parser.peekTokenIs(end) {
+		parser.nextToken()
+		return types
+	}
+
+	for {
+		parser.nextToken()
+		parsed := parser.parseType()
+		if parsed == nil {
+			return nil
+		}
+		types = append(types, parsed)
+
+		if !parser.peekTokenIs(token.COMMA) {
+			break
+		}
+		parser.nextToken()
+	}
+	if !parser.expectPeek(end) {
+		return nil
+	}
+
+	return types
+}
+
+func (parser *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	expression := &ast.CallExpression{Token: parser.currentToken, Function: function}
+	expression.Arguments = parser.parseExpressionList(token.RPAREN)
+	expression.End = parser.currentToken
+	return expression
+}
+
+// parseExpressionList parses comma separated expressions up to end, as
+// in call arguments and array literals.
+func (parser *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	if parser.peekTokenIs(end) {
+		parser.nextToken()
+		return list
+	}
+
+	parser.nextToken()
+	list = append(list, parser.parseExpression(LOWEST))
+
+	for parser.peekTokenIs(token.COMMA) {
+		parser.nextToken()
+		parser.nextToken()
+		list = append(list, parser.parseExpression(LOWEST))
+	}
+
+	if !parser.expectPeek(end) {
+		return nil
+	}
+
+	return list
+}
+
+func (parser *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: parser.currentToken}
+	array.Elements = parser.parseExpressionList(token.RBRACKET)
+	array.End = parser.currentToken
+	return array
+}
+
+func (parser *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: parser.currentToken, Keys: []ast.Expression{}, Values: []ast.Expression{}}
+
+	for !parser.peekTokenIs(token.RBRACE) {
+		parser.nextToken()
+		key := parser.parseExpression(LOWEST)
+
+		if !parser.expectPeek(token.COLON) {
+			return &ast.BadExpression{Token: hash.Token}
+		}
+
+		parser.nextToken()
+		hash.Keys = append(hash.Keys, key)
+		hash.Values = append(hash.Values, parser.parseExpression(LOWEST))
+
+		if !parser.peekTokenIs(token.RBRACE) && !parser.expectPeek(token.COMMA) {
+			return &ast.BadExpression{Token: hash.Token}
+		}
+	}
+
+	if !parser.expectPeek(token.RBRACE) {
+		return &ast.BadExpression{Token: hash.Token}
+	}
+	hash.End = parser.currentToken
+
+	return hash
+}
+
+func (parser *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	expression := &ast.IndexExpression{Token: parser.currentToken, Left: left}
+
+	parser.nextToken()
+	expression.Index = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.RBRACKET) {
+		return &ast.BadExpression{Token: expression.Token}
+	}
+	expression.End = parser.currentToken
+
+	return expression
+}
+
+func (parser *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	expression := &ast.MemberExpression{Token: parser.currentToken, Object: object}
+
+	if !parser.expectPeek(token.IDENT) {
+		return &ast.BadExpression{Token: expression.Token}
+	}
+	expression.Property = parser.identifier()
+
+	return expression
+}
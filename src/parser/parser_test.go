@@ -175,6 +175,22 @@ func TestIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestNumberLiterals(t *testing.T) {
+	parser := NewParser(lexer.New("99999999999999999999; 2.5;"))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	integer, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IntegerLiteral)
+	if !ok || integer.Big == nil || integer.Big.String() != "99999999999999999999" {
+		t.Errorf("big integer wrong. got=%#v", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+
+	float, ok := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.FloatLiteral)
+	if !ok || float.Value != 2.5 || float.String() != "2.5" {
+		t.Errorf("float wrong. got=%#v", program.Statements[1].(*ast.ExpressionStatement).Expression)
+	}
+}
+
 func TestParsingPrefix(test *testing.T) {
 	prefixTests := []struct {
 		input        string
@@ -355,3 +371,721 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestCompleteOperatorPrecedenceParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"туура", "туура"},
+		{"3 > 5 == ката", "((3 > 5) == ката)"},
+		{"1 + (2 + 3) + 4", "((1 + (2 + 3)) + 4)"},
+		{"-(5 + 5)", "(-(5 + 5))"},
+		{"!(туура == туура)", "(!(туура == туура))"},
+		{"a + add(b * c) + d", "((a + add((b * c))) + d)"},
+		{"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8))", "add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)))"},
+		{"a * [1, 2, 3, 4][b * c] * d", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
+		{"add(a * b[2], b[1], 2 * [1, 2][1])", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
+		{"-сап.узундук(x) + 1", "((-сап.узундук(x)) + 1)"},
+		{"a.b.c[0]", "(a.b.c[0])"},
+	}
+
+	for _, tt := range tests {
+		parser := NewParser(lexer.New(tt.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestLetStatementValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		name     string
+		expected string
+	}{
+		{"сакта x = 5;", "x", "5"},
+		{"сакта y = туура;", "y", "туура"},
+		{"сакта foobar = x + y;", "foobar", "(x + y)"},
+	}
+
+	for _, tt := range tests {
+		parser := NewParser(lexer.New(tt.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+
+		statement := program.Statements[0]
+		if !testLetStatement(t, statement, tt.name) {
+			return
+		}
+
+		value := statement.(*ast.LetStatement).Value
+		if value.String() != tt.expected {
+			t.Errorf("letStatement.Value is not %s. got=%s", tt.expected, value.String())
+		}
+	}
+}
+
+func TestIfElseExpression(t *testing.T) {
+	input := `эгер (x < y) { x } же { y }`
+
+	parser := NewParser(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	expression, ok := statement.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("statement.Expression is not ast.IfExpression. got=%T", statement.Expression)
+	}
+
+	if expression.Condition.String() != "(x < y)" {
+		t.Errorf("expression.Condition is not (x < y). got=%s", expression.Condition.String())
+	}
+
+	if len(expression.Consequence.Statements) != 1 || expression.Consequence.String() != "x" {
+		t.Errorf("expression.Consequence is not x. got=%s", expression.Consequence.String())
+	}
+
+	if expression.Alternative == nil || expression.Alternative.String() != "y" {
+		t.Errorf("expression.Alternative is not y. got=%+v", expression.Alternative)
+	}
+}
+
+func TestFunctionLiteralParsing(t *testing.T) {
+	input := `функция(x, y) { x + y; }`
+
+	parser := NewParser(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	statement := program.Statements[0].(*ast.ExpressionStatement)
+	function, ok := statement.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("statement.Expression is not ast.FunctionLiteral. got=%T", statement.Expression)
+	}
+
+	if len(function.Parameters) != 2 {
+		t.Fatalf("function literal parameters wrong. want 2, got=%d", len(function.Parameters))
+	}
+
+	if function.Parameters[0].String() != "x" || function.Parameters[1].String() != "y" {
+		t.Errorf("function literal parameters wrong. got=%v", function.Parameters)
+	}
+
+	if function.Body.String() != "(x + y)" {
+		t.Errorf("function.Body is not (x + y). got=%s", function.Body.String())
+	}
+}
+
+func TestCallExpressionParsing(t *testing.T) {
+	input := "кош(1, 2 * 3, 4 + 5);"
+
+	parser := NewParser(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	statement := program.Statements[0].(*ast.ExpressionStatement)
+	call, ok := statement.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("statement.Expression is not ast.CallExpression. got=%T", statement.Expression)
+	}
+
+	if call.Function.String() != "кош" {
+		t.Errorf("call.Function is not кош. got=%s", call.Function.String())
+	}
+
+	if len(call.Arguments) != 3 {
+		t.Fatalf("wrong length of arguments. got=%d", len(call.Arguments))
+	}
+
+	testIntegerLiteral(t, call.Arguments[0], 1)
+}
+
+func TestArrayLiteralParsing(t *testing.T) {
+	parser := NewParser(lexer.New("[1, 2 * 2, []]"))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	array, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("expression is not ast.ArrayLiteral. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	if len(array.Elements) != 3 {
+		t.Fatalf("wrong number of elements. got=%d", len(array.Elements))
+	}
+
+	testIntegerLiteral(t, array.Elements[0], 1)
+	if array.Elements[1].String() != "(2 * 2)" || array.Elements[2].String() != "[]" {
+		t.Errorf("elements wrong. got=%s", array.String())
+	}
+}
+
+func TestHashLiteralParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"бир": 1, "эки": 2 * 2}`, `{бир: 1, эки: (2 * 2)}`},
+		{"{}", "{}"},
+		{"{1: [1], туура: {}, }", "{1: [1], туура: {}}"},
+		{`{"а": 1}["а"]`, `({а: 1}[а])`},
+	}
+
+	for _, test := range tests {
+		parser := NewParser(lexer.New(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		hash, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.HashLiteral)
+		if _, isIndex := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IndexExpression); !ok && !isIndex {
+			t.Fatalf("%s: expression is not ast.HashLiteral. got=%T", test.input, program.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+		if ok && len(hash.Keys) != len(hash.Values) {
+			t.Errorf("%s: %d keys for %d values", test.input, len(hash.Keys), len(hash.Values))
+		}
+		if actual := program.String(); actual != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.input, test.expected, actual)
+		}
+	}
+
+	for _, input := range []string{`{"а" 1}`, `{"а": 1 "б": 2}`, `{"а": 1`} {
+		parser := NewParser(lexer.New(input))
+		parser.ParseProgram()
+		if len(parser.Errors()) == 0 {
+			t.Errorf("%s: expected an error", input)
+		}
+	}
+}
+
+func TestMemberExpressionParsing(t *testing.T) {
+	parser := NewParser(lexer.New("сап.узундук;"))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	member, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("expression is not ast.MemberExpression. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	if member.Object.String() != "сап" || member.Property.Value != "узундук" {
+		t.Errorf("member wrong. got=%s", member.String())
+	}
+
+	parser = NewParser(lexer.New("сап.5;"))
+	parser.ParseProgram()
+	if len(parser.Errors()) != 1 {
+		t.Errorf("expected one error. got=%v", parser.Errors())
+	}
+}
+
+func TestTryStatementParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"аракет { f(); } кармоо (e) { e } акыры { g() }", "аракет {f()} кармоо (e) {e} акыры {g()}"},
+		{"аракет { 1 } кармоо { 2 }", "аракет {1} кармоо {2}"},
+		{"аракет { 1 } акыры { 2 } 3", "аракет {1} акыры {2}3"},
+		{`ыргыт "ката"; 1`, "ыргыт ката;1"},
+		{"// тил: ky-latn\naraket { ırgıt [1] } karmoo (e) { e }", "araket {ırgıt [1];} кармоо (e) {e}"},
+	}
+
+	for _, test := range tests {
+		parser := NewParser(lexer.New(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if actual := program.String(); actual != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.input, test.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input   string
+		message string
+	}{
+		{"аракет { 1 }", "expected next token to be КАРМОО or АКЫРЫ, got БҮТТҮ instead"},
+		{"аракет { 1 } кармоо (1) { 2 }", "expected next token to be ИДЕНТИФИКАТОР, got БҮТҮН_САН instead"},
+		{"аракет { 1 } кармоо (e { 2 }", "expected next token to be ), got { instead"},
+		{"аракет 1 акыры { 2 }", "expected next token to be {, got БҮТҮН_САН instead"},
+		{"аракет { 1 } акыры 2", "expected next token to be {, got БҮТҮН_САН instead"},
+	}
+
+	for _, test := range errors {
+		parser := NewParser(lexer.New(test.input))
+		parser.ParseProgram()
+		if messages := parser.Errors(); len(messages) == 0 || messages[0] != test.message {
+			t.Errorf("%s: want %q, got=%q", test.input, test.message, messages)
+		}
+	}
+}
+
+func TestModuleStatementParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`импорт { кош, азайт } "./математика.alipp"; кош(1, 2)`, `импорт { кош, азайт } "./математика.alipp";кош(1, 2)`},
+		{`импорт { кош, } "математика"`, `импорт { кош } "математика";`},
+		{`импорт {} "./башта.alipp";`, `импорт {  } "./башта.alipp";`},
+		{"экспорт сакта кош = функ(a, b) { a + b };", "экспорт сакта кош = функ(a, b) (a + b);"},
+		{"// тил: ky-latn\nimport { kosh } \"./m.alipp\"; eksport sakta x = kosh;", `import { kosh } "./m.alipp";eksport sakta x = kosh;`},
+	}
+
+	for _, test := range tests {
+		parser := NewParser(lexer.New(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if actual := program.String(); actual != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.input, test.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input   string
+		message string
+	}{
+		{`импорт кош "./м.alipp"`, "expected next token to be {, got ИДЕНТИФИКАТОР instead"},
+		{`импорт { кош азайт } "./м.alipp"`, "expected next token to be ,, got ИДЕНТИФИКАТОР instead"},
+		{`импорт { кош }`, "expected next token to be САП, got БҮТТҮ instead"},
+		{`экспорт кош`, "expected next token to be САКТА, got ИДЕНТИФИКАТОР instead"},
+		{`сакта f = функ() { импорт { кош } "./м.alipp"; }`, "импорт is only allowed at the top level"},
+		{`эгер (туура) { экспорт сакта x = 1; }`, "экспорт is only allowed at the top level"},
+	}
+
+	for _, test := range errors {
+		parser := NewParser(lexer.New(test.input))
+		parser.ParseProgram()
+		if messages := parser.Errors(); len(messages) == 0 || messages[0] != test.message {
+			t.Errorf("%s: want %q, got=%q", test.input, test.message, messages)
+		}
+	}
+}
+
+func TestExternStatementParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`тышкы Math; Math.max(1, 2)`, `тышкы Math;Math.max(1, 2)`},
+		{`тышкы журнал = "console.log"`, `тышкы журнал = "console.log";`},
+		{`тышкы { readFileSync, оку = "readFileSync", } "node:fs";`, `тышкы { readFileSync, оку = "readFileSync" } "node:fs";`},
+		{`тышкы {} "./side-effects.js"`, `тышкы {  } "./side-effects.js";`},
+		{"// тил: kk\nсыртқы { jq = \"jQuery\" } \"jquery\";", `сыртқы { jq = "jQuery" } "jquery";`},
+		{"// тил: ky-latn\ntışkı document;", `tışkı document;`},
+	}
+
+	for _, test := range tests {
+		parser := NewParser(lexer.New(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if actual := program.String(); actual != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.input, test.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input   string
+		message string
+	}{
+		{`тышкы "Math"`, "expected next token to be ИДЕНТИФИКАТОР, got САП instead"},
+		{`тышкы { a } b`, "expected next token to be САП, got ИДЕНТИФИКАТОР instead"},
+		{`тышкы a = b`, "expected next token to be САП, got ИДЕНТИФИКАТОР instead"},
+		{`тышкы a = "a-b"`, `"a-b" is not a JavaScript name`},
+		{`тышкы a = "console..log"`, `"console..log" is not a JavaScript name`},
+		{`тышкы a = "1a"`, `"1a" is not a JavaScript name`},
+		{`тышкы { a = "fs.read" } "fs"`, `"fs.read" is not the name of an export`},
+		{`сакта f = функ() { тышкы Math; }`, "тышкы is only allowed at the top level"},
+	}
+
+	for _, test := range errors {
+		parser := NewParser(lexer.New(test.input))
+		parser.ParseProgram()
+		if messages := parser.Errors(); len(messages) == 0 || messages[0] != test.message {
+			t.Errorf("%s: want %q, got=%q", test.input, test.message, messages)
+		}
+	}
+}
+
+func TestTypeAnnotationParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`сакта x: Сан = 5`, `сакта x: Сан = 5;`},
+		{`сакта тизме: Тизме[Сап] = []`, `сакта тизме: Тизме[Сап] = [];`},
+		{`сакта с: Сөздүк[Сап, Тизме[Бөлчөк]] = {}`, `сакта с: Сөздүк[Сап, Тизме[Бөлчөк]] = {};`},
+		{`функ(a: Сан, b): Сан { a + b }`, `функ(a: Сан, b) : Сан (a + b)`},
+		{`функ(f: функ(Сан, Сан): Логикалык, g: функ(): Бош) { f }`, `функ(f: функ(Сан, Сан): Логикалык, g: функ(): Бош) f`},
+		{`функ(): функ(Сан): Сан { функ(x) { x } }`, `функ() : функ(Сан): Сан функ(x) x`},
+		{`{"a": 1}`, `{a: 1}`},
+	}
+
+	for _, test := range tests {
+		parser := NewParser(lexer.New(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if actual := program.String(); actual != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.input, test.expected, actual)
+		}
+	}
+
+	function := parseFunction(t, `функ(a, b: Сан, c) { a }`)
+	if len(function.ParameterTypes) != 3 || function.ParameterTypes[0] != nil || function.ParameterTypes[1].String() != "Сан" || function.ParameterTypes[2] != nil {
+		t.Errorf("wrong parameter types. got=%v", function.ParameterTypes)
+	}
+	if function := parseFunction(t, `функ(a, b) { a }`); function.ParameterTypes != nil || function.ReturnType != nil {
+		t.Errorf("unannotated function has types. got=%v, %v", function.ParameterTypes, function.ReturnType)
+	}
+
+	errors := []struct {
+		input   string
+		message string
+	}{
+		{`сакта x: = 5`, "expected a type, got = instead"},
+		{`сакта x: Тизме[] = []`, "expected a type, got ] instead"},
+		{`сакта x: Тизме[Сан = []`, "expected next token to be ], got = instead"},
+		{`сакта f: функ(Сан) = f`, "expected next token to be :, got = instead"},
+		{`функ(a:) { a }`, "expected a type, got ) instead"},
+	}
+
+	for _, test := range errors {
+		parser := NewParser(lexer.New(test.input))
+		parser.ParseProgram()
+		if messages := parser.Errors(); len(messages) == 0 || messages[0] != test.message {
+			t.Errorf("%s: want %q, got=%q", test.input, test.message, messages)
+		}
+	}
+}
+
+// parseFunction parses input, which must be a function literal.
+func parseFunction(t *testing.T, input string) *ast.FunctionLiteral {
+	t.Helper()
+
+	parser := NewParser(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	function, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("%s is not a function literal", input)
+	}
+
+	return function
+}
+
+func TestMatchExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`салыштыр (x) { 1 => "бир", -2.5 => "минус", _ => "башка" }`, `салыштыр (x) { 1 => бир, (-2.5) => минус, _ => башка }`},
+		{`салыштыр (x) { [a, [b, _]] => a + b, [] => 0, }`, `салыштыр (x) { [a, [b, _]] => (a + b), [] => 0 }`},
+		{`салыштыр (адам) { {"аты": n, "жашы": 18} => n, {} => бош }`, `салыштыр (адам) { {аты: n, жашы: 18} => n, {} => бош }`},
+		{`салыштыр (x) { n эгер n > 0 => n, n => -n }`, `салыштыр (x) { n эгер (n > 0) => n, n => (-n) }`},
+		{`сакта y = салыштыр (туура) { туура => 1, ката => 0 };`, `сакта y = салыштыр (туура) { туура => 1, ката => 0 };`},
+	}
+
+	for _, test := range tests {
+		parser := NewParser(lexer.New(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if actual := program.String(); actual != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.input, test.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input   string
+		message string
+	}{
+		{`салыштыр (x) { }`, "expected a pattern, got } instead"},
+		{`салыштыр (x) { a + 1 => a }`, "expected next token to be =>, got + instead"},
+		{`салыштыр (x) { f(1) => 1 }`, "expected next token to be =>, got ( instead"},
+		{`салыштыр (x) { {a: 1} => 1 }`, "expected a literal key, got ИДЕНТИФИКАТОР instead"},
+		{`салыштыр (x) { [a, a] => a }`, "a is bound twice in one pattern"},
+		{`салыштыр (x) { 1 => 1 2 => 2 }`, "expected next token to be ,, got БҮТҮН_САН instead"},
+	}
+
+	for _, test := range errors {
+		parser := NewParser(lexer.New(test.input))
+		parser.ParseProgram()
+		if messages := parser.Errors(); len(messages) == 0 || messages[0] != test.message {
+			t.Errorf("%s: want %q, got=%q", test.input, test.message, messages)
+		}
+	}
+}
+
+func TestDestructuringParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`сакта [a, b, ...калган] = тизме;`, `сакта [a, b, ...калган] = тизме;`},
+		{`сакта { аты, жашы = 0 } = адам;`, `сакта {аты, жашы = 0} = адам;`},
+		{`сакта {"аты": n, 1: [x, _]} = адам;`, `сакта {аты: n, 1: [x, _]} = адам;`},
+		{`сакта [a = 1, [b] = [a + 1],] = [];`, `сакта [a = 1, [b] = [(a + 1)]] = [];`},
+		{`сакта [...бардыгы] = x;`, `сакта [...бардыгы] = x;`},
+		{`сакта [a, b]: Тизме[Сан] = x;`, `сакта [a, b]: Тизме[Сан] = x;`},
+		{`функ([a, b], { аты }, c) { a }`, `функ([a, b], {аты}, c) a`},
+		{`функ({ x = 1 }: Сөздүк[Сап, Сан]) { x }`, `функ({x = 1}: Сөздүк[Сап, Сан]) x`},
+		{`функ(a, b = a + 1, [c] = []) { a }`, `функ(a, b = (a + 1), [c] = []) a`},
+		{`функ(n: Сан = 1) { n }`, `функ(n: Сан = 1) n`},
+		{`салыштыр (x) { [h, ...t] => t, { аты } => аты }`, `салыштыр (x) { [h, ...t] => t, {аты} => аты }`},
+	}
+
+	for _, test := range tests {
+		parser := NewParser(lexer.New(test.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if actual := program.String(); actual != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.input, test.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input   string
+		message string
+	}{
+		{`сакта [a, 1] = x;`, "expected a name, got БҮТҮН_САН instead"},
+		{`сакта [a, ...b, c] = x;`, "expected next token to be ], got , instead"},
+		{`сакта [...1] = x;`, "expected next token to be ИДЕНТИФИКАТОР, got БҮТҮН_САН instead"},
+		{`сакта {a, a} = x;`, "a is bound twice in one pattern"},
+		{`сакта {1.5: a} = x;`, "expected a literal key, got БӨЛЧӨК_САН instead"},
+		{`сакта [a, {"b": a}] = x;`, "a is bound twice in one pattern"},
+		{`функ([a, ...a]) { a }`, "a is bound twice in one pattern"},
+		{`функ(x = ) { x }`, "no prefix parse function for ) found"},
+		{`салыштыр (x) { [a = 1] => a }`, "expected next token to be ,, got = instead"},
+	}
+
+	for _, test := range errors {
+		parser := NewParser(lexer.New(test.input))
+		parser.ParseProgram()
+		if messages := parser.Errors(); len(messages) == 0 || messages[0] != test.message {
+			t.Errorf("%s: want %q, got=%q", test.input, test.message, messages)
+		}
+	}
+}
+
+func TestMatchWarnings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"салыштыр (x) { туура => 1, ката => 0 }", []string{}},
+		{"салыштыр (x) { туура => 1 }", []string{"1:1 салыштыр has no arm for ката"}},
+		{"салыштыр (x) { туура => 1, _ => 0 }", []string{}},
+		{"салыштыр (x) { ката эгер y => 1, туура => 0 }", []string{"1:1 салыштыр has no arm for ката"}},
+		{"салыштыр (x) { 1 => 1 }", []string{}},
+		{"// тил: ky-latn\nsalıştır (x) { kata => 0 }", []string{"2:1 salıştır has no arm for tuura"}},
+	}
+
+	for _, tt := range tests {
+		parser := NewParser(lexer.New(tt.input))
+		parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		warnings := []string{}
+		for _, warning := range parser.Warnings() {
+			warnings = append(warnings, fmt.Sprintf("%d:%d %s", warning.Token.Line, warning.Token.Column, warning.Message))
+		}
+		if fmt.Sprint(warnings) != fmt.Sprint(tt.expected) {
+			t.Errorf("%q: warnings wrong.\nwant=%q\ngot= %q", tt.input, tt.expected, warnings)
+		}
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	input := `сакта x = 5;
+сакта = 10;`
+
+	parser := NewParser(lexer.New(input))
+	parser.ParseProgram()
+
+	errors := parser.ErrorList()
+	if len(errors) == 0 {
+		t.Fatalf("expected parser errors, got none")
+	}
+
+	if errors[0].Token.Line != 2 || errors[0].Token.Column != 7 {
+		t.Errorf("error position wrong. want 2:7, got=%d:%d", errors[0].Token.Line, errors[0].Token.Column)
+	}
+}
+
+func TestUnknownDictionary(t *testing.T) {
+	parser := NewParser(lexer.New("// тил: xx\nсакта x = 1;"))
+	program := parser.ParseProgram()
+
+	errors := parser.ErrorList()
+	if len(errors) != 1 {
+		t.Fatalf("wrong number of errors. want=1, got=%v", parser.Errors())
+	}
+	if errors[0].Message != `unknown keyword dictionary "xx"` || errors[0].Token.Line != 1 || errors[0].Token.Column != 9 {
+		t.Errorf("wrong error. got=%q at %d:%d", errors[0].Message, errors[0].Token.Line, errors[0].Token.Column)
+	}
+
+	// The default dictionary is used instead.
+	if program.String() != "сакта x = 1;" {
+		t.Errorf("program wrong. got=%q", program.String())
+	}
+}
+
+func TestNormalizedIdentifiers(t *testing.T) {
+	// The second ай is typed with и and a combining breve.
+	input := "сакта ай = 1; а\u0438\u0306 + 1;"
+
+	parser := NewParser(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	let := program.Statements[0].(*ast.LetStatement)
+	infix := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+	if name := infix.Left.(*ast.Identifier).Value; name != let.Name.Value {
+		t.Errorf("identifier not normalized. want=%+q, got=%+q", let.Name.Value, name)
+	}
+}
+
+func TestIdentifierWarnings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"сакта x = 1;", []string{}},
+		{"сaкта x = 5;", []string{`1:1 identifier "сaкта" looks like the keyword "сакта"`}},
+		{"сакта сaны = 1; сaны;", []string{
+			`1:7 identifier "сaны" mixes Latin and Cyrillic letters`,
+			`1:17 identifier "сaны" mixes Latin and Cyrillic letters`,
+		}},
+		{"// тил: kk\nсақта x = ақиқaт;", []string{`2:11 identifier "ақиқaт" looks like the keyword "ақиқат"`}},
+	}
+
+	for _, tt := range tests {
+		parser := NewParser(lexer.New(tt.input))
+		parser.ParseProgram()
+
+		warnings := []string{}
+		for _, warning := range parser.Warnings() {
+			warnings = append(warnings, fmt.Sprintf("%d:%d %s", warning.Token.Line, warning.Token.Column, warning.Message))
+		}
+		if fmt.Sprint(warnings) != fmt.Sprint(tt.expected) {
+			t.Errorf("%q: warnings wrong.\nwant=%q\ngot= %q", tt.input, tt.expected, warnings)
+		}
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedErrors int
+		expected       string
+	}{
+		// A broken let statement is replaced and the next one survives.
+		{"сакта = 5; сакта y = 10;", 1, "<?>;сакта y = 10;"},
+		// Skipping stops at the keyword starting the next statement.
+		{"сакта x 5 6 7 сакта y = 1;", 1, "<?>;сакта y = 1;"},
+		// A missing operand leaves a placeholder in the expression.
+		{"сакта x = 5 + ; y;", 1, "сакта x = (5 + <?>);y"},
+		// The error inside the block does not leak out of it.
+		{"сакта f = функ(a) { a + ; }; f(1);", 1, "сакта f = функ(a) (a + <?>);f(1)"},
+		// A missing expression right before the closing brace keeps the block intact.
+		{"эгер (x) { сакта y = } z;", 1, "эгерx сакта y = <?>;z"},
+		// A stray closing brace at the top level is skipped.
+		{"} сакта x = 1;", 1, "<?>сакта x = 1;"},
+		{"функ(1, 2) { x }; y;", 1, "<?>y"},
+	}
+
+	for _, tt := range tests {
+		parser := NewParser(lexer.New(tt.input))
+		program := parser.ParseProgram()
+
+		if len(parser.Errors()) != tt.expectedErrors {
+			t.Errorf("%q: wrong number of errors. want=%d, got=%d %q", tt.input, tt.expectedErrors, len(parser.Errors()), parser.Errors())
+		}
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestErrorLimit(t *testing.T) {
+	input := ""
+	for i := 0; i < MaxErrors*2; i++ {
+		input += "сакта = 1;\n"
+	}
+
+	parser := NewParser(lexer.New(input))
+	parser.ParseProgram()
+
+	errors := parser.Errors()
+	if len(errors) != MaxErrors+1 {
+		t.Fatalf("wrong number of errors. want=%d, got=%d", MaxErrors+1, len(errors))
+	}
+
+	if errors[len(errors)-1] != "too many errors" {
+		t.Errorf("last error is not the limit message. got=%q", errors[len(errors)-1])
+	}
+}
+
+func TestSpans(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"сап.узундук(\"өң\", x)", "1:1-1:21"},
+		{"-a * b[1]", "1:1-1:10"},
+		{"функ(x) {\n  x\n}", "1:1-3:2"},
+		{"эгер (а) { 1 } же {\n2 }", "1:1-2:4"},
+		{"\"эки\nсап\"", "1:1-2:5"},
+		{"{\"а\": [1, 2]}", "1:1-1:14"},
+		{"x", "1:1-1:2"},
+		{"салыштыр (x) {\n  [a, _] => a\n}", "1:1-3:2"},
+	}
+
+	for _, test := range tests {
+		parserInstance := NewParser(lexer.New(test.input))
+		program := parserInstance.ParseProgram()
+		checkParserErrors(t, parserInstance)
+
+		span := ast.SpanOf(program.Statements[0].(*ast.ExpressionStatement).Expression)
+		actual := fmt.Sprintf("%d:%d-%d:%d", span.Start.Line, span.Start.Column, span.End.Line, span.End.Column)
+		if actual != test.expected {
+			t.Errorf("%q: want=%s, got=%s", test.input, test.expected, actual)
+		}
+	}
+	types := map[string]string{
+		"сакта x: Сөздүк[Сап, Сан] = 1": "1:10-1:26",
+		"сакта x: функ(Сан): Сан = 1":   "1:10-1:24",
+	}
+	for input, expected := range types {
+		parserInstance := NewParser(lexer.New(input))
+		program := parserInstance.ParseProgram()
+		checkParserErrors(t, parserInstance)
+
+		span := ast.SpanOf(program.Statements[0].(*ast.LetStatement).Type)
+		actual := fmt.Sprintf("%d:%d-%d:%d", span.Start.Line, span.Start.Column, span.End.Line, span.End.Column)
+		if actual != expected {
+			t.Errorf("%q: want=%s, got=%s", input, expected, actual)
+		}
+	}
+}
@@ -4,34 +4,161 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/evaluator"
+	"github.com/asanoviskhak/alipp/src/highlight"
 	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/object"
+	"github.com/asanoviskhak/alipp/src/parser"
+	"github.com/asanoviskhak/alipp/src/stdlib"
 	"github.com/asanoviskhak/alipp/src/token"
+	"github.com/asanoviskhak/alipp/src/types"
 )
 
 const PROMPT = "киргизүү>> "
 const EXIT_KEYWORD = "чыгуу"
 
-func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
+// redraw moves the cursor back up to the line the terminal echoed and
+// clears it, so the line can be written again in colour.
+const redraw = "\x1b[1A\x1b[2K"
+
+// TYPE_COMMAND followed by an expression prints its type instead of its
+// value.
+const TYPE_COMMAND = ":type"
+
+// Start reads lines from in and evaluates them until EXIT_KEYWORD. The
+// keywords are spelled as in dictionary, or in Kyrgyz when it is nil.
+func Start(in io.Reader, out io.Writer, dictionary *token.Dictionary) {
+	// окуу reads from the same reader, so it gets the lines typed after
+	// the one that called it.
+	reader := bufio.NewReader(in)
+	// Only a line the terminal echoed can be redrawn in colour.
+	colored := isTerminal(in) && isTerminal(out)
+	// Bindings made on one line stay visible on the next ones.
+	newEnvironment := func() *object.Environment {
+		return evaluator.NewEnvironment(reader, out, stdlib.Capabilities{})
+	}
+	env := newEnvironment()
+	// Imports are relative to the working directory.
+	loaderInstance := loader.New(loader.DefaultSearchPath())
+	loaderInstance.Dictionary = dictionary
+	loaderInstance.Reserved = stdlib.ReservedNames()
+	modules := evaluator.NewModules(loaderInstance, newEnvironment)
+	env.SetModule("", modules.Importer("."))
+	// The types of the names bound so far, for :type.
+	typeEnvironment := types.NewEnvironment()
+	// Every line is numbered after the ones before it, so that an error
+	// in a function defined earlier points at the line that defined it.
+	var history strings.Builder
+	number := 0
 
 	for {
 		fmt.Fprintf(out, PROMPT)
-		scanned := scanner.Scan()
-
-		if !scanned {
+		currentLine, err := reader.ReadString('\n')
+		if err != nil && currentLine == "" {
 			return
 		}
 
-		currentLine := scanner.Text()
+		currentLine = strings.TrimRight(currentLine, "\r\n")
 		if currentLine == EXIT_KEYWORD {
 			return
 		}
 
-		lexerInstance := lexer.New(currentLine)
+		if colored {
+			fmt.Fprintln(out, redraw+PROMPT+highlight.ANSI(currentLine, dictionary))
+		}
 
-		for tok := lexerInstance.NextToken(); tok.Type != token.EOF; tok = lexerInstance.NextToken() {
-			fmt.Fprintf(out, "%+v\n", tok)
+		if expression, ok := strings.CutPrefix(currentLine, TYPE_COMMAND+" "); ok {
+			printType(out, typeEnvironment, newLexer(expression, 0, 1, dictionary))
+			continue
 		}
+
+		number++
+		offset := history.Len()
+		history.WriteString(currentLine + "\n")
+
+		parserInstance := parser.NewParser(newLexer(history.String(), offset, number, dictionary))
+		program := parserInstance.ParseProgram()
+		if len(parserInstance.Errors()) != 0 {
+			printParserErrors(out, parserInstance.Errors())
+			continue
+		}
+
+		typeEnvironment.Check(program)
+		evaluated := evaluator.Eval(program, env)
+		if err, ok := evaluated.(*object.Error); ok {
+			fmt.Fprint(out, evaluator.Report(err, "киргизүү", history.String()))
+		} else if evaluated != nil {
+			fmt.Fprintln(out, evaluated.Inspect())
+		}
+	}
+}
+
+// newLexer lexes input from offset, which is at the start of the line
+// numbered line, with dictionary when it is not nil.
+func newLexer(input string, offset int, line int, dictionary *token.Dictionary) *lexer.Lexer {
+	lexerInstance := lexer.NewAt(input, offset, line, 1)
+	if dictionary != nil {
+		lexerInstance.UseDictionary(dictionary)
+	}
+
+	return lexerInstance
+}
+
+func printParserErrors(out io.Writer, errors []string) {
+	fmt.Fprintln(out, "Синтаксистик каталар:")
+	for _, message := range errors {
+		fmt.Fprintf(out, "\t%s\n", message)
 	}
 }
+
+// printType prints the type the checker infers for the expression
+// lexerInstance reads, with the names bound on the lines before it.
+func printType(out io.Writer, environment *types.Environment, lexerInstance *lexer.Lexer) {
+	parserInstance := parser.NewParser(lexerInstance)
+	program := parserInstance.ParseProgram()
+	if len(parserInstance.Errors()) != 0 {
+		printParserErrors(out, parserInstance.Errors())
+		return
+	}
+
+	var statement *ast.ExpressionStatement
+	if len(program.Statements) == 1 {
+		statement, _ = program.Statements[0].(*ast.ExpressionStatement)
+	}
+	if statement == nil {
+		fmt.Fprintln(out, TYPE_COMMAND+" бир туюнтманы күтөт")
+		return
+	}
+
+	scheme, errors := environment.TypeOf(statement.Expression)
+	if len(errors) != 0 {
+		fmt.Fprintln(out, "Түр каталары:")
+		for _, typeError := range errors {
+			fmt.Fprintf(out, "\t%s\n", typeError)
+		}
+		return
+	}
+
+	fmt.Fprintln(out, scheme)
+}
+
+// isTerminal reports whether stream is an interactive terminal, so escape
+// sequences are not written into pipes and files.
+func isTerminal(stream interface{}) bool {
+	file, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
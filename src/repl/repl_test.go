@@ -0,0 +1,28 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTypeCommand(t *testing.T) {
+	input := "сакта id = функ(x) { x };\n" +
+		":type id\n" +
+		":type id(5)\n" +
+		":type 1 + \"a\"\n" +
+		":type сакта y = 1;\n"
+
+	var out bytes.Buffer
+	Start(strings.NewReader(input), &out, nil)
+
+	expected := PROMPT +
+		PROMPT + "функ(а): а\n" +
+		PROMPT + "Сан\n" +
+		PROMPT + "Түр каталары:\n\t1:1: түрлөр дал келбейт: Сан + Сап\n" +
+		PROMPT + TYPE_COMMAND + " бир туюнтманы күтөт\n" +
+		PROMPT
+	if out.String() != expected {
+		t.Errorf("wrong output.\nwant=%q\ngot= %q", expected, out.String())
+	}
+}
@@ -0,0 +1,67 @@
+package stdlib
+
+import "unicode"
+
+// kyrgyzAlphabet is the order of the Kyrgyz Cyrillic alphabet, which
+// code point order gets wrong: ё, ң, ө and ү come after я in Unicode.
+const kyrgyzAlphabet = "абвгдеёжзийклмнңоөпрстуүфхцчшщъыьэюя"
+
+var alphabetRanks = func() map[rune]int {
+	ranks := map[rune]int{}
+	for _, r := range kyrgyzAlphabet {
+		ranks[r] = len(ranks)
+	}
+	return ranks
+}()
+
+// collationKey orders letters of the alphabet by their place in it and
+// every other rune by code point, ignoring case. The alphabet sorts where
+// а is, before the other Cyrillic letters.
+func collationKey(r rune) int {
+	lower := unicode.ToLower(r)
+	if rank, ok := alphabetRanks[lower]; ok {
+		return 'а'<<8 | rank
+	}
+
+	return int(lower) << 8
+}
+
+// Compare orders two strings alphabetically for Kyrgyz and returns -1, 0
+// or 1. Case only matters between strings that are otherwise equal, and
+// then lower case comes first.
+func Compare(a string, b string) int {
+	left, right := []rune(a), []rune(b)
+
+	for i := 0; i < len(left) && i < len(right); i++ {
+		if result := sign(collationKey(left[i]) - collationKey(right[i])); result != 0 {
+			return result
+		}
+	}
+	if result := sign(len(left) - len(right)); result != 0 {
+		return result
+	}
+
+	for i := range left {
+		if left[i] != right[i] {
+			if unicode.IsLower(left[i]) != unicode.IsLower(right[i]) {
+				if unicode.IsLower(left[i]) {
+					return -1
+				}
+				return 1
+			}
+			return sign(int(left[i]) - int(right[i]))
+		}
+	}
+
+	return 0
+}
+
+func sign(difference int) int {
+	switch {
+	case difference < 0:
+		return -1
+	case difference > 0:
+		return 1
+	}
+	return 0
+}
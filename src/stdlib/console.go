@@ -0,0 +1,151 @@
+package stdlib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+// Console is where the input and output builtins read and write. The
+// interpreter passes the standard streams, and the REPL and tests their
+// own.
+type Console struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewConsole returns a console on in and out. A *bufio.Reader is used as
+// it is, so the caller can keep reading lines from it too.
+func NewConsole(in io.Reader, out io.Writer) *Console {
+	reader, ok := in.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(in)
+	}
+
+	return &Console{in: reader, out: out}
+}
+
+// Builtins returns the functions called without a module name, bound to
+// console. Their JavaScript versions are the members of js/console.js.
+func (console *Console) Builtins() map[string]*object.Builtin {
+	return map[string]*object.Builtin{
+		"көрсөтүү": {Name: "көрсөтүү", Function: console.show},
+		"жаз":      {Name: "жаз", Function: console.print},
+		"окуу":     {Name: "окуу", Function: console.read},
+		// ката_билдирүү does not use the console, but is called without a
+		// module name like the others.
+		"ката_билдирүү": {Name: "ката_билдирүү", Function: describeError},
+	}
+}
+
+// BuiltinNames returns the names of the builtin functions, sorted. The
+// functions themselves come from a Console, so that none reads or writes
+// streams nobody chose.
+func BuiltinNames() []string {
+	names := []string{}
+	for name := range (&Console{}).Builtins() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// IsBuiltin reports whether name is the name of a builtin function.
+func IsBuiltin(name string) bool {
+	_, ok := (&Console{}).Builtins()[name]
+	return ok
+}
+
+// BuiltinsJavaScript returns an expression that evaluates to an object
+// with the JavaScript versions of the builtin functions.
+func BuiltinsJavaScript() string {
+	source, err := scripts.ReadFile("js/console.js")
+	if err != nil {
+		panic(err)
+	}
+
+	return string(source)
+}
+
+// show prints its arguments separated by spaces.
+func (console *Console) show(args ...object.Object) object.Object {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = arg.Inspect()
+	}
+
+	fmt.Fprintln(console.out, strings.Join(values, " "))
+	return nil
+}
+
+// print prints a template with every {} replaced by the next argument.
+// {{ and }} stand for the braces themselves.
+func (console *Console) print(args ...object.Object) object.Object {
+	if len(args) == 0 {
+		return newError("жаз: жок дегенде бир аргумент керек")
+	}
+	template, ok := args[0].(*object.String)
+	if !ok {
+		return newError("жаз: 1-аргумент %s болушу керек, %s берилди", object.STRING_OBJ, args[0].Type())
+	}
+
+	var out strings.Builder
+	values := args[1:]
+	used := 0
+	text := template.Value
+	for i := 0; i < len(text); i++ {
+		switch {
+		case strings.HasPrefix(text[i:], "{{"):
+			out.WriteByte('{')
+			i++
+		case strings.HasPrefix(text[i:], "}}"):
+			out.WriteByte('}')
+			i++
+		case strings.HasPrefix(text[i:], "{}"):
+			if used < len(values) {
+				out.WriteString(values[used].Inspect())
+			}
+			used++
+			i++
+		default:
+			out.WriteByte(text[i])
+		}
+	}
+
+	if used != len(values) {
+		return newError("жаз: калыпта %d орун бар, %d маани берилди", used, len(values))
+	}
+
+	fmt.Fprintln(console.out, out.String())
+	return nil
+}
+
+// read returns the next line of input without its line ending, or бош at
+// the end of the input. An argument is printed first as a prompt.
+func (console *Console) read(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("окуу: 0 же 1 аргумент керек, %d берилди", len(args))
+	}
+	if len(args) == 1 {
+		if err := checkArguments("окуу", args, object.STRING_OBJ); err != nil {
+			return err
+		}
+		fmt.Fprint(console.out, args[0].(*object.String).Value)
+	}
+
+	line, err := console.in.ReadString('\n')
+	if err != nil && line == "" {
+		if err == io.EOF {
+			return nil
+		}
+		return newError("окуу: %s", err)
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+	return &object.String{Value: strings.TrimSuffix(line, "\r")}
+}
@@ -0,0 +1,63 @@
+package stdlib
+
+import (
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+// Apply calls a function value with arguments. The evaluator sets it, so
+// that builtins can call the functions they are given.
+var Apply func(function object.Object, args []object.Object) object.Object
+
+// describeError calls its argument, a function without parameters, and
+// returns бош when it succeeds or a hash describing the error it raised.
+func describeError(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("ката_билдирүү: 1 аргумент керек, %d берилди", len(args))
+	}
+
+	switch function := args[0].(type) {
+	case *object.Function:
+		if len(function.Parameters) != 0 {
+			return newError("ката_билдирүү: функция аргументсиз болушу керек, %d параметри бар", len(function.Parameters))
+		}
+	case *object.Builtin:
+	default:
+		return newError("ката_билдирүү: 1-аргумент %s болушу керек, %s берилди", object.FUNCTION_OBJ, args[0].Type())
+	}
+
+	if err, ok := Apply(args[0], nil).(*object.Error); ok {
+		return errorHash(err)
+	}
+	return nil
+}
+
+// errorHash describes err with the keys билдирүү, its message, сап and
+// тилке, where it happened, and чакыруулар, the calls it left, innermost
+// first. Each call has the keys функция, сап and тилке.
+func errorHash(err *object.Error) *object.Hash {
+	stack := make([]object.Object, len(err.Stack))
+	for i, frame := range err.Stack {
+		call := object.NewHash()
+		var name object.Object = object.NULL
+		if frame.Function != "" {
+			name = &object.String{Value: frame.Function}
+		}
+		call.Set(&object.String{Value: "функция"}, name)
+		call.Set(&object.String{Value: "сап"}, &object.Integer{Value: int64(frame.Position.Line)})
+		call.Set(&object.String{Value: "тилке"}, &object.Integer{Value: int64(frame.Position.Column)})
+		stack[i] = call
+	}
+
+	var line, column object.Object = object.NULL, object.NULL
+	if !err.Span.IsZero() {
+		line = &object.Integer{Value: int64(err.Span.Start.Line)}
+		column = &object.Integer{Value: int64(err.Span.Start.Column)}
+	}
+
+	hash := object.NewHash()
+	hash.Set(&object.String{Value: "билдирүү"}, &object.String{Value: err.Message})
+	hash.Set(&object.String{Value: "сап"}, line)
+	hash.Set(&object.String{Value: "тилке"}, column)
+	hash.Set(&object.String{Value: "чакыруулар"}, &object.Array{Elements: stack})
+	return hash
+}
@@ -0,0 +1,231 @@
+package stdlib
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+// Capabilities lists the directories a program may read and write,
+// given on the command line as --allow-read and --allow-write. The zero
+// value allows nothing.
+type Capabilities struct {
+	Read  []string
+	Write []string
+}
+
+func init() {
+	register("файл", "file.js", Capabilities{}.fileFunctions())
+	modules["файл"].configured = true
+}
+
+// Modules returns the builtin modules whose behaviour depends on
+// capabilities, to be bound in place of the ones that allow nothing.
+func (capabilities Capabilities) Modules() map[string]*object.Module {
+	return map[string]*object.Module{
+		"файл": newModule("файл", capabilities.fileFunctions()),
+	}
+}
+
+// javaScript passes capabilities to a JavaScript module that takes them.
+func (capabilities Capabilities) javaScript(script string) string {
+	read, write := capabilities.Read, capabilities.Write
+	if read == nil {
+		read = []string{}
+	}
+	if write == nil {
+		write = []string{}
+	}
+
+	encoded, err := json.Marshal(map[string][]string{"read": read, "write": write})
+	if err != nil {
+		panic(err)
+	}
+
+	return strings.TrimSpace(script) + "(" + string(encoded) + ")"
+}
+
+func (capabilities Capabilities) fileFunctions() map[string]object.BuiltinFunction {
+	return map[string]object.BuiltinFunction{
+		"оку":       capabilities.readFile,
+		"жаз":       capabilities.writeFile,
+		"кошуп_жаз": capabilities.appendFile,
+		"каталог":   capabilities.listDirectory,
+		"барбы":     capabilities.exists,
+	}
+}
+
+func (capabilities Capabilities) readFile(args ...object.Object) object.Object {
+	path, err := capabilities.readable("файл.оку", args, object.STRING_OBJ)
+	if err != nil {
+		return err
+	}
+
+	content, readError := os.ReadFile(path)
+	if readError != nil {
+		return fileError("файл.оку", path, readError)
+	}
+
+	return &object.String{Value: string(content)}
+}
+
+func (capabilities Capabilities) writeFile(args ...object.Object) object.Object {
+	path, err := capabilities.writable("файл.жаз", args, object.STRING_OBJ, object.STRING_OBJ)
+	if err != nil {
+		return err
+	}
+
+	if writeError := os.WriteFile(path, []byte(args[1].(*object.String).Value), 0644); writeError != nil {
+		return fileError("файл.жаз", path, writeError)
+	}
+
+	return nil
+}
+
+func (capabilities Capabilities) appendFile(args ...object.Object) object.Object {
+	path, err := capabilities.writable("файл.кошуп_жаз", args, object.STRING_OBJ, object.STRING_OBJ)
+	if err != nil {
+		return err
+	}
+
+	file, openError := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if openError != nil {
+		return fileError("файл.кошуп_жаз", path, openError)
+	}
+	defer file.Close()
+
+	if _, writeError := file.WriteString(args[1].(*object.String).Value); writeError != nil {
+		return fileError("файл.кошуп_жаз", path, writeError)
+	}
+
+	return nil
+}
+
+// listDirectory returns the names in a directory in the byte order of
+// their UTF-8 spelling.
+func (capabilities Capabilities) listDirectory(args ...object.Object) object.Object {
+	path, err := capabilities.readable("файл.каталог", args, object.STRING_OBJ)
+	if err != nil {
+		return err
+	}
+
+	entries, readError := os.ReadDir(path)
+	if readError != nil {
+		return fileError("файл.каталог", path, readError)
+	}
+
+	elements := make([]object.Object, len(entries))
+	for i, entry := range entries {
+		elements[i] = &object.String{Value: entry.Name()}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func (capabilities Capabilities) exists(args ...object.Object) object.Object {
+	path, err := capabilities.readable("файл.барбы", args, object.STRING_OBJ)
+	if err != nil {
+		return err
+	}
+
+	_, statError := os.Stat(path)
+	return object.NativeBoolean(statError == nil)
+}
+
+// readable and writable check the arguments of function, whose first
+// one is a path, and return the path when the capabilities allow it.
+func (capabilities Capabilities) readable(function string, args []object.Object, types ...object.ObjectType) (string, *object.Error) {
+	return checkPath(function, capabilities.Read, "окууга", args, types)
+}
+
+func (capabilities Capabilities) writable(function string, args []object.Object, types ...object.ObjectType) (string, *object.Error) {
+	return checkPath(function, capabilities.Write, "жазууга", args, types)
+}
+
+func checkPath(function string, roots []string, action string, args []object.Object, types []object.ObjectType) (string, *object.Error) {
+	if err := checkArguments(function, args, types...); err != nil {
+		return "", err
+	}
+
+	path := args[0].(*object.String).Value
+	if !allows(roots, path) {
+		return "", newError("%s: %s %s уруксат жок", function, path, action)
+	}
+
+	return path, nil
+}
+
+// allows reports whether path lies inside one of the directories in
+// roots.
+func allows(roots []string, path string) bool {
+	resolved, err := resolve(path)
+	if err != nil {
+		return false
+	}
+
+	for _, root := range roots {
+		resolvedRoot, err := resolve(root)
+		if err != nil {
+			continue
+		}
+
+		relative, err := filepath.Rel(resolvedRoot, resolved)
+		if err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolve makes path absolute and follows its symbolic links, so that a
+// link cannot lead out of an allowed directory. A path that does not
+// exist yet resolves through its nearest existing parent.
+func resolve(path string) (string, error) {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := filepath.EvalSymlinks(absolute)
+	if err == nil {
+		return resolved, nil
+	}
+	// A link to nowhere would be followed by a write.
+	if _, statError := os.Lstat(absolute); !errors.Is(err, fs.ErrNotExist) || statError == nil {
+		return "", err
+	}
+
+	parent := filepath.Dir(absolute)
+	if parent == absolute {
+		return absolute, nil
+	}
+	resolvedParent, err := resolve(parent)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(resolvedParent, filepath.Base(absolute)), nil
+}
+
+// fileError describes an error of the os package in Kyrgyz.
+func fileError(function string, path string, err error) *object.Error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return newError("%s: %s табылган жок", function, path)
+	case errors.Is(err, fs.ErrPermission):
+		return newError("%s: %s үчүн системада уруксат жок", function, path)
+	case errors.Is(err, syscall.EISDIR):
+		return newError("%s: %s файл эмес, папка", function, path)
+	case errors.Is(err, syscall.ENOTDIR):
+		return newError("%s: %s папка эмес", function, path)
+	default:
+		return newError("%s: %s: %s", function, path, err)
+	}
+}
@@ -0,0 +1,94 @@
+package stdlib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+func TestFileModule(t *testing.T) {
+	root := t.TempDir()
+	data := filepath.Join(root, "data")
+	out := filepath.Join(root, "out")
+	for _, directory := range []string{data, out, filepath.Join(data, "ички")} {
+		if err := os.Mkdir(directory, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(data, "сандар.csv"), []byte("1,2\n3,4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "купуя.txt"), []byte("сыр"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "купуя.txt"), filepath.Join(data, "шилтеме")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "жок"), filepath.Join(out, "бош_шилтеме")); err != nil {
+		t.Fatal(err)
+	}
+
+	file := Capabilities{Read: []string{data, out}, Write: []string{out}}.Modules()["файл"]
+	call := func(name string, args ...string) object.Object {
+		objects := make([]object.Object, len(args))
+		for i, arg := range args {
+			objects[i] = &object.String{Value: arg}
+		}
+		return file.Members[name].(*object.Builtin).Function(objects...)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"оку", []string{filepath.Join(data, "сандар.csv")}, "1,2\n3,4\n"},
+		{"оку", []string{filepath.Join(data, "ички", "..", "сандар.csv")}, "1,2\n3,4\n"},
+		{"жаз", []string{filepath.Join(out, "отчет.txt"), "биринчи\n"}, "бош"},
+		{"кошуп_жаз", []string{filepath.Join(out, "отчет.txt"), "экинчи\n"}, "бош"},
+		{"кошуп_жаз", []string{filepath.Join(out, "жаңы.txt"), "бир"}, "бош"},
+		{"оку", []string{filepath.Join(out, "отчет.txt")}, "биринчи\nэкинчи\n"},
+		{"каталог", []string{data}, "[ички, сандар.csv, шилтеме]"},
+		{"барбы", []string{filepath.Join(data, "сандар.csv")}, "туура"},
+		{"барбы", []string{filepath.Join(data, "жок.csv")}, "ката"},
+
+		{"оку", []string{filepath.Join(root, "купуя.txt")}, "КАТА: файл.оку: " + filepath.Join(root, "купуя.txt") + " окууга уруксат жок"},
+		{"оку", []string{filepath.Join(data, "..", "купуя.txt")}, "КАТА: файл.оку: " + filepath.Join(data, "..", "купуя.txt") + " окууга уруксат жок"},
+		{"оку", []string{filepath.Join(data, "шилтеме")}, "КАТА: файл.оку: " + filepath.Join(data, "шилтеме") + " окууга уруксат жок"},
+		{"барбы", []string{root}, "КАТА: файл.барбы: " + root + " окууга уруксат жок"},
+		{"жаз", []string{filepath.Join(data, "x"), ""}, "КАТА: файл.жаз: " + filepath.Join(data, "x") + " жазууга уруксат жок"},
+		{"жаз", []string{filepath.Join(out, "бош_шилтеме"), ""}, "КАТА: файл.жаз: " + filepath.Join(out, "бош_шилтеме") + " жазууга уруксат жок"},
+		{"жаз", []string{out + "2", ""}, "КАТА: файл.жаз: " + out + "2 жазууга уруксат жок"},
+		{"оку", []string{filepath.Join(data, "жок.csv")}, "КАТА: файл.оку: " + filepath.Join(data, "жок.csv") + " табылган жок"},
+		{"оку", []string{filepath.Join(data, "ички")}, "КАТА: файл.оку: " + filepath.Join(data, "ички") + " файл эмес, папка"},
+		{"каталог", []string{filepath.Join(data, "сандар.csv")}, "КАТА: файл.каталог: " + filepath.Join(data, "сандар.csv") + " папка эмес"},
+		{"жаз", []string{filepath.Join(out, "жок", "x"), ""}, "КАТА: файл.жаз: " + filepath.Join(out, "жок", "x") + " табылган жок"},
+		{"оку", nil, "КАТА: файл.оку: 1 аргумент керек, 0 берилди"},
+	}
+
+	for _, test := range tests {
+		result := call(test.name, test.args...)
+		if result == nil {
+			result = object.NULL
+		}
+		if result.Inspect() != test.expected {
+			t.Errorf("файл.%s%q: want=%q, got=%q", test.name, test.args, test.expected, result.Inspect())
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "жок")); err == nil {
+		t.Errorf("a write followed a link out of the allowed directory")
+	}
+}
+
+func TestFileModuleAllowsNothing(t *testing.T) {
+	file, _ := Lookup("файл")
+	path := &object.String{Value: os.DevNull}
+
+	result := file.Members["оку"].(*object.Builtin).Function(path)
+	if expected := "КАТА: файл.оку: " + os.DevNull + " окууга уруксат жок"; result.Inspect() != expected {
+		t.Errorf("want=%q, got=%q", expected, result.Inspect())
+	}
+}
@@ -0,0 +1,79 @@
+(() => {
+  // Values print as the evaluator's Inspect prints them.
+  const show = (value) => {
+    if (value === null || value === undefined) return "бош";
+    if (value === true) return "туура";
+    if (value === false) return "ката";
+    if (Array.isArray(value)) return "[" + value.map(show).join(", ") + "]";
+    if (value instanceof Map) return "{" + Array.from(value, ([key, element]) => show(key) + ": " + show(element)).join(", ") + "}";
+    if (typeof value === "function") return "функция";
+    return String(value);
+  };
+
+  const typeName = (value) => {
+    if (value === null || value === undefined) return "БОШ";
+    if (typeof value === "boolean") return "ЛОГИКАЛЫК";
+    if (typeof value === "string") return "САП";
+    if (typeof value === "bigint" || Number.isSafeInteger(value)) return "БҮТҮН_САН";
+    if (typeof value === "number" || value instanceof Number) return "БӨЛЧӨК_САН";
+    if (Array.isArray(value)) return "ТИЗМЕ";
+    if (value instanceof Map) return "СӨЗДҮК";
+    if (typeof value === "function") return "ФУНКЦИЯ";
+    return "МОДУЛЬ";
+  };
+
+  const format = (template, values) => {
+    let used = 0;
+    const text = template.replace(/\{\{|\}\}|\{\}/g, (match) => {
+      if (match !== "{}") return match[0];
+      return used < values.length ? show(values[used++]) : (used++, "");
+    });
+    if (used !== values.length) throw new Error(`жаз: калыпта ${used} орун бар, ${values.length} маани берилди`);
+    return text;
+  };
+
+  // Browsers have prompt; node reads standard input a byte at a time, so
+  // that nothing after the line is consumed.
+  const readLine = (message) => {
+    if (typeof process === "undefined" && typeof prompt === "function") return prompt(message ?? "");
+    if (message !== undefined) process.stdout.write(message);
+    const fs = require("fs");
+    const bytes = [];
+    const buffer = Buffer.alloc(1);
+    while (fs.readSync(0, buffer, 0, 1, null) === 1) {
+      if (buffer[0] === 10) break;
+      bytes.push(buffer[0]);
+    }
+    if (bytes.length === 0 && buffer[0] !== 10) return null;
+    const line = Buffer.from(bytes).toString("utf8");
+    return line.endsWith("\r") ? line.slice(0, -1) : line;
+  };
+
+  return {
+    "көрсөтүү": (...values) => {
+      console.log(values.map(show).join(" "));
+      return null;
+    },
+    "жаз": (...args) => {
+      if (args.length === 0) throw new Error("жаз: жок дегенде бир аргумент керек");
+      if (typeof args[0] !== "string") throw new Error(`жаз: 1-аргумент САП болушу керек, ${typeName(args[0])} берилди`);
+      console.log(format(args[0], args.slice(1)));
+      return null;
+    },
+    "окуу": (message) => readLine(message),
+    // JavaScript errors do not know where in the alipp source they
+    // happened, so only the message is filled in.
+    "ката_билдирүү": (...args) => {
+      if (args.length !== 1) throw new Error(`ката_билдирүү: 1 аргумент керек, ${args.length} берилди`);
+      const action = args[0];
+      if (typeof action !== "function") throw new Error(`ката_билдирүү: 1-аргумент ФУНКЦИЯ болушу керек, ${typeName(action)} берилди`);
+      if (action.length !== 0) throw new Error(`ката_билдирүү: функция аргументсиз болушу керек, ${action.length} параметри бар`);
+      try {
+        action();
+        return null;
+      } catch (error) {
+        return new Map([["билдирүү", error.message], ["сап", null], ["тилке", null], ["чакыруулар", []]]);
+      }
+    },
+  };
+})()
@@ -0,0 +1,77 @@
+((capabilities) => {
+  // Paths are checked as in file.go: made absolute with their symbolic
+  // links followed, and then looked for inside an allowed directory.
+  const fs = require("fs");
+  const path = require("path");
+
+  const resolve = (target) => {
+    const absolute = path.resolve(target);
+    try {
+      return fs.realpathSync(absolute);
+    } catch (error) {
+      let isLink = true;
+      try {
+        fs.lstatSync(absolute);
+      } catch {
+        isLink = false;
+      }
+      if (error.code !== "ENOENT" || isLink) return null;
+    }
+    const parent = path.dirname(absolute);
+    if (parent === absolute) return absolute;
+    const resolvedParent = resolve(parent);
+    return resolvedParent === null ? null : path.join(resolvedParent, path.basename(absolute));
+  };
+
+  const allows = (roots, target) => {
+    const resolved = resolve(target);
+    if (resolved === null) return false;
+    return roots.some((root) => {
+      const resolvedRoot = resolve(root);
+      if (resolvedRoot === null) return false;
+      const relative = path.relative(resolvedRoot, resolved);
+      return relative !== ".." && !relative.startsWith(".." + path.sep) && !path.isAbsolute(relative);
+    });
+  };
+
+  const check = (name, roots, action, target) => {
+    if (!allows(roots, target)) throw new Error(`файл.${name}: ${target} ${action} уруксат жок`);
+  };
+
+  const failure = (name, target, error) => {
+    switch (error.code) {
+      case "ENOENT": return new Error(`файл.${name}: ${target} табылган жок`);
+      case "EACCES":
+      case "EPERM": return new Error(`файл.${name}: ${target} үчүн системада уруксат жок`);
+      case "EISDIR": return new Error(`файл.${name}: ${target} файл эмес, папка`);
+      case "ENOTDIR": return new Error(`файл.${name}: ${target} папка эмес`);
+      default: return new Error(`файл.${name}: ${target}: ${error.message}`);
+    }
+  };
+
+  const operation = (name, roots, action, run) => (target, ...args) => {
+    check(name, roots, action, target);
+    try {
+      return run(target, ...args);
+    } catch (error) {
+      throw failure(name, target, error);
+    }
+  };
+
+  const readable = (name, run) => operation(name, capabilities.read, "окууга", run);
+  const writable = (name, run) => operation(name, capabilities.write, "жазууга", run);
+
+  return {
+    "оку": readable("оку", (target) => fs.readFileSync(target, "utf8")),
+    "жаз": writable("жаз", (target, text) => {
+      fs.writeFileSync(target, text);
+      return null;
+    }),
+    "кошуп_жаз": writable("кошуп_жаз", (target, text) => {
+      fs.appendFileSync(target, text);
+      return null;
+    }),
+    "каталог": readable("каталог", (target) => fs.readdirSync(target).sort((a, b) => Buffer.compare(Buffer.from(a), Buffer.from(b)))),
+    "барбы": readable("барбы", (target) => fs.existsSync(target)),
+  };
+})
@@ -0,0 +1,242 @@
+(() => {
+  // The decoder and encoder follow json.go by hand, so that integers of
+  // any size survive, objects become Maps, and the errors are the same.
+  const normalize = (value) => value >= -9007199254740991n && value <= 9007199254740991n ? Number(value) : value;
+  // A float that would pass for an integer is a Number object.
+  const float = (value) => Number.isSafeInteger(value) ? new Number(value) : value;
+
+  const typeName = (value) => {
+    if (value === null || value === undefined) return "БОШ";
+    if (typeof value === "boolean") return "ЛОГИКАЛЫК";
+    if (typeof value === "string") return "САП";
+    if (typeof value === "bigint" || Number.isSafeInteger(value)) return "БҮТҮН_САН";
+    if (typeof value === "number" || value instanceof Number) return "БӨЛЧӨК_САН";
+    if (Array.isArray(value)) return "ТИЗМЕ";
+    if (value instanceof Map) return "СӨЗДҮК";
+    if (typeof value === "function") return "ФУНКЦИЯ";
+    return "МОДУЛЬ";
+  };
+
+  const decode = (text) => {
+    let offset = 0;
+
+    const fail = (at, message) => {
+      const before = text.slice(0, at);
+      const line = before.split("\n").length;
+      const column = Array.from(before.slice(before.lastIndexOf("\n") + 1)).length + 1;
+      throw new Error(`json.оку: ${line}:${column}: ${message}`);
+    };
+    const unexpected = () => {
+      if (offset >= text.length) fail(offset, "JSON толук эмес");
+      fail(offset, `күтүлбөгөн «${String.fromCodePoint(text.codePointAt(offset))}»`);
+    };
+    const skipSpace = () => {
+      while (offset < text.length && " \t\n\r".includes(text[offset])) offset++;
+    };
+    const expect = (c) => {
+      skipSpace();
+      if (text[offset] !== c) unexpected();
+      offset++;
+    };
+    const digits = () => {
+      const start = offset;
+      while (offset < text.length && text[offset] >= "0" && text[offset] <= "9") offset++;
+      return offset - start;
+    };
+
+    const string = () => {
+      const start = offset++;
+      while (offset < text.length) {
+        const c = text[offset];
+        if (c === "\"") {
+          offset++;
+          try {
+            return JSON.parse(text.slice(start, offset));
+          } catch {
+            fail(start, "туура эмес сап");
+          }
+        } else if (c === "\\") {
+          offset += 2;
+        } else if (c < " ") {
+          unexpected();
+        } else {
+          offset++;
+        }
+      }
+      offset = text.length;
+      unexpected();
+    };
+
+    const number = () => {
+      const start = offset;
+      if (text[offset] === "-") offset++;
+      if (text[offset] === "0") offset++;
+      else if (digits() === 0) unexpected();
+
+      let isFloat = false;
+      if (text[offset] === ".") {
+        isFloat = true;
+        offset++;
+        if (digits() === 0) unexpected();
+      }
+      if (text[offset] === "e" || text[offset] === "E") {
+        isFloat = true;
+        offset++;
+        if (text[offset] === "+" || text[offset] === "-") offset++;
+        if (digits() === 0) unexpected();
+      }
+
+      const literal = text.slice(start, offset);
+      if (!isFloat) return normalize(BigInt(literal));
+      const value = Number(literal);
+      if (!Number.isFinite(value)) fail(start, `${literal} саны өтө чоң`);
+      return float(value);
+    };
+
+    const value = () => {
+      skipSpace();
+      const c = text[offset];
+      if (c === "{") {
+        const hash = new Map();
+        offset++;
+        skipSpace();
+        if (text[offset] === "}") {
+          offset++;
+          return hash;
+        }
+        for (;;) {
+          skipSpace();
+          if (text[offset] !== "\"") unexpected();
+          const key = string();
+          expect(":");
+          hash.set(key, value());
+          skipSpace();
+          if (text[offset] === "}") {
+            offset++;
+            return hash;
+          }
+          expect(",");
+        }
+      }
+      if (c === "[") {
+        const elements = [];
+        offset++;
+        skipSpace();
+        if (text[offset] === "]") {
+          offset++;
+          return elements;
+        }
+        for (;;) {
+          elements.push(value());
+          skipSpace();
+          if (text[offset] === "]") {
+            offset++;
+            return elements;
+          }
+          expect(",");
+        }
+      }
+      if (c === "\"") return string();
+      if (c === "-" || (c >= "0" && c <= "9")) return number();
+      for (const [word, result] of [["true", true], ["false", false], ["null", null]]) {
+        if (text.startsWith(word, offset)) {
+          offset += word.length;
+          return result;
+        }
+      }
+      unexpected();
+    };
+
+    const result = value();
+    skipSpace();
+    if (offset < text.length) unexpected();
+    return result;
+  };
+
+  const escape = (text) => "\"" + Array.from(text, (c) => {
+    switch (c) {
+      case "\"": return "\\\"";
+      case "\\": return "\\\\";
+      case "\b": return "\\b";
+      case "\f": return "\\f";
+      case "\n": return "\\n";
+      case "\r": return "\\r";
+      case "\t": return "\\t";
+      default: return c < " " ? "\\u" + c.charCodeAt(0).toString(16).padStart(4, "0") : c;
+    }
+  }).join("") + "\"";
+
+  const byCodePoint = (a, b) => {
+    const left = Array.from(a, (c) => c.codePointAt(0)), right = Array.from(b, (c) => c.codePointAt(0));
+    for (let i = 0; i < left.length && i < right.length; i++) {
+      if (left[i] !== right[i]) return left[i] - right[i];
+    }
+    return left.length - right.length;
+  };
+
+  const encode = (value, options, indent) => {
+    const inner = indent + options.indent;
+    const newline = (at) => options.indent !== "" ? "\n" + at : "";
+
+    if (value === null || value === undefined) return "null";
+    if (typeof value === "boolean" || typeof value === "bigint") return String(value);
+    if (typeof value === "number" || value instanceof Number) {
+      if (!Number.isFinite(Number(value))) throw new Error(`json.жаз: ${value} JSON менен жазылбайт`);
+      // Safe integers are integers; any other number is a float, and
+      // keeps a ".0" so that it reads back as one.
+      const written = String(Number(value));
+      return typeof value === "number" && Number.isSafeInteger(value) || /[.e]/.test(written) ? written : written + ".0";
+    }
+    if (typeof value === "string") return escape(value);
+    if (Array.isArray(value)) {
+      if (value.length === 0) return "[]";
+      return "[" + value.map((element) => newline(inner) + encode(element, options, inner)).join(",") + newline(indent) + "]";
+    }
+    if (value instanceof Map) {
+      if (value.size === 0) return "{}";
+      const keys = Array.from(value.keys());
+      for (const key of keys) {
+        if (typeof key !== "string") throw new Error(`json.жаз: сөздүктүн ачкычы САП болушу керек, ${typeName(key)} берилди`);
+      }
+      if (options.sortKeys) keys.sort(byCodePoint);
+      const separator = options.indent !== "" ? ": " : ":";
+      return "{" + keys.map((key) => newline(inner) + escape(key) + separator + encode(value.get(key), options, inner)).join(",") + newline(indent) + "}";
+    }
+    throw new Error(`json.жаз: ${typeName(value)} JSON менен жазылбайт`);
+  };
+
+  const configure = (hash) => {
+    const options = { indent: "", sortKeys: false };
+    if (!(hash instanceof Map)) throw new Error(`json.жаз: 2-аргумент СӨЗДҮК болушу керек, ${typeName(hash)} берилди`);
+    for (const [key, value] of hash) {
+      if (key === "чегинүү") {
+        if (typeof value === "string") {
+          options.indent = value;
+        } else if (typeName(value) === "БҮТҮН_САН") {
+          if (value < 0 || value > 10) throw new Error(`json.жаз: чегинүү 0дөн 10го чейин болушу керек, ${value} берилди`);
+          options.indent = " ".repeat(Number(value));
+        } else {
+          throw new Error(`json.жаз: чегинүү сан же сап болушу керек, ${typeName(value)} берилди`);
+        }
+      } else if (key === "иреттөө") {
+        if (typeof value !== "boolean") throw new Error(`json.жаз: иреттөө ЛОГИКАЛЫК болушу керек, ${typeName(value)} берилди`);
+        options.sortKeys = value;
+      } else {
+        throw new Error(`json.жаз: белгисиз параметр ${key === true ? "туура" : key === false ? "ката" : key}`);
+      }
+    }
+    return options;
+  };
+
+  return {
+    "оку": (...args) => {
+      if (args.length !== 1) throw new Error(`json.оку: 1 аргумент керек, ${args.length} берилди`);
+      if (typeof args[0] !== "string") throw new Error(`json.оку: 1-аргумент САП болушу керек, ${typeName(args[0])} берилди`);
+      return decode(args[0]);
+    },
+    "жаз": (...args) => {
+      if (args.length !== 1 && args.length !== 2) throw new Error(`json.жаз: 1 же 2 аргумент керек, ${args.length} берилди`);
+      return encode(args[0], args.length === 2 ? configure(args[1]) : { indent: "", sortKeys: false }, "");
+    },
+  };
+})()
@@ -0,0 +1,59 @@
+(() => {
+  // Integers are numbers while they are safe integers and BigInts past
+  // that, as in the arithmetic of the compiled program.
+  const normalize = (value) => value >= -9007199254740991n && value <= 9007199254740991n ? Number(value) : value;
+  const isInteger = (value) => typeof value === "bigint" || Number.isSafeInteger(value);
+  // A float that would pass for an integer is a Number object.
+  const float = (value) => Number.isSafeInteger(value) ? new Number(value) : value;
+  const maxPowerBits = 1 << 20;
+
+  const round = (name, direction) => (value) => {
+    if (isInteger(value)) return value;
+    if (!Number.isFinite(Number(value))) throw new Error(`математика.${name}: ${value} бүтүн санга айланбайт`);
+    const rounded = direction(Number(value));
+    return Number.isSafeInteger(rounded) ? rounded + 0 : normalize(BigInt(rounded));
+  };
+
+  // mulberry32, as in math.go.
+  let state = (Math.random() * 2 ** 32) >>> 0;
+  const next = () => {
+    state = (state + 0x6D2B79F5) >>> 0;
+    let t = Math.imul(state ^ (state >>> 15), state | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return (t ^ (t >>> 14)) >>> 0;
+  };
+
+  return {
+    "абсолют": (value) => typeof value === "bigint" ? (value < 0n ? -value : value) : isInteger(value) ? Math.abs(value) : float(Math.abs(value)),
+    "эң_кичине": (...values) => values.reduce((best, value) => value < best ? value : best),
+    "эң_чоң": (...values) => values.reduce((best, value) => value > best ? value : best),
+    "даража": (base, exponent) => {
+      if (!isInteger(base) || !isInteger(exponent) || exponent < 0) return float(Math.pow(Number(base), Number(exponent)));
+      const magnitude = BigInt(base) < 0n ? -BigInt(base) : BigInt(base);
+      const bits = magnitude.toString(2).length;
+      if (magnitude > 1n && BigInt(exponent) > BigInt(Math.floor(maxPowerBits / bits))) {
+        throw new Error("математика.даража: натыйжа өтө чоң");
+      }
+      return normalize(BigInt(base) ** BigInt(exponent));
+    },
+    "тамыр": (value) => {
+      if (value < 0) throw new Error("математика.тамыр: терс сандын тамыры жок");
+      return float(Math.sqrt(Number(value)));
+    },
+    "төмөн_тегеректе": round("төмөн_тегеректе", Math.floor),
+    "жогору_тегеректе": round("жогору_тегеректе", Math.ceil),
+    "кокус": (...args) => {
+      const fraction = next() / 2 ** 32;
+      if (args.length === 0) return float(fraction);
+      const limit = args[0];
+      if (!isInteger(limit) || limit <= 0 || limit > 2 ** 53) {
+        throw new Error(`математика.кокус: чек 1ден 2^53кө чейин болушу керек, ${limit} берилди`);
+      }
+      return Math.floor(fraction * Number(limit));
+    },
+    "үрөн": (value) => {
+      state = Number(BigInt.asUintN(32, BigInt(value)));
+      return null;
+    },
+  };
+})()
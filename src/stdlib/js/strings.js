@@ -0,0 +1,48 @@
+(() => {
+  // Case mapping and collation follow strings.go and collate.go.
+  const alphabet = Array.from("абвгдеёжзийклмнңоөпрстуүфхцчшщъыьэюя");
+  const ranks = new Map(alphabet.map((letter, rank) => [letter, rank]));
+
+  const mapRunes = (text, map) => Array.from(text, (letter) => {
+    const mapped = map(letter);
+    return Array.from(mapped).length === 1 ? mapped : letter;
+  }).join("");
+
+  const simpleLower = (letter) => letter === "İ" ? "i" : mapRunes(letter, (r) => r.toLowerCase());
+  const turkicLower = (letter) => letter === "I" ? "ı" : simpleLower(letter);
+  const key = (letter) => {
+    const lower = simpleLower(letter);
+    return ranks.has(lower) ? 0x430 * 256 + ranks.get(lower) : lower.codePointAt(0) * 256;
+  };
+  const isLower = (letter) => letter !== letter.toUpperCase() && letter === letter.toLowerCase();
+
+  const compare = (a, b) => {
+    const left = Array.from(a), right = Array.from(b);
+    for (let i = 0; i < left.length && i < right.length; i++) {
+      const difference = key(left[i]) - key(right[i]);
+      if (difference !== 0) return Math.sign(difference);
+    }
+    if (left.length !== right.length) return Math.sign(left.length - right.length);
+    for (let i = 0; i < left.length; i++) {
+      if (left[i] !== right[i]) {
+        if (isLower(left[i]) !== isLower(right[i])) return isLower(left[i]) ? -1 : 1;
+        return Math.sign(left[i].codePointAt(0) - right[i].codePointAt(0));
+      }
+    }
+    return 0;
+  };
+
+  return {
+    "узундук": (text) => Array.from(text).length,
+    "бөл": (text, separator) => separator === "" ? Array.from(text) : text.split(separator),
+    "бириктир": (list, separator) => list.join(separator),
+    "кырк": (text) => text.trim(),
+    "алмаштыр": (text, old, replacement) => old !== "" ? text.split(old).join(replacement)
+      : text === "" ? replacement : replacement + Array.from(text).join(replacement) + replacement,
+    "камтыйбы": (text, part) => text.includes(part),
+    "чоң_тамга": (text) => mapRunes(text, (r) => r === "i" ? "İ" : r === "ı" ? "I" : r.toUpperCase()),
+    "кичине_тамга": (text) => mapRunes(text, turkicLower),
+    "алфавит_боюнча": compare,
+    "иреттө": (list) => [...list].sort(compare),
+  };
+})()
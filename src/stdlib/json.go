@@ -0,0 +1,479 @@
+package stdlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+func init() {
+	register("json", "json.js", map[string]object.BuiltinFunction{
+		"оку": decodeJSON,
+		"жаз": encodeJSON,
+	})
+}
+
+// decodeJSON parses a JSON text. Numbers without a fraction or exponent
+// become integers of any size, objects become hashes with their keys in
+// the order they appear.
+func decodeJSON(args ...object.Object) object.Object {
+	if err := checkArguments("json.оку", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	decoder := &jsonDecoder{text: args[0].(*object.String).Value}
+	value := decoder.value()
+	if decoder.err == nil {
+		decoder.skipSpace()
+		if decoder.offset < len(decoder.text) {
+			decoder.unexpected()
+		}
+	}
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	return value
+}
+
+// jsonDecoder reads a JSON text by hand rather than with encoding/json,
+// so that its errors and the JavaScript version's are the same.
+type jsonDecoder struct {
+	text   string
+	offset int
+	err    *object.Error
+}
+
+func (decoder *jsonDecoder) fail(offset int, message string) {
+	if decoder.err != nil {
+		return
+	}
+
+	line := 1 + strings.Count(decoder.text[:offset], "\n")
+	lineStart := strings.LastIndex(decoder.text[:offset], "\n") + 1
+	column := 1 + utf8.RuneCountInString(decoder.text[lineStart:offset])
+	decoder.err = newError("json.оку: %d:%d: %s", line, column, message)
+}
+
+func (decoder *jsonDecoder) unexpected() {
+	if decoder.offset >= len(decoder.text) {
+		decoder.fail(decoder.offset, "JSON толук эмес")
+		return
+	}
+
+	r, _ := utf8.DecodeRuneInString(decoder.text[decoder.offset:])
+	decoder.fail(decoder.offset, fmt.Sprintf("күтүлбөгөн «%c»", r))
+}
+
+func (decoder *jsonDecoder) skipSpace() {
+	for decoder.offset < len(decoder.text) && strings.IndexByte(" \t\n\r", decoder.text[decoder.offset]) >= 0 {
+		decoder.offset++
+	}
+}
+
+func (decoder *jsonDecoder) peek() byte {
+	if decoder.offset < len(decoder.text) {
+		return decoder.text[decoder.offset]
+	}
+	return 0
+}
+
+// expect consumes the byte c after any whitespace.
+func (decoder *jsonDecoder) expect(c byte) bool {
+	decoder.skipSpace()
+	if decoder.peek() != c {
+		decoder.unexpected()
+		return false
+	}
+
+	decoder.offset++
+	return true
+}
+
+func (decoder *jsonDecoder) value() object.Object {
+	decoder.skipSpace()
+
+	switch c := decoder.peek(); {
+	case c == '{':
+		return decoder.object()
+	case c == '[':
+		return decoder.array()
+	case c == '"':
+		if value, ok := decoder.string(); ok {
+			return &object.String{Value: value}
+		}
+	case c == '-' || c >= '0' && c <= '9':
+		return decoder.number()
+	case strings.HasPrefix(decoder.text[decoder.offset:], "true"):
+		decoder.offset += len("true")
+		return object.TRUE
+	case strings.HasPrefix(decoder.text[decoder.offset:], "false"):
+		decoder.offset += len("false")
+		return object.FALSE
+	case strings.HasPrefix(decoder.text[decoder.offset:], "null"):
+		decoder.offset += len("null")
+		return object.NULL
+	default:
+		decoder.unexpected()
+	}
+
+	return nil
+}
+
+func (decoder *jsonDecoder) object() object.Object {
+	hash := object.NewHash()
+	decoder.offset++
+
+	decoder.skipSpace()
+	if decoder.peek() == '}' {
+		decoder.offset++
+		return hash
+	}
+
+	for decoder.err == nil {
+		decoder.skipSpace()
+		if decoder.peek() != '"' {
+			decoder.unexpected()
+			break
+		}
+		key, ok := decoder.string()
+		if !ok || !decoder.expect(':') {
+			break
+		}
+
+		value := decoder.value()
+		if decoder.err != nil {
+			break
+		}
+		hash.Set(&object.String{Value: key}, value)
+
+		decoder.skipSpace()
+		if decoder.peek() == '}' {
+			decoder.offset++
+			return hash
+		}
+		decoder.expect(',')
+	}
+
+	return nil
+}
+
+func (decoder *jsonDecoder) array() object.Object {
+	elements := []object.Object{}
+	decoder.offset++
+
+	decoder.skipSpace()
+	if decoder.peek() == ']' {
+		decoder.offset++
+		return &object.Array{Elements: elements}
+	}
+
+	for decoder.err == nil {
+		element := decoder.value()
+		if decoder.err != nil {
+			break
+		}
+		elements = append(elements, element)
+
+		decoder.skipSpace()
+		if decoder.peek() == ']' {
+			decoder.offset++
+			return &object.Array{Elements: elements}
+		}
+		decoder.expect(',')
+	}
+
+	return nil
+}
+
+// string finds the end of a string literal and leaves its escapes to
+// encoding/json.
+func (decoder *jsonDecoder) string() (string, bool) {
+	start := decoder.offset
+	decoder.offset++
+
+	for decoder.offset < len(decoder.text) {
+		switch c := decoder.text[decoder.offset]; {
+		case c == '"':
+			decoder.offset++
+			var value string
+			if err := json.Unmarshal([]byte(decoder.text[start:decoder.offset]), &value); err != nil {
+				decoder.fail(start, "туура эмес сап")
+				return "", false
+			}
+			return value, true
+		case c == '\\':
+			decoder.offset += 2
+		case c < 0x20:
+			decoder.unexpected()
+			return "", false
+		default:
+			decoder.offset++
+		}
+	}
+
+	decoder.offset = len(decoder.text)
+	decoder.unexpected()
+	return "", false
+}
+
+func (decoder *jsonDecoder) number() object.Object {
+	start := decoder.offset
+	digits := func() int {
+		count := 0
+		for c := decoder.peek(); c >= '0' && c <= '9'; c = decoder.peek() {
+			decoder.offset++
+			count++
+		}
+		return count
+	}
+
+	if decoder.peek() == '-' {
+		decoder.offset++
+	}
+	if decoder.peek() == '0' {
+		decoder.offset++
+	} else if digits() == 0 {
+		decoder.unexpected()
+		return nil
+	}
+
+	isFloat := false
+	if decoder.peek() == '.' {
+		isFloat = true
+		decoder.offset++
+		if digits() == 0 {
+			decoder.unexpected()
+			return nil
+		}
+	}
+	if c := decoder.peek(); c == 'e' || c == 'E' {
+		isFloat = true
+		decoder.offset++
+		if c := decoder.peek(); c == '+' || c == '-' {
+			decoder.offset++
+		}
+		if digits() == 0 {
+			decoder.unexpected()
+			return nil
+		}
+	}
+
+	literal := decoder.text[start:decoder.offset]
+	if isFloat {
+		// Numbers too small for a float round to zero; ones too big have
+		// no value to take.
+		value, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			decoder.fail(start, fmt.Sprintf("%s саны өтө чоң", literal))
+			return nil
+		}
+		return &object.Float{Value: value}
+	}
+	if value, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return &object.Integer{Value: value}
+	}
+	value, _ := new(big.Int).SetString(literal, 10)
+	return object.NewInteger(value)
+}
+
+// encodeJSON writes a value as JSON. An optional hash of options sets
+// "чегинүү", the indentation as a number of spaces or a string, and
+// "иреттөө", whether object keys are sorted.
+func encodeJSON(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("json.жаз: 1 же 2 аргумент керек, %d берилди", len(args))
+	}
+
+	encoder := &jsonEncoder{}
+	if len(args) == 2 {
+		options, ok := args[1].(*object.Hash)
+		if !ok {
+			return newError("json.жаз: 2-аргумент %s болушу керек, %s берилди", object.HASH_OBJ, args[1].Type())
+		}
+		if err := encoder.configure(options); err != nil {
+			return err
+		}
+	}
+
+	var out strings.Builder
+	if err := encoder.encode(&out, args[0], ""); err != nil {
+		return err
+	}
+
+	return &object.String{Value: out.String()}
+}
+
+type jsonEncoder struct {
+	indent   string
+	sortKeys bool
+}
+
+func (encoder *jsonEncoder) configure(options *object.Hash) *object.Error {
+	for _, key := range options.Keys {
+		pair := options.Pairs[key]
+
+		switch key {
+		case object.HashKey{Type: object.STRING_OBJ, Value: "чегинүү"}:
+			switch value := pair.Value.(type) {
+			case *object.Integer:
+				if value.Big != nil || value.Value < 0 || value.Value > 10 {
+					return newError("json.жаз: чегинүү 0дөн 10го чейин болушу керек, %s берилди", value.Inspect())
+				}
+				encoder.indent = strings.Repeat(" ", int(value.Value))
+			case *object.String:
+				encoder.indent = value.Value
+			default:
+				return newError("json.жаз: чегинүү сан же сап болушу керек, %s берилди", pair.Value.Type())
+			}
+		case object.HashKey{Type: object.STRING_OBJ, Value: "иреттөө"}:
+			value, ok := pair.Value.(*object.Boolean)
+			if !ok {
+				return newError("json.жаз: иреттөө %s болушу керек, %s берилди", object.BOOLEAN_OBJ, pair.Value.Type())
+			}
+			encoder.sortKeys = value.Value
+		default:
+			return newError("json.жаз: белгисиз параметр %s", pair.Key.Inspect())
+		}
+	}
+
+	return nil
+}
+
+// encode writes value at the nesting given by indent, the whitespace
+// that starts its line when the output is indented.
+func (encoder *jsonEncoder) encode(out *strings.Builder, value object.Object, indent string) *object.Error {
+	switch value := value.(type) {
+	case *object.Null:
+		out.WriteString("null")
+	case *object.Boolean:
+		out.WriteString(strconv.FormatBool(value.Value))
+	case *object.Integer:
+		out.WriteString(value.Inspect())
+	case *object.Float:
+		if math.IsNaN(value.Value) || math.IsInf(value.Value, 0) {
+			return newError("json.жаз: %s JSON менен жазылбайт", value.Inspect())
+		}
+		out.WriteString(floatJSON(value))
+	case *object.String:
+		writeJSONString(out, value.Value)
+	case *object.Array:
+		if len(value.Elements) == 0 {
+			out.WriteString("[]")
+			return nil
+		}
+
+		out.WriteString("[")
+		for i, element := range value.Elements {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			encoder.newline(out, indent+encoder.indent)
+			if err := encoder.encode(out, element, indent+encoder.indent); err != nil {
+				return err
+			}
+		}
+		encoder.newline(out, indent)
+		out.WriteString("]")
+	case *object.Hash:
+		return encoder.encodeHash(out, value, indent)
+	default:
+		return newError("json.жаз: %s JSON менен жазылбайт", value.Type())
+	}
+
+	return nil
+}
+
+func (encoder *jsonEncoder) encodeHash(out *strings.Builder, hash *object.Hash, indent string) *object.Error {
+	if len(hash.Keys) == 0 {
+		out.WriteString("{}")
+		return nil
+	}
+
+	keys := append([]object.HashKey{}, hash.Keys...)
+	for _, key := range keys {
+		if key.Type != object.STRING_OBJ {
+			return newError("json.жаз: сөздүктүн ачкычы %s болушу керек, %s берилди", object.STRING_OBJ, key.Type)
+		}
+	}
+	if encoder.sortKeys {
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Value < keys[j].Value })
+	}
+
+	separator := ":"
+	if encoder.indent != "" {
+		separator = ": "
+	}
+
+	out.WriteString("{")
+	for i, key := range keys {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		encoder.newline(out, indent+encoder.indent)
+		writeJSONString(out, key.Value)
+		out.WriteString(separator)
+		if err := encoder.encode(out, hash.Pairs[key].Value, indent+encoder.indent); err != nil {
+			return err
+		}
+	}
+	encoder.newline(out, indent)
+	out.WriteString("}")
+
+	return nil
+}
+
+func (encoder *jsonEncoder) newline(out *strings.Builder, indent string) {
+	if encoder.indent != "" {
+		out.WriteString("\n" + indent)
+	}
+}
+
+// writeJSONString escapes like JSON.stringify: quotes, backslashes and
+// control characters, and nothing else.
+func writeJSONString(out *strings.Builder, value string) {
+	out.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			out.WriteString(`\"`)
+		case '\\':
+			out.WriteString(`\\`)
+		case '\b':
+			out.WriteString(`\b`)
+		case '\f':
+			out.WriteString(`\f`)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\t':
+			out.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(out, `\u%04x`, r)
+			} else {
+				out.WriteRune(r)
+			}
+		}
+	}
+	out.WriteByte('"')
+}
+
+// floatJSON writes a float so that it reads back as one: with ".0" when
+// it would otherwise look like an integer.
+func floatJSON(value *object.Float) string {
+	text := value.Inspect()
+	if strings.ContainsAny(text, ".e") {
+		return text
+	}
+
+	return text + ".0"
+}
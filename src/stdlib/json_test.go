@@ -0,0 +1,156 @@
+package stdlib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+func callJSON(name string, args ...object.Object) object.Object {
+	module, _ := Lookup("json")
+	return module.Members[name].(*object.Builtin).Function(args...)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		input   string
+		inspect string
+		compact string
+	}{
+		{`{"аты": "Айбек", "жашы": 30, "бою": 1.75, "тилдер": ["ky", "ru"], "бош": null, "ок": true}`,
+			"{аты: Айбек, жашы: 30, бою: 1.75, тилдер: [ky, ru], бош: бош, ок: туура}",
+			`{"аты":"Айбек","жашы":30,"бою":1.75,"тилдер":["ky","ru"],"бош":null,"ок":true}`},
+		{"123456789012345678901234567890", "123456789012345678901234567890", "123456789012345678901234567890"},
+		{"-9223372036854775809", "-9223372036854775809", "-9223372036854775809"},
+		{" [1.5e3, -0, 0.000001, 2.0, 1e21, 1e-400] ", "[1500, 0, 0.000001, 2, 1e+21, 0]", "[1500.0,0,0.000001,2.0,1e+21,0.0]"},
+		{"[-2.0, 9007199254740993.0]", "[-2, 9007199254740992]", "[-2.0,9007199254740992.0]"},
+		{`"\"\\\/\b\f\n\r\t\u0001é😀"`, "\"\\/\b\f\n\r\t\x01é😀", `"\"\\/\b\f\n\r\t\u0001é😀"`},
+		{`{"б": 1, "а": {"в": []}, "б": 2}`, "{б: 2, а: {в: []}}", `{"б":2,"а":{"в":[]}}`},
+		{"{}", "{}", "{}"},
+	}
+
+	for _, test := range tests {
+		decoded := callJSON("оку", &object.String{Value: test.input})
+		if decoded.Inspect() != test.inspect {
+			t.Errorf("json.оку(%s): want=%q, got=%q", test.input, test.inspect, decoded.Inspect())
+			continue
+		}
+
+		encoded := callJSON("жаз", decoded)
+		if encoded.Inspect() != test.compact {
+			t.Errorf("json.жаз(%s): want=%q, got=%q", test.inspect, test.compact, encoded.Inspect())
+		}
+		if again := callJSON("оку", encoded); typedInspect(again) != typedInspect(decoded) {
+			t.Errorf("round trip of %s gave %s", test.input, typedInspect(again))
+		}
+	}
+}
+
+// typedInspect is Inspect with the type of every value that is not a
+// list or a hash, so that 2 and 2.0 differ.
+func typedInspect(value object.Object) string {
+	switch value := value.(type) {
+	case *object.Array:
+		elements := []string{}
+		for _, element := range value.Elements {
+			elements = append(elements, typedInspect(element))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *object.Hash:
+		pairs := []string{}
+		for _, key := range value.Keys {
+			pair := value.Pairs[key]
+			pairs = append(pairs, typedInspect(pair.Key)+": "+typedInspect(pair.Value))
+		}
+		return "{" + strings.Join(pairs, ", ") + "}"
+	}
+
+	return string(value.Type()) + " " + value.Inspect()
+}
+
+func TestJSONOptions(t *testing.T) {
+	value := callJSON("оку", &object.String{Value: `{"б": [1, {}], "а": []}`})
+
+	options := func(pairs ...object.Object) *object.Hash {
+		hash := object.NewHash()
+		for i := 0; i < len(pairs); i += 2 {
+			hash.Set(pairs[i].(object.Hashable), pairs[i+1])
+		}
+		return hash
+	}
+	indent := &object.String{Value: "чегинүү"}
+	sortKeys := &object.String{Value: "иреттөө"}
+
+	tests := []struct {
+		options  *object.Hash
+		expected string
+	}{
+		{options(), `{"б":[1,{}],"а":[]}`},
+		{options(sortKeys, object.TRUE), `{"а":[],"б":[1,{}]}`},
+		{options(indent, &object.Integer{Value: 2}), "{\n  \"б\": [\n    1,\n    {}\n  ],\n  \"а\": []\n}"},
+		{options(indent, &object.String{Value: "\t"}, sortKeys, object.TRUE), "{\n\t\"а\": [],\n\t\"б\": [\n\t\t1,\n\t\t{}\n\t]\n}"},
+		{options(indent, &object.Integer{Value: 11}), "КАТА: json.жаз: чегинүү 0дөн 10го чейин болушу керек, 11 берилди"},
+		{options(indent, object.TRUE), "КАТА: json.жаз: чегинүү сан же сап болушу керек, ЛОГИКАЛЫК берилди"},
+		{options(sortKeys, &object.Integer{Value: 1}), "КАТА: json.жаз: иреттөө ЛОГИКАЛЫК болушу керек, БҮТҮН_САН берилди"},
+		{options(&object.String{Value: "жок"}, object.TRUE), "КАТА: json.жаз: белгисиз параметр жок"},
+	}
+
+	for _, test := range tests {
+		if actual := callJSON("жаз", value, test.options).Inspect(); actual != test.expected {
+			t.Errorf("json.жаз with %s: want=%q, got=%q", test.options.Inspect(), test.expected, actual)
+		}
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"а" 1}`, "json.оку: 1:6: күтүлбөгөн «1»"},
+		{"[1,]", "json.оку: 1:4: күтүлбөгөн «]»"},
+		{"{\n  \"а\": 1", "json.оку: 2:9: JSON толук эмес"},
+		{`"\x"`, "json.оку: 1:1: туура эмес сап"},
+		{"\"а\nб\"", "json.оку: 1:3: күтүлбөгөн «\n»"},
+		{"01", "json.оку: 1:2: күтүлбөгөн «1»"},
+		{"[1,\n -1e400]", "json.оку: 2:2: -1e400 саны өтө чоң"},
+		{"-", "json.оку: 1:2: JSON толук эмес"},
+		{"1.", "json.оку: 1:3: JSON толук эмес"},
+		{"трю", "json.оку: 1:1: күтүлбөгөн «т»"},
+		{"[1] x", "json.оку: 1:5: күтүлбөгөн «x»"},
+		{"", "json.оку: 1:1: JSON толук эмес"},
+		{`"ачык`, "json.оку: 1:6: JSON толук эмес"},
+	}
+
+	for _, test := range tests {
+		result := callJSON("оку", &object.String{Value: test.input})
+		if err, ok := result.(*object.Error); !ok || err.Message != test.expected {
+			t.Errorf("json.оку(%q): want=%q, got=%q", test.input, test.expected, result.Inspect())
+		}
+	}
+
+	unsupported := []struct {
+		value    object.Object
+		expected string
+	}{
+		{&object.Builtin{Name: "көрсөтүү"}, "json.жаз: КУРУЛГАН_ФУНКЦИЯ JSON менен жазылбайт"},
+		{&object.Array{Elements: []object.Object{&object.Function{}}}, "json.жаз: ФУНКЦИЯ JSON менен жазылбайт"},
+		{&object.Float{Value: 1.0 / zero}, "json.жаз: Infinity JSON менен жазылбайт"},
+	}
+	hash := object.NewHash()
+	hash.Set(&object.Integer{Value: 1}, object.NULL)
+	unsupported = append(unsupported, struct {
+		value    object.Object
+		expected string
+	}{hash, "json.жаз: сөздүктүн ачкычы САП болушу керек, БҮТҮН_САН берилди"})
+
+	for _, test := range unsupported {
+		result := callJSON("жаз", test.value)
+		if err, ok := result.(*object.Error); !ok || err.Message != test.expected {
+			t.Errorf("json.жаз(%T): want=%q, got=%q", test.value, test.expected, result.Inspect())
+		}
+	}
+}
+
+var zero = 0.0
@@ -0,0 +1,237 @@
+package stdlib
+
+import (
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+func init() {
+	register("математика", "math.js", map[string]object.BuiltinFunction{
+		"абсолют":          abs,
+		"эң_кичине":        minimum,
+		"эң_чоң":           maximum,
+		"даража":           power,
+		"тамыр":            sqrt,
+		"төмөн_тегеректе":  floor,
+		"жогору_тегеректе": ceil,
+		"кокус":            random,
+		"үрөн":             seed,
+	})
+}
+
+// maxPowerBits limits the size of an exact integer power, so that a
+// typo like даража(10, 1000000000) fails instead of hanging.
+const maxPowerBits = 1 << 20
+
+func isNumber(value object.Object) bool {
+	return value.Type() == object.INTEGER_OBJ || value.Type() == object.FLOAT_OBJ
+}
+
+func toFloat(value object.Object) float64 {
+	if integer, ok := value.(*object.Integer); ok {
+		return integer.Float64()
+	}
+	return value.(*object.Float).Value
+}
+
+// checkNumbers is checkArguments for functions of count numbers, of any
+// count from 1 up when count is negative.
+func checkNumbers(function string, args []object.Object, count int) *object.Error {
+	if count < 0 && len(args) == 0 {
+		return newError("%s: жок дегенде бир аргумент керек", function)
+	}
+	if count >= 0 && len(args) != count {
+		return newError("%s: %d аргумент керек, %d берилди", function, count, len(args))
+	}
+
+	for i, arg := range args {
+		if !isNumber(arg) {
+			return newError("%s: %d-аргумент сан болушу керек, %s берилди", function, i+1, arg.Type())
+		}
+	}
+
+	return nil
+}
+
+// compareNumbers compares two integers exactly, and anything else as
+// floats.
+func compareNumbers(left object.Object, right object.Object) int {
+	leftInteger, leftIsInteger := left.(*object.Integer)
+	rightInteger, rightIsInteger := right.(*object.Integer)
+	if leftIsInteger && rightIsInteger {
+		return leftInteger.BigValue().Cmp(rightInteger.BigValue())
+	}
+
+	leftFloat, rightFloat := toFloat(left), toFloat(right)
+	switch {
+	case leftFloat < rightFloat:
+		return -1
+	case leftFloat > rightFloat:
+		return 1
+	}
+	return 0
+}
+
+func abs(args ...object.Object) object.Object {
+	if err := checkNumbers("математика.абсолют", args, 1); err != nil {
+		return err
+	}
+
+	if integer, ok := args[0].(*object.Integer); ok {
+		value := integer.BigValue()
+		return object.NewInteger(value.Abs(value))
+	}
+
+	return &object.Float{Value: math.Abs(args[0].(*object.Float).Value)}
+}
+
+// minimum and maximum return the first of the smallest or largest
+// arguments, keeping its type.
+func minimum(args ...object.Object) object.Object {
+	return extreme("математика.эң_кичине", args, -1)
+}
+
+func maximum(args ...object.Object) object.Object {
+	return extreme("математика.эң_чоң", args, 1)
+}
+
+func extreme(function string, args []object.Object, direction int) object.Object {
+	if err := checkNumbers(function, args, -1); err != nil {
+		return err
+	}
+
+	best := args[0]
+	for _, arg := range args[1:] {
+		if compareNumbers(arg, best) == direction {
+			best = arg
+		}
+	}
+
+	return best
+}
+
+// power is exact for an integer raised to a non-negative integer, and a
+// float otherwise.
+func power(args ...object.Object) object.Object {
+	if err := checkNumbers("математика.даража", args, 2); err != nil {
+		return err
+	}
+
+	base, baseIsInteger := args[0].(*object.Integer)
+	exponent, exponentIsInteger := args[1].(*object.Integer)
+	if !baseIsInteger || !exponentIsInteger || exponent.BigValue().Sign() < 0 {
+		return &object.Float{Value: math.Pow(toFloat(args[0]), toFloat(args[1]))}
+	}
+
+	baseValue := base.BigValue()
+	if bits := new(big.Int).Abs(baseValue).BitLen(); bits > 1 {
+		if exponent.Big != nil || exponent.Value > int64(maxPowerBits/bits) {
+			return newError("математика.даража: натыйжа өтө чоң")
+		}
+	}
+
+	return object.NewInteger(baseValue.Exp(baseValue, exponent.BigValue(), nil))
+}
+
+func sqrt(args ...object.Object) object.Object {
+	if err := checkNumbers("математика.тамыр", args, 1); err != nil {
+		return err
+	}
+
+	value := toFloat(args[0])
+	if value < 0 {
+		return newError("математика.тамыр: терс сандын тамыры жок")
+	}
+
+	return &object.Float{Value: math.Sqrt(value)}
+}
+
+func floor(args ...object.Object) object.Object {
+	return round("математика.төмөн_тегеректе", args, math.Floor)
+}
+
+func ceil(args ...object.Object) object.Object {
+	return round("математика.жогору_тегеректе", args, math.Ceil)
+}
+
+// round turns a float into an integer, leaving integers as they are.
+func round(function string, args []object.Object, direction func(float64) float64) object.Object {
+	if err := checkNumbers(function, args, 1); err != nil {
+		return err
+	}
+
+	float, ok := args[0].(*object.Float)
+	if !ok {
+		return args[0]
+	}
+	if math.IsNaN(float.Value) || math.IsInf(float.Value, 0) {
+		return newError("%s: %s бүтүн санга айланбайт", function, float.Inspect())
+	}
+
+	rounded := direction(float.Value)
+	if rounded >= math.MinInt64 && rounded < math.MaxInt64 {
+		return &object.Integer{Value: int64(rounded)}
+	}
+
+	value, _ := big.NewFloat(rounded).Int(nil)
+	return object.NewInteger(value)
+}
+
+// The random numbers come from mulberry32, which the JavaScript version
+// implements too, so a program seeded with үрөн draws the same numbers
+// on both backends.
+var (
+	randomLock  sync.Mutex
+	randomState = uint32(time.Now().UnixNano())
+)
+
+func nextRandom() uint32 {
+	randomLock.Lock()
+	defer randomLock.Unlock()
+
+	randomState += 0x6D2B79F5
+	t := randomState
+	t = (t ^ t>>15) * (t | 1)
+	t ^= t + (t^t>>7)*(t|61)
+	return t ^ t>>14
+}
+
+// random returns a float from 0 up to 1, or with an argument n, an
+// integer from 0 up to n.
+func random(args ...object.Object) object.Object {
+	fraction := float64(nextRandom()) / (1 << 32)
+	if len(args) == 0 {
+		return &object.Float{Value: fraction}
+	}
+
+	if err := checkArguments("математика.кокус", args, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+	limit := args[0].(*object.Integer)
+	if limit.Big != nil || limit.Value <= 0 || limit.Value > 1<<53 {
+		return newError("математика.кокус: чек 1ден 2^53кө чейин болушу керек, %s берилди", limit.Inspect())
+	}
+
+	return &object.Integer{Value: int64(math.Floor(fraction * float64(limit.Value)))}
+}
+
+// seed restarts the random numbers from a given integer, of which only
+// the low 32 bits count.
+func seed(args ...object.Object) object.Object {
+	if err := checkArguments("математика.үрөн", args, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+
+	value := args[0].(*object.Integer).BigValue()
+	value.Mod(value, big.NewInt(1<<32))
+
+	randomLock.Lock()
+	randomState = uint32(value.Uint64())
+	randomLock.Unlock()
+
+	return nil
+}
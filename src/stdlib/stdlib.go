@@ -0,0 +1,117 @@
+// Package stdlib holds the builtin modules of alipp. Every module is
+// implemented twice: in Go for the interpreter, and in JavaScript for
+// the compiled output, where it behaves the same.
+package stdlib
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+//go:embed js/*.js
+var scripts embed.FS
+
+type module struct {
+	object *object.Module
+	// javaScript is an expression that evaluates to an object with the
+	// same members, or when configured, to a function that takes the
+	// program's capabilities and returns that object.
+	javaScript string
+	configured bool
+}
+
+var modules = map[string]*module{}
+
+// register adds a module whose JavaScript version is js/<script>.
+func register(name string, script string, functions map[string]object.BuiltinFunction) {
+	source, err := scripts.ReadFile("js/" + script)
+	if err != nil {
+		panic(err)
+	}
+
+	modules[name] = &module{object: newModule(name, functions), javaScript: string(source)}
+}
+
+func newModule(name string, functions map[string]object.BuiltinFunction) *object.Module {
+	members := map[string]object.Object{}
+	for member, function := range functions {
+		members[member] = &object.Builtin{Name: name + "." + member, Function: function}
+	}
+
+	return &object.Module{Name: name, Members: members}
+}
+
+// Lookup returns the builtin module called name. Modules that depend on
+// capabilities allow nothing; see Capabilities.Modules.
+func Lookup(name string) (*object.Module, bool) {
+	module, ok := modules[name]
+	if !ok {
+		return nil, false
+	}
+
+	return module.object, true
+}
+
+// JavaScript returns an expression for the JavaScript version of the
+// module called name with capabilities, or "" when there is none.
+func JavaScript(name string, capabilities Capabilities) string {
+	module, ok := modules[name]
+	switch {
+	case !ok:
+		return ""
+	case module.configured:
+		return capabilities.javaScript(module.javaScript)
+	default:
+		return strings.TrimSpace(module.javaScript)
+	}
+}
+
+// Names returns the names of the builtin modules sorted alphabetically.
+func Names() []string {
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ReservedNames returns the names of the builtin modules, their members
+// and the builtin functions, none of which a keyword may be spelled as.
+func ReservedNames() []string {
+	names := append(Names(), BuiltinNames()...)
+	for _, name := range Names() {
+		module, _ := Lookup(name)
+		for member := range module.Members {
+			names = append(names, member)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func newError(format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...)}
+}
+
+// checkArguments reports a call of function with the wrong number of
+// arguments or with arguments of the wrong types.
+func checkArguments(function string, args []object.Object, types ...object.ObjectType) *object.Error {
+	if len(args) != len(types) {
+		return newError("%s: %d аргумент керек, %d берилди", function, len(types), len(args))
+	}
+
+	for i, arg := range args {
+		if arg.Type() != types[i] {
+			return newError("%s: %d-аргумент %s болушу керек, %s берилди", function, i+1, types[i], arg.Type())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package stdlib
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/object"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"а", "б", -1},
+		{"е", "ё", -1},
+		{"ё", "ж", -1},
+		{"н", "ң", -1},
+		{"ң", "о", -1},
+		{"о", "ө", -1},
+		{"ө", "п", -1},
+		{"ү", "ф", -1},
+		{"Ү", "ф", -1},
+		{"ө", "Ө", -1},
+		{"Өрүк", "өрүк", 1},
+		{"үй", "үй", 0},
+		{"үй", "үйлөр", -1},
+		{"zoo", "ар", -1},
+		{"я", "і", -1},
+	}
+
+	for _, test := range tests {
+		if actual := Compare(test.a, test.b); actual != test.expected {
+			t.Errorf("Compare(%q, %q) wrong. want=%d, got=%d", test.a, test.b, test.expected, actual)
+		}
+		if actual := Compare(test.b, test.a); actual != -test.expected {
+			t.Errorf("Compare(%q, %q) wrong. want=%d, got=%d", test.b, test.a, -test.expected, actual)
+		}
+	}
+}
+
+func TestAlphabetOrder(t *testing.T) {
+	letters := []string{}
+	for _, r := range kyrgyzAlphabet {
+		letters = append(letters, string(r))
+	}
+
+	shuffled := append([]string{}, letters...)
+	sort.Strings(shuffled)
+	sort.Slice(shuffled, func(i, j int) bool { return Compare(shuffled[i], shuffled[j]) < 0 })
+
+	for i := range letters {
+		if shuffled[i] != letters[i] {
+			t.Fatalf("wrong order. want=%v, got=%v", letters, shuffled)
+		}
+	}
+}
+
+func TestModules(t *testing.T) {
+	for _, name := range Names() {
+		module, ok := Lookup(name)
+		if !ok || module.Name != name {
+			t.Errorf("Lookup(%q) wrong. got=%v", name, module)
+		}
+		if JavaScript(name, Capabilities{}) == "" {
+			t.Errorf("module %s has no JavaScript version", name)
+		}
+	}
+
+	if _, ok := Lookup("жок"); ok {
+		t.Errorf("Lookup found a module that does not exist")
+	}
+}
+
+func TestConsole(t *testing.T) {
+	tests := []struct {
+		call     string
+		args     []object.Object
+		input    string
+		output   string
+		expected object.Object
+	}{
+		{"көрсөтүү", []object.Object{&object.String{Value: "сан:"}, &object.Integer{Value: 5}, object.TRUE}, "", "сан: 5 туура\n", object.NULL},
+		{"көрсөтүү", nil, "", "\n", object.NULL},
+		{"жаз", []object.Object{&object.String{Value: "{} + {} = {}"}, &object.Integer{Value: 1}, &object.Integer{Value: 2}, &object.Float{Value: 3.5}}, "", "1 + 2 = 3.5\n", object.NULL},
+		{"жаз", []object.Object{&object.String{Value: "{{}} {}"}, &object.Array{}}, "", "{} []\n", object.NULL},
+		{"жаз", []object.Object{&object.String{Value: "{} {}"}, object.NULL}, "", "", &object.Error{Message: "жаз: калыпта 2 орун бар, 1 маани берилди"}},
+		{"жаз", []object.Object{&object.Integer{Value: 1}}, "", "", &object.Error{Message: "жаз: 1-аргумент САП болушу керек, БҮТҮН_САН берилди"}},
+		{"окуу", nil, "саламдар\r\nкийинки", "", &object.String{Value: "саламдар"}},
+		{"окуу", []object.Object{&object.String{Value: "Атыңыз: "}}, "Айбек", "Атыңыз: ", &object.String{Value: "Айбек"}},
+		{"окуу", nil, "", "", object.NULL},
+		{"окуу", []object.Object{object.TRUE}, "", "", &object.Error{Message: "окуу: 1-аргумент САП болушу керек, ЛОГИКАЛЫК берилди"}},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		builtin := NewConsole(strings.NewReader(test.input), &out).Builtins()[test.call]
+
+		result := builtin.Function(test.args...)
+		if result == nil {
+			result = object.NULL
+		}
+		if result.Inspect() != test.expected.Inspect() || result.Type() != test.expected.Type() {
+			t.Errorf("%s%v: want=%s, got=%s", test.call, test.args, test.expected.Inspect(), result.Inspect())
+		}
+		if out.String() != test.output {
+			t.Errorf("%s%v: wrong output. want=%q, got=%q", test.call, test.args, test.output, out.String())
+		}
+	}
+}
+
+func TestBuiltinNames(t *testing.T) {
+	for _, name := range BuiltinNames() {
+		if _, ok := Lookup(name); ok {
+			t.Errorf("builtin function %s has the name of a module", name)
+		}
+	}
+}
+
+func TestDictionaries(t *testing.T) {
+	for _, name := range token.Dictionaries() {
+		dictionary, _ := token.LookupDictionary(name)
+		if err := dictionary.Validate(ReservedNames()); err != nil {
+			t.Error(err)
+		}
+	}
+}
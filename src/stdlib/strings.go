@@ -0,0 +1,150 @@
+package stdlib
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/asanoviskhak/alipp/src/object"
+)
+
+func init() {
+	register("сап", "strings.js", map[string]object.BuiltinFunction{
+		"узундук":        length,
+		"бөл":            split,
+		"бириктир":       join,
+		"кырк":           trim,
+		"алмаштыр":       replace,
+		"камтыйбы":       contains,
+		"чоң_тамга":      upper,
+		"кичине_тамга":   lower,
+		"алфавит_боюнча": compare,
+		"иреттө":         sortStrings,
+	})
+}
+
+// length counts runes, not bytes, so "өң" is 2 long.
+func length(args ...object.Object) object.Object {
+	if err := checkArguments("сап.узундук", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	return &object.Integer{Value: int64(utf8.RuneCountInString(args[0].(*object.String).Value))}
+}
+
+// split cuts a string at every separator. An empty separator splits it
+// into runes.
+func split(args ...object.Object) object.Object {
+	if err := checkArguments("сап.бөл", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	parts := strings.Split(args[0].(*object.String).Value, args[1].(*object.String).Value)
+	elements := make([]object.Object, len(parts))
+	for i, part := range parts {
+		elements[i] = &object.String{Value: part}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func join(args ...object.Object) object.Object {
+	if err := checkArguments("сап.бириктир", args, object.ARRAY_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	values, err := stringElements("сап.бириктир", args[0].(*object.Array))
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.Join(values, args[1].(*object.String).Value)}
+}
+
+func trim(args ...object.Object) object.Object {
+	if err := checkArguments("сап.кырк", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.TrimSpace(args[0].(*object.String).Value)}
+}
+
+// replace replaces every occurrence.
+func replace(args ...object.Object) object.Object {
+	if err := checkArguments("сап.алмаштыр", args, object.STRING_OBJ, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	text, old, replacement := args[0].(*object.String).Value, args[1].(*object.String).Value, args[2].(*object.String).Value
+	return &object.String{Value: strings.ReplaceAll(text, old, replacement)}
+}
+
+func contains(args ...object.Object) object.Object {
+	if err := checkArguments("сап.камтыйбы", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	return object.NativeBoolean(strings.Contains(args[0].(*object.String).Value, args[1].(*object.String).Value))
+}
+
+// upper and lower follow the Turkic rules for the dotted and dotless i
+// used by the Latin script, so "ıi" becomes "Iİ". Cyrillic letters such
+// as ң, ө and ү need no special rules.
+func upper(args ...object.Object) object.Object {
+	if err := checkArguments("сап.чоң_тамга", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.ToUpperSpecial(unicode.TurkishCase, args[0].(*object.String).Value)}
+}
+
+func lower(args ...object.Object) object.Object {
+	if err := checkArguments("сап.кичине_тамга", args, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	return &object.String{Value: strings.ToLowerSpecial(unicode.TurkishCase, args[0].(*object.String).Value)}
+}
+
+func compare(args ...object.Object) object.Object {
+	if err := checkArguments("сап.алфавит_боюнча", args, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	return &object.Integer{Value: int64(Compare(args[0].(*object.String).Value, args[1].(*object.String).Value))}
+}
+
+// sortStrings returns a sorted copy of a list of strings.
+func sortStrings(args ...object.Object) object.Object {
+	if err := checkArguments("сап.иреттө", args, object.ARRAY_OBJ); err != nil {
+		return err
+	}
+
+	values, err := stringElements("сап.иреттө", args[0].(*object.Array))
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(values, func(i, j int) bool { return Compare(values[i], values[j]) < 0 })
+
+	elements := make([]object.Object, len(values))
+	for i, value := range values {
+		elements[i] = &object.String{Value: value}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func stringElements(function string, array *object.Array) ([]string, *object.Error) {
+	values := make([]string, len(array.Elements))
+	for i, element := range array.Elements {
+		str, ok := element.(*object.String)
+		if !ok {
+			return nil, newError("%s: тизменин %d-элементи %s, %s болушу керек", function, i+1, element.Type(), object.STRING_OBJ)
+		}
+		values[i] = str.Value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,221 @@
+package token
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	unicode "github.com/asanoviskhak/alipp/src/helpers"
+)
+
+//go:embed dictionaries/*.json
+var dictionaryFiles embed.FS
+
+// keywordKinds names the keyword token types in dictionary files, so the
+// files do not depend on the Kyrgyz TokenType values.
+var keywordKinds = map[string]TokenType{
+	"function": FUNCTION,
+	"let":      LET,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"throw":    THROW,
+	"import":   IMPORT,
+	"export":   EXPORT,
+	"extern":   EXTERN,
+	"match":    MATCH,
+}
+
+// Dictionary is one language's spellings of the keywords. A source file
+// selects its dictionary with a "// тил: <name>" comment before its
+// first token.
+type Dictionary struct {
+	Name  string
+	Title string
+
+	keywords map[string]TokenType
+	// spellings holds the preferred spelling of every keyword type,
+	// which is the first one listed in the file.
+	spellings map[TokenType]string
+}
+
+type dictionaryFile struct {
+	Name     string              `json:"name"`
+	Title    string              `json:"title"`
+	Keywords map[string][]string `json:"keywords"`
+}
+
+// ParseDictionary reads a dictionary in the JSON format of the files in
+// dictionaries/. It rejects files that miss a keyword, spell one that
+// the lexer would not read as a single identifier, or use the same
+// spelling twice.
+func ParseDictionary(data []byte) (*Dictionary, error) {
+	var file dictionaryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Name == "" {
+		return nil, fmt.Errorf("dictionary has no name")
+	}
+
+	dictionary := &Dictionary{
+		Name:      file.Name,
+		Title:     file.Title,
+		keywords:  map[string]TokenType{},
+		spellings: map[TokenType]string{},
+	}
+
+	kinds := make([]string, 0, len(file.Keywords))
+	for kind := range file.Keywords {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		tokenType, ok := keywordKinds[kind]
+		if !ok {
+			return nil, fmt.Errorf("dictionary %s: unknown keyword %q", file.Name, kind)
+		}
+
+		for _, spelling := range file.Keywords[kind] {
+			if !isIdentifier(spelling) {
+				return nil, fmt.Errorf("dictionary %s: %q is not an identifier", file.Name, spelling)
+			}
+			if other, ok := dictionary.keywords[spelling]; ok {
+				return nil, fmt.Errorf("dictionary %s: %q is both %s and %s", file.Name, spelling, kindOf(other), kind)
+			}
+
+			dictionary.keywords[spelling] = tokenType
+			if _, ok := dictionary.spellings[tokenType]; !ok {
+				dictionary.spellings[tokenType] = spelling
+			}
+		}
+	}
+
+	for kind, tokenType := range keywordKinds {
+		if _, ok := dictionary.spellings[tokenType]; !ok {
+			return nil, fmt.Errorf("dictionary %s: missing keyword %q", file.Name, kind)
+		}
+	}
+
+	return dictionary, nil
+}
+
+// isIdentifier follows the lexer: an XID_Start rune or '_', then
+// XID_Continue runes and marks.
+func isIdentifier(word string) bool {
+	for i, r := range word {
+		if i == 0 && !unicode.IsXIDStart(r) && r != '_' {
+			return false
+		}
+		if i > 0 && !unicode.IsXIDContinue(r) && !unicode.IsMark(r) {
+			return false
+		}
+	}
+
+	return word != ""
+}
+
+func kindOf(tokenType TokenType) string {
+	for kind, kindType := range keywordKinds {
+		if kindType == tokenType {
+			return kind
+		}
+	}
+
+	return string(tokenType)
+}
+
+// Validate reports a keyword that is spelled like one of names, such as
+// the builtins of the runtime the dictionary is used with.
+func (dictionary *Dictionary) Validate(names []string) error {
+	for _, name := range names {
+		if tokenType, ok := dictionary.keywords[name]; ok {
+			return fmt.Errorf("dictionary %s: %s keyword %q collides with builtin %q", dictionary.Name, kindOf(tokenType), name, name)
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns the keyword type spelled ident, or IDENT.
+func (dictionary *Dictionary) Lookup(ident string) TokenType {
+	if tok, ok := dictionary.keywords[ident]; ok {
+		return tok
+	}
+
+	return IDENT
+}
+
+// Keywords returns every keyword spelling sorted alphabetically.
+func (dictionary *Dictionary) Keywords() []string {
+	words := make([]string, 0, len(dictionary.keywords))
+	for word := range dictionary.keywords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	return words
+}
+
+// Spelling returns the preferred spelling of a keyword type, or "" when
+// tokenType is not a keyword.
+func (dictionary *Dictionary) Spelling(tokenType TokenType) string {
+	return dictionary.spellings[tokenType]
+}
+
+var dictionaries = loadDictionaries()
+
+func loadDictionaries() map[string]*Dictionary {
+	files, err := dictionaryFiles.ReadDir("dictionaries")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := map[string]*Dictionary{}
+	for _, file := range files {
+		data, err := dictionaryFiles.ReadFile("dictionaries/" + file.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		dictionary, err := ParseDictionary(data)
+		if err != nil {
+			panic(err)
+		}
+		if dictionary.Name != strings.TrimSuffix(file.Name(), ".json") {
+			panic(fmt.Sprintf("dictionary %s is stored in %s", dictionary.Name, file.Name()))
+		}
+		loaded[dictionary.Name] = dictionary
+	}
+
+	return loaded
+}
+
+// LookupDictionary returns the built-in dictionary called name.
+func LookupDictionary(name string) (*Dictionary, bool) {
+	dictionary, ok := dictionaries[name]
+	return dictionary, ok
+}
+
+// Dictionaries returns the names of the built-in dictionaries sorted
+// alphabetically.
+func Dictionaries() []string {
+	names := make([]string, 0, len(dictionaries))
+	for name := range dictionaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Kyrgyz is the dictionary used by files without a "тил" comment.
+var Kyrgyz = dictionaries["ky"]
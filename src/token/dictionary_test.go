@@ -0,0 +1,63 @@
+package token
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDictionaries(t *testing.T) {
+	names := Dictionaries()
+	expected := []string{"kk", "ky", "ky-latn", "tt", "uz"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Fatalf("wrong dictionaries. want=%v, got=%v", expected, names)
+	}
+
+	for _, name := range names {
+		dictionary, _ := LookupDictionary(name)
+		for kind, tokenType := range keywordKinds {
+			spelling := dictionary.Spelling(tokenType)
+			if dictionary.Lookup(spelling) != tokenType {
+				t.Errorf("%s: preferred spelling %q of %s does not look up", name, spelling, kind)
+			}
+		}
+	}
+}
+
+func TestParseDictionaryErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"keywords": {}}`, "dictionary has no name"},
+		{
+			`{"name": "x", "keywords": {"function": ["ф"], "let": ["с"], "true": ["т"], "false": ["к"], "if": ["э"], "else": ["э"], "return": ["р"]}}`,
+			`dictionary x: "э" is both else and if`,
+		},
+		{`{"name": "x", "keywords": {"while": ["чейин"]}}`, `dictionary x: unknown keyword "while"`},
+		{`{"name": "x", "keywords": {"function": ["эки сөз"]}}`, `dictionary x: "эки сөз" is not an identifier`},
+		{`{"name": "x", "keywords": {"function": ["ф"]}}`, "dictionary x: missing keyword"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseDictionary([]byte(tt.input))
+		if err == nil {
+			t.Errorf("ParseDictionary(%s) did not fail", tt.input)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), tt.expected) {
+			t.Errorf("wrong error. want=%q, got=%q", tt.expected, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Kyrgyz.Validate([]string{"узундук", "көрсөт"}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	err := Kyrgyz.Validate([]string{"узундук", "сакта"})
+	expected := `dictionary ky: let keyword "сакта" collides with builtin "сакта"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong error. want=%q, got=%v", expected, err)
+	}
+}
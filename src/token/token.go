@@ -5,6 +5,11 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	// Line and Column are 1-based and point at the first rune of the token,
+	// Offset is the byte offset of that rune in the input.
+	Line   int
+	Column int
+	Offset int
 }
 
 const (
@@ -12,6 +17,8 @@ const (
 	EOF     = "БҮТТҮ"
 	IDENT   = "ИДЕНТИФИКАТОР"
 	INT     = "БҮТҮН_САН"
+	FLOAT   = "БӨЛЧӨК_САН"
+	STRING  = "САП"
 
 	// Operators
 	ASSIGN      = "="
@@ -24,16 +31,23 @@ const (
 	GT          = ">"
 	EQ          = "=="
 	NOT_EQ      = "!="
+	ARROW       = "=>"
 
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
 
 	LPAREN = "("
 	RPAREN = ")"
 	LBRACE = "{"
 	RBRACE = "}"
 
+	LBRACKET = "["
+	RBRACKET = "]"
+	DOT      = "."
+	ELLIPSIS = "..."
+
 	// Keywords
 	FUNCTION = "ФУНКЦИЯ"
 	LET      = "САКТА"
@@ -42,6 +56,14 @@ const (
 	IF       = "ЭГЕР"
 	ELSE     = "ЖЕ"
 	RETURN   = "КАЙТАР"
+	TRY      = "АРАКЕТ"
+	CATCH    = "КАРМОО"
+	FINALLY  = "АКЫРЫ"
+	THROW    = "ЫРГЫТ"
+	IMPORT   = "ИМПОРТ"
+	EXPORT   = "ЭКСПОРТ"
+	EXTERN   = "ТЫШКЫ"
+	MATCH    = "САЛЫШТЫР"
 
 	// Excerpt From
 	// Writing An Interpreter In Go
@@ -49,21 +71,22 @@ const (
 	// This material may be protected by copyright.
 )
 
-var keywords = map[string]TokenType{
-	"функция": FUNCTION,
-	"функ":    FUNCTION,
-	"сакта":   LET,
-	"туура":   TRUE,
-	"ката":    FALSE,
-	"эгер":    IF,
-	"же":      ELSE,
-	"кайтар":  RETURN,
+// Keywords returns every keyword spelling sorted alphabetically.
+func Keywords() []string {
+	return Kyrgyz.Keywords()
 }
 
-func LookupIdent(ident string) TokenType {
-	if tok, ok := keywords[ident]; ok {
-		return tok
+// IsKeyword reports whether tokenType is produced by a keyword.
+func IsKeyword(tokenType TokenType) bool {
+	for _, keywordType := range keywordKinds {
+		if keywordType == tokenType {
+			return true
+		}
 	}
 
-	return IDENT
+	return false
+}
+
+func LookupIdent(ident string) TokenType {
+	return Kyrgyz.Lookup(ident)
 }
@@ -0,0 +1,95 @@
+package translit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/token"
+)
+
+type Script int
+
+const (
+	Latin Script = iota
+	Cyrillic
+)
+
+// Warning points at an identifier whose conversion changes the program
+// or cannot be undone.
+type Warning struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Source converts the keywords and identifiers of an alipp program to
+// the target script. String literals, numbers, operators, whitespace
+// and anything the lexer does not recognise are copied unchanged.
+func Source(source string, target Script) (string, []Warning) {
+	latin, _ := token.LookupDictionary("ky-latn")
+	convert, back, dictionary := ToLatin, ToCyrillic, latin
+	lexerInstance := lexer.New(source)
+	if target == Cyrillic {
+		convert, back, dictionary = ToCyrillic, ToLatin, token.Kyrgyz
+		lexerInstance.UseDictionary(latin)
+	}
+
+	var out strings.Builder
+	warnings := []Warning{}
+	converted := map[string]string{}
+	sources := map[string]string{}
+
+	position := 0
+	for tok := lexerInstance.NextToken(); tok.Type != token.EOF; tok = lexerInstance.NextToken() {
+		if tok.Type != token.IDENT && !token.IsKeyword(tok.Type) {
+			continue
+		}
+
+		out.WriteString(source[position:tok.Offset])
+		position = tok.Offset + len(tok.Literal)
+
+		if tok.Type != token.IDENT {
+			out.WriteString(keyword(tok, convert, dictionary))
+			continue
+		}
+
+		result := convert(tok.Literal)
+		out.WriteString(result)
+
+		if _, seen := converted[tok.Literal]; seen {
+			continue
+		}
+		converted[tok.Literal] = result
+
+		warn := func(format string, args ...interface{}) {
+			warnings = append(warnings, Warning{Line: tok.Line, Column: tok.Column, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if token.Kyrgyz.Lookup(result) != token.IDENT || latin.Lookup(result) != token.IDENT {
+			warn("%q идентификатору %q ачкыч сөзүнө айланат", tok.Literal, result)
+		}
+		if other, ok := sources[result]; ok {
+			warn("%q жана %q идентификаторлору экөө тең %q болуп калат", other, tok.Literal, result)
+		} else {
+			sources[result] = tok.Literal
+		}
+		if restored := back(result); restored != tok.Literal {
+			warn("%q идентификатору кайра которулганда %q болуп калат", tok.Literal, restored)
+		}
+	}
+	out.WriteString(source[position:])
+
+	return out.String(), warnings
+}
+
+// keyword spells a keyword token in the target script. The
+// transliteration is used when the target dictionary has it as a keyword
+// of the same kind, so функ stays funk, otherwise its preferred spelling.
+func keyword(tok token.Token, convert func(string) string, dictionary *token.Dictionary) string {
+	if candidate := convert(tok.Literal); dictionary.Lookup(candidate) == tok.Type {
+		return candidate
+	}
+
+	return dictionary.Spelling(tok.Type)
+}
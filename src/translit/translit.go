@@ -0,0 +1,112 @@
+// Package translit converts Kyrgyz text and alipp source code between the
+// Cyrillic and the Latin script.
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "j",
+	'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'ң': "ñ",
+	'о': "o", 'ө': "ö", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ү': "ü",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "ʺ", 'ы': "ı",
+	'ь': "ʹ", 'э': "é", 'ю': "yu", 'я': "ya",
+}
+
+// latinToCyrillic is tried longest spelling first. "i" is missing on
+// purpose: it stands for both и and й and is decided by its neighbour.
+var latinToCyrillic = []struct {
+	latin    string
+	cyrillic rune
+}{
+	{"shch", 'щ'},
+	{"sh", 'ш'}, {"ch", 'ч'}, {"ts", 'ц'}, {"yo", 'ё'}, {"yu", 'ю'}, {"ya", 'я'},
+	{"a", 'а'}, {"b", 'б'}, {"v", 'в'}, {"g", 'г'}, {"d", 'д'}, {"e", 'е'}, {"j", 'ж'},
+	{"z", 'з'}, {"k", 'к'}, {"l", 'л'}, {"m", 'м'}, {"n", 'н'}, {"ñ", 'ң'}, {"o", 'о'},
+	{"ö", 'ө'}, {"p", 'п'}, {"r", 'р'}, {"s", 'с'}, {"t", 'т'}, {"u", 'у'}, {"ü", 'ү'},
+	{"f", 'ф'}, {"h", 'х'}, {"ʺ", 'ъ'}, {"ı", 'ы'}, {"ʹ", 'ь'}, {"é", 'э'},
+}
+
+func isCyrillicVowel(r rune) bool {
+	return strings.ContainsRune("аеёиоөуүыэюя", unicode.ToLower(r))
+}
+
+// ToLatin transliterates Cyrillic letters and leaves everything else as
+// it is. Capital letters become capitalised Latin spellings, so Ш is Sh.
+// As in other Turkic Latin alphabets the capital of ı is I and the
+// capital of i is İ.
+func ToLatin(text string) string {
+	var out strings.Builder
+
+	for _, r := range text {
+		latin, ok := cyrillicToLatin[unicode.ToLower(r)]
+		if !ok {
+			out.WriteRune(r)
+			continue
+		}
+
+		if unicode.IsUpper(r) {
+			first := []rune(latin)
+			switch first[0] {
+			case 'i':
+				first[0] = 'İ'
+			case 'ı':
+				first[0] = 'I'
+			default:
+				first[0] = unicode.ToUpper(first[0])
+			}
+			latin = string(first)
+		}
+		out.WriteString(latin)
+	}
+
+	return out.String()
+}
+
+// ToCyrillic is the inverse of ToLatin. "i" after a vowel becomes й and
+// и everywhere else, so words like аил do not survive a round trip.
+func ToCyrillic(text string) string {
+	var out strings.Builder
+
+	runes := []rune(text)
+	previous := rune(0)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		cyrillic, width := rune(0), 1
+
+		if r == 'i' || r == 'İ' {
+			cyrillic = 'и'
+			if isCyrillicVowel(previous) {
+				cyrillic = 'й'
+			}
+		} else if r == 'I' {
+			cyrillic = 'ы'
+		} else {
+			for _, spelling := range latinToCyrillic {
+				length := len([]rune(spelling.latin))
+				if i+length <= len(runes) && strings.ToLower(string(runes[i:i+length])) == spelling.latin {
+					cyrillic, width = spelling.cyrillic, length
+					break
+				}
+			}
+		}
+
+		if cyrillic == 0 {
+			out.WriteRune(r)
+			previous = r
+			i++
+			continue
+		}
+
+		if unicode.IsUpper(r) {
+			cyrillic = unicode.ToUpper(cyrillic)
+		}
+		out.WriteRune(cyrillic)
+		previous = cyrillic
+		i += width
+	}
+
+	return out.String()
+}
@@ -0,0 +1,101 @@
+package translit
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToLatin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"кайтар", "kaitar"},
+		{"функция", "funktsiya"},
+		{"өлчөм", "ölchöm"},
+		{"үйүң", "üiüñ"},
+		{"Шаар", "Shaar"},
+		{"Ыр", "Ir"},
+		{"Ийне", "İine"},
+		{"ырчы", "ırchı"},
+		{"эне", "éne"},
+		{"x + 5", "x + 5"},
+	}
+
+	for _, tt := range tests {
+		if actual := ToLatin(tt.input); actual != tt.expected {
+			t.Errorf("ToLatin(%q) wrong. want=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestToCyrillic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"kaitar", "кайтар"},
+		{"funktsiya", "функция"},
+		{"ölchöm", "өлчөм"},
+		{"üiüñ", "үйүң"},
+		{"Shaar", "Шаар"},
+		{"Ir", "Ыр"},
+		{"İine", "Ийне"},
+		{"shchetka", "щетка"},
+		{"x", "x"},
+	}
+
+	for _, tt := range tests {
+		if actual := ToCyrillic(tt.input); actual != tt.expected {
+			t.Errorf("ToCyrillic(%q) wrong. want=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	words := []string{"сакта", "эгер", "кайтар", "функция", "өлчөм", "жыйынтык", "көрсөтүү", "оюн", "маанилүү", "ЖАҢЫ"}
+
+	for _, word := range words {
+		if restored := ToCyrillic(ToLatin(word)); restored != word {
+			t.Errorf("%q did not survive a round trip. got=%q", word, restored)
+		}
+	}
+}
+
+func TestSource(t *testing.T) {
+	input := `сакта өлчөм = функ(x) { эгер (x > 5) { кайтар "чоң өлчөм"; } же { кайтар туура; } };`
+	expected := `sakta ölchöm = funk(x) { eger (x > 5) { kaitar "чоң өлчөм"; } je { kaitar tuura; } };`
+
+	latin, warnings := Source(input, Latin)
+	if latin != expected {
+		t.Fatalf("Source(Latin) wrong.\nwant=%q\ngot= %q", expected, latin)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	cyrillic, warnings := Source(latin, Cyrillic)
+	if cyrillic != input {
+		t.Fatalf("Source(Cyrillic) wrong.\nwant=%q\ngot= %q", input, cyrillic)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestSourceWarnings(t *testing.T) {
+	input := "сакта аил = 1;\nсакта ш = 2; сакта сх = 3;\nсакта je = 4; je;"
+
+	_, warnings := Source(input, Latin)
+
+	expected := []Warning{
+		{1, 7, `"аил" идентификатору кайра которулганда "айл" болуп калат`},
+		{2, 20, `"ш" жана "сх" идентификаторлору экөө тең "sh" болуп калат`},
+		{2, 20, `"сх" идентификатору кайра которулганда "ш" болуп калат`},
+		{3, 7, `"je" идентификатору "je" ачкыч сөзүнө айланат`},
+		{3, 7, `"je" идентификатору кайра которулганда "же" болуп калат`},
+	}
+	if fmt.Sprint(warnings) != fmt.Sprint(expected) {
+		t.Errorf("warnings wrong.\nwant=%v\ngot= %v", expected, warnings)
+	}
+}
@@ -0,0 +1,813 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/stdlib"
+)
+
+// Error is a type error at Span of the module called File.
+type Error struct {
+	File    string
+	Span    ast.Span
+	Message string
+}
+
+func (err Error) Error() string {
+	if err.File == "" {
+		return fmt.Sprintf("%d:%d: %s", err.Span.Start.Line, err.Span.Start.Column, err.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", err.File, err.Span.Start.Line, err.Span.Start.Column, err.Message)
+}
+
+// Check returns the type errors of a program that imports nothing.
+func Check(program *ast.Program) []Error {
+	return NewEnvironment().Check(program)
+}
+
+// CheckModules returns the type errors of modules, in the order the
+// loader returns them, which checks the modules a module imports before
+// it. Imported names have the types their module gives them.
+func CheckModules(modules []*loader.Module) []Error {
+	_, errors := checkModules(modules)
+	return errors
+}
+
+// Exports returns the types of the names each of modules exports, as
+// CheckModules works them out. Errors leave Каалаган where they are.
+func Exports(modules []*loader.Module) map[*loader.Module]map[string]*Scheme {
+	exports, _ := checkModules(modules)
+	return exports
+}
+
+func checkModules(modules []*loader.Module) (map[*loader.Module]map[string]*Scheme, []Error) {
+	exports := map[*loader.Module]map[string]*Scheme{}
+	var errors []Error
+
+	for _, module := range modules {
+		checkerInstance := newChecker(func(path string, name string) *Scheme {
+			if found, ok := exports[module.Imports[path]][name]; ok {
+				return found
+			}
+			return anyScheme
+		})
+		checkerInstance.program(module.Program)
+
+		exports[module] = map[string]*Scheme{}
+		for _, name := range module.Exports {
+			if found, ok := checkerInstance.scope.names[name]; ok {
+				exports[module][name] = found
+			}
+		}
+		for _, checkError := range checkerInstance.errors {
+			checkError.File = module.Name
+			errors = append(errors, checkError)
+		}
+	}
+
+	return exports, errors
+}
+
+// Environment checks programs one after another, as the REPL reads
+// them, each seeing the names the ones before it bound.
+type Environment struct {
+	checker *checker
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{checker: newChecker(nil)}
+}
+
+// Check returns the type errors of program.
+func (environment *Environment) Check(program *ast.Program) []Error {
+	environment.checker.errors = nil
+	environment.checker.program(program)
+
+	return environment.checker.errors
+}
+
+// TypeOf returns the type of expression, generalized as сакта would
+// generalize it, and the type errors in it.
+func (environment *Environment) TypeOf(expression ast.Expression) (*Scheme, []Error) {
+	environment.checker.errors = nil
+	value := environment.checker.expression(expression)
+
+	return environment.checker.generalize(value), environment.checker.errors
+}
+
+// scope holds the types of the names a function, or the top level,
+// binds. A name is Каалаган from the start of the function until a
+// сакта statement binds it.
+type scope struct {
+	parent *scope
+	names  map[string]*Scheme
+}
+
+// anyScheme is the type of a name the checker knows nothing about.
+var anyScheme = &Scheme{Type: Any}
+
+func (scope *scope) lookup(name string) (*Scheme, bool) {
+	for ; scope != nil; scope = scope.parent {
+		if found, ok := scope.names[name]; ok {
+			return found, true
+		}
+	}
+
+	return nil, false
+}
+
+// function is what the checker knows of the function whose body it is
+// in.
+type function struct {
+	// result is the annotated type of the result, or nil.
+	result Type
+	// returns collects the types of the values of кайтар statements.
+	returns Type
+}
+
+type checker struct {
+	errors []Error
+	scope  *scope
+	// function is nil at the top level.
+	function *function
+	// imported returns the type of a name a module imports from path.
+	imported func(path string, name string) *Scheme
+}
+
+func newChecker(imported func(path string, name string) *Scheme) *checker {
+	if imported == nil {
+		imported = func(string, string) *Scheme { return anyScheme }
+	}
+
+	return &checker{scope: &scope{names: map[string]*Scheme{}}, imported: imported}
+}
+
+func (checker *checker) addError(node ast.Node, format string, a ...interface{}) {
+	checker.errors = append(checker.errors, Error{Span: ast.SpanOf(node), Message: fmt.Sprintf(format, a...)})
+}
+
+// mismatch reports a value of type actual where one of type expected
+// was, saying what the value is for, or the infinite type unifying them
+// would make.
+func (checker *checker) mismatch(node ast.Node, what string, expected Type, actual Type, err error) {
+	if cycle, ok := err.(*infinite); ok {
+		checker.addError(node, "%s", cycle)
+		return
+	}
+
+	names := newNames()
+	checker.addError(node, "%s: %s күтүлгөн, %s берилди", what, names.format(expected), names.format(actual))
+}
+
+// operands reports an operator whose operands are of types that do not
+// go with it, or with each other.
+func (checker *checker) operands(expression *ast.InfixExpression, message string, left Type, right Type) {
+	names := newNames()
+	checker.addError(expression, "%s: %s %s %s", message, names.format(left), expression.Operator, names.format(right))
+}
+
+// generalize returns the scheme of a name bound to a value of type
+// value, which leaves to each use the variables no name in scope holds.
+func (checker *checker) generalize(value Type) *Scheme {
+	var held []*Variable
+	for scope := checker.scope; scope != nil; scope = scope.parent {
+		for _, scheme := range scope.names {
+			held = append(held, scheme.free()...)
+		}
+	}
+
+	scheme := &Scheme{Type: value}
+	for _, variable := range variables(value, nil) {
+		if !contains(held, variable) {
+			scheme.Variables = append(scheme.Variables, variable)
+		}
+	}
+
+	return scheme
+}
+
+func (checker *checker) annotation(expression ast.TypeExpression) Type {
+	if expression == nil {
+		return Any
+	}
+
+	return resolve(expression, func(node ast.Node, message string) {
+		checker.addError(node, "%s", message)
+	})
+}
+
+func (checker *checker) program(program *ast.Program) {
+	for name, scheme := range declared(program.Statements) {
+		checker.scope.names[name] = scheme
+	}
+	for _, statement := range program.Statements {
+		checker.statement(statement)
+	}
+}
+
+// declared returns the names a function body binds, as Каалаган.
+// They are visible in the whole body, as in the evaluator, where they
+// are looked up when they are used.
+func declared(statements []ast.Statement) map[string]*Scheme {
+	names := map[string]*Scheme{}
+	for _, statement := range statements {
+		ast.Inspect(statement, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.LetStatement:
+				if node.Pattern != nil {
+					for _, name := range ast.Bindings(node.Pattern) {
+						names[name.Value] = anyScheme
+					}
+				} else {
+					names[node.Name.Value] = anyScheme
+				}
+			case *ast.ImportStatement:
+				for _, name := range node.Names {
+					names[name.Value] = anyScheme
+				}
+			case *ast.ExternStatement:
+				for _, extern := range node.Names {
+					names[extern.Name.Value] = anyScheme
+				}
+			case *ast.TryStatement:
+				if node.Parameter != nil {
+					names[node.Parameter.Value] = anyScheme
+				}
+			case *ast.FunctionLiteral:
+				return false
+			}
+			return true
+		})
+	}
+
+	return names
+}
+
+// statement checks statement and returns the type of its value, which
+// is nil when control never gets past it.
+func (checker *checker) statement(statement ast.Statement) Type {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		checker.letStatement(statement)
+		return Null
+	case *ast.ExportStatement:
+		checker.letStatement(statement.Statement)
+		return Null
+	case *ast.ReturnStatement:
+		value := checker.expression(statement.ReturnValue)
+		if checker.function != nil {
+			if checker.function.result == nil {
+				checker.function.returns = join(checker.function.returns, value)
+			} else if err := subsume(value, checker.function.result); err != nil {
+				checker.mismatch(statement.ReturnValue, "кайтарылган маани", checker.function.result, value, err)
+			}
+		}
+		return nil
+	case *ast.ExpressionStatement:
+		return checker.expression(statement.Expression)
+	case *ast.BlockStatement:
+		return checker.block(statement)
+	case *ast.TryStatement:
+		checker.block(statement.Block)
+		checker.block(statement.Catch)
+		checker.block(statement.Finally)
+		return Null
+	case *ast.ThrowStatement:
+		checker.expression(statement.Value)
+		return nil
+	case *ast.ImportStatement:
+		for _, name := range statement.Names {
+			checker.scope.names[name.Value] = checker.imported(statement.Path.Value, name.Value)
+		}
+	}
+
+	return Null
+}
+
+func (checker *checker) letStatement(statement *ast.LetStatement) {
+	if statement.Pattern != nil {
+		checker.destructuringLet(statement)
+		return
+	}
+
+	name := statement.Name.Value
+	if statement.Type != nil {
+		// The name is bound before the value is checked, so that a
+		// function can call itself.
+		declaredType := checker.annotation(statement.Type)
+		checker.scope.names[name] = &Scheme{Type: declaredType}
+		value := checker.expression(statement.Value)
+		if err := subsume(value, declaredType); err != nil {
+			checker.mismatch(statement.Value, name, declaredType, value, err)
+		}
+		return
+	}
+
+	// A function is bound to a variable while its body is checked, so
+	// that its calls to itself tell what the variable stands for.
+	var self *Variable
+	if _, ok := statement.Value.(*ast.FunctionLiteral); ok {
+		self = &Variable{}
+		checker.scope.names[name] = &Scheme{Type: self}
+	}
+	value := checker.expression(statement.Value)
+	if self != nil {
+		if err := unify(self, value); err != nil {
+			checker.mismatch(statement.Value, name, self, value, err)
+		}
+	}
+
+	// Unbound first, so that the variable does not keep what it stands
+	// for from being generalized.
+	checker.scope.names[name] = anyScheme
+	checker.scope.names[name] = checker.generalize(value)
+}
+
+// destructuringLet binds the names of the pattern of statement to the
+// types of the parts of its value, and generalizes them as letStatement
+// does.
+func (checker *checker) destructuringLet(statement *ast.LetStatement) {
+	value := checker.expression(statement.Value)
+	if statement.Type != nil {
+		declaredType := checker.annotation(statement.Type)
+		if err := subsume(value, declaredType); err != nil {
+			checker.mismatch(statement.Value, statement.Pattern.String(), declaredType, value, err)
+		}
+		value = declaredType
+	}
+	checker.destructure(statement.Pattern, value)
+
+	names := ast.Bindings(statement.Pattern)
+	values := make([]Type, len(names))
+	for i, name := range names {
+		values[i] = checker.scope.names[name.Value].Type
+		checker.scope.names[name.Value] = anyScheme
+	}
+	for i, name := range names {
+		checker.scope.names[name.Value] = checker.generalize(values[i])
+	}
+}
+
+// destructure binds the names of pattern, the pattern of a сакта or of
+// a parameter, in the current scope to the types of the parts of a
+// value of type value, which must be a list or hash as the pattern is.
+func (checker *checker) destructure(pattern ast.Pattern, value Type) {
+	switch pattern := pattern.(type) {
+	case *ast.BindingPattern:
+		checker.scope.names[pattern.Name.Value] = &Scheme{Type: value}
+	case *ast.DefaultPattern:
+		defaultType := checker.expression(pattern.Default)
+		if err := subsume(defaultType, value); err != nil {
+			checker.mismatch(pattern.Default, "демейки маани", value, defaultType, err)
+		}
+		checker.destructure(pattern.Pattern, value)
+	case *ast.ArrayPattern:
+		var element Type = Any
+		if Prune(value) != Any {
+			list := &List{Element: &Variable{}}
+			if err := unify(value, list); err != nil {
+				checker.mismatch(pattern, pattern.String(), list, value, err)
+			} else {
+				element = list.Element
+			}
+		}
+		for _, each := range pattern.Elements {
+			checker.destructure(each, element)
+		}
+		if pattern.Rest != nil {
+			checker.scope.names[pattern.Rest.Value] = &Scheme{Type: &List{Element: element}}
+		}
+	case *ast.HashPattern:
+		var key, element Type = Any, Any
+		if Prune(value) != Any {
+			hash := &Hash{Key: &Variable{}, Value: &Variable{}}
+			if err := unify(value, hash); err != nil {
+				checker.mismatch(pattern, pattern.String(), hash, value, err)
+			} else {
+				key, element = hash.Key, hash.Value
+			}
+		}
+		for i, keyNode := range pattern.Keys {
+			keyType := checker.expression(keyNode)
+			if err := unify(keyType, key); err != nil {
+				checker.mismatch(keyNode, "ачкыч", key, keyType, err)
+			}
+			checker.destructure(pattern.Values[i], element)
+		}
+	}
+}
+
+// block returns the type of the value of the last statement of block.
+func (checker *checker) block(block *ast.BlockStatement) Type {
+	if block == nil || len(block.Statements) == 0 {
+		return Null
+	}
+
+	var value Type = Null
+	for _, statement := range block.Statements {
+		value = checker.statement(statement)
+	}
+
+	return value
+}
+
+func (checker *checker) functionLiteral(literal *ast.FunctionLiteral) Type {
+	var statements []ast.Statement
+	if literal.Body != nil {
+		statements = literal.Body.Statements
+	}
+
+	// A parameter without an annotation is a variable, which the body
+	// and the calls work out.
+	signature := &Function{Parameters: make([]Type, len(literal.Parameters))}
+	savedScope, savedFunction := checker.scope, checker.function
+	checker.scope = &scope{parent: checker.scope, names: declared(statements)}
+	for i, parameter := range literal.Parameters {
+		signature.Parameters[i] = &Variable{}
+		if i < len(literal.ParameterTypes) && literal.ParameterTypes[i] != nil {
+			signature.Parameters[i] = checker.annotation(literal.ParameterTypes[i])
+		}
+		checker.destructure(parameter, signature.Parameters[i])
+		if _, ok := parameter.(*ast.DefaultPattern); ok {
+			signature.Optional++
+		} else {
+			signature.Optional = 0
+		}
+	}
+	var result Type
+	if literal.ReturnType != nil {
+		result = checker.annotation(literal.ReturnType)
+	}
+
+	checker.function = &function{result: result}
+	value := checker.block(literal.Body)
+	returns := checker.function.returns
+	checker.scope, checker.function = savedScope, savedFunction
+
+	if result != nil && value != nil {
+		if err := subsume(value, result); err != nil {
+			checker.mismatch(lastValue(literal.Body), "кайтарылган маани", result, value, err)
+		}
+	}
+	// A function that never returns may be taken to return anything.
+	signature.Result = result
+	if signature.Result == nil {
+		if signature.Result = join(value, returns); signature.Result == nil {
+			signature.Result = &Variable{}
+		}
+	}
+
+	return signature
+}
+
+// matchExpression returns the join of the types of the arms. Each arm
+// has a scope of its own for the names its pattern binds.
+func (checker *checker) matchExpression(expression *ast.MatchExpression) Type {
+	subject := checker.expression(expression.Subject)
+
+	var value Type
+	for _, arm := range expression.Arms {
+		inner := &scope{parent: checker.scope, names: map[string]*Scheme{}}
+		checker.pattern(arm.Pattern, subject, inner)
+		savedScope := checker.scope
+		checker.scope = inner
+		if arm.Guard != nil {
+			checker.expression(arm.Guard)
+		}
+		value = join(value, checker.expression(arm.Body))
+		checker.scope = savedScope
+	}
+	if value == nil {
+		return Any
+	}
+
+	return value
+}
+
+// pattern binds the names in pattern, matched against a value of type
+// subject, in scope. A pattern only tests the shape of the value, so
+// it never makes the subject a type it is not.
+func (checker *checker) pattern(pattern ast.Pattern, subject Type, scope *scope) {
+	switch pattern := pattern.(type) {
+	case *ast.BindingPattern:
+		scope.names[pattern.Name.Value] = &Scheme{Type: subject}
+	case *ast.ArrayPattern:
+		element := Type(Any)
+		if list, ok := Prune(subject).(*List); ok {
+			element = list.Element
+		}
+		for _, each := range pattern.Elements {
+			checker.pattern(each, element, scope)
+		}
+		if pattern.Rest != nil {
+			scope.names[pattern.Rest.Value] = &Scheme{Type: &List{Element: element}}
+		}
+	case *ast.HashPattern:
+		value := Type(Any)
+		if hash, ok := Prune(subject).(*Hash); ok {
+			value = hash.Value
+		}
+		for _, each := range pattern.Values {
+			checker.pattern(each, value, scope)
+		}
+	}
+}
+
+// lastValue returns the node that gives block its value, to point at.
+func lastValue(block *ast.BlockStatement) ast.Node {
+	if len(block.Statements) == 0 {
+		return block
+	}
+	if last, ok := block.Statements[len(block.Statements)-1].(*ast.ExpressionStatement); ok {
+		return last.Expression
+	}
+
+	return block.Statements[len(block.Statements)-1]
+}
+
+func (checker *checker) expression(expression ast.Expression) Type {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return Integer
+	case *ast.FloatLiteral:
+		return Float
+	case *ast.StringLiteral:
+		return String
+	case *ast.Boolean:
+		return Boolean
+	case *ast.Identifier:
+		if found, ok := checker.scope.lookup(expression.Value); ok {
+			return found.instantiate()
+		}
+		if stdlib.IsBuiltin(expression.Value) {
+			return Any
+		}
+		if _, ok := stdlib.Lookup(expression.Value); ok {
+			return Any
+		}
+		checker.addError(expression, "белгисиз идентификатор: %s", expression.Value)
+		return Any
+	case *ast.PrefixExpression:
+		right := checker.expression(expression.Right)
+		if expression.Operator == "!" {
+			return Boolean
+		}
+		if !constrain(right, numeric) {
+			checker.addError(expression, "белгисиз оператор: %s%s", expression.Operator, right)
+			return Any
+		}
+		return right
+	case *ast.InfixExpression:
+		return checker.infixExpression(expression)
+	case *ast.IfExpression:
+		checker.expression(expression.Condition)
+		consequence := checker.block(expression.Consequence)
+		alternative := Type(Null)
+		if expression.Alternative != nil {
+			alternative = checker.block(expression.Alternative)
+		}
+		if value := join(consequence, alternative); value != nil {
+			return value
+		}
+		return Any
+	case *ast.MatchExpression:
+		return checker.matchExpression(expression)
+	case *ast.FunctionLiteral:
+		return checker.functionLiteral(expression)
+	case *ast.CallExpression:
+		return checker.callExpression(expression)
+	case *ast.ArrayLiteral:
+		var element Type
+		for _, value := range expression.Elements {
+			element = join(element, checker.expression(value))
+		}
+		if element == nil {
+			element = &Variable{}
+		}
+		return &List{Element: element}
+	case *ast.HashLiteral:
+		var key, value Type
+		for i, keyNode := range expression.Keys {
+			keyType := checker.expression(keyNode)
+			if !hashable(keyType) {
+				checker.addError(keyNode, "%s сөздүктүн ачкычы боло албайт", keyType)
+			}
+			key = join(key, keyType)
+			value = join(value, checker.expression(expression.Values[i]))
+		}
+		if key == nil {
+			key, value = &Variable{}, &Variable{}
+		}
+		return &Hash{Key: key, Value: value}
+	case *ast.IndexExpression:
+		return checker.indexExpression(expression)
+	case *ast.MemberExpression:
+		checker.expression(expression.Object)
+	}
+
+	return Any
+}
+
+// infixExpression checks that the operands suit the operator, as the
+// evaluator does when it runs: numbers go with numbers, strings with
+// strings for +, and values of one kind with each other for == and !=.
+func (checker *checker) infixExpression(expression *ast.InfixExpression) Type {
+	left, right := Prune(checker.expression(expression.Left)), Prune(checker.expression(expression.Right))
+
+	switch expression.Operator {
+	case "==", "!=":
+		// Comparing does not unify: a value that may be бош is compared
+		// with бош to find out.
+		_, leftVariable := left.(*Variable)
+		_, rightVariable := right.(*Variable)
+		if !leftVariable && !rightVariable && left != Any && right != Any && kind(left) != kind(right) && !(isNumber(left) && isNumber(right)) {
+			checker.operands(expression, "түрлөр дал келбейт", left, right)
+		}
+		return Boolean
+	case "<", ">":
+		checker.arithmetic(expression, left, right, numeric)
+		return Boolean
+	case "+":
+		return checker.arithmetic(expression, left, right, addable)
+	}
+
+	return checker.arithmetic(expression, left, right, numeric)
+}
+
+// arithmetic checks the operands of an operator that takes two values
+// of class, and returns the type of its result. A variable and a number
+// make the variable a number, which an integer leaves of any kind and a
+// float makes the result a float of. Two variables, or a variable and
+// another type, are unified: both operands are of one type.
+func (checker *checker) arithmetic(expression *ast.InfixExpression, left Type, right Type, class class) Type {
+	_, leftVariable := left.(*Variable)
+	_, rightVariable := right.(*Variable)
+
+	switch {
+	case (leftVariable && isNumber(right)) || (rightVariable && isNumber(left)):
+		variable, number := left, right
+		if rightVariable {
+			variable, number = right, left
+		}
+		constrain(variable, numeric)
+		if number == Float {
+			return Float
+		}
+		return variable
+	case left == Any || right == Any:
+		known := left
+		if known == Any {
+			known = right
+		}
+		switch {
+		case !constrain(known, class):
+			checker.operands(expression, "белгисиз оператор", left, right)
+		case known == Float || known == String:
+			return known
+		}
+		return Any
+	case leftVariable || rightVariable:
+		if err := unify(left, right); err != nil {
+			checker.operands(expression, "түрлөр дал келбейт", left, right)
+			return Any
+		}
+		if !constrain(left, class) {
+			checker.operands(expression, "белгисиз оператор", left, right)
+			return Any
+		}
+		return left
+	case isNumber(left) && isNumber(right):
+		return join(left, right)
+	case kind(left) != kind(right):
+		checker.operands(expression, "түрлөр дал келбейт", left, right)
+		return Any
+	case class == addable && left == String:
+		return String
+	}
+
+	checker.operands(expression, "белгисиз оператор", left, right)
+	return Any
+}
+
+func isNumber(value Type) bool {
+	return value == Integer || value == Float
+}
+
+// kind returns what the evaluator calls the values of a type, which
+// tells lists apart from hashes but not lists of one type from lists of
+// another.
+func kind(value Type) string {
+	switch value.(type) {
+	case *List:
+		return "Тизме"
+	case *Hash:
+		return "Сөздүк"
+	case *Function:
+		return "функ"
+	}
+
+	return value.String()
+}
+
+func (checker *checker) callExpression(call *ast.CallExpression) Type {
+	callee := Prune(checker.expression(call.Function))
+	arguments := make([]Type, len(call.Arguments))
+	for i, argument := range call.Arguments {
+		arguments[i] = checker.expression(argument)
+	}
+
+	switch callee := callee.(type) {
+	case *Function:
+		required := len(callee.Parameters) - callee.Optional
+		switch {
+		case callee.Optional == 0 && len(arguments) != required:
+			checker.addError(call, "%d аргумент керек, %d берилди", required, len(arguments))
+			return callee.Result
+		case len(arguments) < required || len(arguments) > len(callee.Parameters):
+			checker.addError(call, "%d-%d аргумент керек, %d берилди", required, len(callee.Parameters), len(arguments))
+			return callee.Result
+		}
+		for _, i := range argumentOrder(arguments) {
+			if err := subsume(arguments[i], callee.Parameters[i]); err != nil {
+				checker.mismatch(call.Arguments[i], fmt.Sprintf("%d-аргумент", i+1), callee.Parameters[i], arguments[i], err)
+			}
+		}
+		return callee.Result
+	case *Variable:
+		// What is called is a function of the arguments.
+		result := &Variable{}
+		err := unify(callee, &Function{Parameters: arguments, Result: result})
+		if cycle, ok := err.(*infinite); ok {
+			checker.addError(call, "%s", cycle)
+			return Any
+		}
+		if err == nil {
+			return result
+		}
+	case Basic:
+		if callee == Any {
+			return Any
+		}
+	}
+
+	checker.addError(call.Function, "функция эмес: %s", callee)
+	return Any
+}
+
+// argumentOrder returns the order in which to unify arguments with the
+// parameters: as written, except that when there are floats, integers
+// go last, so that a parameter a float makes Бөлчөк takes them as floats.
+func argumentOrder(arguments []Type) []int {
+	var order, integers []int
+	floats := false
+	for i, argument := range arguments {
+		switch Prune(argument) {
+		case Integer:
+			integers = append(integers, i)
+			continue
+		case Float:
+			floats = true
+		}
+		order = append(order, i)
+	}
+	if !floats {
+		order = order[:0]
+		for i := range arguments {
+			order = append(order, i)
+		}
+		return order
+	}
+
+	return append(order, integers...)
+}
+
+func (checker *checker) indexExpression(expression *ast.IndexExpression) Type {
+	left, index := Prune(checker.expression(expression.Left)), Prune(checker.expression(expression.Index))
+
+	switch left := left.(type) {
+	case *List:
+		if unify(index, Integer) == nil {
+			return left.Element
+		}
+	case *Hash:
+		if !hashable(index) {
+			checker.addError(expression.Index, "%s сөздүктүн ачкычы боло албайт", index)
+		}
+		unify(index, left.Key)
+		return left.Value
+	case *Variable:
+		// It may be a list or a hash.
+		return Any
+	case Basic:
+		if left == Any {
+			return Any
+		}
+	}
+
+	names := newNames()
+	checker.addError(expression, "индекс оператору колдоого алынбайт: %s[%s]", names.format(left), names.format(index))
+	return Any
+}
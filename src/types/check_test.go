@@ -0,0 +1,174 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+	"github.com/asanoviskhak/alipp/src/lexer"
+	"github.com/asanoviskhak/alipp/src/loader"
+	"github.com/asanoviskhak/alipp/src/parser"
+)
+
+func check(t *testing.T, input string) []string {
+	t.Helper()
+
+	parserInstance := parser.NewParser(lexer.New(input))
+	program := parserInstance.ParseProgram()
+	if len(parserInstance.Errors()) > 0 {
+		t.Fatalf("%q: parser errors: %v", input, parserInstance.Errors())
+	}
+
+	messages := []string{}
+	for _, checkError := range Check(program) {
+		messages = append(messages, checkError.Error())
+	}
+
+	return messages
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`сакта x: Сан = 5; x + 1;`, nil},
+		{`сакта x = 5 + туура;`, []string{"1:11: түрлөр дал келбейт: Сан + Логикалык"}},
+		{`сакта x: Сан = "беш";`, []string{`1:16: x: Сан күтүлгөн, Сап берилди`}},
+		{`сакта x: Бөлчөк = 5;`, nil},
+		{`сакта [a] = 5;`, []string{"1:7: [a]: Тизме[а] күтүлгөн, Сан берилди"}},
+		{`сакта [a = "бир"] = [1];`, []string{"1:12: демейки маани: Сан күтүлгөн, Сап берилди"}},
+		{`сакта {1: a} = {"а": 1};`, []string{"1:8: ачкыч: Сап күтүлгөн, Сан берилди"}},
+		{`сакта [a, ...b]: Тизме[Сан] = [1]; a + b;`, []string{"1:36: түрлөр дал келбейт: Сан + Тизме[Сан]"}},
+		{`сакта f = функ([a, b], {аты}) { a + аты }; f([1, 2], {"аты": 3});`, nil},
+		{`салыштыр ([1]) { [n] => n + "а", _ => 0 };`, []string{"1:25: түрлөр дал келбейт: Сан + Сап"}},
+		{`сакта x: Сан = 2.5;`, []string{"1:16: x: Сан күтүлгөн, Бөлчөк берилди"}},
+		{`"a" - "b";`, []string{"1:1: белгисиз оператор: Сап - Сап"}},
+		{`"a" < 1;`, []string{"1:1: түрлөр дал келбейт: Сап < Сан"}},
+		{`"a" + "b" == "ab";`, nil},
+		{`-"a";`, []string{"1:1: белгисиз оператор: -Сап"}},
+		{`[1] == [2]; [1] == ["a"]; [1] == {};`, []string{"1:27: түрлөр дал келбейт: Тизме[Сан] == Сөздүк[а, б]"}},
+		{`сакта кош = функ(a: Сан, b: Сан): Сан { a + b }; кош(1, "эки");`, []string{"1:57: 2-аргумент: Сан күтүлгөн, Сап берилди"}},
+		{`сакта кош = функ(a: Сан, b: Сан): Сан { a + b }; кош(1);`, []string{"1:50: 2 аргумент керек, 1 берилди"}},
+		{`сакта кош = функ(a, b = 1) { a + b }; кош(1) + кош(1, 2);`, nil},
+		{`сакта кош = функ(a, b = 1) { a + b }; кош();`, []string{"1:39: 1-2 аргумент керек, 0 берилди"}},
+		{`сакта кош = функ(a, b = 1) { a + b }; кош(1, 2, 3);`, []string{"1:39: 1-2 аргумент керек, 3 берилди"}},
+		{`сакта f = функ(a: Сан = "бир") { a };`, []string{"1:25: демейки маани: Сан күтүлгөн, Сап берилди"}},
+		{`сакта f = функ(a): Сан { "a" };`, []string{`1:26: кайтарылган маани: Сан күтүлгөн, Сап берилди`}},
+		{`сакта f = функ(a): Сан { эгер (a) { кайтар туура; } 1 };`, []string{"1:44: кайтарылган маани: Сан күтүлгөн, Логикалык берилди"}},
+		{`сакта f = функ(a) { эгер (a) { кайтар 1; } 2 }; f(0) + "a";`, []string{"1:49: түрлөр дал келбейт: Сан + Сап"}},
+		{`сакта факт = функ(n: Сан): Сан { эгер (n < 2) { 1 } же { n * факт(n - 1) } }; факт("беш");`, []string{"1:84: 1-аргумент: Сан күтүлгөн, Сап берилди"}},
+		{`5(1);`, []string{"1:1: функция эмес: Сан"}},
+		{`белгисиз + 1;`, []string{"1:1: белгисиз идентификатор: белгисиз"}},
+		{`сакта f = функ() { кийин }; сакта кийин = 1;`, nil},
+		{`көрсөтүү(сап.узундук("a") + 1);`, nil},
+		{`сакта т = [1, 2]; т["a"]; т[0] + 1;`, []string{`1:19: индекс оператору колдоого алынбайт: Тизме[Сан][Сап]`}},
+		{`сакта с = {"a": 1}; с[[1]];`, []string{"1:23: Тизме[Сан] сөздүктүн ачкычы боло албайт"}},
+		{`сакта с: Сөздүк[Сап, Сан] = {"a": 1, "b": 2.5};`, []string{"1:29: с: Сөздүк[Сап, Сан] күтүлгөн, Сөздүк[Сап, Бөлчөк] берилди"}},
+		{`сакта т: Тизме[Сап] = [];`, nil},
+		{`сакта x: Сандар = 1;`, []string{"1:10: белгисиз түр: Сандар"}},
+		{`сакта x: Тизме = 1;`, []string{"1:10: Тизме 1 түрдү алат, 0 берилди"}},
+		{`сакта x: Сөздүк[Тизме[Сан], Сан] = {};`, []string{"1:17: Тизме[Сан] сөздүктүн ачкычы боло албайт"}},
+		{`сакта колдон = функ(f: функ(Сан): Сан) { f(1) }; колдон(функ(x: Сан): Сан { x }); колдон(функ(x: Сап) { x });`, []string{"1:90: 1-аргумент: функ(Сан): Сан күтүлгөн, функ(Сап): Сап берилди"}},
+		{`сакта x = эгер (туура) { 1 } же { 2.5 }; x + "a";`, []string{"1:42: түрлөр дал келбейт: Бөлчөк + Сап"}},
+		{`аракет { ыргыт "ката"; } кармоо (e) { e + 1 }`, nil},
+		{`сакта id = функ(x) { x }; id(1) + 1; id("a") + "b";`, nil},
+		{`сакта кош = функ(a, b) { a + b }; кош(1, 2.5); кош(1, "эки");`, []string{"1:55: 2-аргумент: Сан күтүлгөн, Сап берилди"}},
+		{`функ(x) { x + 1; x + "a" };`, []string{"1:18: түрлөр дал келбейт: а + Сап"}},
+		{`сакта эки_эсе = функ(x) { x * 2 }; эки_эсе(2.5) + 0.5; эки_эсе(2) + 1; эки_эсе("a");`, []string{"1:80: 1-аргумент: а күтүлгөн, Сап берилди"}},
+		{`функ(x) { x + туура };`, []string{"1:11: белгисиз оператор: Логикалык + Логикалык"}},
+		{`функ(f) { f(1); f("a") };`, []string{"1:19: 1-аргумент: Сан күтүлгөн, Сап берилди"}},
+		{`функ(x) { x(x) };`, []string{"1:11: чексиз түр: а = функ(а): б"}},
+		{`сакта f = функ(x) { f(x, 1) };`, []string{"1:11: f: функ(а, Сан): б күтүлгөн, функ(а): б берилди"}},
+		{`сакта т = []; сакта сандар: Тизме[Сан] = т; сакта саптар: Тизме[Сап] = т;`, nil},
+		{`функ(x) { эгер (x) { 1 } же { "a" } };`, nil},
+	}
+
+	for _, test := range tests {
+		messages := check(t, test.input)
+		if strings.Join(messages, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("%s:\nwant=%q\ngot=%q", test.input, test.expected, messages)
+		}
+	}
+}
+
+func TestCheckModules(t *testing.T) {
+	directory := t.TempDir()
+	files := map[string]string{
+		"сан.alipp":     "экспорт сакта квадрат = функ(x: Сан): Сан { x * x };\nэкспорт сакта аты = \"сан\";",
+		"негизги.alipp": "импорт { квадрат, аты } \"./сан.alipp\";\nквадрат(аты);",
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	modules, loadErrors := loader.New(nil).Load(filepath.Join(directory, "негизги.alipp"))
+	if len(loadErrors) > 0 {
+		t.Fatalf("load errors: %v", loadErrors)
+	}
+
+	errors := CheckModules(modules)
+	expected := modules[len(modules)-1].Name + ":2:9: 1-аргумент: Сан күтүлгөн, Сап берилди"
+	if len(errors) != 1 || errors[0].Error() != expected {
+		t.Errorf("want=%q, got=%v", expected, errors)
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`1 + 2.5`, "Бөлчөк"},
+		{`сакта id = функ(x) { x };`, ""},
+		{`id`, "функ(а): а"},
+		{`[id(1), id(2)]`, "Тизме[Сан]"},
+		{`сакта кош = функ(a, b) { a + b };`, ""},
+		{`кош`, "функ(а, а): а (а: Сан, Бөлчөк же Сап)"},
+		{`кош(1, 2.5)`, "Бөлчөк"},
+		{`функ(a, b) { a < b }`, "функ(а, а): Логикалык (а: Сан же Бөлчөк)"},
+		{`функ(x) { x * 2 }`, "функ(а): а (а: Сан же Бөлчөк)"},
+		{`функ(x) { 1.5 - x }`, "функ(а): Бөлчөк (а: Сан же Бөлчөк)"},
+		{`функ(x) { [x + 1, x] }`, "функ(а): Тизме[а] (а: Сан же Бөлчөк)"},
+		{`функ(f, g) { функ(x) { f(g(x)) } }`, "функ(функ(а): б, функ(в): а): функ(в): б"},
+		{`сакта факт = функ(n) { эгер (n < 2) { 1 } же { n * факт(n - 1) } };`, ""},
+		{`факт`, "функ(Сан): Сан"},
+		{`функ(x) { ыргыт x; }`, "функ(а): б"},
+		{`{}`, "Сөздүк[а, б]"},
+		{`функ(x: Сап) { x }`, "функ(Сап): Сап"},
+		{`функ(f) { f(f) }`, "функ(а): Каалаган"},
+		{`салыштыр ([1, 2]) { [a, b] => a + b, _ => 0 }`, "Сан"},
+		{`салыштыр ({"а": "б"}) { {"а": x} => x, _ => "жок" }`, "Сап"},
+		{`функ(x) { салыштыр (x) { n эгер n > 0 => n, _ => 0 } }`, "функ(Сан): Сан"},
+		{`салыштыр (1) { 1 => "бир", _ => 2 }`, "Каалаган"},
+		{`сакта [x, y, ...z] = [1, 2, 3];`, ""},
+		{`[x, z]`, "Тизме[Каалаган]"},
+		{`z`, "Тизме[Сан]"},
+		{`сакта [f, g] = [функ(x) { x }, функ(x) { x }];`, ""},
+		{`[f(1), f(2)]`, "Тизме[Сан]"},
+		{`функ([a, b]) { a + b }`, "функ(Тизме[а]): а (а: Сан, Бөлчөк же Сап)"},
+		{`функ({ аты, жашы = 0 }) { аты }`, "функ(Сөздүк[Сап, Сан]): Сан"},
+		{`салыштыр ([1, 2]) { [h, ...t] => t }`, "Тизме[Сан]"},
+	}
+
+	environment := NewEnvironment()
+	for _, test := range tests {
+		program := parser.NewParser(lexer.New(test.input)).ParseProgram()
+		if test.expected == "" {
+			if errors := environment.Check(program); len(errors) > 0 {
+				t.Fatalf("%s: %v", test.input, errors)
+			}
+			continue
+		}
+
+		statement := program.Statements[0].(*ast.ExpressionStatement)
+		scheme, _ := environment.TypeOf(statement.Expression)
+		if scheme.String() != test.expected {
+			t.Errorf("%s: want=%q, got=%q", test.input, test.expected, scheme)
+		}
+	}
+}
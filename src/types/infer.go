@@ -0,0 +1,272 @@
+package types
+
+import "errors"
+
+// errMismatch is what unify returns for types that cannot be the same.
+var errMismatch = errors.New("түрлөр дал келбейт")
+
+// infinite is what unify returns when a variable would have to stand
+// for a type that contains it, such as the type of the parameter f of
+// функ(f) { f(f) }.
+type infinite struct {
+	variable *Variable
+	value    Type
+}
+
+func (err *infinite) Error() string {
+	names := newNames()
+	return "чексиз түр: " + names.format(err.variable) + " = " + names.format(err.value)
+}
+
+// Prune follows bound variables to the type they stand for.
+func Prune(value Type) Type {
+	for {
+		variable, ok := value.(*Variable)
+		if !ok || variable.bound == nil {
+			return value
+		}
+		value = variable.bound
+	}
+}
+
+// unify makes a and b the same type by binding the variables in them.
+// When it cannot, it binds nothing and returns errMismatch or an
+// *infinite. Каалаган is the same as every type.
+func unify(a Type, b Type) error {
+	unifierInstance := &unifier{}
+	err := unifierInstance.equate(a, b)
+	if err != nil {
+		unifierInstance.undo()
+	}
+
+	return err
+}
+
+// subsume is unify for a value of type actual used where one of type
+// expected is, which also lets an integer be used as a float.
+func subsume(actual Type, expected Type) error {
+	if Prune(actual) == Integer && Prune(expected) == Float {
+		return nil
+	}
+
+	return unify(actual, expected)
+}
+
+// binding is a variable as it was before the unifier changed it.
+type binding struct {
+	variable *Variable
+	class    class
+}
+
+type unifier struct {
+	trail []binding
+}
+
+func (unifier *unifier) equate(a Type, b Type) error {
+	a, b = Prune(a), Prune(b)
+	if a == Any || b == Any || a == b {
+		return nil
+	}
+	if variable, ok := a.(*Variable); ok {
+		return unifier.bind(variable, b)
+	}
+	if variable, ok := b.(*Variable); ok {
+		return unifier.bind(variable, a)
+	}
+
+	switch a := a.(type) {
+	case *List:
+		if b, ok := b.(*List); ok {
+			return unifier.equate(a.Element, b.Element)
+		}
+	case *Hash:
+		if b, ok := b.(*Hash); ok {
+			if err := unifier.equate(a.Key, b.Key); err != nil {
+				return err
+			}
+			return unifier.equate(a.Value, b.Value)
+		}
+	case *Function:
+		if b, ok := b.(*Function); ok && len(a.Parameters) == len(b.Parameters) {
+			for i, parameter := range a.Parameters {
+				if err := unifier.equate(parameter, b.Parameters[i]); err != nil {
+					return err
+				}
+			}
+			return unifier.equate(a.Result, b.Result)
+		}
+	}
+
+	return errMismatch
+}
+
+// bind binds variable to value, which is pruned and not variable. A
+// variable bound to another passes its class on to it.
+func (unifier *unifier) bind(variable *Variable, value Type) error {
+	if other, ok := value.(*Variable); ok {
+		unifier.trail = append(unifier.trail, binding{variable: other, class: other.class})
+		other.class = max(other.class, variable.class)
+	} else if occurs(variable, value) {
+		return &infinite{variable: variable, value: value}
+	} else if !variable.class.admits(value) {
+		return errMismatch
+	}
+
+	unifier.trail = append(unifier.trail, binding{variable: variable, class: variable.class})
+	variable.bound = value
+	return nil
+}
+
+func (unifier *unifier) undo() {
+	for i := len(unifier.trail) - 1; i >= 0; i-- {
+		unifier.trail[i].variable.bound = nil
+		unifier.trail[i].variable.class = unifier.trail[i].class
+	}
+	unifier.trail = nil
+}
+
+// occurs reports whether variable is part of value.
+func occurs(variable *Variable, value Type) bool {
+	switch value := Prune(value).(type) {
+	case *Variable:
+		return value == variable
+	case *List:
+		return occurs(variable, value.Element)
+	case *Hash:
+		return occurs(variable, value.Key) || occurs(variable, value.Value)
+	case *Function:
+		for _, parameter := range value.Parameters {
+			if occurs(variable, parameter) {
+				return true
+			}
+		}
+		return occurs(variable, value.Result)
+	}
+
+	return false
+}
+
+// constrain limits value to the types of class, and reports whether it
+// is one of them or a variable that may yet be.
+func constrain(value Type, class class) bool {
+	value = Prune(value)
+	if variable, ok := value.(*Variable); ok {
+		variable.class = max(variable.class, class)
+		return true
+	}
+
+	return class.admits(value)
+}
+
+// join returns the type of a value that is of type a or of type b,
+// unifying them when it can. A nil type is that of a value that never
+// comes, such as the value of a block that ends with кайтар. Types that
+// cannot be unified join to Каалаган, and so does Бош with a variable,
+// which would otherwise become Бош.
+func join(a Type, b Type) Type {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	a, b = Prune(a), Prune(b)
+	_, aVariable := a.(*Variable)
+	_, bVariable := b.(*Variable)
+	switch {
+	case a == Any || b == Any:
+		return Any
+	case (a == Integer && b == Float) || (a == Float && b == Integer):
+		return Float
+	case (aVariable && b == Null) || (bVariable && a == Null):
+		return Any
+	case unify(a, b) == nil:
+		return a
+	}
+
+	return Any
+}
+
+// variables appends the unbound variables of value that found does not
+// hold yet to found, in the order they appear.
+func variables(value Type, found []*Variable) []*Variable {
+	switch value := Prune(value).(type) {
+	case *Variable:
+		for _, variable := range found {
+			if variable == value {
+				return found
+			}
+		}
+		return append(found, value)
+	case *List:
+		return variables(value.Element, found)
+	case *Hash:
+		return variables(value.Value, variables(value.Key, found))
+	case *Function:
+		for _, parameter := range value.Parameters {
+			found = variables(parameter, found)
+		}
+		return variables(value.Result, found)
+	}
+
+	return found
+}
+
+// free returns the variables of the scheme that every use of it shares.
+func (scheme *Scheme) free() []*Variable {
+	var free []*Variable
+	for _, variable := range variables(scheme.Type, nil) {
+		if !contains(scheme.Variables, variable) {
+			free = append(free, variable)
+		}
+	}
+
+	return free
+}
+
+// instantiate returns the type of one use of the scheme.
+func (scheme *Scheme) instantiate() Type {
+	if len(scheme.Variables) == 0 {
+		return scheme.Type
+	}
+
+	fresh := map[*Variable]Type{}
+	for _, variable := range scheme.Variables {
+		fresh[variable] = &Variable{class: variable.class}
+	}
+
+	return substitute(scheme.Type, fresh)
+}
+
+func substitute(value Type, fresh map[*Variable]Type) Type {
+	switch value := Prune(value).(type) {
+	case *Variable:
+		if replacement, ok := fresh[value]; ok {
+			return replacement
+		}
+		return value
+	case *List:
+		return &List{Element: substitute(value.Element, fresh)}
+	case *Hash:
+		return &Hash{Key: substitute(value.Key, fresh), Value: substitute(value.Value, fresh)}
+	case *Function:
+		function := &Function{Parameters: make([]Type, len(value.Parameters)), Result: substitute(value.Result, fresh), Optional: value.Optional}
+		for i, parameter := range value.Parameters {
+			function.Parameters[i] = substitute(parameter, fresh)
+		}
+		return function
+	default:
+		return value
+	}
+}
+
+func contains(list []*Variable, variable *Variable) bool {
+	for _, each := range list {
+		if each == variable {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,257 @@
+// Package types checks alipp programs for type errors without running
+// them. Annotations are optional: the checker infers the types of what
+// has none by unification, and what it cannot infer is Каалаган, which
+// agrees with every type.
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/asanoviskhak/alipp/src/ast"
+)
+
+// Type is the type of a value, written the way annotations write it.
+type Type interface {
+	String() string
+}
+
+// Basic is a type that has no parts.
+type Basic string
+
+const (
+	Integer Basic = "Сан"
+	Float   Basic = "Бөлчөк"
+	String  Basic = "Сап"
+	Boolean Basic = "Логикалык"
+	Null    Basic = "Бош"
+	// Any is the type of a value the checker knows nothing about.
+	Any Basic = "Каалаган"
+)
+
+func (basic Basic) String() string { return string(basic) }
+
+// List is the type of a list whose elements are all of type Element.
+type List struct {
+	Element Type
+}
+
+func (list *List) String() string { return newNames().format(list) }
+
+// Hash is the type of a hash from keys of type Key to values of type
+// Value.
+type Hash struct {
+	Key   Type
+	Value Type
+}
+
+func (hash *Hash) String() string { return newNames().format(hash) }
+
+// Function is the type of a function.
+type Function struct {
+	Parameters []Type
+	Result     Type
+	// Optional is how many of the last parameters have a default, and so
+	// may be left out of a call.
+	Optional int
+}
+
+func (function *Function) String() string { return newNames().format(function) }
+
+// Variable is a type the checker has yet to work out. Once unification
+// binds it, it stands for the type it is bound to.
+type Variable struct {
+	bound Type
+	class class
+}
+
+func (variable *Variable) String() string { return newNames().format(variable) }
+
+// Limits returns the types the operators used on the variable limit it
+// to, or nil when it may stand for any type.
+func (variable *Variable) Limits() []Type {
+	switch variable.class {
+	case addable:
+		return []Type{Integer, Float, String}
+	case numeric:
+		return []Type{Integer, Float}
+	}
+
+	return nil
+}
+
+// class limits the types a variable may stand for, as an operand of an
+// arithmetic operator does.
+type class int
+
+const (
+	anything class = iota
+	// addable are the types + takes: Сан, Бөлчөк and Сап.
+	addable
+	// numeric are the types the other arithmetic operators take.
+	numeric
+)
+
+func (class class) admits(value Type) bool {
+	switch class {
+	case addable:
+		return value == Integer || value == Float || value == String || value == Any
+	case numeric:
+		return value == Integer || value == Float || value == Any
+	}
+
+	return true
+}
+
+func (class class) String() string {
+	if class == numeric {
+		return "Сан же Бөлчөк"
+	}
+
+	return "Сан, Бөлчөк же Сап"
+}
+
+// Scheme is the type of a name bound with сакта. Every use of the name
+// fills in Variables afresh, so that a function that works on values of
+// any type may be used with values of different types.
+type Scheme struct {
+	Variables []*Variable
+	Type      Type
+}
+
+// String writes the type with its variables named а, б, в and so on,
+// followed by what the operators used on them limit them to.
+func (scheme *Scheme) String() string {
+	names := newNames()
+	text := names.format(scheme.Type)
+
+	var limits []string
+	for _, variable := range names.order {
+		if variable.class != anything {
+			limits = append(limits, names.format(variable)+": "+variable.class.String())
+		}
+	}
+	if len(limits) > 0 {
+		text += " (" + strings.Join(limits, "; ") + ")"
+	}
+
+	return text
+}
+
+// letters name type variables, in the order they are met.
+var letters = []string{"а", "б", "в", "г", "д", "е", "ж", "з", "и", "к", "л", "м", "н", "о", "п", "р", "с", "т", "у", "ф", "х", "ч", "ш", "ы", "э", "ю", "я"}
+
+// names writes types, naming the variables in them. Types written with
+// the same names agree on which variable each name stands for.
+type names struct {
+	variables map[*Variable]string
+	order     []*Variable
+}
+
+func newNames() *names {
+	return &names{variables: map[*Variable]string{}}
+}
+
+func (names *names) format(value Type) string {
+	switch value := Prune(value).(type) {
+	case *Variable:
+		if name, ok := names.variables[value]; ok {
+			return name
+		}
+		name := letters[len(names.order)%len(letters)]
+		if round := len(names.order) / len(letters); round > 0 {
+			name += strconv.Itoa(round)
+		}
+		names.variables[value] = name
+		names.order = append(names.order, value)
+		return name
+	case *List:
+		return "Тизме[" + names.format(value.Element) + "]"
+	case *Hash:
+		return "Сөздүк[" + names.format(value.Key) + ", " + names.format(value.Value) + "]"
+	case *Function:
+		parameters := make([]string, len(value.Parameters))
+		for i, parameter := range value.Parameters {
+			parameters[i] = names.format(parameter)
+		}
+		return "функ(" + strings.Join(parameters, ", ") + "): " + names.format(value.Result)
+	case Basic:
+		return string(value)
+	}
+
+	return value.String()
+}
+
+// basics are the types annotations name with a word.
+var basics = map[string]Basic{}
+
+func init() {
+	for _, basic := range []Basic{Integer, Float, String, Boolean, Null, Any} {
+		basics[string(basic)] = basic
+	}
+}
+
+// Resolve returns the type an annotation stands for. Names that are not
+// types stand for Каалаган; Check reports them.
+func Resolve(expression ast.TypeExpression) Type {
+	return resolve(expression, func(ast.Node, string) {})
+}
+
+// resolve is Resolve that reports what is wrong with the annotation.
+func resolve(expression ast.TypeExpression, report func(node ast.Node, message string)) Type {
+	switch expression := expression.(type) {
+	case *ast.NamedType:
+		arguments := make([]Type, len(expression.Arguments))
+		for i, argument := range expression.Arguments {
+			arguments[i] = resolve(argument, report)
+		}
+
+		expected := 0
+		switch expression.Name {
+		case "Тизме":
+			expected = 1
+		case "Сөздүк":
+			expected = 2
+		default:
+			if _, ok := basics[expression.Name]; !ok {
+				report(expression, "белгисиз түр: "+expression.Name)
+				return Any
+			}
+		}
+		if len(arguments) != expected {
+			report(expression, fmt.Sprintf("%s %d түрдү алат, %d берилди", expression.Name, expected, len(arguments)))
+			return Any
+		}
+
+		switch expression.Name {
+		case "Тизме":
+			return &List{Element: arguments[0]}
+		case "Сөздүк":
+			if !hashable(arguments[0]) {
+				report(expression.Arguments[0], arguments[0].String()+" сөздүктүн ачкычы боло албайт")
+			}
+			return &Hash{Key: arguments[0], Value: arguments[1]}
+		}
+		return basics[expression.Name]
+	case *ast.FunctionType:
+		function := &Function{Parameters: make([]Type, len(expression.Parameters))}
+		for i, parameter := range expression.Parameters {
+			function.Parameters[i] = resolve(parameter, report)
+		}
+		function.Result = resolve(expression.Result, report)
+		return function
+	}
+
+	return Any
+}
+
+// hashable reports whether values of a type may be the keys of a hash.
+func hashable(key Type) bool {
+	key = Prune(key)
+	if _, ok := key.(*Variable); ok {
+		return true
+	}
+
+	return key == Integer || key == String || key == Boolean || key == Any
+}